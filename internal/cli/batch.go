@@ -20,15 +20,23 @@ type BatchConfig struct {
 
 // BatchDefaults holds default settings applied to all runs unless overridden.
 type BatchDefaults struct {
+	AgentFallback  string `toml:"agent_fallback"`
 	Tier           string `toml:"tier"`
 	Difficulty     string `toml:"difficulty"`
 	Lang           string `toml:"lang"`
+	SkipLangs      string `toml:"skip_langs"`
 	Tasks          string `toml:"tasks"`
 	Timeout        int    `toml:"timeout"`
+	TimeoutGrace   int    `toml:"timeout_grace"`
+	SystemPrompt   string `toml:"system_prompt"`
 	Parallel       int    `toml:"parallel"`
 	KeepWorkspaces bool   `toml:"keep_workspaces"`
 	UseMCPTools    bool   `toml:"use_mcp_tools"`
 	UseSkills      bool   `toml:"use_skills"`
+	Lint           bool   `toml:"lint"`
+	PromptBudget   int    `toml:"prompt_budget_tokens"`
+	ReuseContainer bool   `toml:"reuse_container"`
+	Robustness     bool   `toml:"robustness"`
 	DisableMCP     bool   `toml:"disable_mcp"`
 	NoSandbox      bool   `toml:"no_sandbox"`
 	Legacy         bool   `toml:"legacy"`
@@ -82,18 +90,28 @@ The TOML file supports defaults that apply to all runs, with per-run overrides.`
 		// Build shared config from defaults.
 		defaults := batchCfg.Defaults
 		shared := SharedConfig{
+			AgentFallback:  defaults.AgentFallback,
 			Tier:           defaults.Tier,
 			Difficulty:     defaults.Difficulty,
 			Lang:           defaults.Lang,
+			SkipLangs:      defaults.SkipLangs,
 			Tasks:          defaults.Tasks,
 			Timeout:        defaults.Timeout,
+			TimeoutGrace:   defaults.TimeoutGrace,
+			SystemPrompt:   defaults.SystemPrompt,
 			Parallel:       defaults.Parallel,
 			KeepWorkspaces: defaults.KeepWorkspaces,
 			UseMCPTools:    defaults.UseMCPTools,
 			UseSkills:      defaults.UseSkills,
+			Lint:           defaults.Lint,
+			PromptBudget:   defaults.PromptBudget,
+			ReuseContainer: defaults.ReuseContainer,
+			Robustness:     defaults.Robustness,
 			DisableMCP:     defaults.DisableMCP,
 			NoSandbox:      defaults.NoSandbox,
 			Legacy:         defaults.Legacy,
+
+			TimeoutExplicit: defaults.Timeout > 0,
 		}
 		if shared.Timeout == 0 {
 			if cfg != nil && cfg.Harness.DefaultTimeout > 0 {
@@ -138,10 +156,13 @@ The TOML file supports defaults that apply to all runs, with per-run overrides.`
 					available := strings.Join(cfg.ListAgents(), ", ")
 					return fmt.Errorf("unknown agent: %s (available: %s)", spec.Agent, available)
 				}
-				if _, err := exec.LookPath(agentCfg.Command); err != nil {
-					return fmt.Errorf("agent %q binary %q not found in PATH", spec.Agent, agentCfg.Command)
+				if _, err := exec.LookPath(agentCfg.Executable()); err != nil {
+					return fmt.Errorf("agent %q binary %q not found in PATH", spec.Agent, agentCfg.Executable())
 				}
 			}
+			if err := checkDangerousAgents(specs); err != nil {
+				return err
+			}
 		}
 
 		// Dry-run mode.
@@ -180,18 +201,25 @@ The TOML file supports defaults that apply to all runs, with per-run overrides.`
 		if shared.Legacy {
 			r.LegacyHiddenTests = true
 		}
+		r.ReuseContainers = shared.ReuseContainer
 
 		// Load and filter tasks.
 		allTasks, err := r.ListTasks()
 		if err != nil {
 			return fmt.Errorf("listing tasks: %w", err)
 		}
-		allTasks = filterTasksForShared(allTasks, shared)
+		if _, err := parseSkipLangs(shared.SkipLangs); err != nil {
+			return err
+		}
+		allTasks = filterTasksForShared(allTasks, &shared)
 		if len(allTasks) == 0 {
 			return fmt.Errorf("no tasks match the specified filters")
 		}
 
 		evalSandboxActive = initSandbox()
+		if err := checkSandboxNetwork(); err != nil {
+			return err
+		}
 
 		if restoreFn, err := protectTasksDir(); err != nil {
 			logger.Warn("failed to protect tasks directory", "error", err)
@@ -209,17 +237,19 @@ The TOML file supports defaults that apply to all runs, with per-run overrides.`
 		}
 
 		writeMultiRunConfig(umbrellaDir, specs, shared, repeat)
+		setRunGlobals(shared)
 
 		var allSummaries []runResult
 		for specIdx, spec := range specs {
 			// Apply per-run timeout override.
 			runShared := shared
 			runShared.Timeout = perRunTimeouts[specIdx]
+			runShared.TimeoutExplicit = shared.TimeoutExplicit || batchCfg.Runs[specIdx].Timeout > 0
 
 			for rep := 1; rep <= repeat; rep++ {
 				if checkInterrupted(interruptCtx) {
 					updateMultiRunState(umbrellaDir, allSummaries, specs, repeat, true)
-					printMultiRunResumeCommand(umbrellaDir)
+					printMultiRunResumeCommand(os.Stdout, umbrellaDir)
 					return nil
 				}
 
@@ -228,7 +258,7 @@ The TOML file supports defaults that apply to all runs, with per-run overrides.`
 					interruptCtx, spec, runShared, allTasks, allTasks,
 					runDir, timestamp, r, false, nil, nil, nil, nil, nil,
 				)
-				rr := runResult{spec: spec, repeat: rep, summary: summary}
+				rr := runResult{spec: spec, specIdx: specIdx, repeat: rep, summary: summary}
 				if err != nil {
 					logger.Warn("run failed", "agent", spec.Agent, "repeat", rep, "error", err)
 					rr.err = err
@@ -250,6 +280,7 @@ The TOML file supports defaults that apply to all runs, with per-run overrides.`
 				comparison := generateComparison(summaries)
 				writeComparisonJSON(umbrellaDir, comparison)
 				writeComparisonMarkdown(umbrellaDir, comparison)
+				writeComparisonCSV(umbrellaDir, comparison)
 			}
 		}
 
@@ -266,5 +297,6 @@ func init() {
 	batchCmd.Flags().StringVar(&batchConfigFile, "config", "", "path to batch TOML config file (required)")
 	batchCmd.Flags().IntVar(&batchRepeat, "repeat", 1, "repeat each configuration N times")
 	batchCmd.Flags().BoolVar(&batchDryRun, "dry-run", false, "show what would be run without executing")
+	batchCmd.Flags().BoolVar(&evalUnderstandCosts, "i-understand-costs", false, "run agents that skip permission prompts without asking first (see [harness] confirm_dangerous_agents)")
 	_ = batchCmd.MarkFlagRequired("config")
 }
@@ -9,7 +9,10 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var compareOutputFile string
+var (
+	compareOutputFile    string
+	compareBaselineModel string
+)
 
 var compareCmd = &cobra.Command{
 	Use:   "compare <dir> [dir...]",
@@ -17,12 +20,19 @@ var compareCmd = &cobra.Command{
 	Long: `Compare two or more eval result directories and produce a side-by-side
 comparison table showing pass rates, weighted scores, and per-task results.
 
-Supports glob patterns for convenient selection of multiple directories.`,
+Supports glob patterns for convenient selection of multiple directories.
+
+With --compare-baseline-model, each run is also scored relative to a
+baseline model's run, overall and per task, with the baseline at 1.0.`,
 	Example: `  sanity compare eval-results/*-gemini eval-results/*-codex
   sanity compare ./run-a ./run-b ./run-c
-  sanity compare eval-results/multi-2026-02-21T024300/codex-gpt-5.2 eval-results/multi-2026-02-21T024300/opencode-kimi-k2.5`,
-	Args: cobra.MinimumNArgs(2),
+  sanity compare eval-results/multi-2026-02-21T024300/codex-gpt-5.2 eval-results/multi-2026-02-21T024300/opencode-kimi-k2.5
+  sanity compare eval-results/*-codex --compare-baseline-model eval-results/2026-02-01T120000-gemini`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 && compareBaselineModel == "" {
+			return fmt.Errorf("compare needs at least 2 run directories, or 1 with --compare-baseline-model")
+		}
 		var summaries []EvalSummary
 		for _, dir := range args {
 			s, err := loadSummaryFromDir(dir)
@@ -33,6 +43,13 @@ Supports glob patterns for convenient selection of multiple directories.`,
 		}
 
 		comparison := generateComparison(summaries)
+		if compareBaselineModel != "" {
+			baseline, err := loadSummaryFromDir(compareBaselineModel)
+			if err != nil {
+				return fmt.Errorf("loading baseline from %s: %w", compareBaselineModel, err)
+			}
+			applyBaselineRelative(&comparison, summaries, *baseline, compareBaselineModel)
+		}
 
 		// Write JSON if output file specified.
 		if compareOutputFile != "" {
@@ -48,6 +65,7 @@ Supports glob patterns for convenient selection of multiple directories.`,
 
 func init() {
 	compareCmd.Flags().StringVarP(&compareOutputFile, "output", "o", "", "write comparison JSON to file")
+	compareCmd.Flags().StringVar(&compareBaselineModel, "compare-baseline-model", "", "score each run relative to this baseline run directory (baseline = 1.0)")
 }
 
 // loadSummaryFromDir loads an EvalSummary from a directory's summary.json.
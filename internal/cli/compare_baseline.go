@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BaselineRelative expresses a comparison's runs relative to a baseline
+// model's run, which scores 1.0. Ratios only cover tasks present in both
+// runs, so they hold up better than raw scores across suite versions.
+type BaselineRelative struct {
+	Source string `json:"source"`
+	Agent  string `json:"agent"`
+	Model  string `json:"model,omitempty"`
+	// Tasks maps task -> run ID -> relative score, for tasks the baseline
+	// scored on.
+	Tasks map[string]map[string]float64 `json:"tasks"`
+}
+
+// applyBaselineRelative sets each run's relative score against baseline and
+// records per-task ratios. summaries are the comparison's runs, in order. A
+// run's relative score is its weighted score over the tasks it shares with
+// the baseline divided by the baseline's on those tasks; it is left unset
+// when the baseline scored nothing there.
+func applyBaselineRelative(c *Comparison, summaries []EvalSummary, baseline EvalSummary, source string) {
+	baseScores := make(map[string]float64, len(baseline.Results))
+	for _, r := range baseline.Results {
+		baseScores[r.Task] = r.WeightedScore
+	}
+
+	rel := &BaselineRelative{
+		Source: source,
+		Agent:  baseline.Agent,
+		Model:  baseline.Model,
+		Tasks:  make(map[string]map[string]float64),
+	}
+	for i, s := range summaries {
+		id := c.Runs[i].ID
+		var score, base float64
+		for _, r := range s.Results {
+			b, ok := baseScores[r.Task]
+			if !ok {
+				continue
+			}
+			score += r.WeightedScore
+			base += b
+			if b > 0 {
+				if rel.Tasks[r.Task] == nil {
+					rel.Tasks[r.Task] = make(map[string]float64)
+				}
+				rel.Tasks[r.Task][id] = r.WeightedScore / b
+			}
+		}
+		if base > 0 {
+			relative := score / base
+			c.Runs[i].RelativeScore = &relative
+		}
+	}
+	c.Baseline = rel
+}
+
+// writeBaselineRelativeReport appends the relative-to-baseline section of a
+// comparison report.
+func writeBaselineRelativeReport(sb *strings.Builder, c Comparison) {
+	rel := c.Baseline
+	name := rel.Agent
+	if rel.Model != "" && rel.Model != "unknown" {
+		name += "/" + rel.Model
+	}
+	fmt.Fprintf(sb, "### Relative to Baseline (%s = 1.00×)\n\n", name)
+	fmt.Fprintf(sb, "| Run | Relative Score |\n")
+	fmt.Fprintf(sb, "|-----|----------------|\n")
+	for _, r := range c.Runs {
+		fmt.Fprintf(sb, "| %s | %s |\n", r.ID, formatRelative(r.RelativeScore))
+	}
+	sb.WriteString("\n")
+
+	if len(rel.Tasks) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "| Task |")
+	for _, r := range c.Runs {
+		fmt.Fprintf(sb, " %s |", r.ID)
+	}
+	sb.WriteString("\n|------|")
+	for range c.Runs {
+		sb.WriteString("------|")
+	}
+	sb.WriteString("\n")
+
+	tasks := make([]string, 0, len(rel.Tasks))
+	for t := range rel.Tasks {
+		tasks = append(tasks, t)
+	}
+	sort.Strings(tasks)
+	for _, t := range tasks {
+		fmt.Fprintf(sb, "| %s |", t)
+		for _, r := range c.Runs {
+			var score *float64
+			if v, ok := rel.Tasks[t][r.ID]; ok {
+				score = &v
+			}
+			fmt.Fprintf(sb, " %s |", formatRelative(score))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+}
+
+func formatRelative(score *float64) string {
+	if score == nil {
+		return "—"
+	}
+	return fmt.Sprintf("%.2f×", *score)
+}
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/cobra"
+
+	"github.com/lemon07r/sanityharness/internal/config"
+)
+
+var configShowEffective bool
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the harness configuration",
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check sanity.toml and show the config the harness resolves",
+	Long: `Loads the config the way every other command does (--config, --profile and
+the standard locations) and reports:
+
+  - which file was loaded, or that only built-in defaults apply
+  - which [harness], [docker] and [sandbox] settings kept their defaults
+  - keys the file sets that match no setting, e.g. a misspelled key
+  - agent entries eval cannot run: no command, or a model/reasoning flag
+    position other than "before" or "after"
+  - invalid regexes and retry schedules eval would not follow as written
+
+With --show the resolved config is printed as TOML. Exits with status 1 when
+any agent, language or key problem is found.`,
+	Example: `  sanity config validate
+  sanity config validate --config ./ci.toml --profile local --show`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		loaded, report, err := config.LoadProfileReport(cfgFile, profile)
+		if err != nil {
+			return err
+		}
+
+		if report.Path == "" {
+			fmt.Println(" Config: none found, using built-in defaults")
+		} else {
+			fmt.Printf(" Config: %s\n", report.Path)
+		}
+		if loaded.Profile != "" {
+			fmt.Printf(" Profile: %s\n", loaded.Profile)
+		}
+		if report.Path != "" && len(report.Defaulted) > 0 {
+			fmt.Printf(" Defaults: %s\n", strings.Join(report.Defaulted, ", "))
+		}
+
+		problems, warnings := validateConfig(loaded, report)
+		for _, w := range warnings {
+			fmt.Printf(" \033[33m⚠ %s\033[0m\n", w)
+		}
+		for _, p := range problems {
+			fmt.Printf(" \033[31m✗ %s\033[0m\n", p)
+		}
+
+		if configShowEffective {
+			fmt.Println()
+			if err := toml.NewEncoder(os.Stdout).Encode(loaded); err != nil {
+				return fmt.Errorf("encoding config: %w", err)
+			}
+		}
+
+		if len(problems) > 0 {
+			fmt.Printf("\n %d problems found\n", len(problems))
+			cmd.SilenceUsage = true
+			return &exitError{code: 1}
+		}
+		fmt.Println(" \033[32m✓ Config is valid\033[0m")
+		return nil
+	},
+}
+
+func init() {
+	configValidateCmd.Flags().BoolVar(&configShowEffective, "show", false, "print the resolved config as TOML")
+	configCmd.AddCommand(configValidateCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// checkUnknownConfigKeys warns about every key in the loaded config file that
+// matches no setting, so typos are not silently ignored. With strict set the
+// keys are an error instead.
+func checkUnknownConfigKeys(log *slog.Logger, report *config.LoadReport, strict bool) error {
+	if len(report.Unknown) == 0 {
+		return nil
+	}
+	keys := make([]string, len(report.Unknown))
+	for i, k := range report.Unknown {
+		keys[i] = k.String()
+	}
+	if strict {
+		return fmt.Errorf("config %s: unknown keys: %s", report.Path, strings.Join(keys, ", "))
+	}
+	for _, k := range keys {
+		log.Warn(fmt.Sprintf("config %s: unknown key %s is ignored (check for a typo, or use --strict-config to fail)", report.Path, k))
+	}
+	return nil
+}
+
+// validateConfig checks a loaded config, returning problems that break eval
+// and warnings about settings eval adjusts.
+func validateConfig(c *config.Config, report *config.LoadReport) (problems, warnings []string) {
+	for _, key := range report.Unknown {
+		problems = append(problems, "unknown key "+key.String())
+	}
+
+	names := make([]string, 0, len(c.Agents))
+	for name := range c.Agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ac := c.Agents[name]
+		if err := ac.Validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("[agents.%s] %v", name, err))
+		}
+		for _, w := range ac.Warnings() {
+			problems = append(problems, fmt.Sprintf("[agents.%s] %s", name, w))
+		}
+	}
+
+	langs := make([]string, 0, len(c.Languages))
+	for name := range c.Languages {
+		langs = append(langs, name)
+	}
+	sort.Strings(langs)
+	for _, name := range langs {
+		if err := c.Languages[name].Parse.Validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("[languages.%s.parse] %v", name, err))
+		}
+	}
+
+	for _, w := range c.Harness.Retry.Warnings() {
+		warnings = append(warnings, "[harness.retry] "+w)
+	}
+	return problems, warnings
+}
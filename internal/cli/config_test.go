@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/lemon07r/sanityharness/internal/config"
+)
+
+func TestCheckUnknownConfigKeys(t *testing.T) {
+	t.Parallel()
+
+	report := &config.LoadReport{
+		Path:    "sanity.toml",
+		Unknown: []config.UnknownKey{{Key: "harness.default_timout", Line: 3}, {Key: "harnes"}},
+	}
+
+	var buf bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&buf, nil))
+	if err := checkUnknownConfigKeys(log, report, false); err != nil {
+		t.Fatalf("checkUnknownConfigKeys() error = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"level=WARN", "unknown key harness.default_timout (line 3)", "unknown key harnes is ignored"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	err := checkUnknownConfigKeys(log, report, true)
+	if err == nil || !strings.Contains(err.Error(), "harness.default_timout (line 3), harnes") {
+		t.Errorf("strict error = %v, want both unknown keys", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("strict mode logged %q, want an error only", buf.String())
+	}
+
+	if err := checkUnknownConfigKeys(log, &config.LoadReport{Path: "sanity.toml"}, true); err != nil {
+		t.Errorf("no unknown keys: error = %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffJSON           bool
+	diffMaxRegressions int
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <dirA> <dirB>",
+	Short: "Show which tasks changed between two eval runs",
+	Long: `Compare the summary.json files of two eval result directories, e.g. the
+same agent before and after a change, aligning tasks by ID.
+
+Lists every task that flipped from pass to fail (a regression) or from fail
+to pass (a fix), along with the change in overall pass rate and weighted
+score (B - A). Tasks present in only one run are counted but not compared.
+
+Exits with status 1 when there are more regressions than --max-regressions
+(default 0), so the command can gate a change in CI.`,
+	Example: `  sanity diff eval-results/2026-02-01T100000-codex eval-results/2026-02-08T100000-codex
+  sanity diff ./before ./after --max-regressions 2
+  sanity diff ./before ./after --json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		a, err := loadDiffSummary(args[0])
+		if err != nil {
+			return err
+		}
+		b, err := loadDiffSummary(args[1])
+		if err != nil {
+			return err
+		}
+
+		d := diffSummaries(*a, *b)
+		if diffJSON {
+			data, err := json.MarshalIndent(d, "", "  ")
+			if err != nil {
+				return fmt.Errorf("marshaling diff: %w", err)
+			}
+			fmt.Println(string(data))
+		} else {
+			fmt.Print(buildSummaryDiffReport(d, args[0], args[1]))
+		}
+
+		if len(d.Regressions) > diffMaxRegressions {
+			return &exitError{code: 1}
+		}
+		return nil
+	},
+}
+
+func init() {
+	diffCmd.Flags().BoolVar(&diffJSON, "json", false, "output as JSON")
+	diffCmd.Flags().IntVar(&diffMaxRegressions, "max-regressions", 0, "exit with status 1 when more than this many tasks regress")
+}
+
+// SummaryDiff compares the per-task results of two eval runs.
+type SummaryDiff struct {
+	PassRateA      float64  `json:"pass_rate_a"`
+	PassRateB      float64  `json:"pass_rate_b"`
+	PassRateDelta  float64  `json:"pass_rate_delta"`
+	WeightedScoreA float64  `json:"weighted_score_a"`
+	WeightedScoreB float64  `json:"weighted_score_b"`
+	WeightedDelta  float64  `json:"weighted_score_delta"`
+	Regressions    []string `json:"regressions"`
+	Fixes          []string `json:"fixes"`
+	Unchanged      int      `json:"unchanged"`
+	OnlyInA        []string `json:"only_in_a,omitempty"`
+	OnlyInB        []string `json:"only_in_b,omitempty"`
+}
+
+// loadDiffSummary loads a run's summary.json, treating a missing file as an
+// error.
+func loadDiffSummary(dir string) (*EvalSummary, error) {
+	s, err := loadPreviousSummary(dir)
+	if err != nil {
+		return nil, fmt.Errorf("loading summary from %s: %w", dir, err)
+	}
+	if s == nil {
+		return nil, fmt.Errorf("no summary.json in %s", dir)
+	}
+	return s, nil
+}
+
+// diffSummaries aligns the results of a and b by task ID. Task lists in the
+// result are sorted.
+func diffSummaries(a, b EvalSummary) SummaryDiff {
+	d := SummaryDiff{
+		PassRateA:      a.PassRate,
+		PassRateB:      b.PassRate,
+		PassRateDelta:  b.PassRate - a.PassRate,
+		WeightedScoreA: a.WeightedScore,
+		WeightedScoreB: b.WeightedScore,
+		WeightedDelta:  b.WeightedScore - a.WeightedScore,
+		Regressions:    []string{},
+		Fixes:          []string{},
+	}
+
+	passedA := make(map[string]bool, len(a.Results))
+	for _, r := range a.Results {
+		passedA[r.Task] = r.Passed
+	}
+	inB := make(map[string]bool, len(b.Results))
+	for _, r := range b.Results {
+		inB[r.Task] = true
+		before, ok := passedA[r.Task]
+		switch {
+		case !ok:
+			d.OnlyInB = append(d.OnlyInB, r.Task)
+		case before && !r.Passed:
+			d.Regressions = append(d.Regressions, r.Task)
+		case !before && r.Passed:
+			d.Fixes = append(d.Fixes, r.Task)
+		default:
+			d.Unchanged++
+		}
+	}
+	for _, r := range a.Results {
+		if !inB[r.Task] {
+			d.OnlyInA = append(d.OnlyInA, r.Task)
+		}
+	}
+
+	sort.Strings(d.Regressions)
+	sort.Strings(d.Fixes)
+	sort.Strings(d.OnlyInA)
+	sort.Strings(d.OnlyInB)
+	return d
+}
+
+// buildSummaryDiffReport builds a human-readable diff report as a string.
+func buildSummaryDiffReport(d SummaryDiff, dirA, dirB string) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "### Diff — A: %s vs B: %s\n\n", dirA, dirB)
+	fmt.Fprintf(&sb, "| Metric | A | B | Diff (B-A) |\n")
+	fmt.Fprintf(&sb, "|--------|---|---|------------|\n")
+	fmt.Fprintf(&sb, "| Pass Rate | %.1f%% | %.1f%% | %s%.1f%% |\n", d.PassRateA, d.PassRateB, signPrefix(d.PassRateDelta), d.PassRateDelta)
+	fmt.Fprintf(&sb, "| Weighted Score | %.2f | %.2f | %s%.2f |\n\n", d.WeightedScoreA, d.WeightedScoreB, signPrefix(d.WeightedDelta), d.WeightedDelta)
+
+	if len(d.Regressions) == 0 && len(d.Fixes) == 0 {
+		fmt.Fprintf(&sb, "No tasks changed (%d unchanged).\n", d.Unchanged)
+	} else {
+		fmt.Fprintf(&sb, "| Task | A | B |\n")
+		fmt.Fprintf(&sb, "|------|---|---|\n")
+		for _, id := range d.Regressions {
+			fmt.Fprintf(&sb, "| %s | ✅ | ❌ |\n", id)
+		}
+		for _, id := range d.Fixes {
+			fmt.Fprintf(&sb, "| %s | ❌ | ✅ |\n", id)
+		}
+		fmt.Fprintf(&sb, "\n%d regressed, %d fixed, %d unchanged.\n", len(d.Regressions), len(d.Fixes), d.Unchanged)
+	}
+
+	if len(d.OnlyInA) > 0 || len(d.OnlyInB) > 0 {
+		fmt.Fprintf(&sb, "Not compared: %d tasks only in A, %d only in B.\n", len(d.OnlyInA), len(d.OnlyInB))
+	}
+	return sb.String()
+}
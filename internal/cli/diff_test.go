@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffSummaries(t *testing.T) {
+	t.Parallel()
+
+	a := EvalSummary{
+		PassRate:      50,
+		WeightedScore: 4,
+		Results: []EvalResult{
+			{Task: "go/bank-account", Passed: true},
+			{Task: "go/react", Passed: false},
+			{Task: "rust/regex-lite", Passed: true},
+			{Task: "zig/ring-buffer", Passed: true},
+			{Task: "dart/removed", Passed: true},
+		},
+	}
+	b := EvalSummary{
+		PassRate:      62.5,
+		WeightedScore: 3.5,
+		Results: []EvalResult{
+			{Task: "zig/ring-buffer", Passed: false},
+			{Task: "go/react", Passed: true},
+			{Task: "go/bank-account", Passed: false},
+			{Task: "rust/regex-lite", Passed: true},
+			{Task: "kotlin/added", Passed: true},
+		},
+	}
+
+	d := diffSummaries(a, b)
+	if want := []string{"go/bank-account", "zig/ring-buffer"}; !reflect.DeepEqual(d.Regressions, want) {
+		t.Errorf("regressions = %v, want %v", d.Regressions, want)
+	}
+	if want := []string{"go/react"}; !reflect.DeepEqual(d.Fixes, want) {
+		t.Errorf("fixes = %v, want %v", d.Fixes, want)
+	}
+	if d.Unchanged != 1 {
+		t.Errorf("unchanged = %d, want 1", d.Unchanged)
+	}
+	if !reflect.DeepEqual(d.OnlyInA, []string{"dart/removed"}) || !reflect.DeepEqual(d.OnlyInB, []string{"kotlin/added"}) {
+		t.Errorf("only in A = %v, only in B = %v", d.OnlyInA, d.OnlyInB)
+	}
+	if d.PassRateDelta != 12.5 || d.WeightedDelta != -0.5 {
+		t.Errorf("deltas = %v, %v, want 12.5, -0.5", d.PassRateDelta, d.WeightedDelta)
+	}
+
+	report := buildSummaryDiffReport(d, "before", "after")
+	for _, want := range []string{"+12.5%", "-0.50", "| go/bank-account | ✅ | ❌ |", "| go/react | ❌ | ✅ |", "2 regressed, 1 fixed, 1 unchanged", "1 tasks only in A"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestDiffSummariesNoChanges(t *testing.T) {
+	t.Parallel()
+
+	s := EvalSummary{Results: []EvalResult{{Task: "go/react", Passed: true}}}
+	d := diffSummaries(s, s)
+	if len(d.Regressions) != 0 || len(d.Fixes) != 0 || d.Unchanged != 1 {
+		t.Fatalf("diff = %+v, want one unchanged task", d)
+	}
+	if report := buildSummaryDiffReport(d, "a", "b"); !strings.Contains(report, "No tasks changed") {
+		t.Errorf("unexpected report:\n%s", report)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package cli
+
+import "syscall"
+
+// freeDiskBytes returns the space available to unprivileged users on the
+// filesystem containing path.
+func freeDiskBytes(path string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	return st.Bavail * uint64(st.Bsize), nil //nolint:gosec // Bsize is always positive.
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package cli
+
+import "errors"
+
+// freeDiskBytes is not implemented on Windows; disk space checks are skipped.
+func freeDiskBytes(_ string) (uint64, error) {
+	return 0, errors.New("disk space check not supported on windows")
+}
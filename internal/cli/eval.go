@@ -7,15 +7,19 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"math/rand/v2"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 	"unicode/utf8"
@@ -31,42 +35,76 @@ import (
 )
 
 var (
-	evalAgent string
-	evalModel string
-	// TODO(consistency): Consider passing evalReasoning explicitly through the call
-	// stack (runTaskWithAgent -> executeAgentWithRetries -> runAgentAttempt) to match
-	// the pattern used for model. Currently safe since it's read-only after CLI parse.
+	evalAgent         string
+	evalModel         string
+	evalAgentFallback string
+	// evalReasoning is the --reasoning flag; tasks read their run's reasoning
+	// from the run settings in their context (see runSettingsFrom).
 	evalReasoning       string
 	evalTasks           string
 	evalLang            string
+	evalSkipLangs       string
 	evalTier            string
 	evalDifficulty      string
 	evalTimeout         int
+	evalTimeoutExplicit bool // --timeout was passed, so language_timeouts cannot lower it
+	evalTimeoutGrace    int
+	evalSystemPrompt    string
+	evalAgentEnvFile    string
+	evalOnlyNew         string
+	evalChangedSince    string
+	evalSample          int
+	evalSampleUniform   bool
+	evalSampleSeed      int64
+	evalShard           string
+	evalMergingShards   bool // set by merge-shards: evalRunSingle only combines prior results
+	evalMinFreeDiskMB   int
+	evalAgentLogMax     int64
+	evalAgentRunaway    int64
 	evalOutputDir       string
 	evalKeepWorkspaces  bool
+	evalDebugWorkspaces bool
+	evalNotify          bool
+	evalNotifyCommand   string
+	evalProgress        bool
+	evalJSONLogs        bool
+	evalNoWarmup        bool
+	evalFailFast        bool
+	evalRetryFailed     bool
+	evalExportFormat    string
+	evalReportChart     bool
+	evalUnderstandCosts bool
 	evalParallel        int
+	evalRunParallel     int
 	evalDryRun          bool
+	evalOutputFormat    string
 	evalUseMCPTools     bool
+	evalMCPAblation     bool
+	evalAgentVersions   string
+	evalAgentCommand    string
 	evalUseSkills       bool
+	evalLint            bool
+	evalPromptBudget    int
+	evalPromptTemplate  string
+	evalReuseContainer  bool
+	evalRobustness      bool
 	evalDisableMCP      bool
 	evalNoSandbox       bool
+	evalNoNetwork       bool
 	evalLegacy          bool
 	evalSandboxActive   bool
 	evalSandboxDenylist []string
 	evalSandboxSharedRW []string
 	evalSandboxSharedRO []string
 	evalResume          string
+	evalVerifyOnly      string
+	evalBaselines       []string
 	evalRepeat          int
 )
 
-// Quota retry configuration.
+// Quota retry configuration. [harness.retry] overrides the schedule.
 const (
-	quotaMaxRetries  = 5
-	quotaRetryDelay1 = 30 * time.Second
-	quotaRetryDelay2 = 60 * time.Second
-	quotaRetryDelay3 = 120 * time.Second
-	quotaRetryDelay4 = 240 * time.Second
-	quotaRetryDelay5 = 480 * time.Second
+	defaultQuotaMaxRetries = 5
 
 	// Threshold for considering an agent log as an infra failure (empty or near-empty).
 	infraFailureLogThreshold = 10 // bytes
@@ -77,15 +115,17 @@ const (
 	quotaExhaustedStopThreshold = 5
 )
 
+var defaultQuotaRetryDelays = []time.Duration{
+	30 * time.Second, 60 * time.Second, 120 * time.Second, 240 * time.Second, 480 * time.Second,
+}
+
 // Infra failure retry configuration (separate from quota retries).
-const (
-	infraMaxRetries  = 5
-	infraRetryDelay1 = 15 * time.Second
-	infraRetryDelay2 = 30 * time.Second
-	infraRetryDelay3 = 60 * time.Second
-	infraRetryDelay4 = 120 * time.Second
-	infraRetryDelay5 = 240 * time.Second
-)
+// [harness.retry] overrides the schedule.
+const defaultInfraMaxRetries = 5
+
+var defaultInfraRetryDelays = []time.Duration{
+	15 * time.Second, 30 * time.Second, 60 * time.Second, 120 * time.Second, 240 * time.Second,
+}
 
 // Agent-timeout retry configuration. Plain agent timeouts (the agent produced
 // *some* output but then stalled for the whole wall-clock budget) are not
@@ -210,6 +250,17 @@ var outOfWorkspaceReadPatterns = []*regexp.Regexp{
 	regexp.MustCompile(`(?i)/sessions/`),
 }
 
+// escapeWritePrefix matches a redirect or a command that writes, copies or
+// removes files, up to the start of a path argument in the same command.
+const escapeWritePrefix = `(?i)(?:>>?|\b(?:tee|cp|mv|rm|touch|mkdir|ln|rsync|install|truncate|sed\s+-i)\b)[^|;&\n]*?`
+
+// escapeAttemptPatterns match commands that write to or copy out of the
+// harness's own results or session directories. These are tampering
+// signals, unlike the benign lookups counted by outOfWorkspaceReadPatterns.
+var escapeAttemptPatterns = []*regexp.Regexp{
+	regexp.MustCompile(escapeWritePrefix + `(?:^|[\s'"=/>])(?:eval-results|sessions)/`),
+}
+
 var toolchainSearchPatterns = []*regexp.Regexp{
 	regexp.MustCompile(`(?i)\b(?:find|locate|which|whereis)\b.*\b(?:dart|zig|rustc|cargo|go|node|npx|tsx|kotlin|kotlinc|gradle|gradlew|javac|flutter)\b`),
 	regexp.MustCompile(`(?i)\bfind\s+/(?:usr|opt|lib)\b`),
@@ -236,8 +287,14 @@ type agentBehaviorMetrics struct {
 	OutOfWorkspaceReads          int
 	OutOfWorkspaceReadsConfident bool
 	ToolchainSearchAttempts      int
+	EscapeAttempts               int
 	SkillsUsed                   bool
 	SkillsUsageSignals           int
+	SkillNames                   []string // Lowercased names of skills activated or read
+	ThinkingTime                 float64  // Seconds between log events without tool calls (needs timestamp_pattern)
+	ActingTime                   float64  // Seconds between log events that ran a tool
+	TotalTokens                  int      // Sum of token counts found in the log
+	TotalTokensConfident         bool     // A token count was found, so TotalTokens is not just unknown
 }
 
 // FailureClass categorizes the root cause of non-successful or degraded runs.
@@ -252,40 +309,104 @@ const (
 	FailureClassIntegrity         FailureClass = "integrity"
 	FailureClassValidationError   FailureClass = "validation_error"
 	FailureClassValidationTimeout FailureClass = "validation_timeout"
+	FailureClassRunaway           FailureClass = "runaway"
+	FailureClassValidationOOM     FailureClass = "validation_oom"
+	FailureClassRefusal           FailureClass = "refusal"
+	FailureClassAgentExit         FailureClass = "agent_exit"
+)
+
+// TimeoutOutcome describes what a timed-out agent left behind.
+type TimeoutOutcome string
+
+const (
+	TimeoutPartialPass TimeoutOutcome = "partial_pass" // partial work passed validation
+	TimeoutPartialFail TimeoutOutcome = "partial_fail" // partial work failed validation
+	TimeoutNoWork      TimeoutOutcome = "no_work"      // stub files left untouched
 )
 
 // EvalResult holds the result of evaluating a single task.
 type EvalResult struct {
-	Task                         string            `json:"task"`
-	Language                     string            `json:"language"`
-	Tier                         string            `json:"tier,omitempty"`
-	Difficulty                   string            `json:"difficulty,omitempty"`
-	Passed                       bool              `json:"passed"`
-	AgentTimedOut                bool              `json:"agent_timed_out"`
-	Status                       task.ResultStatus `json:"status"`
-	Attempts                     int               `json:"attempts"`
-	Duration                     float64           `json:"duration_seconds"`
-	AgentTime                    float64           `json:"agent_duration_seconds,omitempty"`
-	ValidateTime                 float64           `json:"validation_duration_seconds,omitempty"`
-	PromptChars                  int               `json:"prompt_chars,omitempty"`
-	Error                        string            `json:"error,omitempty"`
-	FailureClass                 FailureClass      `json:"failure_class"`
-	Weight                       float64           `json:"weight,omitempty"`
-	WeightedScore                float64           `json:"weighted_score,omitempty"`
-	QuotaRetries                 int               `json:"quota_retries"`
-	InfraRetries                 int               `json:"infra_retries"`
-	AgentTimeoutRetries          int               `json:"agent_timeout_retries,omitempty"`
-	QuotaExhausted               bool              `json:"quota_exhausted"`
-	InfraFailure                 bool              `json:"infra_failure"`
-	SelfTestCommands             int               `json:"self_test_commands"`
-	SelfTestCommandsConfident    bool              `json:"self_test_commands_confident"`
-	ToolchainInstallAttempts     int               `json:"toolchain_install_attempts"`
-	OutOfWorkspaceReadAttempts   int               `json:"out_of_workspace_read_attempts"`
-	OutOfWorkspaceReadsConfident bool              `json:"out_of_workspace_read_attempts_confident"`
-	ToolchainSearchAttempts      int               `json:"toolchain_search_attempts"`
-	SkillsUsed                   bool              `json:"skills_used"`
-	SkillsUsageSignals           int               `json:"skills_usage_signals"`
-	WorkspaceDir                 string            `json:"-"` // Not serialized, used for cleanup
+	Task                         string                `json:"task"`
+	Language                     string                `json:"language"`
+	Tier                         string                `json:"tier,omitempty"`
+	Difficulty                   string                `json:"difficulty,omitempty"`
+	Passed                       bool                  `json:"passed"`
+	AgentTimedOut                bool                  `json:"agent_timed_out"`
+	AgentRunaway                 bool                  `json:"agent_runaway,omitempty"`
+	AgentRefused                 bool                  `json:"agent_refused,omitempty"`
+	AgentLogBytes                int64                 `json:"agent_log_bytes,omitempty"`
+	AgentLogTruncated            bool                  `json:"agent_log_truncated,omitempty"`
+	TimeoutOutcome               TimeoutOutcome        `json:"timeout_outcome,omitempty"`
+	Status                       task.ResultStatus     `json:"status"`
+	Attempts                     int                   `json:"attempts"`
+	Duration                     float64               `json:"duration_seconds"`
+	AgentTime                    float64               `json:"agent_duration_seconds,omitempty"`
+	AgentTimeout                 int                   `json:"agent_timeout_seconds,omitempty"`
+	AgentCommand                 string                `json:"agent_shell_command,omitempty"`
+	ValidateTime                 float64               `json:"validation_duration_seconds,omitempty"`
+	ThinkingTime                 float64               `json:"agent_thinking_seconds,omitempty"`
+	ActingTime                   float64               `json:"agent_acting_seconds,omitempty"`
+	TotalTokens                  int                   `json:"total_tokens,omitempty"`
+	TotalTokensConfident         bool                  `json:"total_tokens_confident,omitempty"`
+	ValidationCommand            []string              `json:"validation_command,omitempty"`
+	Tests                        *resultpkg.TestCounts `json:"tests,omitempty"` // parsed by [languages.<lang>.parse] patterns
+	PhaseTimes                   map[string]float64    `json:"phase_seconds,omitempty"`
+	PromptChars                  int                   `json:"prompt_chars,omitempty"`
+	Error                        string                `json:"error,omitempty"`
+	FailureClass                 FailureClass          `json:"failure_class"`
+	FailurePhase                 runner.Phase          `json:"failure_phase,omitempty"`
+	Weight                       float64               `json:"weight,omitempty"`
+	WeightedScore                float64               `json:"weighted_score,omitempty"`
+	QuotaRetries                 int                   `json:"quota_retries"`
+	InfraRetries                 int                   `json:"infra_retries"`
+	AgentTimeoutRetries          int                   `json:"agent_timeout_retries,omitempty"`
+	RetryReasons                 map[string]int        `json:"retry_reasons,omitempty"`
+	QuotaExhausted               bool                  `json:"quota_exhausted"`
+	InfraFailure                 bool                  `json:"infra_failure"`
+	SelfTestCommands             int                   `json:"self_test_commands"`
+	SelfTestCommandsConfident    bool                  `json:"self_test_commands_confident"`
+	ToolchainInstallAttempts     int                   `json:"toolchain_install_attempts"`
+	OutOfWorkspaceReadAttempts   int                   `json:"out_of_workspace_read_attempts"`
+	OutOfWorkspaceReadsConfident bool                  `json:"out_of_workspace_read_attempts_confident"`
+	ToolchainSearchAttempts      int                   `json:"toolchain_search_attempts"`
+	EscapeAttempts               int                   `json:"escape_attempts"`
+	SkillsUsed                   bool                  `json:"skills_used"`
+	SkillsUsageSignals           int                   `json:"skills_usage_signals"`
+	RelevantSkill                string                `json:"relevant_skill,omitempty"`
+	RelevantSkillUsed            bool                  `json:"relevant_skill_used,omitempty"`
+	Agent                        string                `json:"agent,omitempty"`
+	FallbackFrom                 []string              `json:"fallback_from,omitempty"`
+	Variants                     []VariantResult       `json:"variants,omitempty"`
+	Linted                       bool                  `json:"linted,omitempty"`
+	LintWarnings                 int                   `json:"lint_warnings,omitempty"`
+	LintError                    string                `json:"lint_error,omitempty"`
+	PromptTrimmed                bool                  `json:"prompt_trimmed,omitempty"`
+	RobustnessChecked            bool                  `json:"robustness_checked,omitempty"`
+	RobustnessPassed             bool                  `json:"robustness_passed,omitempty"`
+	RobustnessError              string                `json:"robustness_error,omitempty"`
+	ExpectedFail                 bool                  `json:"expected_fail,omitempty"`
+	stubsUntouched               bool                  // set for timed-out agents that did not edit any stub
+	WorkspaceDir                 string                `json:"-"` // Not serialized, used for cleanup
+
+	// Coverage is the coverage percentage reported by validation for tasks
+	// with coverage = true, nil when none was reported.
+	Coverage *float64 `json:"coverage_percent,omitempty"`
+
+	// ValidationAttempts records each attempt when validation ran more than
+	// once, so retried passes and failures stay visible.
+	ValidationAttempts []ValidationAttempt `json:"validation_attempts,omitempty"`
+}
+
+// VariantResult holds the validation outcome of one parameter set of a
+// parameterized task. The task passes only when every variant passes.
+type VariantResult struct {
+	Name         string            `json:"name"`
+	Params       map[string]string `json:"params,omitempty"`
+	Passed       bool              `json:"passed"`
+	Duration     float64           `json:"duration_seconds"`
+	Command      []string          `json:"command,omitempty"`
+	Error        string            `json:"error,omitempty"`
+	FailureClass FailureClass      `json:"failure_class,omitempty"`
 }
 
 // EvalAggregate summarizes results for a group (language, tier, difficulty).
@@ -301,12 +422,14 @@ type EvalAggregate struct {
 
 // ExternalFailure captures a task skipped from scoring due to external issues.
 type ExternalFailure struct {
-	Task          string       `json:"task"`
-	FailureClass  FailureClass `json:"failure_class"`
-	Error         string       `json:"error,omitempty"`
-	QuotaRetries  int          `json:"quota_retries"`
-	InfraRetries  int          `json:"infra_retries"`
-	AgentTimedOut bool         `json:"agent_timed_out"`
+	Task          string         `json:"task"`
+	FailureClass  FailureClass   `json:"failure_class"`
+	FailurePhase  runner.Phase   `json:"failure_phase,omitempty"`
+	Error         string         `json:"error,omitempty"`
+	QuotaRetries  int            `json:"quota_retries"`
+	InfraRetries  int            `json:"infra_retries"`
+	RetryReasons  map[string]int `json:"retry_reasons,omitempty"`
+	AgentTimedOut bool           `json:"agent_timed_out"`
 }
 
 // EvalSummary holds the overall evaluation summary.
@@ -314,8 +437,11 @@ type EvalSummary struct {
 	Agent                           string                   `json:"agent"`
 	Model                           string                   `json:"model,omitempty"`
 	Reasoning                       string                   `json:"reasoning,omitempty"`
+	RunLabel                        string                   `json:"run_label,omitempty"`
+	FailFastTask                    string                   `json:"fail_fast_task,omitempty"` // Task that stopped a --fail-fast run
 	Timestamp                       string                   `json:"timestamp"`
 	Tier                            string                   `json:"tier,omitempty"`
+	Shard                           string                   `json:"shard,omitempty"`
 	Difficulty                      string                   `json:"difficulty,omitempty"`
 	Timeout                         int                      `json:"timeout"`
 	Parallel                        int                      `json:"parallel"`
@@ -328,10 +454,32 @@ type EvalSummary struct {
 	WeightedScore                   float64                  `json:"weighted_score,omitempty"`
 	MaxPossibleScore                float64                  `json:"max_possible_score,omitempty"`
 	WeightedPassRate                float64                  `json:"weighted_pass_rate,omitempty"`
+	FullSuiteMaxScore               float64                  `json:"full_suite_max_score,omitempty"`
+	NormalizedWeightedPassRate      float64                  `json:"normalized_weighted_pass_rate,omitempty"`
 	IntegrityViolations             int                      `json:"integrity_violations,omitempty"`
+	AgentFallback                   []string                 `json:"agent_fallback,omitempty"`
+	FallbackTasks                   int                      `json:"fallback_tasks,omitempty"`
+	SkipLangs                       []string                 `json:"skip_langs,omitempty"`
+	SkippedLanguageTasks            int                      `json:"skipped_language_tasks,omitempty"`
+	Lint                            bool                     `json:"lint,omitempty"`
+	LintedTasks                     int                      `json:"linted_tasks,omitempty"`
+	TotalLintWarnings               int                      `json:"total_lint_warnings,omitempty"`
+	Robustness                      bool                     `json:"robustness,omitempty"`
+	RobustnessChecked               int                      `json:"robustness_checked,omitempty"`
+	RobustnessPassed                int                      `json:"robustness_passed,omitempty"`
+	PromptBudgetTokens              int                      `json:"prompt_budget_tokens,omitempty"`
+	PromptTrimmedTasks              int                      `json:"prompt_trimmed_tasks,omitempty"`
+	ExpectedFailures                int                      `json:"expected_failures,omitempty"`
+	UnexpectedPasses                []string                 `json:"unexpected_passes,omitempty"`
+	Baselines                       []BaselineScore          `json:"baselines,omitempty"`
 	Duration                        float64                  `json:"duration_seconds,omitempty"`
 	AgentTime                       float64                  `json:"agent_duration_seconds,omitempty"`
 	ValidateTime                    float64                  `json:"validation_duration_seconds,omitempty"`
+	ThinkingTime                    float64                  `json:"agent_thinking_seconds,omitempty"`
+	ActingTime                      float64                  `json:"agent_acting_seconds,omitempty"`
+	TasksWithTimeline               int                      `json:"tasks_with_timeline,omitempty"`
+	TotalTokens                     int                      `json:"total_tokens,omitempty"`
+	TasksWithTokenUsage             int                      `json:"tasks_with_token_usage,omitempty"`
 	PromptChars                     int                      `json:"prompt_chars,omitempty"`
 	ByLanguage                      map[string]EvalAggregate `json:"by_language,omitempty"`
 	ByTier                          map[string]EvalAggregate `json:"by_tier,omitempty"`
@@ -341,6 +489,8 @@ type EvalSummary struct {
 	UseSkills                       bool                     `json:"use_skills"`
 	DisableMCP                      bool                     `json:"disable_mcp"`
 	Sandbox                         bool                     `json:"sandbox"`
+	SandboxNoNetwork                bool                     `json:"sandbox_no_network,omitempty"`
+	ReuseContainer                  bool                     `json:"reuse_container,omitempty"`
 	Legacy                          bool                     `json:"legacy"`
 	QuotaAffectedTasks              int                      `json:"quota_affected_tasks"`
 	AuthAffectedTasks               int                      `json:"auth_affected_tasks"`
@@ -348,8 +498,10 @@ type EvalSummary struct {
 	TotalQuotaRetries               int                      `json:"total_quota_retries"`
 	TotalInfraRetries               int                      `json:"total_infra_retries"`
 	TotalAgentTimeoutRetries        int                      `json:"total_agent_timeout_retries"`
+	RetryReasons                    map[string]int           `json:"retry_reasons,omitempty"`
 	AgentTimeoutTasks               int                      `json:"agent_timeout_tasks"`
 	AgentTimeoutRetriedTasks        int                      `json:"agent_timeout_retried_tasks"`
+	RefusalTasks                    int                      `json:"refusal_tasks,omitempty"`
 	TotalSelfTestCommands           int                      `json:"total_self_test_commands"`
 	TotalToolchainInstallAttempts   int                      `json:"total_toolchain_install_attempts"`
 	TotalOutOfWorkspaceReadAttempts int                      `json:"total_out_of_workspace_read_attempts"`
@@ -360,7 +512,11 @@ type EvalSummary struct {
 	TasksWithOutOfWorkspaceReads    int                      `json:"tasks_with_out_of_workspace_reads"`
 	TotalToolchainSearchAttempts    int                      `json:"total_toolchain_search_attempts"`
 	TasksWithToolchainSearch        int                      `json:"tasks_with_toolchain_search"`
+	TotalEscapeAttempts             int                      `json:"total_escape_attempts"`
+	TasksWithEscapeAttempts         int                      `json:"tasks_with_escape_attempts"`
 	TasksWithSkillsUsage            int                      `json:"tasks_with_skills_usage"`
+	TasksWithRelevantSkill          int                      `json:"tasks_with_relevant_skill,omitempty"`
+	TasksUsingRelevantSkill         int                      `json:"tasks_using_relevant_skill,omitempty"`
 }
 
 // RunSpec defines a single eval run's configuration.
@@ -368,23 +524,55 @@ type RunSpec struct {
 	Agent     string `json:"agent"`
 	Model     string `json:"model,omitempty"`
 	Reasoning string `json:"reasoning,omitempty"`
+
+	// Label names the run in comparisons when agent and model alone do not,
+	// and MCPTools overrides --use-mcp-tools for it (--mcp-ablation).
+	Label    string `json:"label,omitempty"`
+	MCPTools *bool  `json:"use_mcp_tools,omitempty"`
+	// Command overrides the agent's command for the run (--agent-versions).
+	Command string `json:"command,omitempty"`
 }
 
 // SharedConfig holds settings common to all runs.
 type SharedConfig struct {
+	AgentFallback  string
 	Tier           string
 	Difficulty     string
 	Lang           string
+	SkipLangs      string
 	Tasks          string
 	Timeout        int
+	TimeoutGrace   int
+	SystemPrompt   string
+	PromptTemplate string
 	Parallel       int
 	KeepWorkspaces bool
 	UseMCPTools    bool
 	UseSkills      bool
+	Lint           bool
+	Robustness     bool
+	PromptBudget   int
+	ReuseContainer bool
 	DisableMCP     bool
 	NoSandbox      bool
+	NoNetwork      bool
 	Legacy         bool
 	DryRun         bool
+
+	AgentRunawayBytes int64
+	// TimeoutExplicit is set when Timeout was given on the command line or
+	// in a batch file rather than taken from [harness] default_timeout.
+	TimeoutExplicit bool
+	// AgentLogMaxBytes is nil in sessions saved before it was recorded,
+	// which keep the current default rather than an unlimited log.
+	AgentLogMaxBytes *int64
+
+	// TaskList holds the IDs of the tasks selected once --sample, --shard
+	// and the other filters were applied, so a resumed or topped-up
+	// multi-run runs the same tasks.
+	TaskList []string
+
+	SkippedLangTasks int // Tasks dropped by SkipLangs, for the summary
 }
 
 // RunConfig stores the original eval configuration for resume capability.
@@ -392,20 +580,48 @@ type RunConfig struct {
 	Agent          string   `json:"agent"`
 	Model          string   `json:"model,omitempty"`
 	Reasoning      string   `json:"reasoning,omitempty"`
+	AgentFallback  string   `json:"agent_fallback,omitempty"`
 	Tier           string   `json:"tier,omitempty"`
 	Difficulty     string   `json:"difficulty,omitempty"`
 	Lang           string   `json:"lang,omitempty"`
+	SkipLangs      string   `json:"skip_langs,omitempty"`
 	Tasks          string   `json:"tasks,omitempty"`
 	Timeout        int      `json:"timeout"`
+	TimeoutGrace   int      `json:"timeout_grace,omitempty"`
+	SystemPrompt   string   `json:"system_prompt,omitempty"`
+	PromptTemplate string   `json:"prompt_template,omitempty"`
+	AgentEnvFile   string   `json:"agent_env_file,omitempty"`
 	Parallel       int      `json:"parallel"`
 	UseMCPTools    bool     `json:"use_mcp_tools"`
 	UseSkills      bool     `json:"use_skills"`
+	Lint           bool     `json:"lint,omitempty"`
+	Robustness     bool     `json:"robustness,omitempty"`
+	PromptBudget   int      `json:"prompt_budget_tokens,omitempty"`
+	ReuseContainer bool     `json:"reuse_container,omitempty"`
 	DisableMCP     bool     `json:"disable_mcp"`
 	NoSandbox      bool     `json:"no_sandbox"`
+	NoNetwork      bool     `json:"no_network,omitempty"`
 	Legacy         bool     `json:"legacy"`
 	KeepWorkspaces bool     `json:"keep_workspaces"`
+	Sample         int      `json:"sample,omitempty"`
+	SampleUniform  bool     `json:"sample_uniform,omitempty"`
+	SampleSeed     int64    `json:"sample_seed,omitempty"`
+	Shard          string   `json:"shard,omitempty"`
+	MinFreeDiskMB  int      `json:"min_free_disk_mb,omitempty"`
+	Baselines      []string `json:"baselines,omitempty"`
+	ExportFormat   string   `json:"export_format,omitempty"`
+	ReportChart    bool     `json:"report_chart,omitempty"`
+	FailFast       bool     `json:"fail_fast,omitempty"`
 	TaskList       []string `json:"task_list"`
 	CreatedAt      string   `json:"created_at"`
+
+	AgentRunawayBytes int64 `json:"agent_runaway_bytes,omitempty"`
+	// TimeoutExplicit is set when Timeout came from --timeout, so a
+	// [harness.language_timeouts] entry cannot lower it on resume.
+	TimeoutExplicit bool `json:"timeout_explicit,omitempty"`
+	// AgentLogMaxBytes is nil in run configs saved before it was recorded,
+	// which keep the current default rather than an unlimited log.
+	AgentLogMaxBytes *int64 `json:"agent_log_max_bytes,omitempty"`
 }
 
 var evalCmd = &cobra.Command{
@@ -444,28 +660,49 @@ Examples:
   sanity eval --agent claude --lang go
   sanity eval --agent my-custom-agent --tasks bank-account,react
   sanity eval --agent gemini --dry-run
-  sanity eval --resume ./eval-results/2026-01-19T192910-gemini`,
+  sanity eval --resume ./eval-results/2026-01-19T192910-gemini
+  sanity eval --verify-only ./eval-results/2026-01-19T192910-gemini`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Apply config defaults for flags not explicitly set.
-		if !cmd.Flags().Changed("timeout") && evalTimeout == 0 {
+		evalTimeoutExplicit = cmd.Flags().Changed("timeout")
+		if !evalTimeoutExplicit && evalTimeout == 0 {
 			if cfg != nil && cfg.Harness.DefaultTimeout > 0 {
 				evalTimeout = cfg.Harness.DefaultTimeout
 			} else {
 				evalTimeout = 600
 			}
 		}
+		if !cmd.Flags().Changed("agent") && evalAgent == "" && cfg != nil {
+			evalAgent = cfg.Harness.DefaultAgent
+		}
+		if !cmd.Flags().Changed("system-prompt") && evalSystemPrompt == "" && cfg != nil {
+			evalSystemPrompt = cfg.Harness.SystemPrompt
+		}
+		if !cmd.Flags().Changed("skip-langs") && evalSkipLangs == "" && cfg != nil {
+			evalSkipLangs = strings.Join(cfg.Harness.SkipLangs, ",")
+		}
+		evalAgentLogMax = resolveAgentLogMax(cmd.Flags().Changed("agent-log-max-bytes"), evalAgentLogMax, cfg)
 
 		if evalRepeat < 1 {
 			evalRepeat = 1
 		}
+		if evalJSONLogs && evalProgress {
+			return fmt.Errorf("--json-logs and --progress are mutually exclusive")
+		}
+		if evalRunParallel > 1 && evalProgress {
+			return fmt.Errorf("--run-parallel and --progress are mutually exclusive")
+		}
 
-		shared := SharedConfig{
-			Tier: evalTier, Difficulty: evalDifficulty, Lang: evalLang,
-			Tasks: evalTasks, Timeout: evalTimeout, Parallel: evalParallel,
-			KeepWorkspaces: evalKeepWorkspaces, UseMCPTools: evalUseMCPTools,
-			UseSkills: evalUseSkills, DisableMCP: evalDisableMCP, NoSandbox: evalNoSandbox,
-			Legacy: evalLegacy, DryRun: evalDryRun,
+		// With --json-logs, stdout carries only NDJSON events; other output
+		// moves to stderr. This comes before the resume branch so resumed
+		// multi-runs stream events too.
+		if evalJSONLogs {
+			evalEvents = newEventLog(os.Stdout)
+			defer func() { evalEvents = nil }()
 		}
+		out := evalOutput()
+
+		shared := sharedConfigFromGlobals()
 
 		// Track if we're resuming a previous run.
 		var isResuming bool
@@ -475,12 +712,31 @@ Examples:
 		var runCfg *RunConfig
 		var timestamp string
 
+		if evalVerifyOnly != "" {
+			return runVerifyOnly(evalVerifyOnly)
+		}
+		for _, b := range evalBaselines {
+			if _, _, err := parseBaselineFlag(b); err != nil {
+				return err
+			}
+		}
+
 		// Handle resume mode: load config and apply settings.
 		var prevAttestation *EvalAttestation
+		if evalRetryFailed && evalResume == "" {
+			return fmt.Errorf("--retry-failed requires --resume")
+		}
 		if evalResume != "" {
 			// Check if this is a multi-run directory.
 			if isMultiRunDir(evalResume) {
-				return resumeMultiRun(evalResume)
+				if evalRetryFailed {
+					return fmt.Errorf("--retry-failed does not support multi-run directories; resume a run subdirectory instead")
+				}
+				repeat := 0
+				if cmd.Flags().Changed("repeat") {
+					repeat = evalRepeat
+				}
+				return resumeMultiRun(evalResume, repeat)
 			}
 
 			var err error
@@ -493,23 +749,25 @@ Examples:
 			isResuming = true
 
 			// Re-build shared from restored globals.
-			shared = SharedConfig{
-				Tier: evalTier, Difficulty: evalDifficulty, Lang: evalLang,
-				Tasks: evalTasks, Timeout: evalTimeout, Parallel: evalParallel,
-				KeepWorkspaces: evalKeepWorkspaces, UseMCPTools: evalUseMCPTools,
-				UseSkills: evalUseSkills, DisableMCP: evalDisableMCP, NoSandbox: evalNoSandbox,
-				Legacy: evalLegacy, DryRun: evalDryRun,
-			}
+			shared = sharedConfigFromGlobals()
 
 			completedTasks, err = findCompletedTasks(evalOutputDir)
 			if err != nil {
 				return fmt.Errorf("finding completed tasks: %w", err)
 			}
 
-			prevSummary, err := loadPreviousSummary(evalOutputDir)
+			prevSummary, err := loadResumeSummary(evalOutputDir)
 			if err != nil {
 				return fmt.Errorf("loading previous results: %w", err)
 			}
+			if dropped := dropUnrecordedTasks(completedTasks, prevSummary); len(dropped) > 0 {
+				fmt.Fprintf(out, " Warning: %d completed task(s) have no recorded result and will be re-run: %v\n", len(dropped), dropped)
+			}
+			if evalRetryFailed {
+				if requeued := requeueFailedTasks(completedTasks, prevSummary); len(requeued) > 0 {
+					fmt.Fprintf(out, " Retrying %d failed task(s): %v\n", len(requeued), requeued)
+				}
+			}
 			if prevSummary != nil {
 				previousResults = prevSummary.Results
 				previousExternalFailures = prevSummary.ExternalFailures
@@ -547,21 +805,52 @@ Examples:
 				Agent: agents[i], Model: models[i], Reasoning: reasonings[i],
 			})
 		}
+		if evalMCPAblation {
+			if shared.UseMCPTools {
+				return fmt.Errorf("--mcp-ablation runs with and without --use-mcp-tools; do not pass both")
+			}
+			if specs, err = mcpAblationSpecs(specs); err != nil {
+				return err
+			}
+		}
+		if evalAgentVersions != "" {
+			if evalMCPAblation {
+				return fmt.Errorf("--agent-versions and --mcp-ablation are mutually exclusive")
+			}
+			if specs, err = agentVersionSpecs(specs, evalAgentVersions); err != nil {
+				return err
+			}
+		}
 		isMultiRun := len(specs) > 1 || evalRepeat > 1
 
+		fallbacks := parseAgentFallback(shared.AgentFallback)
+
 		// Dry-run mode doesn't require agent to be installed.
 		if !evalDryRun {
-			for _, spec := range specs {
+			for _, spec := range append(append([]RunSpec{}, specs...), fallbacks...) {
 				if spec.Agent == "" {
-					return fmt.Errorf("--agent is required (use --help to see available agents)")
+					return fmt.Errorf("--agent is required unless [harness] default_agent is set (use --help to see available agents)")
 				}
-				agentCfg := cfg.GetAgent(spec.Agent)
+				agentCfg := specAgentConfig(spec)
 				if agentCfg == nil {
 					available := strings.Join(cfg.ListAgents(), ", ")
 					return fmt.Errorf("unknown agent: %s (available: %s)", spec.Agent, available)
 				}
-				if _, err := exec.LookPath(agentCfg.Command); err != nil {
-					return fmt.Errorf("agent %q binary %q not found in PATH", spec.Agent, agentCfg.Command)
+				if _, err := exec.LookPath(agentCfg.Executable()); err != nil {
+					return fmt.Errorf("agent %q binary %q not found in PATH", spec.Agent, agentCfg.Executable())
+				}
+				if _, err := agentFileEnv(agentCfg); err != nil {
+					return fmt.Errorf("agent %q: %w", spec.Agent, err)
+				}
+				if shared.SystemPrompt != "" && (agentCfg.SystemPromptFlag == "" || agentCfg.ShellCommand != "") {
+					logger.Warn("agent has no system_prompt_flag; the system prompt is prepended to the task prompt instead", "agent", spec.Agent)
+				}
+			}
+
+			// A resumed run was confirmed when it started.
+			if !isResuming {
+				if err := checkDangerousAgents(append(append([]RunSpec{}, specs...), fallbacks...)); err != nil {
+					return err
 				}
 			}
 		}
@@ -576,6 +865,7 @@ Examples:
 			r.LegacyHiddenTests = true
 			logger.Info("legacy mode enabled: hidden tests exposed to agent (pre-v1.6.0 behavior)")
 		}
+		r.ReuseContainers = shared.ReuseContainer
 
 		// If the user specified another selector, default tier should not hide tasks.
 		tierChanged := cmd.Flags().Changed("tier")
@@ -590,6 +880,17 @@ Examples:
 		default:
 			return fmt.Errorf("invalid --tier %q (valid: core, extended, all)", shared.Tier)
 		}
+		if err := validateExportFormat(evalExportFormat); err != nil {
+			return err
+		}
+		if err := validateOutputFormat(evalOutputFormat); err != nil {
+			return err
+		}
+		// Notices go to stderr when stdout carries the JSON dry-run plan.
+		notices := out
+		if shared.DryRun && evalOutputFormat == "json" {
+			notices = os.Stderr
+		}
 
 		// Get tasks to run
 		allTasks, err := r.ListTasks()
@@ -619,6 +920,24 @@ Examples:
 			allTasks = selected
 		}
 
+		// Keep only tasks touched since a git ref. A resumed run keeps the
+		// selection recorded in its run config's task list.
+		if evalChangedSince != "" && !isResuming {
+			dir := tasksDir
+			if dir == "" {
+				dir = "tasks"
+			}
+			changed, err := changedTaskIDs(dir, evalChangedSince)
+			if err != nil {
+				return err
+			}
+			allTasks = filterChangedTasks(allTasks, changed)
+			if len(allTasks) == 0 {
+				return fmt.Errorf("no selected tasks changed since %s", evalChangedSince)
+			}
+			fmt.Fprintf(notices, " Selected %d task(s) changed since %s\n", len(allTasks), evalChangedSince)
+		}
+
 		// Filter by language if specified
 		if evalLang != "" {
 			lang, err := task.ParseLanguage(evalLang)
@@ -634,6 +953,18 @@ Examples:
 			allTasks = filtered
 		}
 
+		// Drop languages the user can't run (e.g. images not pulled).
+		if evalSkipLangs != "" {
+			skipped, err := parseSkipLangs(evalSkipLangs)
+			if err != nil {
+				return err
+			}
+			allTasks, shared.SkippedLangTasks = filterSkipLangs(allTasks, skipped)
+			if shared.SkippedLangTasks > 0 {
+				fmt.Fprintf(notices, " Skipped %d task(s) for unavailable languages (%s)\n", shared.SkippedLangTasks, evalSkipLangs)
+			}
+		}
+
 		// Filter by difficulty if specified
 		if evalDifficulty != "" {
 			want := make(map[string]bool)
@@ -664,56 +995,129 @@ Examples:
 			allTasks = filtered
 		}
 
+		// Skip tasks already submitted for this agent/model.
+		if evalOnlyNew != "" && !isResuming {
+			if len(specs) > 1 {
+				return fmt.Errorf("--only-new requires a single --agent")
+			}
+			submitted, err := loadSubmitted(evalOnlyNew)
+			if err != nil {
+				return err
+			}
+			selected := len(allTasks)
+			allTasks = filterUnsubmitted(allTasks, submitted, specs[0])
+			if len(allTasks) == 0 {
+				return fmt.Errorf("all %d selected tasks were already submitted (per %s)", selected, evalOnlyNew)
+			}
+			if skipped := selected - len(allTasks); skipped > 0 {
+				fmt.Fprintf(notices, " Skipping %d already-submitted task(s) (per %s)\n", skipped, evalOnlyNew)
+			}
+		}
+
 		if len(allTasks) == 0 {
 			return fmt.Errorf("no tasks match the specified filters")
 		}
 
+		// Sample a subset of the filtered tasks. A resumed run keeps the
+		// sample recorded in its run config's task list.
+		if evalSample < 0 {
+			return fmt.Errorf("--sample must be positive, got %d", evalSample)
+		}
+		if evalSample > 0 && !isResuming && evalSample < len(allTasks) {
+			if !cmd.Flags().Changed("sample-seed") {
+				evalSampleSeed = time.Now().UnixNano()
+			}
+			mode := "weighted"
+			if evalSampleUniform {
+				mode = "uniform"
+			}
+			fmt.Fprintf(notices, " Sampled %d of %d task(s) (%s, seed %d)\n", evalSample, len(allTasks), mode, evalSampleSeed)
+			allTasks = sampleTasks(allTasks, evalSample, evalSampleUniform, evalSampleSeed)
+		}
+
+		// Keep only this machine's shard. A resumed run keeps the shard
+		// recorded in its run config's task list.
+		if evalShard != "" && !isResuming {
+			shard, shards, err := parseShard(evalShard)
+			if err != nil {
+				return err
+			}
+			if evalSample > 0 && !cmd.Flags().Changed("sample-seed") {
+				return fmt.Errorf("--shard with --sample needs --sample-seed so every shard draws the same sample")
+			}
+			selected := len(allTasks)
+			allTasks = shardTasks(allTasks, shard, shards)
+			fmt.Fprintf(notices, " Shard %d/%d: %d of %d task(s)\n", shard, shards, len(allTasks), selected)
+			if len(allTasks) == 0 {
+				return fmt.Errorf("shard %s has no tasks", evalShard)
+			}
+		}
+
+		if _, err := loadPromptTemplate(shared.PromptTemplate); err != nil {
+			return err
+		}
+
 		// Dry-run mode: print what would be executed and exit
 		if shared.DryRun {
-			fmt.Println()
-			fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-			fmt.Println(" SANITY HARNESS - Dry Run")
-			fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-			fmt.Println()
+			plan := buildDryRunPlan(specs, allTasks, shared.Timeout, evalRepeat)
+			plan.EstimatedSeconds, plan.WorstCaseSeconds = estimateDryRunSeconds(plan, shared.Parallel, evalRunParallel)
+			if evalOutputFormat == "json" {
+				return writeDryRunPlanJSON(os.Stdout, plan)
+			}
+			fmt.Fprintln(out)
+			fmt.Fprintln(out, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+			fmt.Fprintln(out, " SANITY HARNESS - Dry Run")
+			fmt.Fprintln(out, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+			fmt.Fprintln(out)
 			for _, spec := range specs {
 				if spec.Agent != "" {
-					fmt.Printf(" Agent:      %s\n", spec.Agent)
+					fmt.Fprintf(out, " Agent:      %s\n", spec.Agent)
 				}
 				if spec.Model != "" {
-					fmt.Printf(" Model:      %s\n", spec.Model)
+					fmt.Fprintf(out, " Model:      %s\n", spec.Model)
 				}
 				if spec.Reasoning != "" {
-					fmt.Printf(" Reasoning:  %s\n", spec.Reasoning)
+					fmt.Fprintf(out, " Reasoning:  %s\n", spec.Reasoning)
+				}
+				if spec.Label != "" {
+					fmt.Fprintf(out, " Run:        %s\n", spec.Label)
 				}
 			}
+			if len(fallbacks) > 0 {
+				fmt.Fprintf(out, " Fallback:   %s\n", strings.Join(fallbackChainLabels(shared.AgentFallback), " → "))
+			}
+			if shared.SystemPrompt != "" {
+				fmt.Fprintf(out, " System:     %d chars\n", len(shared.SystemPrompt))
+			}
 			if shared.Tier != "" {
-				fmt.Printf(" Tier:       %s\n", shared.Tier)
+				fmt.Fprintf(out, " Tier:       %s\n", shared.Tier)
 			}
 			if shared.Difficulty != "" {
-				fmt.Printf(" Difficulty: %s\n", shared.Difficulty)
+				fmt.Fprintf(out, " Difficulty: %s\n", shared.Difficulty)
 			}
 			if evalRepeat > 1 {
-				fmt.Printf(" Repeat:     %d\n", evalRepeat)
+				fmt.Fprintf(out, " Repeat:     %d\n", evalRepeat)
 			}
-			fmt.Printf(" Tasks:      %d\n", len(allTasks))
-			fmt.Println()
-			fmt.Println(" Tasks that would be executed:")
-			fmt.Println("─────────────────────────────────────────────────────────────")
-			for i, t := range allTasks {
-				timeout := shared.Timeout
-				if t.AgentTimeout > 0 {
-					timeout = t.AgentTimeout
-				}
-				fmt.Printf(" %3d. %-35s [%s, %s, %ds]\n",
-					i+1, t.ID(), t.Tier, t.Difficulty, timeout)
+			fmt.Fprintf(out, " Tasks:      %d\n", len(allTasks))
+			fmt.Fprintf(out, " Estimated:  %s (optimistic: agent timeouts / --parallel)\n", time.Duration(plan.EstimatedSeconds)*time.Second)
+			fmt.Fprintf(out, " Worst case: %s (plus every quota/infra retry delay)\n", time.Duration(plan.WorstCaseSeconds)*time.Second)
+			fmt.Fprintln(out)
+			fmt.Fprintln(out, " Tasks that would be executed:")
+			fmt.Fprintln(out, "─────────────────────────────────────────────────────────────")
+			for i, t := range plan.Tasks {
+				fmt.Fprintf(out, " %3d. %-35s [%s, %s, %ds]\n",
+					i+1, t.ID, t.Tier, t.Difficulty, t.TimeoutSeconds)
 			}
-			fmt.Println("─────────────────────────────────────────────────────────────")
-			fmt.Println()
+			fmt.Fprintln(out, "─────────────────────────────────────────────────────────────")
+			fmt.Fprintln(out)
 			return nil
 		}
 
 		// Detect sandbox availability.
 		evalSandboxActive = initSandbox()
+		if err := checkSandboxNetwork(); err != nil {
+			return err
+		}
 		evalSandboxDenylist = resolveSandboxDenylistPaths(cfg.Sandbox.ReadableDenylist, evalOutputDir)
 		evalSandboxSharedRW = append([]string(nil), cfg.Sandbox.SharedReadWriteDirs...)
 		evalSandboxSharedRO = append([]string(nil), cfg.Sandbox.SharedReadOnlyDirs...)
@@ -736,7 +1140,9 @@ Examples:
 				umbrellaDir = evalOutputDir
 			} else if len(specs) == 1 {
 				// Single-agent repeat: use normal naming.
-				umbrellaDir = filepath.Join("eval-results", fmt.Sprintf("%s-%s", timestamp, specs[0].Agent))
+				if umbrellaDir, err = defaultOutputDir(specs[0], timestamp); err != nil {
+					return err
+				}
 			} else {
 				umbrellaDir = filepath.Join("eval-results", fmt.Sprintf("multi-%s", timestamp))
 			}
@@ -744,31 +1150,54 @@ Examples:
 				return fmt.Errorf("creating umbrella directory: %w", err)
 			}
 
+			for _, t := range allTasks {
+				shared.TaskList = append(shared.TaskList, t.ID())
+			}
 			writeMultiRunConfig(umbrellaDir, specs, shared, evalRepeat)
 
-			var allSummaries []runResult
+			var jobs []multiRunJob
 			for specIdx, spec := range specs {
 				for rep := 1; rep <= evalRepeat; rep++ {
-					if checkInterrupted(interruptCtx) {
-						updateMultiRunState(umbrellaDir, allSummaries, specs, evalRepeat, true)
-						printMultiRunResumeCommand(umbrellaDir)
-						return nil
-					}
-
-					runDir := multiRunSubdir(umbrellaDir, spec, specIdx, rep, evalRepeat)
-					summary, _, err := evalRunSingle(
-						interruptCtx, spec, shared, allTasks, allTasks,
-						runDir, timestamp, r, false, nil, nil, nil, nil, nil,
-					)
-					rr := runResult{spec: spec, repeat: rep, summary: summary}
-					if err != nil {
-						logger.Warn("run failed", "agent", spec.Agent, "repeat", rep, "error", err)
-						rr.err = err
+					jobs = append(jobs, multiRunJob{
+						specIdx: specIdx, spec: spec, repeat: rep,
+						dir: multiRunSubdir(umbrellaDir, spec, specIdx, rep, evalRepeat),
+					})
+				}
+			}
+			printRunParallelNotice(out, len(jobs), shared.Parallel)
+			setRunGlobals(shared)
+			tracker := newMultiRunTracker(umbrellaDir, specs, evalRepeat, nil)
+			interrupted := runMultiRunJobs(interruptCtx, jobs, evalRunParallel, tracker, func(job multiRunJob) runResult {
+				runR := r
+				if evalRunParallel > 1 {
+					var err error
+					if runR, err = newSharedRunner(shared); err != nil {
+						return runResult{spec: job.spec, specIdx: job.specIdx, repeat: job.repeat, err: err}
 					}
-					allSummaries = append(allSummaries, rr)
-					updateMultiRunState(umbrellaDir, allSummaries, specs, evalRepeat, false)
+					defer func() { _ = runR.Close() }()
 				}
+				summary, _, err := evalRunSingle(
+					interruptCtx, job.spec, shared, allTasks, allTasks,
+					job.dir, timestamp, runR, false, nil, nil, nil, nil, nil,
+				)
+				rr := runResult{
+					spec: job.spec, specIdx: job.specIdx, repeat: job.repeat, summary: summary,
+					interrupted: checkInterrupted(interruptCtx),
+				}
+				if err != nil {
+					logger.Warn("run failed", "agent", job.spec.Agent, "repeat", job.repeat, "error", err)
+					rr.err = err
+				}
+				return rr
+			})
+			if interrupted {
+				printMultiRunResumeCommand(out, umbrellaDir)
+				return nil
 			}
+			if stopped := stopMultiRunOnFailFast(umbrellaDir, tracker); stopped != nil {
+				return stopped
+			}
+			allSummaries := tracker.summaries()
 
 			// Generate comparison if multiple specs.
 			if len(specs) > 1 {
@@ -782,6 +1211,7 @@ Examples:
 					comparison := generateComparison(summaries)
 					writeComparisonJSON(umbrellaDir, comparison)
 					writeComparisonMarkdown(umbrellaDir, comparison)
+					writeComparisonCSV(umbrellaDir, comparison)
 				}
 			}
 
@@ -790,7 +1220,8 @@ Examples:
 				writeRepeatStats(umbrellaDir, specs, allSummaries, evalRepeat)
 			}
 
-			fmt.Printf("\n Multi-run results saved to: %s\n\n", umbrellaDir)
+			fmt.Fprintf(out, "\n Multi-run results saved to: %s\n\n", umbrellaDir)
+			notifyCompletion(umbrellaDir, meanPassRate(allSummaries))
 			return nil
 		}
 
@@ -799,18 +1230,83 @@ Examples:
 
 		// Create output directory.
 		if evalOutputDir == "" {
-			evalOutputDir = filepath.Join("eval-results", fmt.Sprintf("%s-%s", timestamp, spec.Agent))
+			if evalOutputDir, err = defaultOutputDir(spec, timestamp); err != nil {
+				return err
+			}
 		}
 
-		_, _, err = evalRunSingle(
+		setRunGlobals(shared)
+		summary, _, err := evalRunSingle(
 			interruptCtx, spec, shared, allTasks, allTasks,
 			evalOutputDir, timestamp, r, isResuming,
 			previousResults, previousExternalFailures, completedTasks, prevAttestation, runCfg,
 		)
-		return err
+		if summary != nil {
+			notifyCompletion(evalOutputDir, summary.PassRate)
+		}
+		if err != nil {
+			return err
+		}
+		return failFastError(summary)
 	},
 }
 
+// sharedConfigFromGlobals builds the SharedConfig for the current eval flag
+// globals, e.g. after applyRunConfig restored them from a run config.
+func sharedConfigFromGlobals() SharedConfig {
+	agentLogMax := evalAgentLogMax
+	return SharedConfig{
+		Tier: evalTier, Difficulty: evalDifficulty, Lang: evalLang, SkipLangs: evalSkipLangs,
+		Tasks: evalTasks, Timeout: evalTimeout, TimeoutExplicit: evalTimeoutExplicit, TimeoutGrace: evalTimeoutGrace, Parallel: evalParallel,
+		KeepWorkspaces: evalKeepWorkspaces, UseMCPTools: evalUseMCPTools,
+		UseSkills: evalUseSkills, DisableMCP: evalDisableMCP, NoSandbox: evalNoSandbox, NoNetwork: evalNoNetwork, Lint: evalLint,
+		Legacy: evalLegacy, DryRun: evalDryRun, AgentFallback: evalAgentFallback, SystemPrompt: evalSystemPrompt,
+		PromptTemplate: evalPromptTemplate, PromptBudget: evalPromptBudget, ReuseContainer: evalReuseContainer, Robustness: evalRobustness,
+		AgentRunawayBytes: evalAgentRunaway, AgentLogMaxBytes: &agentLogMax,
+	}
+}
+
+// printEvalHeader prints the banner that opens a single eval run.
+func printEvalHeader(w io.Writer, spec RunSpec, shared SharedConfig, outputDir string, isResuming bool, remaining, total int) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	switch {
+	case evalMergingShards:
+		fmt.Fprintln(w, " SANITY HARNESS - Agent Evaluation (MERGING SHARDS)")
+	case isResuming:
+		fmt.Fprintln(w, " SANITY HARNESS - Agent Evaluation (RESUMING)")
+	default:
+		fmt.Fprintln(w, " SANITY HARNESS - Agent Evaluation")
+	}
+	fmt.Fprintln(w, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, " Agent:   %s\n", spec.Agent)
+	if spec.Model != "" {
+		fmt.Fprintf(w, " Model:   %s\n", spec.Model)
+	}
+	if shared.Tier != "" {
+		fmt.Fprintf(w, " Tier:    %s\n", shared.Tier)
+	}
+	if shared.Difficulty != "" {
+		fmt.Fprintf(w, " Difficulty: %s\n", shared.Difficulty)
+	}
+	if shared.Parallel > 1 {
+		fmt.Fprintf(w, " Parallel: %d\n", shared.Parallel)
+	}
+	if evalSandboxActive && !sandboxAllowNetwork() {
+		fmt.Fprintln(w, " Sandbox: enabled (bwrap, no network)")
+	} else if evalSandboxActive {
+		fmt.Fprintln(w, " Sandbox: enabled (bwrap)")
+	}
+	if isResuming {
+		fmt.Fprintf(w, " Tasks:   %d remaining of %d total\n", remaining, total)
+	} else {
+		fmt.Fprintf(w, " Tasks:   %d\n", remaining)
+	}
+	fmt.Fprintf(w, " Output:  %s\n", outputDir)
+	fmt.Fprintln(w)
+}
+
 // evalRunSingle executes a single eval run for one agent/model/reasoning combination.
 // It handles output directory creation, task execution, aggregation, and output file writing.
 func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
@@ -829,15 +1325,37 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 	prevAttestation *EvalAttestation,
 	runCfg *RunConfig,
 ) (*EvalSummary, *EvalAttestation, error) {
-	// Set globals that sub-functions (runTaskWithAgent, runAgentAttempt, etc.) read.
-	evalAgent = spec.Agent
-	evalModel = spec.Model
-	evalReasoning = spec.Reasoning
-	evalUseMCPTools = shared.UseMCPTools
-	evalUseSkills = shared.UseSkills
-	evalDisableMCP = shared.DisableMCP
-	evalLegacy = shared.Legacy
-	evalKeepWorkspaces = shared.KeepWorkspaces
+	out := evalOutput()
+	if spec.MCPTools != nil {
+		shared.UseMCPTools = *spec.MCPTools
+	}
+
+	// The globals sub-functions (runTaskWithAgent, runAgentAttempt, etc.)
+	// read were set from shared by setRunGlobals before any run started;
+	// per-spec values go in the run settings below.
+	fallbacks := parseAgentFallback(shared.AgentFallback)
+
+	promptTmpl, err := loadPromptTemplate(shared.PromptTemplate)
+	if err != nil {
+		return nil, nil, err
+	}
+	var workDirRoot string
+	if evalDebugWorkspaces {
+		workDirRoot, err = os.MkdirTemp("", "sanity-eval-"+debugWorkspaceRunID(outputDir)+"-*")
+		if err != nil {
+			return nil, nil, fmt.Errorf("creating workspace root: %w", err)
+		}
+		defer func() { _ = os.RemoveAll(workDirRoot) }()
+	}
+	interruptCtx = withRunSettings(interruptCtx, runSettings{
+		agent:           spec.Agent,
+		command:         spec.Command,
+		reasoning:       spec.Reasoning,
+		useMCPTools:     shared.UseMCPTools,
+		promptTmpl:      promptTmpl,
+		workDirRoot:     workDirRoot,
+		timeoutExplicit: shared.TimeoutExplicit,
+	})
 
 	// Create output directory.
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
@@ -852,52 +1370,42 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 		if err != nil {
 			return nil, nil, err
 		}
-		if len(tasksToRun) == 0 {
-			fmt.Println("\n All tasks already completed. Nothing to resume.")
+		if evalMergingShards {
+			// Merging only combines the shards' results; tasks no shard
+			// finished are left for a later --resume.
+			tasksToRun = nil
+		} else if len(tasksToRun) == 0 {
+			fmt.Fprintln(out, "\n All tasks already completed. Nothing to resume.")
 			return nil, nil, nil
 		}
 	} else {
 		// Save run config for new runs (enables resume).
-		if err := saveRunConfig(outputDir, allTasks); err != nil {
+		if err := saveRunConfig(outputDir, spec, shared.UseMCPTools, allTasks); err != nil {
 			return nil, nil, fmt.Errorf("saving run config: %w", err)
 		}
 	}
 
+	if err := checkDiskSpace(outputDir); err != nil {
+		return nil, nil, err
+	}
+
 	var wasInterrupted bool
+	var stoppedByFailFast string // task that stopped a --fail-fast run
 
 	// Print header
-	fmt.Println()
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	if isResuming {
-		fmt.Println(" SANITY HARNESS - Agent Evaluation (RESUMING)")
-	} else {
-		fmt.Println(" SANITY HARNESS - Agent Evaluation")
-	}
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println()
-	fmt.Printf(" Agent:   %s\n", spec.Agent)
-	if spec.Model != "" {
-		fmt.Printf(" Model:   %s\n", spec.Model)
-	}
-	if shared.Tier != "" {
-		fmt.Printf(" Tier:    %s\n", shared.Tier)
-	}
-	if shared.Difficulty != "" {
-		fmt.Printf(" Difficulty: %s\n", shared.Difficulty)
-	}
-	if shared.Parallel > 1 {
-		fmt.Printf(" Parallel: %d\n", shared.Parallel)
+	if !evalJSONLogs {
+		printEvalHeader(out, spec, shared, outputDir, isResuming, len(tasksToRun), totalTaskCount)
 	}
-	if evalSandboxActive {
-		fmt.Println(" Sandbox: enabled (bwrap)")
+
+	if !evalNoWarmup {
+		warmupImages(interruptCtx, r, tasksToRun)
 	}
-	if isResuming {
-		fmt.Printf(" Tasks:   %d remaining of %d total\n", len(tasksToRun), totalTaskCount)
+	var agentVersions map[string]string
+	if evalMergingShards && prevAttestation != nil {
+		agentVersions = prevAttestation.Eval.AgentVersions
 	} else {
-		fmt.Printf(" Tasks:   %d\n", len(tasksToRun))
+		agentVersions = detectAgentVersions(interruptCtx, append([]RunSpec{spec}, fallbacks...))
 	}
-	fmt.Printf(" Output:  %s\n", outputDir)
-	fmt.Println()
 
 	// Run tasks
 	results := make([]EvalResult, 0, len(tasksToRun))
@@ -925,9 +1433,11 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 		externalFailures = append(externalFailures, ExternalFailure{
 			Task:          r.Task,
 			FailureClass:  r.FailureClass,
+			FailurePhase:  r.FailurePhase,
 			Error:         r.Error,
 			QuotaRetries:  r.QuotaRetries,
 			InfraRetries:  r.InfraRetries,
+			RetryReasons:  r.RetryReasons,
 			AgentTimedOut: r.AgentTimedOut,
 		})
 	}
@@ -937,53 +1447,104 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 		parallel = 1
 	}
 
+	// With --progress, a single status line replaces the per-task banners
+	// and pass lines; failures and skips are still printed above it.
+	var progress *progressLine
+	if evalProgress {
+		progress = newProgressLine(out, len(tasksToRun))
+	}
+
+	infraStop := infraStopThreshold()
 	if parallel == 1 { //nolint:nestif // Sequential execution loop with deeply interleaved interrupt/quota/progress handling.
 		consecutiveQuotaExhausted := 0
+		consecutiveInfraFailures := 0
 		for i, t := range tasksToRun {
 			// Check for interrupt before starting next task.
 			if checkInterrupted(interruptCtx) {
 				wasInterrupted = true
-				fmt.Println("\n\033[33m⚠ Interrupt received. Saving partial results...\033[0m")
+				fmt.Fprintln(out, "\n\033[33m⚠ Interrupt received. Saving partial results...\033[0m")
+				break
+			}
+			if err := checkDiskSpace(outputDir); err != nil {
+				wasInterrupted = true
+				fmt.Fprintf(out, "\n\033[33m⚠ %v. Saving partial results...\033[0m\n", err)
 				break
 			}
 
-			fmt.Println("─────────────────────────────────────────────────────────────")
-			fmt.Printf(" [%d/%d] %s\n", i+1, len(tasksToRun), t.ID())
-			fmt.Println("─────────────────────────────────────────────────────────────")
+			if progress != nil {
+				progress.startTask(t.ID())
+			} else if !evalJSONLogs {
+				fmt.Fprintln(out, "─────────────────────────────────────────────────────────────")
+				fmt.Fprintf(out, " [%d/%d] %s\n", i+1, len(tasksToRun), t.ID())
+				fmt.Fprintln(out, "─────────────────────────────────────────────────────────────")
+			}
 
-			result := runTaskWithAgent(interruptCtx, r, t, spec.Agent, spec.Model, outputDir, shared.Timeout)
+			result := runTaskWithFallback(interruptCtx, r, t, spec, fallbacks, outputDir, shared.Timeout)
 
 			// External failures are excluded from results so they can be resumed later.
 			if isResumableExternalFailure(result) {
 				recordExternalFailure(result)
-				fmt.Printf(" ⚠ %s — will be skipped (resumable)\n", externalFailureLabel(result.FailureClass))
+				if progress != nil {
+					progress.printf(" ⚠ %s %s — will be skipped (resumable)\n", t.ID(), externalFailureLabel(result.FailureClass))
+					progress.record(false, true)
+				} else if !evalJSONLogs {
+					fmt.Fprintf(out, " ⚠ %s — will be skipped (resumable)\n", externalFailureLabel(result.FailureClass))
+				}
 				resumableFailedTasks = append(resumableFailedTasks, fmt.Sprintf("%s [%s]", t.ID(), result.FailureClass))
 				removeTaskArtifactsForResume(outputDir, result)
 				if result.FailureClass == FailureClassQuotaExhausted {
 					consecutiveQuotaExhausted++
 					if consecutiveQuotaExhausted >= quotaExhaustedStopThreshold {
 						wasInterrupted = true
-						fmt.Printf("\n\033[33m⚠ Quota exhausted for %d consecutive tasks. Stopping early to allow resume.\033[0m\n", consecutiveQuotaExhausted)
+						fmt.Fprintf(out, "\n\033[33m⚠ Quota exhausted for %d consecutive tasks. Stopping early to allow resume.\033[0m\n", consecutiveQuotaExhausted)
 						break
 					}
 				} else {
 					consecutiveQuotaExhausted = 0
 				}
-				fmt.Println()
+				if result.FailureClass == FailureClassInfra {
+					consecutiveInfraFailures++
+					if infraStop > 0 && consecutiveInfraFailures >= infraStop {
+						wasInterrupted = true
+						fmt.Fprintf(out, "\n\033[33m⚠ Infra failures for %d consecutive tasks. Stopping early to allow resume.\033[0m\n", consecutiveInfraFailures)
+						break
+					}
+				} else {
+					consecutiveInfraFailures = 0
+				}
+				if progress == nil && !evalJSONLogs {
+					fmt.Fprintln(out)
+				}
 				continue
 			}
 
 			results = append(results, result)
+			consecutiveInfraFailures = 0
+
+			switch {
+			case evalJSONLogs:
+				// Reported by the task_result event.
+			case progress != nil && result.Passed:
+				progress.record(true, false)
+			case progress != nil:
+				progress.printf(" ✗ %s FAILED (%.2fs)%s\n", t.ID(), result.Duration, expectationNote(result))
+				if result.Error != "" {
+					progress.printf("   Error: %s\n", result.Error)
+				}
+				progress.record(false, false)
+			case result.Passed:
+				fmt.Fprintf(out, " ✓ PASSED (%.2fs)%s\n", result.Duration, expectationNote(result))
+			default:
+				fmt.Fprintf(out, " ✗ FAILED (%.2fs)%s\n", result.Duration, expectationNote(result))
+				if result.Error != "" {
+					fmt.Fprintf(out, "   Error: %s\n", result.Error)
+				}
+			}
 
 			if result.Passed {
-				fmt.Printf(" ✓ PASSED (%.2fs)\n", result.Duration)
 				passed++
 				consecutiveQuotaExhausted = 0 // Reset counter on success
 			} else {
-				fmt.Printf(" ✗ FAILED (%.2fs)\n", result.Duration)
-				if result.Error != "" {
-					fmt.Printf("   Error: %s\n", result.Error)
-				}
 				failed++
 
 				// Track consecutive quota exhaustion
@@ -991,7 +1552,7 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 					consecutiveQuotaExhausted++
 					if consecutiveQuotaExhausted >= quotaExhaustedStopThreshold {
 						wasInterrupted = true
-						fmt.Printf("\n\033[33m⚠ Quota exhausted for %d consecutive tasks. Stopping early to allow resume.\033[0m\n", consecutiveQuotaExhausted)
+						fmt.Fprintf(out, "\n\033[33m⚠ Quota exhausted for %d consecutive tasks. Stopping early to allow resume.\033[0m\n", consecutiveQuotaExhausted)
 						break
 					}
 				} else {
@@ -1006,7 +1567,19 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 				cleanupWorkspaceFiles(result.WorkspaceDir)
 			}
 
-			fmt.Println()
+			if progress == nil && !evalJSONLogs {
+				fmt.Fprintln(out)
+			}
+
+			if evalFailFast && isFailFastTrigger(result) {
+				wasInterrupted = true
+				stoppedByFailFast = t.ID()
+				if progress != nil {
+					progress.finish()
+				}
+				fmt.Fprintf(out, "\n\033[33m⚠ --fail-fast: %s failed. Stopping...\033[0m\n", t.ID())
+				break
+			}
 		}
 	} else {
 		type job struct {
@@ -1028,7 +1601,7 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 			go func() {
 				defer wg.Done()
 				for j := range jobs {
-					res := runTaskWithAgent(interruptCtx, r, j.t, spec.Agent, spec.Model, outputDir, shared.Timeout)
+					res := runTaskWithFallback(interruptCtx, r, j.t, spec, fallbacks, outputDir, shared.Timeout)
 					jobResults <- jobResult{idx: j.idx, r: res}
 				}
 			}()
@@ -1055,6 +1628,8 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 		collected := make([]EvalResult, len(tasksToRun))
 		seen := 0
 		consecutiveQuotaExhausted := 0
+		consecutiveInfraFailures := 0
+		failFastTask := ""
 	collectLoop:
 		for jr := range jobResults {
 			seen++
@@ -1062,7 +1637,12 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 			// External failures are excluded from results so they can be resumed later.
 			if isResumableExternalFailure(jr.r) {
 				recordExternalFailure(jr.r)
-				fmt.Printf(" [%d/%d] %s ⚠ %s — will be skipped (resumable)\n", seen, len(tasksToRun), jr.r.Task, externalFailureLabel(jr.r.FailureClass))
+				if progress != nil {
+					progress.printf(" ⚠ %s %s — will be skipped (resumable)\n", jr.r.Task, externalFailureLabel(jr.r.FailureClass))
+					progress.record(false, true)
+				} else if !evalJSONLogs {
+					fmt.Fprintf(out, " [%d/%d] %s ⚠ %s — will be skipped (resumable)\n", seen, len(tasksToRun), jr.r.Task, externalFailureLabel(jr.r.FailureClass))
+				}
 				resumableFailedTasks = append(resumableFailedTasks, fmt.Sprintf("%s [%s]", jr.r.Task, jr.r.FailureClass))
 				removeTaskArtifactsForResume(outputDir, jr.r)
 				if jr.r.FailureClass == FailureClassQuotaExhausted {
@@ -1070,16 +1650,32 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 				} else {
 					consecutiveQuotaExhausted = 0
 				}
+				if jr.r.FailureClass == FailureClassInfra {
+					consecutiveInfraFailures++
+				} else {
+					consecutiveInfraFailures = 0
+				}
 			} else {
 				collected[jr.idx] = jr.r
+				consecutiveInfraFailures = 0
 
 				status := "FAILED"
 				if jr.r.Passed {
 					status = "PASSED"
 				}
-				fmt.Printf(" [%d/%d] %s %s (%.2fs)\n", seen, len(tasksToRun), jr.r.Task, status, jr.r.Duration)
-				if !jr.r.Passed && jr.r.Error != "" {
-					fmt.Printf("   Error: %s\n", jr.r.Error)
+				if progress != nil {
+					if !jr.r.Passed {
+						progress.printf(" ✗ %s FAILED (%.2fs)%s\n", jr.r.Task, jr.r.Duration, expectationNote(jr.r))
+						if jr.r.Error != "" {
+							progress.printf("   Error: %s\n", jr.r.Error)
+						}
+					}
+					progress.record(jr.r.Passed, false)
+				} else if !evalJSONLogs {
+					fmt.Fprintf(out, " [%d/%d] %s %s (%.2fs)%s\n", seen, len(tasksToRun), jr.r.Task, status, jr.r.Duration, expectationNote(jr.r))
+					if !jr.r.Passed && jr.r.Error != "" {
+						fmt.Fprintf(out, "   Error: %s\n", jr.r.Error)
+					}
 				}
 
 				if jr.r.Passed {
@@ -1098,21 +1694,48 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 				if !shared.KeepWorkspaces && jr.r.WorkspaceDir != "" {
 					cleanupWorkspaceFiles(jr.r.WorkspaceDir)
 				}
+				if evalFailFast && failFastTask == "" && isFailFastTrigger(jr.r) {
+					failFastTask = jr.r.Task
+				}
 			}
 
 			// Check for interrupt after each result.
 			shouldStop := checkInterrupted(interruptCtx)
 			stopReason := "Interrupt received"
 
+			// Stop before the disk fills up and writes start failing.
+			if !shouldStop {
+				if err := checkDiskSpace(outputDir); err != nil {
+					logger.Warn("stopping eval", "error", err)
+					shouldStop = true
+					stopReason = "Insufficient disk space"
+				}
+			}
+
 			// Also stop if we hit consecutive quota exhaustion threshold.
 			if !shouldStop && consecutiveQuotaExhausted >= quotaExhaustedStopThreshold {
 				shouldStop = true
 				stopReason = fmt.Sprintf("Quota exhaustion for %d consecutive tasks", consecutiveQuotaExhausted)
 			}
 
+			// And when Docker or the network is down and every task infra-fails.
+			if !shouldStop && infraStop > 0 && consecutiveInfraFailures >= infraStop {
+				shouldStop = true
+				stopReason = fmt.Sprintf("Infra failures for %d consecutive tasks", consecutiveInfraFailures)
+			}
+
+			if !shouldStop && failFastTask != "" {
+				shouldStop = true
+				stoppedByFailFast = failFastTask
+				stopReason = fmt.Sprintf("--fail-fast: %s failed", failFastTask)
+			}
+
 			if shouldStop {
 				wasInterrupted = true
-				fmt.Printf("\n\033[33m⚠ %s. Waiting for in-flight tasks...\033[0m\n", stopReason)
+				if progress != nil {
+					progress.finish()
+				}
+				fmt.Fprintf(out, "\n\033[33m⚠ %s. Waiting for in-flight tasks...\033[0m\n", stopReason)
 				close(stopSending)
 				// Drain remaining results from in-flight tasks.
 				for jr := range jobResults {
@@ -1127,8 +1750,9 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 						} else {
 							failed++
 						}
+						// Keep the logs of in-flight results, which are scored.
 						if !shared.KeepWorkspaces && jr.r.WorkspaceDir != "" {
-							_ = os.RemoveAll(jr.r.WorkspaceDir)
+							cleanupWorkspaceFiles(jr.r.WorkspaceDir)
 						}
 					}
 				}
@@ -1142,38 +1766,20 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 			}
 		}
 	}
+	if progress != nil {
+		progress.finish()
+	}
 
 	// If resuming, merge with previous results.
 	if isResuming && len(previousResults) > 0 {
-		// Build a set of task IDs from new results.
-		newResultTasks := make(map[string]bool)
-		for _, r := range results {
-			newResultTasks[r.Task] = true
-		}
-		// Exclude any task that is currently tracked as an external failure
-		// (either carried over from a prior resume or recorded this session).
-		// Without this, a task previously stored in `results` under an earlier
-		// bug and now properly classified as infra/auth/quota would be
-		// double-counted — once as a scored failure and once as a skip.
-		externalFailureTasks := make(map[string]bool, len(externalFailures))
-		for _, f := range externalFailures {
-			externalFailureTasks[f.Task] = true
-		}
-		// Prepend previous results that aren't in the new results and aren't
-		// classified as external failures.
-		var merged []EvalResult
-		for _, r := range previousResults {
-			if newResultTasks[r.Task] || externalFailureTasks[r.Task] {
-				continue
-			}
-			merged = append(merged, r)
-			if r.Passed {
-				passed++
-			} else {
-				failed++
-			}
+		var prevPassed, prevFailed int
+		ran := make(map[string]bool, len(tasksToRun))
+		for _, t := range tasksToRun {
+			ran[t.ID()] = true
 		}
-		results = append(merged, results...)
+		results, externalFailures, prevPassed, prevFailed = mergePreviousResults(previousResults, results, externalFailures, ran)
+		passed += prevPassed
+		failed += prevFailed
 	}
 
 	// Sort results to match allTasks order
@@ -1234,22 +1840,25 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 	}
 
 	// Print summary
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println(" EVALUATION SUMMARY")
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println()
-	fmt.Printf(" Agent:     %s\n", spec.Agent)
+	fmt.Fprintln(out, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Fprintln(out, " EVALUATION SUMMARY")
+	fmt.Fprintln(out, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, " Agent:     %s\n", spec.Agent)
 	if spec.Model != "" {
-		fmt.Printf(" Model:     %s\n", spec.Model)
+		fmt.Fprintf(out, " Model:     %s\n", spec.Model)
 	}
-	fmt.Printf(" Passed:    %d\n", passed)
-	fmt.Printf(" Failed:    %d\n", failed)
-	fmt.Printf(" Total:     %d\n", total)
+	fmt.Fprintf(out, " Passed:    %d\n", passed)
+	fmt.Fprintf(out, " Failed:    %d\n", failed)
+	fmt.Fprintf(out, " Total:     %d\n", total)
 	if len(externalFailures) > 0 {
-		fmt.Printf(" Skipped:   %d (external auth/quota/infra)\n", len(externalFailures))
+		fmt.Fprintf(out, " Skipped:   %d (external auth/quota/infra)\n", len(externalFailures))
+	}
+	if shared.SkippedLangTasks > 0 {
+		fmt.Fprintf(out, " Skipped:   %d (unavailable languages: %s)\n", shared.SkippedLangTasks, shared.SkipLangs)
 	}
-	fmt.Printf(" Pass Rate: %.1f%%\n", passRate)
-	fmt.Println()
+	fmt.Fprintf(out, " Pass Rate: %.1f%%\n", passRate)
+	fmt.Fprintln(out)
 
 	// Save summary
 	// Aggregate stats
@@ -1260,10 +1869,19 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 	var totalDuration float64
 	var totalAgentTime float64
 	var totalValidateTime float64
+	var totalThinkingTime, totalActingTime float64
+	var tasksWithTimeline int
+	var totalTokens, tasksWithTokenUsage int
 	var totalPromptChars int
 	var totalWeightedScore float64
 	var maxPossibleScore float64
 	var integrityViolations int
+	var fallbackTasks int
+	var lintedTasks, totalLintWarnings int
+	var robustnessChecked, robustnessPassed int
+	var promptTrimmedTasks int
+	var expectedFailures int
+	var unexpectedPasses []string
 	var quotaAffectedTasks int
 	var authAffectedTasks int
 	var infraAffectedTasks int
@@ -1273,6 +1891,7 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 	var totalAgentTimeoutRetries int
 	var agentTimeoutTasks int        // tasks that ultimately ended as a timeout
 	var agentTimeoutRetriedTasks int // subset of above that got at least one retry
+	var refusalTasks int
 	var totalToolchainInstallAttempts int
 	var totalOutOfWorkspaceReadAttempts int
 	var totalToolchainSearchAttempts int
@@ -1281,7 +1900,9 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 	var tasksWithToolchainInstall int
 	var tasksWithOutOfWorkspaceReads int
 	var tasksWithToolchainSearch int
+	var totalEscapeAttempts, tasksWithEscapeAttempts int
 	var tasksWithSkillsUsage int
+	var tasksWithRelevantSkill, tasksUsingRelevantSkill int
 
 	addAgg := func(m map[string]EvalAggregate, key string, r EvalResult) {
 		agg := m[key]
@@ -1296,7 +1917,11 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 		agg.ValidateTime += r.ValidateTime
 		m[key] = agg
 	}
-	accumulateFailureStats := func(class FailureClass, quotaRetries, infraRetries int) {
+	retryReasons := make(map[string]int)
+	accumulateFailureStats := func(class FailureClass, quotaRetries, infraRetries int, reasons map[string]int) {
+		for reason, n := range reasons {
+			retryReasons[reason] += n
+		}
 		if class == FailureClassQuotaRecoverable || class == FailureClassQuotaExhausted {
 			quotaAffectedTasks++
 		}
@@ -1314,6 +1939,15 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 		totalDuration += r.Duration
 		totalAgentTime += r.AgentTime
 		totalValidateTime += r.ValidateTime
+		if r.TotalTokensConfident {
+			totalTokens += r.TotalTokens
+			tasksWithTokenUsage++
+		}
+		if r.ThinkingTime+r.ActingTime > 0 {
+			totalThinkingTime += r.ThinkingTime
+			totalActingTime += r.ActingTime
+			tasksWithTimeline++
+		}
 		totalPromptChars += r.PromptChars
 		totalWeightedScore += r.WeightedScore
 		maxPossibleScore += r.Weight
@@ -1334,22 +1968,58 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 		if r.ToolchainSearchAttempts > 0 {
 			tasksWithToolchainSearch++
 		}
+		totalEscapeAttempts += r.EscapeAttempts
+		if r.EscapeAttempts > 0 {
+			tasksWithEscapeAttempts++
+		}
 		if r.SkillsUsed {
 			tasksWithSkillsUsage++
 		}
+		if r.RelevantSkill != "" {
+			tasksWithRelevantSkill++
+			if r.RelevantSkillUsed {
+				tasksUsingRelevantSkill++
+			}
+		}
 
 		// Count by status
 		if r.Status == task.StatusIntegrityViolation {
 			integrityViolations++
 		}
+		if r.Agent != "" && r.Agent != spec.Agent {
+			fallbackTasks++
+		}
+		if r.Linted {
+			lintedTasks++
+			totalLintWarnings += r.LintWarnings
+		}
+		if r.PromptTrimmed {
+			promptTrimmedTasks++
+		}
+		if r.RobustnessChecked {
+			robustnessChecked++
+			if r.RobustnessPassed {
+				robustnessPassed++
+			}
+		}
+		if r.ExpectedFail {
+			if r.Passed {
+				unexpectedPasses = append(unexpectedPasses, r.Task)
+			} else {
+				expectedFailures++
+			}
+		}
 		if r.AgentTimedOut {
 			agentTimeoutTasks++
 			if r.AgentTimeoutRetries > 0 {
 				agentTimeoutRetriedTasks++
 			}
 		}
+		if r.AgentRefused {
+			refusalTasks++
+		}
 		totalAgentTimeoutRetries += r.AgentTimeoutRetries
-		accumulateFailureStats(r.FailureClass, r.QuotaRetries, r.InfraRetries)
+		accumulateFailureStats(r.FailureClass, r.QuotaRetries, r.InfraRetries, r.RetryReasons)
 
 		addAgg(byLanguage, r.Language, r)
 		if r.Tier != "" {
@@ -1361,7 +2031,7 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 	}
 
 	for _, f := range externalFailures {
-		accumulateFailureStats(f.FailureClass, f.QuotaRetries, f.InfraRetries)
+		accumulateFailureStats(f.FailureClass, f.QuotaRetries, f.InfraRetries, f.RetryReasons)
 	}
 
 	// Calculate weighted pass rate
@@ -1369,6 +2039,15 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 	if maxPossibleScore > 0 {
 		weightedPassRate = totalWeightedScore / maxPossibleScore * 100
 	}
+	// Normalize against every task in the suite so partial runs (one tier,
+	// one language) are comparable with full runs.
+	var fullSuiteMax, normalizedWeightedPassRate float64
+	if suite, err := r.ListTasks(); err == nil {
+		fullSuiteMax = fullSuiteMaxScore(suite)
+	}
+	if fullSuiteMax > 0 {
+		normalizedWeightedPassRate = totalWeightedScore / fullSuiteMax * 100
+	}
 	skillsUsageRate := 0.0
 	if total > 0 {
 		skillsUsageRate = float64(tasksWithSkillsUsage) / float64(total) * 100
@@ -1394,8 +2073,11 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 		Agent:                           spec.Agent,
 		Model:                           model,
 		Reasoning:                       spec.Reasoning,
+		RunLabel:                        spec.Label,
+		FailFastTask:                    stoppedByFailFast,
 		Timestamp:                       timestamp,
 		Tier:                            shared.Tier,
+		Shard:                           evalShard,
 		Difficulty:                      shared.Difficulty,
 		Timeout:                         shared.Timeout,
 		Parallel:                        parallel,
@@ -1408,10 +2090,31 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 		WeightedScore:                   totalWeightedScore,
 		MaxPossibleScore:                maxPossibleScore,
 		WeightedPassRate:                weightedPassRate,
+		FullSuiteMaxScore:               fullSuiteMax,
+		NormalizedWeightedPassRate:      normalizedWeightedPassRate,
 		IntegrityViolations:             integrityViolations,
+		AgentFallback:                   fallbackChainLabels(shared.AgentFallback),
+		FallbackTasks:                   fallbackTasks,
+		SkipLangs:                       splitSkipLangs(shared.SkipLangs),
+		SkippedLanguageTasks:            shared.SkippedLangTasks,
+		Lint:                            shared.Lint,
+		LintedTasks:                     lintedTasks,
+		TotalLintWarnings:               totalLintWarnings,
+		Robustness:                      shared.Robustness,
+		RobustnessChecked:               robustnessChecked,
+		RobustnessPassed:                robustnessPassed,
+		PromptBudgetTokens:              shared.PromptBudget,
+		PromptTrimmedTasks:              promptTrimmedTasks,
+		ExpectedFailures:                expectedFailures,
+		UnexpectedPasses:                unexpectedPasses,
 		Duration:                        totalDuration,
 		AgentTime:                       totalAgentTime,
 		ValidateTime:                    totalValidateTime,
+		ThinkingTime:                    totalThinkingTime,
+		ActingTime:                      totalActingTime,
+		TasksWithTimeline:               tasksWithTimeline,
+		TotalTokens:                     totalTokens,
+		TasksWithTokenUsage:             tasksWithTokenUsage,
 		PromptChars:                     totalPromptChars,
 		ByLanguage:                      finalize(byLanguage),
 		ByTier:                          finalize(byTier),
@@ -1421,6 +2124,8 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 		UseSkills:                       shared.UseSkills,
 		DisableMCP:                      shared.DisableMCP,
 		Sandbox:                         evalSandboxActive,
+		SandboxNoNetwork:                evalSandboxActive && !sandboxAllowNetwork(),
+		ReuseContainer:                  shared.ReuseContainer,
 		Legacy:                          shared.Legacy,
 		QuotaAffectedTasks:              quotaAffectedTasks,
 		AuthAffectedTasks:               authAffectedTasks,
@@ -1428,8 +2133,10 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 		TotalQuotaRetries:               totalQuotaRetries,
 		TotalInfraRetries:               totalInfraRetries,
 		TotalAgentTimeoutRetries:        totalAgentTimeoutRetries,
+		RetryReasons:                    retryReasons,
 		AgentTimeoutTasks:               agentTimeoutTasks,
 		AgentTimeoutRetriedTasks:        agentTimeoutRetriedTasks,
+		RefusalTasks:                    refusalTasks,
 		TotalSelfTestCommands:           totalSelfTestCommands,
 		TotalToolchainInstallAttempts:   totalToolchainInstallAttempts,
 		TotalOutOfWorkspaceReadAttempts: totalOutOfWorkspaceReadAttempts,
@@ -1440,15 +2147,24 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 		TasksWithOutOfWorkspaceReads:    tasksWithOutOfWorkspaceReads,
 		TotalToolchainSearchAttempts:    totalToolchainSearchAttempts,
 		TasksWithToolchainSearch:        tasksWithToolchainSearch,
+		TotalEscapeAttempts:             totalEscapeAttempts,
+		TasksWithEscapeAttempts:         tasksWithEscapeAttempts,
 		TasksWithSkillsUsage:            tasksWithSkillsUsage,
+		TasksWithRelevantSkill:          tasksWithRelevantSkill,
+		TasksUsingRelevantSkill:         tasksUsingRelevantSkill,
 	}
+	summary.Baselines = loadBaselineScores(evalBaselines, results)
 
 	summaryPath := filepath.Join(outputDir, "summary.json")
 	summaryData, _ := json.MarshalIndent(summary, "", "  ")
-	if err := os.WriteFile(summaryPath, summaryData, 0644); err != nil {
+	if err := writeFileAtomic(summaryPath, summaryData, 0644); err != nil {
 		logger.Warn("failed to save summary", "error", err)
 	} else {
-		fmt.Printf(" Results saved to: %s\n", summaryPath)
+		fmt.Fprintf(out, " Results saved to: %s\n", summaryPath)
+	}
+
+	if err := writeTimingBreakdown(outputDir, buildTimingBreakdown(results)); err != nil {
+		logger.Warn("failed to save timing breakdown", "error", err)
 	}
 
 	// Generate attestation for verification
@@ -1469,12 +2185,17 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 	if err != nil {
 		logger.Warn("failed to generate attestation", "error", err)
 	} else {
+		attestation.Eval.ContainerReuse = shared.ReuseContainer
+		attestation.Eval.AgentVersions = agentVersions
+		if promptTmpl != nil {
+			attestation.Eval.PromptTemplateHash = promptTmpl.hash
+		}
 		attestationPath := filepath.Join(outputDir, "attestation.json")
 		attestationData, _ := json.MarshalIndent(attestation, "", "  ")
-		if err := os.WriteFile(attestationPath, attestationData, 0644); err != nil {
+		if err := writeFileAtomic(attestationPath, attestationData, 0644); err != nil {
 			logger.Warn("failed to save attestation", "error", err)
 		} else {
-			fmt.Printf(" Attestation saved to: %s\n", attestationPath)
+			fmt.Fprintf(out, " Attestation saved to: %s\n", attestationPath)
 		}
 	}
 
@@ -1484,39 +2205,80 @@ func evalRunSingle( //nolint:gocognit,gocyclo,maintidx
 	if err := os.WriteFile(reportPath, []byte(reportMd), 0644); err != nil {
 		logger.Warn("failed to save report", "error", err)
 	} else {
-		fmt.Printf(" Report saved to: %s\n", reportPath)
+		fmt.Fprintf(out, " Report saved to: %s\n", reportPath)
+	}
+
+	// Generate junit.xml for CI dashboards
+	if junitPath, err := writeJUnitReport(outputDir, summary); err != nil {
+		logger.Warn("failed to save JUnit report", "error", err)
+	} else {
+		fmt.Fprintf(out, " JUnit report saved to: %s\n", junitPath)
+	}
+
+	// Generate failures.md, a triage view of just the failed tasks. A
+	// resumed run with no remaining failures drops the stale one.
+	failuresPath := filepath.Join(outputDir, "failures.md")
+	if failuresMd := generateFailuresReport(summary, outputDir); failuresMd != "" {
+		if err := os.WriteFile(failuresPath, []byte(failuresMd), 0644); err != nil {
+			logger.Warn("failed to save failures report", "error", err)
+		} else {
+			fmt.Fprintf(out, " Failures saved to: %s\n", failuresPath)
+		}
+	} else {
+		_ = os.Remove(failuresPath)
 	}
 
 	// Generate leaderboard submission file
 	submission := generateLeaderboardSubmission(summary, attestation)
 	submissionData, _ := json.MarshalIndent(submission, "", "  ")
 	submissionPath := filepath.Join(outputDir, "submission.json")
-	if err := os.WriteFile(submissionPath, submissionData, 0644); err != nil {
+	if err := writeFileAtomic(submissionPath, submissionData, 0644); err != nil {
 		logger.Warn("failed to save submission", "error", err)
 	} else {
-		fmt.Printf(" Submission saved to: %s\n", submissionPath)
+		fmt.Fprintf(out, " Submission saved to: %s\n", submissionPath)
+	}
+
+	if evalExportFormat == exportFormatSWEBench {
+		if exportPath, err := writeSWEBenchExport(outputDir, summary); err != nil {
+			logger.Warn("failed to save swebench export", "error", err)
+		} else {
+			fmt.Fprintf(out, " SWE-bench export saved to: %s\n", exportPath)
+		}
+	}
+
+	// Index every artifact written above so tooling can ingest the run.
+	if err := writeRunManifest(outputDir); err != nil {
+		logger.Warn("failed to save manifest", "error", err)
 	}
 
-	fmt.Println()
+	fmt.Fprintln(out)
+
+	if len(summary.UnexpectedPasses) > 0 {
+		fmt.Fprintf(out, "\033[33m ⚠ %d task(s) marked expected to fail passed (XPASS):\033[0m\n", len(summary.UnexpectedPasses))
+		for _, id := range summary.UnexpectedPasses {
+			fmt.Fprintf(out, "   • %s\n", id)
+		}
+		fmt.Fprintln(out)
+	}
 
 	// Report resumable external failures and provide resume command.
 	if len(resumableFailedTasks) > 0 {
-		fmt.Println("\033[33m━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\033[0m")
-		fmt.Printf("\033[33m ⚠ %d task(s) skipped due to external failures (auth/quota/infra):\033[0m\n", len(resumableFailedTasks))
+		fmt.Fprintln(out, "\033[33m━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\033[0m")
+		fmt.Fprintf(out, "\033[33m ⚠ %d task(s) skipped due to external failures (auth/quota/infra):\033[0m\n", len(resumableFailedTasks))
 		for _, t := range resumableFailedTasks {
-			fmt.Printf("   • %s\n", t)
+			fmt.Fprintf(out, "   • %s\n", t)
 		}
-		fmt.Println()
-		fmt.Println(" These tasks were not counted in the results above.")
-		fmt.Println(" To retry them, run:")
-		fmt.Printf("   ./sanity eval --resume %s\n", outputDir)
-		fmt.Println("\033[33m━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\033[0m")
-		fmt.Println()
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, " These tasks were not counted in the results above.")
+		fmt.Fprintln(out, " To retry them, run:")
+		fmt.Fprintf(out, "   ./sanity eval --resume %s\n", outputDir)
+		fmt.Fprintln(out, "\033[33m━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\033[0m")
+		fmt.Fprintln(out)
 	}
 
 	// If interrupted, print resume command.
 	if wasInterrupted {
-		printResumeCommand(outputDir)
+		printResumeCommand(out, outputDir)
 	}
 
 	return &summary, attestation, nil
@@ -1536,7 +2298,7 @@ func runTaskWithAgent(ctx context.Context, r *runner.Runner, t *task.Task, agent
 	// Create an isolated temp workspace for the agent so it cannot read
 	// other eval results or sibling task directories. After the agent
 	// finishes, files are copied back to the real workspace for validation.
-	agentWorkDir, err := os.MkdirTemp("", fmt.Sprintf("sanity-eval-%s-%s-*", t.Language, t.Slug))
+	agentWorkDir, err := createAgentWorkDir(t, runSettingsFrom(ctx).workDirRoot)
 	if err != nil {
 		result.Error = fmt.Sprintf("creating temp workspace: %v", err)
 		return result
@@ -1561,15 +2323,26 @@ func runTaskWithAgent(ctx context.Context, r *runner.Runner, t *task.Task, agent
 
 	// Get agent configuration
 	agentCfg := cfg.GetAgent(agent)
+	run := runSettingsFrom(ctx)
+	if agent == run.agent {
+		agentCfg = specAgentConfig(RunSpec{Agent: agent, Command: run.command})
+	}
 	if agentCfg == nil {
 		result.Error = fmt.Sprintf("unknown agent: %s", agent)
 		return result
 	}
 
 	// Build agent command
-	prompt := buildAgentPrompt(t, evalUseMCPTools, evalUseSkills, agentCfg.MCPPrompt)
+	prompt, err := agentPrompt(run.promptTmpl, t, run.useMCPTools, evalUseSkills, agentCfg.MCPPrompt)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	prompt, result.PromptTrimmed = trimPromptToBudget(prompt, taskPromptBudget(evalPromptBudget, evalSystemPrompt))
 	result.PromptChars = utf8.RuneCountInString(prompt)
-	agentTimeout := resolveAgentTimeout(timeout, agentCfg.DefaultTimeout, t.AgentTimeout)
+	agentTimeout := resolveAgentTimeout(taskTimeoutSeconds(timeout, run.timeoutExplicit, t), agentCfg.MinTimeout(run.reasoning), t.AgentTimeout)
+	result.AgentTimeout = int(agentTimeout / time.Second)
+	result.AgentCommand = shellCommandProvenance(agentCfg, model, run.reasoning)
 
 	// Place agent.log in the task output directory (eval-results/<run>/<lang>-<slug>/).
 	// This is outside the agent's temp workspace so the agent cannot read it.
@@ -1579,10 +2352,25 @@ func runTaskWithAgent(ctx context.Context, r *runner.Runner, t *task.Task, agent
 		return result
 	}
 
+	// Snapshot what the agent left behind for failed tasks, before the temp
+	// workspace is removed.
+	defer func() {
+		if !result.Passed {
+			writeWorkspaceTree(agentWorkDir, filepath.Join(taskOutputDir, "tree.txt"))
+		}
+	}()
+
 	// Execute agent in the isolated temp workspace
 	workspaceReadyAt := time.Now()
 	agentResult := executeAgentWithRetries(ctx, t, agentCfg, prompt, model, agentWorkDir, agentLogPath, agentTimeout, agent, workspaceReadyAt)
-	applyAgentExecutionResult(&result, agentResult, agentLogPath, agentWorkDir)
+	if fileEnv, _ := agentFileEnv(agentCfg); len(fileEnv) > 0 {
+		redactEnvValues(agentLogPath, fileEnv)
+	}
+	applyAgentExecutionResult(&result, agentResult, agentLogPath, agentWorkDir, t.RelevantSkill, newAgentTimestampFormat(agentCfg), agentTokenPatterns(agentCfg))
+	if result.AgentTimedOut {
+		result.stubsUntouched = stubsUntouched(loader, t, agentWorkDir)
+	}
+	evalEvents.emitAgentFinished(result, agent, model)
 
 	// If agent execution failed due auth/quota/infra, skip validation entirely.
 	// The task will be excluded from results so it can be resumed later.
@@ -1590,6 +2378,24 @@ func runTaskWithAgent(ctx context.Context, r *runner.Runner, t *task.Task, agent
 		return result
 	}
 
+	// A runaway agent was killed mid-loop; its work is not validated.
+	if result.AgentRunaway {
+		result.Error = fmt.Sprintf("agent runaway: %d bytes of output without editing a file", evalAgentRunaway)
+		return result
+	}
+
+	// An agent trusted to report failure through its exit code failed.
+	if result.FailureClass == FailureClassAgentExit {
+		result.Error = fmt.Sprintf("agent exited with code %d after %d attempts", agentResult.exitCode, agentResult.infraRetries)
+		return result
+	}
+
+	// A model that refused the task wrote nothing worth validating.
+	if result.AgentRefused {
+		result.Error = fmt.Sprintf("agent refused the task: %q", agentResult.refusal)
+		return result
+	}
+
 	// Ensure the agent didn't modify task-owned files.
 	integrityViolated, err := detectAndRecordIntegrityViolation(
 		loader,
@@ -1607,8 +2413,10 @@ func runTaskWithAgent(ctx context.Context, r *runner.Runner, t *task.Task, agent
 		return result
 	}
 
-	// Copy agent's work from temp workspace to the real workspace for validation.
-	if err := copyDirContents(agentWorkDir, workspaceDir); err != nil {
+	// Copy agent's work from temp workspace to the real workspace for
+	// validation. Files named like harness artifacts (agent.log,
+	// sandbox.json, ...) are not copied, so the agent cannot forge them.
+	if err := copyDirContentsSkipping(agentWorkDir, workspaceDir, isEvalOutputFile); err != nil {
 		result.Error = fmt.Sprintf("copying agent workspace: %v", err)
 		return result
 	}
@@ -1618,8 +2426,22 @@ func runTaskWithAgent(ctx context.Context, r *runner.Runner, t *task.Task, agent
 		return result
 	}
 
-	validationCmd, effectiveValidationCmd := buildValidationCommands(t)
-	validationTimeout := resolveValidationTimeout(timeout)
+	validationCmd, effectiveValidationCmd, variants := buildValidationCommands(t)
+	result.ValidationCommand = effectiveValidationCmd
+	validationTimeout := resolveValidationTimeout(taskTimeoutSeconds(timeout, run.timeoutExplicit, t), t.Timeout)
+	// Validation execs append to container.log; drop one left by a previous run.
+	_ = os.Remove(filepath.Join(taskOutputDir, containerLogName))
+	if len(variants) > 0 {
+		runVariantValidations(ctx, r, t, workspaceDir, validationLogPath, validationTimeout, variants, &result)
+		evalEvents.emitValidationFinished(result)
+		if evalLint && result.Passed {
+			runLint(ctx, r, t, workspaceDir, taskOutputDir, validationTimeout, &result)
+		}
+		if evalRobustness && result.Passed {
+			runRobustnessCheck(ctx, r, loader, t, workspaceDir, taskOutputDir, validationTimeout, &result)
+		}
+		return result
+	}
 	session, validateDuration, err := runValidationSession(
 		ctx,
 		r,
@@ -1627,25 +2449,163 @@ func runTaskWithAgent(ctx context.Context, r *runner.Runner, t *task.Task, agent
 		workspaceDir,
 		validationTimeout,
 		validationCmd,
+		filepath.Join(taskOutputDir, containerLogName),
 	)
 	result.ValidateTime = validateDuration
 	if err != nil {
 		handleValidationRunError(&result, session, err, validationLogPath, effectiveValidationCmd)
+		evalEvents.emitValidationFinished(result)
 		return result
 	}
 
 	applyValidationSessionResult(&result, session)
+	applyCoverage(&result, t, session)
 	writeValidationSessionLog(validationLogPath, effectiveValidationCmd, session)
+	evalEvents.emitValidationFinished(result)
+	if evalLint && result.Passed {
+		runLint(ctx, r, t, workspaceDir, taskOutputDir, validationTimeout, &result)
+	}
+	if evalRobustness && result.Passed {
+		runRobustnessCheck(ctx, r, loader, t, workspaceDir, taskOutputDir, validationTimeout, &result)
+	}
+	return result
+}
+
+// parseAgentFallback parses the --agent-fallback chain. Entries are agent
+// names with an optional ":model" suffix; without one the agent runs with its
+// default model.
+func parseAgentFallback(value string) []RunSpec {
+	var specs []RunSpec
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		agent, model, _ := strings.Cut(entry, ":")
+		specs = append(specs, RunSpec{Agent: strings.TrimSpace(agent), Model: strings.TrimSpace(model)})
+	}
+	return specs
+}
+
+// SubmittedEntry records one task result already accepted by a leaderboard.
+// A submitted.json file holds a JSON array of these entries.
+type SubmittedEntry struct {
+	Agent string `json:"agent"`
+	Model string `json:"model,omitempty"`
+	Task  string `json:"task"` // Canonical "<language>/<slug>" or an unambiguous slug
+}
+
+// loadSubmitted reads the --only-new record of submitted tasks.
+func loadSubmitted(path string) ([]SubmittedEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading submitted record: %w", err)
+	}
+	var entries []SubmittedEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parsing submitted record %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// filterUnsubmitted drops tasks already submitted for spec's agent and model.
+// Entries that don't resolve to a selected task are ignored.
+func filterUnsubmitted(tasks []*task.Task, submitted []SubmittedEntry, spec RunSpec) []*task.Task {
+	done := make(map[string]bool)
+	for _, e := range submitted {
+		if e.Agent != spec.Agent || e.Model != spec.Model {
+			continue
+		}
+		if t, err := task.ResolveRef(tasks, e.Task); err == nil {
+			done[t.ID()] = true
+		}
+	}
+
+	var remaining []*task.Task
+	for _, t := range tasks {
+		if !done[t.ID()] {
+			remaining = append(remaining, t)
+		}
+	}
+	return remaining
+}
+
+// fallbackChainLabels returns the fallback chain entries as recorded in the
+// summary, or nil when no fallback is configured.
+func fallbackChainLabels(value string) []string {
+	var labels []string
+	for _, fb := range parseAgentFallback(value) {
+		label := fb.Agent
+		if fb.Model != "" {
+			label += ":" + fb.Model
+		}
+		labels = append(labels, label)
+	}
+	return labels
+}
+
+// isAgentInfraFailure reports whether the result is an infra failure raised
+// while running the agent, as opposed to a Docker/validation infra error
+// that switching agents would not fix.
+func isAgentInfraFailure(result EvalResult) bool {
+	return result.FailureClass == FailureClassInfra && result.Attempts == 0 && result.ValidateTime == 0
+}
+
+// runTaskWithFallback runs a task with the primary agent and, if it
+// infra-fails after its own retries, with each fallback agent in turn. The
+// result records the agent that produced it and the agents that failed
+// before it; their logs are kept as agent-<name>.log.
+func runTaskWithFallback(
+	ctx context.Context,
+	r *runner.Runner,
+	t *task.Task,
+	spec RunSpec,
+	fallbacks []RunSpec,
+	outputDir string,
+	timeout int,
+) (result EvalResult) {
+	evalEvents.emit(evalEvent{Event: eventTaskStarted, Task: t.ID(), Agent: spec.Agent, Model: spec.Model})
+	// A requeued task's previous artifacts are only dropped now that it
+	// actually runs again.
+	_, taskOutputDir := evalWorkspacePaths(outputDir, t)
+	_ = os.RemoveAll(taskOutputDir)
+	result = runTaskWithAgent(ctx, r, t, spec.Agent, spec.Model, outputDir, timeout)
+	if len(fallbacks) == 0 {
+		evalEvents.emitTaskResult(result, spec.Agent)
+		return result
+	}
+	result.Agent = spec.Agent
+	defer func() { evalEvents.emitTaskResult(result, result.Agent) }()
+
+	var failedAgents []string
+	for _, fb := range fallbacks {
+		if !isAgentInfraFailure(result) || checkInterrupted(ctx) {
+			break
+		}
+		failedAgents = append(failedAgents, result.Agent)
+		logger.Warn("agent infra-failed, trying fallback agent",
+			"task", t.ID(), "agent", result.Agent, "fallback", fb.Agent)
+
+		_ = os.Rename(
+			filepath.Join(taskOutputDir, "agent.log"),
+			filepath.Join(taskOutputDir, fmt.Sprintf("agent-%s.log", result.Agent)),
+		)
+
+		result = runTaskWithAgent(ctx, r, t, fb.Agent, fb.Model, outputDir, timeout)
+		result.Agent = fb.Agent
+	}
+	result.FallbackFrom = failedAgents
 	return result
 }
 
 func newEvalResult(t *task.Task, weight task.Weight) EvalResult {
 	return EvalResult{
-		Task:       t.ID(),
-		Language:   string(t.Language),
-		Tier:       t.Tier,
-		Difficulty: t.Difficulty,
-		Weight:     weight.Base,
+		Task:         t.ID(),
+		Language:     string(t.Language),
+		Tier:         t.Tier,
+		Difficulty:   t.Difficulty,
+		Weight:       weight.Base,
+		ExpectedFail: t.ExpectsFailure(),
 	}
 }
 
@@ -1654,6 +2614,31 @@ func evalWorkspacePaths(outputDir string, t *task.Task) (workspaceName, workspac
 	return workspaceName, filepath.Join(outputDir, workspaceName)
 }
 
+// languageTimeout returns the [harness.language_timeouts] entry for lang,
+// or timeoutSeconds when lang has none. The entry replaces the default
+// timeout; an explicit --timeout is kept when it is longer.
+func languageTimeout(timeoutSeconds int, explicit bool, overrides map[string]int, lang task.Language) int {
+	seconds := overrides[string(lang)]
+	if seconds <= 0 || (explicit && timeoutSeconds > seconds) {
+		return timeoutSeconds
+	}
+	return seconds
+}
+
+// taskTimeoutSeconds returns the run's timeout for t before per-task and
+// per-agent adjustments: its language's timeout if configured, otherwise
+// timeoutSeconds. explicit reports whether timeoutSeconds came from
+// --timeout rather than the default.
+func taskTimeoutSeconds(timeoutSeconds int, explicit bool, t *task.Task) int {
+	if cfg == nil {
+		return timeoutSeconds
+	}
+	return languageTimeout(timeoutSeconds, explicit, cfg.Harness.LanguageTimeouts, t.Language)
+}
+
+// resolveAgentTimeout returns a task's agent timeout: timeoutSeconds (the
+// global or per-language timeout, 600 when unset), raised to the agent's
+// defaultSeconds and the task's taskSeconds when those are longer.
 func resolveAgentTimeout(timeoutSeconds, defaultSeconds, taskSeconds int) time.Duration {
 	timeout := time.Duration(timeoutSeconds) * time.Second
 	if timeout <= 0 {
@@ -1674,15 +2659,24 @@ func resolveAgentTimeout(timeoutSeconds, defaultSeconds, taskSeconds int) time.D
 	return timeout
 }
 
+// containerLogName is the per-task file holding the raw stdout and stderr of
+// every validation exec, next to the summarized validation.log.
+const containerLogName = "container.log"
+
 // evalOutputFiles lists files and directories produced by the harness in the
 // task output directory. These must be preserved when cleaning up workspace
 // source files after validation.
 var evalOutputFiles = map[string]bool{
 	"agent.log":       true,
 	"validation.log":  true,
+	"container.log":   true,
 	"integrity.json":  true,
 	"integrity-files": true,
 	"integrity-diff":  true,
+	"tree.txt":        true,
+	"lint.log":        true,
+	"robustness.log":  true,
+	"sandbox.json":    true,
 }
 
 // cleanupWorkspaceFiles removes workspace source files from the task output
@@ -1694,13 +2688,110 @@ func cleanupWorkspaceFiles(dir string) {
 		return
 	}
 	for _, e := range entries {
-		if evalOutputFiles[e.Name()] {
+		if isEvalOutputFile(e.Name()) {
 			continue
 		}
 		_ = os.RemoveAll(filepath.Join(dir, e.Name()))
 	}
 }
 
+// createAgentWorkDir creates the temp workspace the agent runs in. With root
+// set (--debug-workspaces), the directory is named after the task without a
+// random suffix so it can be found while running; root is a private temp dir
+// created for the run, so nothing else can plant or share that path.
+func createAgentWorkDir(t *task.Task, root string) (string, error) {
+	name := fmt.Sprintf("sanity-eval-%s-%s", t.Language, t.Slug)
+	if root == "" {
+		return os.MkdirTemp("", name+"-*")
+	}
+
+	dir := filepath.Join(root, name)
+	if err := os.RemoveAll(dir); err != nil {
+		return "", fmt.Errorf("removing stale workspace: %w", err)
+	}
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// debugWorkspaceRunID names a run's --debug-workspaces root after its output
+// directory, which identifies the run, spec and repeat
+// (eval-results/batch-<ts>/<agent>-<model>/run-2 becomes
+// batch-<ts>-<agent>-<model>-run-2).
+func debugWorkspaceRunID(outputDir string) string {
+	id := filepath.ToSlash(filepath.Clean(outputDir))
+	if i := strings.LastIndex(id, "eval-results/"); i >= 0 {
+		id = id[i+len("eval-results/"):]
+	}
+	id = strings.Trim(strings.ReplaceAll(id, "/", "-"), "-.")
+	if id == "" {
+		return "run"
+	}
+	return id
+}
+
+// writeWorkspaceTree writes a listing of the workspace (relative paths and
+// sizes in bytes) to path. Build and dependency directories are summarized
+// rather than listed file by file.
+func writeWorkspaceTree(workspaceDir, path string) {
+	var sb strings.Builder
+	_ = filepath.WalkDir(workspaceDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || p == workspaceDir {
+			return nil //nolint:nilerr // Best-effort listing; skip unreadable entries.
+		}
+		rel, relErr := filepath.Rel(workspaceDir, p)
+		if relErr != nil {
+			return nil //nolint:nilerr // Best-effort listing.
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if newFileIgnoredDirs[d.Name()] || d.Name() == ".git" {
+				files, size := dirUsage(p)
+				fmt.Fprintf(&sb, "%10d  %s/ (%d files, not listed)\n", size, rel, files)
+				return filepath.SkipDir
+			}
+			fmt.Fprintf(&sb, "%10s  %s/\n", "-", rel)
+			return nil
+		}
+		if info, infoErr := d.Info(); infoErr == nil {
+			fmt.Fprintf(&sb, "%10d  %s\n", info.Size(), rel)
+		}
+		return nil
+	})
+	if err := os.WriteFile(path, []byte(sb.String()), 0o644); err != nil {
+		logger.Debug("failed to write workspace tree", "path", path, "error", err)
+	}
+}
+
+// dirUsage returns the number of regular files under dir and their total size.
+func dirUsage(dir string) (files int, size int64) {
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil //nolint:nilerr // Best-effort count.
+		}
+		if info, infoErr := d.Info(); infoErr == nil {
+			files++
+			size += info.Size()
+		}
+		return nil
+	})
+	return files, size
+}
+
+// isEvalOutputFile reports whether name is a harness artifact, including the
+// per-variant validation logs of parameterized tasks and the agent logs of
+// infra-failed agents in a fallback chain.
+func isEvalOutputFile(name string) bool {
+	if evalOutputFiles[name] {
+		return true
+	}
+	if strings.HasPrefix(name, "agent-") && strings.HasSuffix(name, ".log") {
+		return true
+	}
+	return strings.HasPrefix(name, "validation-") && strings.HasSuffix(name, ".log")
+}
+
 func ensureEvalTaskOutputPaths(outputDir, workspaceName string) (taskOutputDir, agentLogPath, validationLogPath string, err error) {
 	taskOutputDir = filepath.Join(outputDir, workspaceName)
 	if err := os.MkdirAll(taskOutputDir, 0o755); err != nil {
@@ -1712,25 +2803,55 @@ func ensureEvalTaskOutputPaths(outputDir, workspaceName string) (taskOutputDir,
 		nil
 }
 
-func applyAgentExecutionResult(result *EvalResult, agentResult agentExecutionResult, agentLogPath, workspaceDir string) {
+// applyAgentExecutionResult copies the agent run outcome and log-derived
+// behavior metrics onto result. relevantSkill is the task's declared skill,
+// if any, checked against the skills the agent used.
+func applyAgentExecutionResult(result *EvalResult, agentResult agentExecutionResult, agentLogPath, workspaceDir, relevantSkill string, timestamps *agentTimestampFormat, tokenPatterns []*regexp.Regexp) {
 	result.AgentTime = agentResult.totalTime
 	result.AgentTimedOut = agentResult.timedOut
+	result.AgentRunaway = agentResult.runaway
+	result.AgentRefused = agentResult.refusal != ""
+	result.AgentLogTruncated = agentResult.logTruncated
+	if info, err := os.Stat(agentLogPath); err == nil {
+		result.AgentLogBytes = info.Size()
+	}
 	result.QuotaRetries = agentResult.quotaRetries
 	result.InfraRetries = agentResult.infraRetries
 	result.AgentTimeoutRetries = agentResult.agentTimeoutRetries
+	result.RetryReasons = agentResult.retryReasons
 	result.QuotaExhausted = agentResult.quotaExhausted
 	result.InfraFailure = agentResult.infraFailure
 	result.FailureClass = agentResult.failureClass
 
-	metrics := parseAgentBehaviorMetrics(agentLogPath, workspaceDir)
+	// The agent log sits in the task's output directory, one level below
+	// the run directory.
+	runDir, err := filepath.Abs(filepath.Dir(filepath.Dir(agentLogPath)))
+	if err != nil {
+		runDir = ""
+	}
+	metrics := parseAgentBehaviorMetrics(agentLogPath, workspaceDir, runDir, timestamps, tokenPatterns)
+	result.ThinkingTime = metrics.ThinkingTime
+	result.ActingTime = metrics.ActingTime
+	result.TotalTokens = metrics.TotalTokens
+	result.TotalTokensConfident = metrics.TotalTokensConfident
 	result.SelfTestCommands = metrics.SelfTestCommands
 	result.SelfTestCommandsConfident = metrics.SelfTestCommandsConfident
 	result.ToolchainInstallAttempts = metrics.ToolchainInstallAttempts
 	result.OutOfWorkspaceReadAttempts = metrics.OutOfWorkspaceReads
 	result.OutOfWorkspaceReadsConfident = metrics.OutOfWorkspaceReadsConfident
 	result.ToolchainSearchAttempts = metrics.ToolchainSearchAttempts
+	result.EscapeAttempts = metrics.EscapeAttempts
 	result.SkillsUsed = metrics.SkillsUsed
 	result.SkillsUsageSignals = metrics.SkillsUsageSignals
+	if relevantSkill != "" {
+		result.RelevantSkill = relevantSkill
+		for _, name := range metrics.SkillNames {
+			if strings.EqualFold(name, relevantSkill) {
+				result.RelevantSkillUsed = true
+				break
+			}
+		}
+	}
 }
 
 func shouldSkipValidationForExternalFailure(result *EvalResult) bool {
@@ -1832,14 +2953,29 @@ func writeHiddenTestsIfNeeded(loader *task.Loader, t *task.Task, workspaceDir st
 	return writeTaskFilesToWorkspace(loader, t, workspaceDir, t.HiddenTestFiles())
 }
 
-func resolveValidationTimeout(timeout int) int {
+// resolveValidationTimeout returns a task's validation timeout: the task's
+// own timeout when set, otherwise timeout (the global or per-language
+// timeout), but never under two minutes.
+func resolveValidationTimeout(timeout, taskSeconds int) int {
+	if taskSeconds > 0 {
+		timeout = taskSeconds
+	}
 	if timeout < 120 {
 		return 120
 	}
 	return timeout
 }
 
-func buildValidationCommands(t *task.Task) (validationCmd, effectiveValidationCmd []string) {
+// validationVariant is one expanded validation run of a parameterized task.
+type validationVariant struct {
+	variant task.Variant
+	command []string
+}
+
+// buildValidationCommands returns the validation command override (nil when
+// the task default applies), the command actually executed, and for
+// parameterized tasks one expanded command per variant.
+func buildValidationCommands(t *task.Task) (validationCmd, effectiveValidationCmd []string, variants []validationVariant) {
 	if t.Language == task.TypeScript && len(t.HiddenTestFiles()) > 0 {
 		validationCmd = append([]string{}, t.ValidationCommand()...)
 		for _, filename := range t.HiddenTestFiles() {
@@ -1851,7 +2987,128 @@ func buildValidationCommands(t *task.Task) (validationCmd, effectiveValidationCm
 	if len(validationCmd) > 0 {
 		effectiveValidationCmd = validationCmd
 	}
-	return validationCmd, effectiveValidationCmd
+	if covered, ok := withCoverageFlags(t, effectiveValidationCmd); ok {
+		validationCmd = covered
+		effectiveValidationCmd = covered
+	}
+
+	// Assertion-only tasks have no command to vary; their assertions run
+	// once, without a container.
+	if t.AssertionOnly() {
+		return validationCmd, effectiveValidationCmd, nil
+	}
+
+	// Variant params are passed through `env` so task tests in any language
+	// can read them without the runner needing per-exec environment support.
+	for _, v := range t.Variants {
+		keys := make([]string, 0, len(v.Params))
+		for k := range v.Params {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		cmd := make([]string, 0, 1+len(keys)+len(effectiveValidationCmd))
+		cmd = append(cmd, "env")
+		for _, k := range keys {
+			cmd = append(cmd, k+"="+v.Params[k])
+		}
+		cmd = append(cmd, effectiveValidationCmd...)
+		variants = append(variants, validationVariant{variant: v, command: cmd})
+	}
+	return validationCmd, effectiveValidationCmd, variants
+}
+
+// variantValidationLogName returns the per-variant validation log file name.
+func variantValidationLogName(name string) string {
+	return "validation-" + name + ".log"
+}
+
+// runVariantValidations validates the same agent solution once per variant.
+// Each variant gets its own validation-<name>.log; validation.log mirrors the
+// first failing variant (or the last one when all pass) so resume detection
+// and existing log consumers keep working.
+func runVariantValidations(
+	ctx context.Context,
+	r *runner.Runner,
+	t *task.Task,
+	workspaceDir, validationLogPath string,
+	validationTimeout int,
+	variants []validationVariant,
+	result *EvalResult,
+) {
+	logDir := filepath.Dir(validationLogPath)
+	representativeLog := ""
+	result.Passed = true
+
+	for _, v := range variants {
+		variantLogPath := filepath.Join(logDir, variantValidationLogName(v.variant.Name))
+		containerLogPath := filepath.Join(logDir, containerLogName)
+		appendVariantHeader(containerLogPath, v.variant.Name)
+		var scratch EvalResult
+		session, duration, err := runValidationSession(ctx, r, t, workspaceDir, validationTimeout, v.command, containerLogPath)
+		scratch.ValidateTime = duration
+		if err != nil {
+			handleValidationRunError(&scratch, session, err, variantLogPath, v.command)
+		} else {
+			applyValidationSessionResult(&scratch, session)
+			applyCoverage(result, t, session)
+			writeValidationSessionLog(variantLogPath, v.command, session)
+		}
+
+		vr := VariantResult{
+			Name:     v.variant.Name,
+			Params:   v.variant.Params,
+			Passed:   scratch.Passed && scratch.Error == "",
+			Duration: duration,
+			Command:  v.command,
+			Error:    scratch.Error,
+		}
+		if scratch.FailureClass != "" && scratch.FailureClass != FailureClassNone {
+			vr.FailureClass = scratch.FailureClass
+		}
+		if mergeVariantResult(result, vr, &scratch) || result.Passed {
+			representativeLog = variantLogPath
+		}
+	}
+
+	if data, err := os.ReadFile(representativeLog); err == nil {
+		_ = os.WriteFile(validationLogPath, data, 0o644)
+	}
+}
+
+// mergeVariantResult adds one variant's outcome to result and reports
+// whether it is the task's first failing variant, whose failure class (an
+// OOM kill, say, which sets no error) and error the task takes on.
+func mergeVariantResult(result *EvalResult, vr VariantResult, scratch *EvalResult) bool {
+	result.Variants = append(result.Variants, vr)
+	result.ValidateTime += vr.Duration
+	result.Attempts += scratch.Attempts
+	for phase, seconds := range scratch.PhaseTimes {
+		addPhaseSeconds(result, phase, seconds)
+	}
+	if vr.Passed || !result.Passed {
+		return false
+	}
+	result.Passed = false
+	if vr.FailureClass != "" {
+		result.FailureClass = vr.FailureClass
+	}
+	if vr.Error != "" {
+		result.Error = fmt.Sprintf("variant %s: %s", vr.Name, vr.Error)
+		result.FailurePhase = scratch.FailurePhase
+		result.InfraFailure = scratch.InfraFailure
+	}
+	return true
+}
+
+// appendVariantHeader marks the start of a variant's validation in the
+// task's container.log, which all variants share.
+func appendVariantHeader(containerLogPath, name string) {
+	f, err := os.OpenFile(containerLogPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+	_, _ = fmt.Fprintf(f, "######## variant %s ########\n\n", name)
 }
 
 func runValidationSession(
@@ -1861,6 +3118,7 @@ func runValidationSession(
 	workspaceDir string,
 	validationTimeout int,
 	validationCmd []string,
+	containerLogPath string,
 ) (*resultpkg.Session, float64, error) {
 	start := time.Now()
 	session, err := r.Run(ctx, runner.RunOptions{
@@ -1869,6 +3127,8 @@ func runValidationSession(
 		Timeout:           validationTimeout,
 		MaxAttempts:       1,
 		ValidationCommand: validationCmd,
+		Quiet:             evalProgress || evalJSONLogs,
+		ContainerLogPath:  containerLogPath,
 	})
 	return session, time.Since(start).Seconds(), err
 }
@@ -1882,6 +3142,7 @@ func handleValidationRunError(
 ) {
 	applyValidationSessionResult(result, session)
 	rawOutput, exitCode, duration := validationErrorEvidence(session, result.ValidateTime)
+	rawOutput = withAttemptLines(rawOutput, result.ValidationAttempts)
 	timedOut := strings.Contains(strings.ToLower(runErr.Error()), "timed out")
 
 	writeValidationLog(
@@ -1895,6 +3156,10 @@ func handleValidationRunError(
 	)
 
 	result.Error = runErr.Error()
+	var phaseErr *runner.PhaseError
+	if errors.As(runErr, &phaseErr) {
+		result.FailurePhase = phaseErr.Phase
+	}
 	if isValidationInfraError(runErr) {
 		result.FailureClass = FailureClassInfra
 		result.InfraFailure = true
@@ -1911,6 +3176,12 @@ func isValidationInfraError(runErr error) bool {
 	if runErr == nil {
 		return false
 	}
+	// Failures before the tests ran are always infrastructure; exec failures
+	// only when the error itself points at Docker or the network.
+	var phaseErr *runner.PhaseError
+	if errors.As(runErr, &phaseErr) && phaseErr.IsSetup() {
+		return true
+	}
 	lower := strings.ToLower(runErr.Error())
 	for _, pattern := range validationInfraErrorPatterns {
 		if strings.Contains(lower, pattern) {
@@ -1926,6 +3197,14 @@ func applyValidationSessionResult(result *EvalResult, session *resultpkg.Session
 	}
 	result.Passed = session.Passed()
 	result.Attempts = len(session.Attempts)
+	if session.Status == resultpkg.StatusOOM {
+		result.FailureClass = FailureClassValidationOOM
+	}
+	result.ValidationAttempts = validationAttempts(session)
+	if last := session.LastAttempt(); last != nil && last.Tests != nil {
+		result.Tests = last.Tests
+	}
+	addSessionPhaseTimes(result, session)
 }
 
 func validationErrorEvidence(session *resultpkg.Session, validateSeconds float64) (rawOutput string, exitCode int, duration time.Duration) {
@@ -1944,7 +3223,7 @@ func writeValidationSessionLog(validationLogPath string, effectiveValidationCmd
 	}
 	writeValidationLog(
 		validationLogPath,
-		rawOutput,
+		withAttemptLines(rawOutput, validationAttempts(session)),
 		effectiveValidationCmd,
 		exitCode,
 		duration,
@@ -1958,7 +3237,7 @@ func lastSessionAttempt(session *resultpkg.Session) (rawOutput string, exitCode
 		return "", 0, 0, false
 	}
 	last := session.Attempts[len(session.Attempts)-1]
-	return last.RawOutput, last.ExitCode, last.Duration, true
+	return last.RawOutput(), last.ExitCode, last.Duration, true
 }
 
 // finalizeEvalResult ensures status/score fields are populated for all return paths.
@@ -1981,18 +3260,63 @@ func finalizeEvalResult(result *EvalResult, start time.Time, weight task.Weight)
 	}
 	result.Status = task.DetermineStatus(result.Passed, result.AgentTimedOut, result.Error)
 	result.WeightedScore = task.ScoreResult(result.Passed, result.AgentTimedOut, result.Error, weight)
+	result.TimeoutOutcome = timeoutOutcome(*result)
+}
+
+// timeoutOutcome classifies a timed-out agent run by whether it left any
+// work and whether that work passed validation.
+func timeoutOutcome(r EvalResult) TimeoutOutcome {
+	switch {
+	case !r.AgentTimedOut:
+		return ""
+	case r.Passed:
+		return TimeoutPartialPass
+	case r.stubsUntouched:
+		return TimeoutNoWork
+	default:
+		return TimeoutPartialFail
+	}
+}
+
+// stubsUntouched reports whether every stub file in workspaceDir still
+// matches the task's original. A missing stub counts as an edit.
+func stubsUntouched(loader *task.Loader, t *task.Task, workspaceDir string) bool {
+	for _, filename := range t.Files.Stub {
+		want, err := loader.ReadTaskFile(t, filename)
+		if err != nil {
+			return false
+		}
+		got, err := os.ReadFile(filepath.Join(workspaceDir, task.StripTxtExtension(filename)))
+		if err != nil || !bytes.Equal(got, want) {
+			return false
+		}
+	}
+	return true
 }
 
 // agentExecutionResult holds the outcome of agent execution with retries.
 type agentExecutionResult struct {
 	totalTime           float64
 	timedOut            bool
+	runaway             bool
+	refusal             string // matched refusal phrase, when the model declined the task
 	quotaRetries        int
 	quotaExhausted      bool
 	infraRetries        int
 	infraFailure        bool // true when agent produced no output after all retries
 	agentTimeoutRetries int  // retries triggered purely by wall-clock agent timeout
+	logTruncated        bool // agent.log hit --agent-log-max-bytes in some attempt
+	exitCode            int  // last attempt's exit code when trust_exit_code failed the task
 	failureClass        FailureClass
+	retryReasons        map[string]int // retry count per "<type>: <reason>"
+}
+
+// addRetryReason records one retry under reason.
+func (r *agentExecutionResult) addRetryReason(reason string) {
+	if r.retryReasons == nil {
+		r.retryReasons = make(map[string]int)
+	}
+	r.retryReasons[reason]++
 }
 
 // executeAgentWithRetries runs the agent command with quota-aware retry logic.
@@ -2020,8 +3344,9 @@ func executeAgentWithRetries(
 		attemptResult := runAgentAttempt(ctx, agentCfg, prompt, model, workspaceDir, agentLogPath, agentTimeout, agent, localAttempts)
 		result.totalTime += attemptResult.duration
 		result.timedOut = attemptResult.timedOut
+		result.logTruncated = result.logTruncated || attemptResult.logTruncated
 
-		decision := classifyAttempt(attemptResult, agentLogPath, workspaceDir, workspaceReadyAt,
+		decision := classifyAttempt(attemptResult, agentCfg.TrustExitCode, agentLogPath, workspaceDir, workspaceReadyAt,
 			&quotaAttempts, &infraAttempts, &agentTimeoutAttempts, &result)
 		if decision.done {
 			break
@@ -2041,11 +3366,11 @@ func waitBeforeRetry(ctx context.Context, taskID string, localAttempts int, last
 		var delay time.Duration
 		switch lastRetryType {
 		case "infra":
-			delay = getInfraRetryDelay(localAttempts)
+			delay = jitterDelay(getInfraRetryDelay(localAttempts), retryJitter(), rand.Float64())
 		case "agent_timeout":
 			delay = agentTimeoutRetryDelay1
 		default:
-			delay = getRetryDelay(localAttempts)
+			delay = jitterDelay(getRetryDelay(localAttempts), retryJitter(), rand.Float64())
 		}
 		logger.Info("retrying agent execution",
 			"task", taskID,
@@ -2071,11 +3396,19 @@ type attemptDecision struct {
 // retry type to use for the next iteration's backoff.
 func classifyAttempt(
 	attempt agentAttemptResult,
+	trustExitCode bool,
 	agentLogPath, workspaceDir string,
 	workspaceReadyAt time.Time,
 	quotaAttempts, infraAttempts, agentTimeoutAttempts *int,
 	result *agentExecutionResult,
 ) attemptDecision {
+	// Runaway agents are killed and failed outright; a retry would loop again.
+	if attempt.runaway {
+		result.runaway = true
+		result.failureClass = FailureClassRunaway
+		return attemptDecision{done: true}
+	}
+
 	// Non-recoverable auth errors first (no retries).
 	if detectAuthError(agentLogPath) {
 		result.failureClass = FailureClassAuth
@@ -2083,13 +3416,22 @@ func classifyAttempt(
 	}
 
 	// Quota/provider errors.
-	if hasError, isRecoverable := detectQuotaError(agentLogPath); hasError {
-		return classifyQuota(isRecoverable, quotaAttempts, result)
+	if hasError, isRecoverable, pattern := detectQuotaError(agentLogPath); hasError {
+		return classifyQuota(isRecoverable, "quota: "+pattern, quotaAttempts, result)
+	}
+
+	// Refusals: the model declined the task and wrote no code, so a retry
+	// would only ask again. An agent that edited files merely apologized
+	// for something along the way.
+	if refused, pattern := detectRefusal(agentLogPath); refused && !hasModifiedFiles(workspaceDir, workspaceReadyAt) {
+		result.refusal = pattern
+		result.failureClass = FailureClassRefusal
+		return attemptDecision{done: true}
 	}
 
 	// Infra failures (empty/near-empty agent log).
 	if isInfraFailure(agentLogPath, workspaceDir, workspaceReadyAt) {
-		return classifyInfra(infraAttempts, result)
+		return classifyInfra("infra: empty agent output", infraAttempts, result)
 	}
 
 	// Wall-clock agent timeout with meaningful output — treated as an
@@ -2101,6 +3443,7 @@ func classifyAttempt(
 		if *agentTimeoutAttempts < agentTimeoutMaxRetries {
 			*agentTimeoutAttempts++
 			result.agentTimeoutRetries = *agentTimeoutAttempts
+			result.addRetryReason("agent_timeout: wall-clock timeout")
 			return attemptDecision{retryType: "agent_timeout"}
 		}
 		result.infraFailure = true
@@ -2108,6 +3451,22 @@ func classifyAttempt(
 		return attemptDecision{done: true}
 	}
 
+	// A non-zero exit from an agent configured with trust_exit_code means
+	// the agent failed, whatever its log shows. It is retried on the infra
+	// schedule in case the failure was transient; once retries run out the
+	// task is a scored agent failure, not a resumable infra skip.
+	if trustExitCode && attempt.exitCode != 0 {
+		*infraAttempts++
+		result.infraRetries = *infraAttempts
+		if *infraAttempts >= infraMaxRetries() {
+			result.exitCode = attempt.exitCode
+			result.failureClass = FailureClassAgentExit
+			return attemptDecision{done: true}
+		}
+		result.addRetryReason(fmt.Sprintf("infra: agent exit code %d", attempt.exitCode))
+		return attemptDecision{retryType: "infra"}
+	}
+
 	// Success path.
 	if result.failureClass == "" {
 		result.failureClass = FailureClassNone
@@ -2115,7 +3474,9 @@ func classifyAttempt(
 	return attemptDecision{done: true}
 }
 
-func classifyQuota(isRecoverable bool, quotaAttempts *int, result *agentExecutionResult) attemptDecision {
+// classifyQuota decides a quota error's retry, recording reason only when
+// there is one.
+func classifyQuota(isRecoverable bool, reason string, quotaAttempts *int, result *agentExecutionResult) attemptDecision {
 	if !isRecoverable {
 		result.quotaExhausted = true
 		result.failureClass = FailureClassQuotaExhausted
@@ -2124,29 +3485,36 @@ func classifyQuota(isRecoverable bool, quotaAttempts *int, result *agentExecutio
 	*quotaAttempts++
 	result.quotaRetries = *quotaAttempts
 	result.failureClass = FailureClassQuotaRecoverable
-	if *quotaAttempts >= quotaMaxRetries {
+	if *quotaAttempts >= quotaMaxRetries() {
 		result.quotaExhausted = true
 		result.failureClass = FailureClassQuotaExhausted
 		return attemptDecision{done: true}
 	}
+	result.addRetryReason(reason)
 	return attemptDecision{retryType: "quota"}
 }
 
-func classifyInfra(infraAttempts *int, result *agentExecutionResult) attemptDecision {
+// classifyInfra decides an infra failure's retry, recording reason only when
+// there is one.
+func classifyInfra(reason string, infraAttempts *int, result *agentExecutionResult) attemptDecision {
 	*infraAttempts++
 	result.infraRetries = *infraAttempts
-	if *infraAttempts >= infraMaxRetries {
+	if *infraAttempts >= infraMaxRetries() {
 		result.infraFailure = true
 		result.failureClass = FailureClassInfra
 		return attemptDecision{done: true}
 	}
+	result.addRetryReason(reason)
 	return attemptDecision{retryType: "infra"}
 }
 
 // agentAttemptResult holds the outcome of a single agent attempt.
 type agentAttemptResult struct {
-	duration float64
-	timedOut bool
+	duration     float64
+	timedOut     bool
+	runaway      bool // killed by --agent-runaway-bytes
+	logTruncated bool // output past --agent-log-max-bytes was dropped
+	exitCode     int  // -1 when the agent could not be started or waited for
 }
 
 // runAgentAttempt executes a single agent command attempt.
@@ -2162,8 +3530,11 @@ func runAgentAttempt(
 
 	agentCtx, cancel := context.WithTimeout(ctx, agentTimeout)
 	defer cancel()
+	agentCtx, killRunaway := context.WithCancel(agentCtx)
+	defer killRunaway()
 
-	cmd := buildAgentCommand(agentCtx, agentCfg, prompt, model, evalReasoning, evalDisableMCP, evalUseMCPTools, agent)
+	run := runSettingsFrom(ctx)
+	cmd := buildAgentCommand(agentCtx, agentCfg, prompt, model, run.reasoning, evalSystemPrompt, evalDisableMCP, run.useMCPTools, agent)
 	cmd.Dir = workspaceDir
 
 	// Use /dev/null for stdin to prevent TTY issues with agents that use Ink/React
@@ -2178,9 +3549,13 @@ func runAgentAttempt(
 
 	// Open log file: create on first attempt, append on retry
 	logFile := openAgentLogFile(agentLogPath, attempt)
+	var runaway *runawayWriter
+	var capped *cappedLogWriter
 	if logFile != nil {
-		cmd.Stdout = logFile
-		cmd.Stderr = logFile
+		capped = newCappedLogWriter(logFile, evalAgentLogMax)
+		runaway = newRunawayWriter(capped, evalAgentRunaway, workspaceDir, killRunaway)
+		cmd.Stdout = runaway
+		cmd.Stderr = runaway
 		defer func() {
 			_ = logFile.Sync()
 			_ = logFile.Close()
@@ -2201,25 +3576,69 @@ func runAgentAttempt(
 			evalSandboxSharedRO,
 			evalSandboxDenylist,
 		)
+		writeSandboxAudit(sandboxAuditPath(agentLogPath), cmd.Args[1:],
+			extraDirs, evalSandboxSharedRW, evalSandboxSharedRO, evalSandboxDenylist)
 	}
 
 	// Run agent in its own process group so we can kill the entire tree on
 	// timeout or interrupt, preventing orphaned child processes.
 	setupProcessGroup(cmd)
-
-	// Run agent
+	// Output is copied through a pipe, so don't let a detached child that
+	// keeps it open block Wait forever.
+	cmd.WaitDelay = agentOutputWaitDelay
+
+	// Run agent. When a grace period is configured, the process group gets
+	// SIGTERM that many seconds before the deadline so cooperative agents can
+	// flush partial work; the context deadline still delivers SIGKILL.
+	grace := agentGracePeriod(agentTimeout, evalTimeoutGrace)
+	var graceSignaled atomic.Bool
 	agentStart := time.Now()
-	agentErr := cmd.Run()
+	agentErr := cmd.Start()
+	if agentErr == nil {
+		if grace > 0 {
+			deadline, _ := agentCtx.Deadline()
+			graceTimer := time.AfterFunc(time.Until(deadline.Add(-grace)), func() {
+				logger.Debug("agent nearing timeout, sending SIGTERM", "grace", grace)
+				// Set before signaling, so an agent that exits on the signal
+				// is seen as timed out; cleared again when nothing was sent
+				// (always on Windows), since such an agent finished on its own.
+				graceSignaled.Store(true)
+				signaled, err := terminateProcessGroup(cmd)
+				if err != nil {
+					logger.Debug("failed to signal agent process group", "error", err)
+				}
+				if !signaled {
+					graceSignaled.Store(false)
+				}
+			})
+			defer graceTimer.Stop()
+		}
+		agentErr = cmd.Wait()
+	}
 	result.duration = time.Since(agentStart).Seconds()
 
-	// Check for timeout
-	if errors.Is(agentCtx.Err(), context.DeadlineExceeded) {
+	// Check for timeout. An agent that exits on its own after the grace
+	// SIGTERM still ran out of time, so it is classified the same way.
+	if errors.Is(agentCtx.Err(), context.DeadlineExceeded) || graceSignaled.Load() {
 		result.timedOut = true
 		logger.Debug("agent timed out", "timeout", agentTimeout)
-		writeAgentTimeoutFooter(logFile, attempt, agentTimeout, time.Since(agentStart))
+		writeAgentTimeoutFooter(logFile, attempt, agentTimeout, grace, time.Since(agentStart))
+	}
+	if capped != nil && capped.truncated {
+		result.logTruncated = true
+	}
+	if runaway != nil && runaway.tripped.Load() {
+		result.runaway = true
+		logger.Debug("agent killed as runaway", "limit", evalAgentRunaway)
+		_, _ = fmt.Fprintf(logFile, "\n\nHARNESS: agent killed as runaway (attempt=%d output_bytes=%d without a file edit)\n", attempt, evalAgentRunaway)
 	}
 	if agentErr != nil {
 		logger.Debug("agent returned error", "error", agentErr)
+		result.exitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(agentErr, &exitErr) {
+			result.exitCode = exitErr.ExitCode()
+		}
 	}
 
 	return result
@@ -2243,22 +3662,128 @@ func openAgentLogFile(agentLogPath string, attempt int) *os.File {
 	if err != nil {
 		return nil
 	}
-	return logFile
+	return logFile
+}
+
+// defaultAgentLogMaxBytes caps agent.log unless --agent-log-max-bytes says
+// otherwise. Real agent logs are a few MB at most.
+const defaultAgentLogMaxBytes = 256 << 20
+
+// resolveAgentLogMax applies [harness] max_agent_log_mb unless
+// --agent-log-max-bytes was given explicitly.
+func resolveAgentLogMax(flagSet bool, flagValue int64, cfg *config.Config) int64 {
+	if flagSet || cfg == nil || cfg.Harness.MaxAgentLogMB <= 0 {
+		return flagValue
+	}
+	return int64(cfg.Harness.MaxAgentLogMB) << 20
+}
+
+// agentOutputWaitDelay bounds how long Wait keeps copying agent output after
+// the agent process has exited or been killed.
+const agentOutputWaitDelay = 10 * time.Second
+
+// cappedLogWriter stops writing to the agent log once it reaches limit bytes,
+// leaving a truncation marker. Later output is discarded without error so a
+// chatty agent keeps running normally. The limit counts what the file held
+// before the attempt, so retries share one budget. The log detectors
+// (detectQuotaError, isInfraFailure, ...) read the truncated file, so an error
+// the agent printed after the cutoff goes unseen.
+type cappedLogWriter struct {
+	file      *os.File
+	limit     int64
+	written   int64
+	truncated bool
+}
+
+// newCappedLogWriter wraps logFile; limit <= 0 disables the cap.
+func newCappedLogWriter(logFile *os.File, limit int64) *cappedLogWriter {
+	w := &cappedLogWriter{file: logFile, limit: limit}
+	if info, err := logFile.Stat(); err == nil {
+		w.written = info.Size()
+	}
+	return w
+}
+
+func (w *cappedLogWriter) Write(p []byte) (int, error) {
+	if w.limit <= 0 {
+		return w.file.Write(p)
+	}
+	if w.truncated {
+		return len(p), nil
+	}
+	if remaining := w.limit - w.written; int64(len(p)) > remaining {
+		if remaining > 0 {
+			_, _ = w.file.Write(p[:remaining])
+		}
+		_, _ = fmt.Fprintf(w.file, "\nHARNESS: log truncated at %d bytes\n", w.limit)
+		w.truncated = true
+		return len(p), nil
+	}
+	n, err := w.file.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+// writeAgentTimeoutFooter appends deterministic timeout evidence to the agent log.
+func writeAgentTimeoutFooter(logFile *os.File, attempt int, timeout, grace, runDuration time.Duration) {
+	if logFile == nil {
+		return
+	}
+	var graceField string
+	if grace > 0 {
+		graceField = fmt.Sprintf(" grace_seconds=%.3f", grace.Seconds())
+	}
+	_, _ = fmt.Fprintf(
+		logFile,
+		"\n\nHARNESS: agent timed out (attempt=%d timeout_seconds=%.3f duration_seconds=%.3f%s)\n",
+		attempt+1,
+		timeout.Seconds(),
+		runDuration.Seconds(),
+		graceField,
+	)
+	_ = logFile.Sync()
+}
+
+// checkDiskSpace returns an error when the filesystem holding dir has less
+// free space than the configured minimum. Checks are skipped when the
+// threshold is disabled or free space cannot be determined.
+func checkDiskSpace(dir string) error {
+	minMB := evalMinFreeDiskMB
+	if minMB <= 0 && cfg != nil {
+		minMB = cfg.Harness.MinFreeDiskMB
+	}
+	if minMB <= 0 {
+		return nil
+	}
+	free, err := freeDiskBytes(dir)
+	if err != nil {
+		logger.Debug("skipping disk space check", "dir", dir, "error", err)
+		return nil
+	}
+	return diskSpaceError(dir, free, minMB)
+}
+
+// diskSpaceError reports whether free bytes fall below the minMB threshold.
+func diskSpaceError(dir string, free uint64, minMB int) error {
+	freeMB := free / (1024 * 1024)
+	if freeMB >= uint64(minMB) { //nolint:gosec // minMB is positive.
+		return nil
+	}
+	return fmt.Errorf("insufficient disk space: %s has %d MB free (minimum %d MB)", dir, freeMB, minMB)
 }
 
-// writeAgentTimeoutFooter appends deterministic timeout evidence to the agent log.
-func writeAgentTimeoutFooter(logFile *os.File, attempt int, timeout, runDuration time.Duration) {
-	if logFile == nil {
-		return
+// agentGracePeriod returns the SIGTERM lead time before the agent deadline.
+// It is zero (disabled) when unset or when it would not leave the agent any
+// time to run before being asked to stop.
+func agentGracePeriod(agentTimeout time.Duration, graceSeconds int) time.Duration {
+	if graceSeconds <= 0 {
+		return 0
 	}
-	_, _ = fmt.Fprintf(
-		logFile,
-		"\n\nHARNESS: agent timed out (attempt=%d timeout_seconds=%.3f duration_seconds=%.3f)\n",
-		attempt+1,
-		timeout.Seconds(),
-		runDuration.Seconds(),
-	)
-	_ = logFile.Sync()
+	grace := time.Duration(graceSeconds) * time.Second
+	if grace >= agentTimeout {
+		return 0
+	}
+	return grace
 }
 
 // writeValidationLog persists validation output with a machine-readable footer.
@@ -2334,6 +3859,9 @@ func toolchainInfo(lang task.Language) string {
 	case task.Kotlin:
 		return "Kotlin (JDK 21, Gradle 8.5)"
 	default:
+		if cfg != nil && cfg.LanguageConfig(string(lang)).ToolchainInfo != "" {
+			return cfg.LanguageConfig(string(lang)).ToolchainInfo
+		}
 		return string(lang)
 	}
 }
@@ -2386,17 +3914,42 @@ func buildAgentPrompt(t *task.Task, useMCPTools, useSkills bool, mcpPrompt strin
 6. Ensure thread-safety if the tests use concurrent operations.`
 	}
 
+	editableFilesRule := ""
+	if len(t.EditableFiles) > 0 {
+		editableFilesRule = " and these additional editable files: " + strings.Join(t.EditableFiles, ", ")
+	}
+	newFilesRule := "You may add new helper source files if needed."
+	if t.NoNewFiles {
+		newFilesRule = "Do NOT create new files; creating files is treated as a protected-file modification."
+		if len(t.EditableFiles) > 0 {
+			newFilesRule = "Do NOT create new files other than the additional editable files listed above."
+		}
+	}
+
+	contextFilesLine := ""
+	if len(t.ContextFiles) > 0 {
+		contextFiles := make([]string, 0, len(t.ContextFiles))
+		for _, f := range t.ContextFiles {
+			contextFiles = append(contextFiles, task.StripTxtExtension(f))
+		}
+		contextFilesLine = "\n- Context files:       " + strings.Join(contextFiles, ", ") + " (read-only reference material)"
+	}
+	promptHintLine := ""
+	if t.PromptHint != "" {
+		promptHintLine = "\n- Hint:        " + t.PromptHint
+	}
+
 	prompt := fmt.Sprintf(`You are solving a coding task called "%s".
 
 TASK INFO:
 - Language:    %s
 - Tier:        %s
 - Difficulty:  %s
-- Description: %s
+- Description: %s%s
 
 FILES TO READ:
 - Stub/solution files: %s
-- Test files:          %s
+- Test files:          %s%s
 
 ENVIRONMENT:
 - Final validation runs automatically in a Docker container.
@@ -2411,14 +3964,15 @@ IMPORTANT:
 - There may be hidden tests that check additional edge cases for the same public API.%s%s
 
 RULES:
-- ONLY edit the stub/solution source file(s).
+- ONLY edit the stub/solution source file(s)%s.
 - Do NOT modify test files or support files.
-- You may add new helper source files if needed.
+- %s
 - Evaluation fails if you modify protected files.
 - Do NOT navigate to parent directories or read files outside the workspace.%s%s`,
-		t.Name, t.Language, t.Tier, t.Difficulty, t.Description,
-		strings.Join(stubFiles, ", "), strings.Join(testFiles, ", "),
-		toolchainInfo(t.Language), mcpEnvironmentLine, skillsEnvironmentLine, taskInstructions, mcpImportantLine, skillsImportantLine, mcpRuleLine, skillsRuleLine)
+		t.Name, t.Language, t.Tier, t.Difficulty, t.Description, promptHintLine,
+		strings.Join(stubFiles, ", "), strings.Join(testFiles, ", "), contextFilesLine,
+		toolchainInfo(t.Language), mcpEnvironmentLine, skillsEnvironmentLine, taskInstructions, mcpImportantLine, skillsImportantLine,
+		editableFilesRule, newFilesRule, mcpRuleLine, skillsRuleLine)
 
 	return prompt
 }
@@ -2426,6 +3980,9 @@ RULES:
 func detectModifiedTaskFiles(loader *task.Loader, t *task.Task, workspaceDir string) ([]string, error) {
 	var modified []string
 	for _, filename := range append(append([]string{}, t.Files.Test...), t.Files.Support...) {
+		if t.IsEditable(task.StripTxtExtension(filename)) || t.IsRegenerable(filename) {
+			continue
+		}
 		want, err := loader.ReadTaskFile(t, filename)
 		if err != nil {
 			return nil, fmt.Errorf("reading canonical %s: %w", filename, err)
@@ -2441,9 +3998,89 @@ func detectModifiedTaskFiles(loader *task.Loader, t *task.Task, workspaceDir str
 			modified = append(modified, task.StripTxtExtension(filename))
 		}
 	}
+
+	if t.NoNewFiles {
+		created, err := detectDisallowedNewFiles(t, workspaceDir)
+		if err != nil {
+			return nil, err
+		}
+		modified = append(modified, created...)
+	}
 	return modified, nil
 }
 
+// newFileIgnoredDirs lists build/dependency directories that toolchains
+// create while the agent self-tests. Their contents never count as new files.
+// Hidden directories (.gradle, .dart_tool, .zig-cache, .agents, ...) are
+// skipped as well.
+var newFileIgnoredDirs = map[string]bool{
+	"node_modules": true,
+	"target":       true,
+	"build":        true,
+	"zig-out":      true,
+}
+
+// newFileIgnoredLockfiles lists lockfiles and checksum files that
+// toolchains write when the agent builds or runs the tests. They never
+// count as new files, wherever they appear.
+var newFileIgnoredLockfiles = map[string]bool{
+	"Cargo.lock":        true,
+	"go.sum":            true,
+	"go.work.sum":       true,
+	"pubspec.lock":      true,
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"bun.lockb":         true,
+	"deno.lock":         true,
+	"Gemfile.lock":      true,
+	"composer.lock":     true,
+	"poetry.lock":       true,
+	"uv.lock":           true,
+	"Package.resolved":  true,
+	"mix.lock":          true,
+	"gradle.lockfile":   true,
+}
+
+// detectDisallowedNewFiles returns workspace files the agent created that are
+// neither task files nor allowed by the task's editable_files.
+func detectDisallowedNewFiles(t *task.Task, workspaceDir string) ([]string, error) {
+	known := make(map[string]bool)
+	for _, f := range t.AllFiles() {
+		known[task.StripTxtExtension(f)] = true
+	}
+
+	var created []string
+	err := filepath.WalkDir(workspaceDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == workspaceDir {
+			return nil
+		}
+		if d.IsDir() {
+			if strings.HasPrefix(d.Name(), ".") || newFileIgnoredDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(workspaceDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if known[rel] || t.IsEditable(rel) || newFileIgnoredLockfiles[d.Name()] {
+			return nil
+		}
+		created = append(created, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scanning workspace for new files: %w", err)
+	}
+	return created, nil
+}
+
 type integrityArtifactReport struct {
 	Task      string                  `json:"task"`
 	Timestamp string                  `json:"timestamp"`
@@ -2567,7 +4204,7 @@ func writeIntegrityViolationArtifacts(
 	if err != nil {
 		return fmt.Errorf("marshal integrity report: %w", err)
 	}
-	if err := os.WriteFile(filepath.Join(taskOutputDir, "integrity.json"), data, 0o644); err != nil {
+	if err := writeFileAtomic(filepath.Join(taskOutputDir, "integrity.json"), data, 0o644); err != nil {
 		return fmt.Errorf("writing integrity report: %w", err)
 	}
 
@@ -2623,8 +4260,19 @@ func writeTaskFilesToWorkspace(loader *task.Loader, t *task.Task, workspaceDir s
 }
 
 // copyDirContents recursively copies all files and directories from src to dst.
-// It preserves directory structure and file permissions.
+// It preserves directory structure and file permissions. Directories named in
+// copyIgnoreDirs (by default .git and .hg) are skipped.
 func copyDirContents(src, dst string) error {
+	return copyDirContentsSkipping(src, dst, nil)
+}
+
+// copyDirContentsSkipping is copyDirContents, also skipping the entries
+// directly under src whose names skipTop reports.
+func copyDirContentsSkipping(src, dst string, skipTop func(name string) bool) error {
+	ignored := make(map[string]bool)
+	for _, name := range copyIgnoreDirs() {
+		ignored[name] = true
+	}
 	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -2635,8 +4283,19 @@ func copyDirContents(src, dst string) error {
 			return err
 		}
 		destPath := filepath.Join(dst, rel)
+		if skipTop != nil && path != src && filepath.Dir(path) == src && skipTop(d.Name()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
 
 		if d.IsDir() {
+			// VCS metadata left by agents would otherwise end up in the
+			// validated workspace and make outputs differ between agents.
+			if path != src && ignored[d.Name()] {
+				return filepath.SkipDir
+			}
 			return os.MkdirAll(destPath, 0755)
 		}
 
@@ -2661,16 +4320,49 @@ func copyDirContents(src, dst string) error {
 	})
 }
 
+// infraStopThreshold returns how many consecutive infra-failed tasks stop
+// an eval, from harness.consecutive_infra_stop_threshold (0 = never).
+func infraStopThreshold() int {
+	if cfg != nil {
+		return cfg.Harness.ConsecutiveInfraStopThreshold
+	}
+	return config.Default.Harness.ConsecutiveInfraStopThreshold
+}
+
+// copyIgnoreDirs returns the directory names copyDirContents skips, from
+// harness.copy_ignore_dirs.
+func copyIgnoreDirs() []string {
+	if cfg != nil {
+		return cfg.Harness.CopyIgnoreDirs
+	}
+	return config.Default.Harness.CopyIgnoreDirs
+}
+
 // buildAgentCommand creates an exec.Cmd for the given agent configuration.
 // It handles prompt placeholder substitution, model flag positioning, reasoning flag, and environment variables.
+// A system prompt is passed via SystemPromptFlag; agents without one get it prepended to the task prompt.
 // For OpenCode, disableMCP disables MCP tools and useMCPTools raises the MCP request timeout.
+// Agents with a ShellCommand run it via sh -c instead (see resolveShellCommand).
 func buildAgentCommand(
 	ctx context.Context,
 	agentCfg *config.AgentConfig,
-	prompt, model, reasoning string,
+	prompt, model, reasoning, systemPrompt string,
 	disableMCP, useMCPTools bool,
 	agentName string,
 ) *exec.Cmd {
+	fileEnv, _ := agentFileEnv(agentCfg) // Checked before the run starts.
+	if agentCfg.ShellCommand != "" {
+		if systemPrompt != "" {
+			prompt = systemPrompt + "\n\n" + prompt
+		}
+		if agentCfg.PromptPrefix != "" {
+			prompt = agentCfg.PromptPrefix + " " + prompt
+		}
+		cmd := exec.CommandContext(ctx, "sh", "-c", resolveShellCommand(agentCfg.ShellCommand, prompt, model, reasoning))
+		cmd.Env = buildAgentEnv(agentCfg.Env, fileEnv, disableMCP, useMCPTools, agentName)
+		return cmd
+	}
+
 	var args []string
 
 	// Determine model flag position (default to "before")
@@ -2709,6 +4401,19 @@ func buildAgentCommand(
 		}
 	}
 
+	// Add system prompt as a dedicated flag, or fall back to prepending it.
+	// If SystemPromptFlag contains {value}, substitute it; otherwise append as separate arg
+	if systemPrompt != "" {
+		switch {
+		case agentCfg.SystemPromptFlag == "":
+			prompt = systemPrompt + "\n\n" + prompt
+		case strings.Contains(agentCfg.SystemPromptFlag, "{value}"):
+			args = append(args, strings.ReplaceAll(agentCfg.SystemPromptFlag, "{value}", systemPrompt))
+		default:
+			args = append(args, agentCfg.SystemPromptFlag, systemPrompt)
+		}
+	}
+
 	// Apply prompt prefix if configured (e.g., "ulw" for OMO ultrawork mode).
 	if agentCfg.PromptPrefix != "" {
 		prompt = agentCfg.PromptPrefix + " " + prompt
@@ -2748,7 +4453,7 @@ func buildAgentCommand(
 	}
 
 	cmd := exec.CommandContext(ctx, agentCfg.Command, args...)
-	cmd.Env = buildAgentEnv(agentCfg.Env, disableMCP, useMCPTools, agentName)
+	cmd.Env = buildAgentEnv(agentCfg.Env, fileEnv, disableMCP, useMCPTools, agentName)
 
 	return cmd
 }
@@ -2756,7 +4461,8 @@ func buildAgentCommand(
 // wrapCommandWithSandbox wraps an exec.Cmd in a bubblewrap sandbox.
 // The sandbox restricts filesystem access so the agent can only write to the
 // workspace directory and /tmp. The rest of the filesystem (including $HOME)
-// is mounted read-only. Network access is preserved for LLM API calls.
+// is mounted read-only. Network access is preserved for LLM API calls unless
+// denied by --no-network or [sandbox] allow_network = false.
 func wrapCommandWithSandbox(
 	ctx context.Context,
 	cmd *exec.Cmd,
@@ -2765,15 +4471,17 @@ func wrapCommandWithSandbox(
 	bwrapArgs := buildSandboxArgs(
 		cmd.Dir,
 		cmd.Path,
+		sandboxAllowNetwork(),
 		extraWritableDirs,
 		sharedReadWriteDirs,
 		sharedReadOnlyDirs,
 		readableDenylist,
+		sandboxExtraArgs(cfg),
 	)
 	bwrapArgs = append(bwrapArgs, "--", cmd.Path)
 	bwrapArgs = append(bwrapArgs, cmd.Args[1:]...)
 
-	wrapped := exec.CommandContext(ctx, "bwrap", bwrapArgs...)
+	wrapped := exec.CommandContext(ctx, sandboxBinary(cfg), bwrapArgs...)
 	wrapped.Env = cmd.Env
 	wrapped.Stdin = cmd.Stdin
 	wrapped.Stdout = cmd.Stdout
@@ -2783,9 +4491,13 @@ func wrapCommandWithSandbox(
 }
 
 // buildSandboxArgs constructs the bubblewrap arguments for filesystem isolation.
+// Without allowNetwork the agent also gets its own network namespace with no
+// interfaces besides loopback, so DNS and HTTP fail. extraArgs come last so
+// the caller can append the "--" separator and the agent command directly.
 func buildSandboxArgs(
 	workspaceDir, commandPath string,
-	extraWritableDirs, sharedReadWriteDirs, sharedReadOnlyDirs, readableDenylist []string,
+	allowNetwork bool,
+	extraWritableDirs, sharedReadWriteDirs, sharedReadOnlyDirs, readableDenylist, extraArgs []string,
 ) []string {
 	homeDir, _ := os.UserHomeDir()
 
@@ -2860,8 +4572,16 @@ func buildSandboxArgs(
 	args = append(args, "--dev", "/dev")
 	args = append(args, "--proc", "/proc")
 
-	// Namespace isolation: new mount/pid/user/ipc/uts namespaces, but keep network.
-	args = append(args, "--unshare-all", "--share-net")
+	// Namespace isolation: new mount/pid/user/ipc/uts namespaces, keeping the
+	// network unless it is denied.
+	args = append(args, "--unshare-all")
+	if allowNetwork {
+		args = append(args, "--share-net")
+	} else {
+		// The new namespace has only its own loopback, so a model server
+		// listening on the host's 127.0.0.1 is unreachable too.
+		args = append(args, "--unshare-net")
+	}
 
 	// Kill agent if harness dies.
 	args = append(args, "--die-with-parent")
@@ -2869,6 +4589,9 @@ func buildSandboxArgs(
 	// Set working directory to workspace.
 	args = append(args, "--chdir", workspaceDir)
 
+	// Site-specific flags from [sandbox] extra_args.
+	args = append(args, extraArgs...)
+
 	return args
 }
 
@@ -2880,6 +4603,10 @@ func resolveSandboxMountPaths(homeDir string, specs []string) []string {
 		if spec == "" {
 			continue
 		}
+		spec, ok := expandSandboxSpec(spec)
+		if !ok {
+			continue
+		}
 		absPath := spec
 		switch {
 		case spec == "~":
@@ -2899,6 +4626,25 @@ func resolveSandboxMountPaths(homeDir string, specs []string) []string {
 	return paths
 }
 
+// expandSandboxSpec expands $VAR and ${VAR} references in a sandbox dir spec
+// from the environment, e.g. "${CARGO_HOME}/bin". Specs referencing an unset
+// or empty variable are skipped (ok is false) rather than collapsing to a
+// path under / or $HOME.
+func expandSandboxSpec(spec string) (string, bool) {
+	if !strings.Contains(spec, "$") {
+		return spec, true
+	}
+	ok := true
+	expanded := os.Expand(spec, func(name string) string {
+		value := os.Getenv(name)
+		if value == "" {
+			ok = false
+		}
+		return value
+	})
+	return expanded, ok
+}
+
 func collectAllowedHomeTopLevel(homeDir string, mountedPaths []string) map[string]struct{} {
 	allowed := make(map[string]struct{})
 	for _, mounted := range mountedPaths {
@@ -3184,13 +4930,18 @@ func buildOpenCodeMCPConfig(disableMCP, useMCPTools bool) string {
 
 // buildAgentEnv creates the environment variable slice for an agent command.
 // It merges the agent's configured env vars with any runtime injections.
-func buildAgentEnv(agentEnv map[string]string, disableMCP, useMCPTools bool, agentName string) []string {
+// Later entries win, so agent env overrides the env file (fileEnv), which
+// overrides the inherited environment.
+func buildAgentEnv(agentEnv, fileEnv map[string]string, disableMCP, useMCPTools bool, agentName string) []string {
 	needsOpenCodeConfig := (agentName == "opencode" || agentName == "omo") && (disableMCP || useMCPTools)
-	if len(agentEnv) == 0 && !needsOpenCodeConfig {
+	if len(agentEnv) == 0 && len(fileEnv) == 0 && !needsOpenCodeConfig {
 		return nil
 	}
 
 	env := os.Environ()
+	for k, v := range fileEnv {
+		env = append(env, k+"="+v)
+	}
 	for k, v := range agentEnv {
 		env = append(env, k+"="+v)
 	}
@@ -3226,6 +4977,15 @@ type AttestationEval struct {
 	Model     string  `json:"model,omitempty"`
 	Timestamp string  `json:"timestamp"`
 	Duration  float64 `json:"duration_seconds"`
+	// ContainerReuse is set when validation containers were shared across
+	// tasks of a language (--reuse-container), which weakens per-task
+	// isolation.
+	ContainerReuse bool `json:"container_reuse,omitempty"`
+	// AgentVersions maps each agent that ran to its version command's output.
+	AgentVersions map[string]string `json:"agent_versions,omitempty"`
+	// PromptTemplateHash is the hash of the --prompt-template file the
+	// prompts were rendered from; empty for the built-in prompt.
+	PromptTemplateHash string `json:"prompt_template_hash,omitempty"`
 }
 
 // AttestationTask contains per-task verification data.
@@ -3248,6 +5008,90 @@ func hashBytes(data []byte) string {
 	return "blake3:" + hex.EncodeToString(h[:])
 }
 
+// fullSuiteMaxScore returns the max possible weighted score over every task
+// in the suite, regardless of which tasks were selected for a run.
+func fullSuiteMaxScore(suite []*task.Task) float64 {
+	var total float64
+	for _, t := range suite {
+		total += task.ComputeWeight(t).Base
+	}
+	return total
+}
+
+// RunManifest lists the artifacts an eval run produced.
+type RunManifest struct {
+	Version   string             `json:"version"`
+	CreatedAt string             `json:"created_at"`
+	Artifacts []ManifestArtifact `json:"artifacts"`
+}
+
+// ManifestArtifact describes one file in the run output directory.
+type ManifestArtifact struct {
+	Path string `json:"path"` // Slash-separated, relative to the run directory
+	Size int64  `json:"size"`
+	Hash string `json:"hash"`
+}
+
+// buildRunManifest indexes the run-level files in outputDir and the eval
+// artifacts in each task directory. Workspace sources kept by
+// --keep-workspaces are not artifacts and are left out.
+func buildRunManifest(outputDir string) (*RunManifest, error) {
+	manifest := &RunManifest{
+		Version:   "1",
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Artifacts: []ManifestArtifact{},
+	}
+	err := filepath.WalkDir(outputDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(outputDir, path)
+		if err != nil {
+			return err
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if d.IsDir() {
+			if len(parts) == 2 && !isEvalOutputFile(parts[1]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch {
+		case len(parts) == 1 && parts[0] == "manifest.json":
+			return nil
+		case len(parts) >= 2 && !isEvalOutputFile(parts[1]):
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		manifest.Artifacts = append(manifest.Artifacts, ManifestArtifact{
+			Path: filepath.ToSlash(rel),
+			Size: int64(len(data)),
+			Hash: hashBytes(data),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// writeRunManifest writes manifest.json to outputDir.
+func writeRunManifest(outputDir string) error {
+	manifest, err := buildRunManifest(outputDir)
+	if err != nil {
+		return fmt.Errorf("building manifest: %w", err)
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling manifest: %w", err)
+	}
+	return writeFileAtomic(filepath.Join(outputDir, "manifest.json"), data, 0o644)
+}
+
 // hashFiles returns the BLAKE3 hash of multiple files concatenated.
 // If no files were readable, foundAny is false and hash is empty.
 func hashFiles(paths []string) (hash string, foundAny bool, err error) {
@@ -3320,14 +5164,8 @@ func generateAttestation(
 			}
 		}
 
-		// Hash task files (stub + test + support)
-		var taskFileContents []byte
-		for _, f := range append(append(t.Files.Stub, t.Files.Test...), t.Files.Support...) {
-			if content, err := loader.ReadTaskFile(t, f); err == nil {
-				taskFileContents = append(taskFileContents, content...)
-			}
-		}
-		taskHash := hashBytes(taskFileContents)
+		// Hash task files (stub + test + support) and the prompt hint
+		taskHash := hashBytes(loader.HashContent(t))
 
 		// Hash solution files if they exist
 		var solutionHash string
@@ -3380,6 +5218,11 @@ type LeaderboardSubmission struct {
 	WeightedScore    float64 `json:"weighted_score"`
 	MaxPossibleScore float64 `json:"max_possible_score"`
 
+	// Weighted pass rate against the full suite's max score, comparable
+	// between partial and full runs.
+	NormalizedWeightedPassRate float64 `json:"normalized_weighted_pass_rate"`
+	FullSuiteMaxScore          float64 `json:"full_suite_max_score"`
+
 	// Quality metrics
 	IntegrityViolations int `json:"integrity_violations"`
 
@@ -3403,6 +5246,7 @@ type LeaderboardSubmission struct {
 	UseSkills                       bool    `json:"use_skills"`
 	DisableMCP                      bool    `json:"disable_mcp"`
 	Sandbox                         bool    `json:"sandbox"`
+	SandboxNoNetwork                bool    `json:"sandbox_no_network,omitempty"`
 	Legacy                          bool    `json:"legacy"`
 	QuotaAffectedTasks              int     `json:"quota_affected_tasks"`
 	AuthAffectedTasks               int     `json:"auth_affected_tasks"`
@@ -3419,7 +5263,11 @@ type LeaderboardSubmission struct {
 	TasksWithToolchainInstall       int     `json:"tasks_with_toolchain_install"`
 	TasksWithOutOfWorkspaceReads    int     `json:"tasks_with_out_of_workspace_reads"`
 	TasksWithToolchainSearch        int     `json:"tasks_with_toolchain_search"`
+	TotalEscapeAttempts             int     `json:"total_escape_attempts"`
+	TasksWithEscapeAttempts         int     `json:"tasks_with_escape_attempts"`
 	TasksWithSkillsUsage            int     `json:"tasks_with_skills_usage"`
+	TotalTokens                     int     `json:"total_tokens,omitempty"`
+	TasksWithTokenUsage             int     `json:"tasks_with_token_usage,omitempty"`
 }
 
 // LeaderboardLanguageStats contains per-language metrics for the leaderboard.
@@ -3439,6 +5287,8 @@ func generateLeaderboardSubmission(summary EvalSummary, attestation *EvalAttesta
 		Timestamp:                       summary.Timestamp,
 		PassRate:                        summary.PassRate,
 		WeightedPassRate:                summary.WeightedPassRate,
+		NormalizedWeightedPassRate:      summary.NormalizedWeightedPassRate,
+		FullSuiteMaxScore:               summary.FullSuiteMaxScore,
 		Passed:                          summary.Passed,
 		Failed:                          summary.Failed,
 		Total:                           summary.Total,
@@ -3454,6 +5304,7 @@ func generateLeaderboardSubmission(summary EvalSummary, attestation *EvalAttesta
 		UseSkills:                       summary.UseSkills,
 		DisableMCP:                      summary.DisableMCP,
 		Sandbox:                         summary.Sandbox,
+		SandboxNoNetwork:                summary.SandboxNoNetwork,
 		Legacy:                          summary.Legacy,
 		QuotaAffectedTasks:              summary.QuotaAffectedTasks,
 		AuthAffectedTasks:               summary.AuthAffectedTasks,
@@ -3470,7 +5321,11 @@ func generateLeaderboardSubmission(summary EvalSummary, attestation *EvalAttesta
 		TasksWithToolchainInstall:       summary.TasksWithToolchainInstall,
 		TasksWithOutOfWorkspaceReads:    summary.TasksWithOutOfWorkspaceReads,
 		TasksWithToolchainSearch:        summary.TasksWithToolchainSearch,
+		TotalEscapeAttempts:             summary.TotalEscapeAttempts,
+		TasksWithEscapeAttempts:         summary.TasksWithEscapeAttempts,
 		TasksWithSkillsUsage:            summary.TasksWithSkillsUsage,
+		TotalTokens:                     summary.TotalTokens,
+		TasksWithTokenUsage:             summary.TasksWithTokenUsage,
 		ByLanguage:                      make(map[string]LeaderboardLanguageStats),
 	}
 
@@ -3501,12 +5356,21 @@ func generateEvalReport(summary EvalSummary, attestation *EvalAttestation) strin
 
 	sb.WriteString("# Evaluation Report\n\n")
 	writeReportSummary(&sb, summary)
+	writeReportBaselines(&sb, summary)
+	writeReportUnexpectedPasses(&sb, summary)
 	writeReportQuality(&sb, summary)
 	writeReportBehaviorTelemetry(&sb, summary)
+	writeReportRelevantSkills(&sb, summary)
 	writeReportByLanguage(&sb, summary)
 	writeReportByTier(&sb, summary)
 	writeReportTaskResults(&sb, summary)
+	writeReportVariants(&sb, summary)
+	writeReportValidationAttempts(&sb, summary)
+	writeReportLint(&sb, summary)
+	writeReportRobustness(&sb, summary)
 	writeReportExternalFailures(&sb, summary)
+	writeReportRetryReasons(&sb, summary)
+	writeReportTiming(&sb, summary)
 	writeReportErrors(&sb, summary)
 	writeReportVerification(&sb, attestation)
 	sb.WriteString("---\n")
@@ -3526,6 +5390,13 @@ func writeReportSummary(sb *strings.Builder, summary EvalSummary) {
 	if summary.Reasoning != "" {
 		fmt.Fprintf(sb, "| Reasoning Effort | %s |\n", summary.Reasoning)
 	}
+	if len(summary.AgentFallback) > 0 {
+		fmt.Fprintf(sb, "| Agent Fallback | %s |\n", strings.Join(summary.AgentFallback, " → "))
+		fmt.Fprintf(sb, "| Tasks Run by Fallback | %d |\n", summary.FallbackTasks)
+	}
+	if len(summary.SkipLangs) > 0 {
+		fmt.Fprintf(sb, "| Skipped Languages | %s (%d tasks) |\n", strings.Join(summary.SkipLangs, ", "), summary.SkippedLanguageTasks)
+	}
 	if summary.UseMCPTools {
 		sb.WriteString("| MCP Tools Mode | Yes |\n")
 	}
@@ -3536,15 +5407,30 @@ func writeReportSummary(sb *strings.Builder, summary EvalSummary) {
 	if summary.DisableMCP {
 		sb.WriteString("| MCP Disabled | Yes |\n")
 	}
-	if summary.Sandbox {
+	if summary.SandboxNoNetwork {
+		sb.WriteString("| Sandbox | Yes (no network) |\n")
+	} else if summary.Sandbox {
 		sb.WriteString("| Sandbox | Yes |\n")
 	}
+	if summary.ReuseContainer {
+		sb.WriteString("| Container Reuse | Yes |\n")
+	}
 	if summary.Legacy {
 		sb.WriteString("| Legacy Mode | Yes |\n")
 	}
+	if summary.ExpectedFailures > 0 || len(summary.UnexpectedPasses) > 0 {
+		fmt.Fprintf(sb, "| Expected Failures | %d xfail, %d xpass |\n", summary.ExpectedFailures, len(summary.UnexpectedPasses))
+	}
+	if summary.PromptBudgetTokens > 0 {
+		fmt.Fprintf(sb, "| Prompt Budget | %d tokens (%d prompts trimmed) |\n", summary.PromptBudgetTokens, summary.PromptTrimmedTasks)
+	}
 	fmt.Fprintf(sb, "| Timestamp | %s |\n", summary.Timestamp)
 	fmt.Fprintf(sb, "| Pass Rate | **%.1f%%** (%d/%d) |\n", summary.PassRate, summary.Passed, summary.Total)
 	fmt.Fprintf(sb, "| Weighted Pass Rate | **%.1f%%** |\n", summary.WeightedPassRate)
+	if summary.FullSuiteMaxScore-summary.MaxPossibleScore > 0.005 {
+		fmt.Fprintf(sb, "| Weighted Pass Rate (normalized to full suite) | %.1f%% (%.2f / %.2f) |\n",
+			summary.NormalizedWeightedPassRate, summary.WeightedScore, summary.FullSuiteMaxScore)
+	}
 	fmt.Fprintf(sb, "| Weighted Score | %.2f / %.2f |\n", summary.WeightedScore, summary.MaxPossibleScore)
 	fmt.Fprintf(sb, "| Duration | %.1fs |\n", summary.Duration)
 	sb.WriteString("\n")
@@ -3560,6 +5446,17 @@ func writeReportQuality(sb *strings.Builder, summary EvalSummary) {
 	fmt.Fprintf(sb, "- **Quota-affected tasks**: %d\n", summary.QuotaAffectedTasks)
 	fmt.Fprintf(sb, "- **Auth-affected tasks**: %d\n", summary.AuthAffectedTasks)
 	fmt.Fprintf(sb, "- **Infra-affected tasks**: %d\n", summary.InfraAffectedTasks)
+	if summary.AgentTimeoutTasks > 0 {
+		outcomes := make(map[TimeoutOutcome]int)
+		for _, r := range summary.Results {
+			outcomes[r.TimeoutOutcome]++
+		}
+		fmt.Fprintf(sb, "- **Agent timeouts**: %d (%d passed with partial work, %d failed with partial work, %d with no work)\n",
+			summary.AgentTimeoutTasks, outcomes[TimeoutPartialPass], outcomes[TimeoutPartialFail], outcomes[TimeoutNoWork])
+	}
+	if summary.RefusalTasks > 0 {
+		fmt.Fprintf(sb, "- **Refusals** (model declined the task): %d\n", summary.RefusalTasks)
+	}
 
 	failureCounts := make(map[FailureClass]int)
 	for _, r := range summary.Results {
@@ -3586,6 +5483,9 @@ func writeReportQuality(sb *strings.Builder, summary EvalSummary) {
 			fmt.Fprintf(sb, "| %s | %d |\n", key, failureCounts[FailureClass(key)])
 		}
 	}
+	if evalReportChart {
+		writeOutcomeChart(sb, outcomeDistribution(summary))
+	}
 	sb.WriteString("\n")
 }
 
@@ -3599,12 +5499,21 @@ func writeReportBehaviorTelemetry(sb *strings.Builder, summary EvalSummary) {
 	fmt.Fprintf(sb, "- **Tasks with out-of-workspace read attempts**: %d/%d\n", summary.TasksWithOutOfWorkspaceReads, summary.Total)
 	fmt.Fprintf(sb, "- **Total toolchain search attempts**: %d\n", summary.TotalToolchainSearchAttempts)
 	fmt.Fprintf(sb, "- **Tasks with toolchain searching**: %d/%d\n", summary.TasksWithToolchainSearch, summary.Total)
+	fmt.Fprintf(sb, "- **Total escape attempts**: %d\n", summary.TotalEscapeAttempts)
+	fmt.Fprintf(sb, "- **Tasks with escape attempts**: %d/%d\n", summary.TasksWithEscapeAttempts, summary.Total)
 	fmt.Fprintf(sb, "- **Total Agent Skills usage signals**: %d\n", summary.TotalSkillsUsageSignals)
 	fmt.Fprintf(sb, "- **Tasks with Agent Skills usage**: %d/%d (%.1f%%)\n", summary.TasksWithSkillsUsage, summary.Total, summary.SkillsUsageRate)
+	if timed := summary.ThinkingTime + summary.ActingTime; timed > 0 {
+		fmt.Fprintf(sb, "- **Agent thinking vs acting time**: %.0fs (%.1f%%) / %.0fs (%.1f%%) over %d tasks with log timestamps\n",
+			summary.ThinkingTime, summary.ThinkingTime/timed*100, summary.ActingTime, summary.ActingTime/timed*100, summary.TasksWithTimeline)
+	}
+	if summary.TasksWithTokenUsage > 0 {
+		fmt.Fprintf(sb, "- **Total tokens**: %d over %d/%d tasks reporting usage\n", summary.TotalTokens, summary.TasksWithTokenUsage, summary.Total)
+	}
 
 	hasTaskRows := false
 	for _, r := range summary.Results {
-		if r.SelfTestCommands > 0 || r.ToolchainInstallAttempts > 0 || r.OutOfWorkspaceReadAttempts > 0 || r.ToolchainSearchAttempts > 0 || r.SkillsUsed {
+		if r.SelfTestCommands > 0 || r.ToolchainInstallAttempts > 0 || r.OutOfWorkspaceReadAttempts > 0 || r.ToolchainSearchAttempts > 0 || r.EscapeAttempts > 0 || r.SkillsUsed {
 			hasTaskRows = true
 			break
 		}
@@ -3614,15 +5523,15 @@ func writeReportBehaviorTelemetry(sb *strings.Builder, summary EvalSummary) {
 		return
 	}
 
-	sb.WriteString("\n| Task | Self Tests | Self Test Conf. | Tool Installs | Out-of-Workspace Reads | Out-of-Workspace Conf. | Toolchain Searches | Skills Used | Skill Signals |\n")
-	sb.WriteString("|------|------------|-----------------|---------------|-------------------------|------------------------|--------------------|-------------|---------------|\n")
+	sb.WriteString("\n| Task | Self Tests | Self Test Conf. | Tool Installs | Out-of-Workspace Reads | Out-of-Workspace Conf. | Toolchain Searches | Escape Attempts | Skills Used | Skill Signals |\n")
+	sb.WriteString("|------|------------|-----------------|---------------|-------------------------|------------------------|--------------------|-----------------|-------------|---------------|\n")
 	for _, r := range summary.Results {
-		if r.SelfTestCommands == 0 && r.ToolchainInstallAttempts == 0 && r.OutOfWorkspaceReadAttempts == 0 && r.ToolchainSearchAttempts == 0 && !r.SkillsUsed {
+		if r.SelfTestCommands == 0 && r.ToolchainInstallAttempts == 0 && r.OutOfWorkspaceReadAttempts == 0 && r.ToolchainSearchAttempts == 0 && r.EscapeAttempts == 0 && !r.SkillsUsed {
 			continue
 		}
 		fmt.Fprintf(
 			sb,
-			"| %s | %d | %t | %d | %d | %t | %d | %t | %d |\n",
+			"| %s | %d | %t | %d | %d | %t | %d | %d | %t | %d |\n",
 			r.Task,
 			r.SelfTestCommands,
 			r.SelfTestCommandsConfident,
@@ -3630,6 +5539,7 @@ func writeReportBehaviorTelemetry(sb *strings.Builder, summary EvalSummary) {
 			r.OutOfWorkspaceReadAttempts,
 			r.OutOfWorkspaceReadsConfident,
 			r.ToolchainSearchAttempts,
+			r.EscapeAttempts,
 			r.SkillsUsed,
 			r.SkillsUsageSignals,
 		)
@@ -3637,6 +5547,25 @@ func writeReportBehaviorTelemetry(sb *strings.Builder, summary EvalSummary) {
 	sb.WriteString("\n")
 }
 
+// writeReportRelevantSkills compares the skills agents used against the
+// relevant_skill declared by each task.
+func writeReportRelevantSkills(sb *strings.Builder, summary EvalSummary) {
+	if summary.TasksWithRelevantSkill == 0 {
+		return
+	}
+	sb.WriteString("## Relevant Skill Usage\n\n")
+	fmt.Fprintf(sb, "- **Tasks using their relevant skill**: %d/%d\n\n", summary.TasksUsingRelevantSkill, summary.TasksWithRelevantSkill)
+	sb.WriteString("| Task | Relevant Skill | Used | Any Skill Used |\n")
+	sb.WriteString("|------|----------------|------|----------------|\n")
+	for _, r := range summary.Results {
+		if r.RelevantSkill == "" {
+			continue
+		}
+		fmt.Fprintf(sb, "| %s | %s | %t | %t |\n", r.Task, r.RelevantSkill, r.RelevantSkillUsed, r.SkillsUsed)
+	}
+	sb.WriteString("\n")
+}
+
 func writeReportByLanguage(sb *strings.Builder, summary EvalSummary) {
 	sb.WriteString("## Results by Language\n\n")
 	sb.WriteString("| Language | Passed | Failed | Total | Pass Rate |\n")
@@ -3671,13 +5600,58 @@ func writeReportByTier(sb *strings.Builder, summary EvalSummary) {
 }
 
 func writeReportTaskResults(sb *strings.Builder, summary EvalSummary) {
+	hasCoverage := slices.ContainsFunc(summary.Results, func(r EvalResult) bool { return r.Coverage != nil })
+
 	sb.WriteString("## Task Results\n\n")
-	sb.WriteString("| Task | Status | Weight | Score | Duration |\n")
-	sb.WriteString("|------|--------|--------|-------|----------|\n")
+	if hasCoverage {
+		sb.WriteString("| Task | Status | Weight | Score | Duration | Coverage |\n")
+		sb.WriteString("|------|--------|--------|-------|----------|----------|\n")
+	} else {
+		sb.WriteString("| Task | Status | Weight | Score | Duration |\n")
+		sb.WriteString("|------|--------|--------|-------|----------|\n")
+	}
 	for _, r := range summary.Results {
 		statusIcon, status := getResultStatusDisplay(r)
-		fmt.Fprintf(sb, "| %s | %s %s | %.2f | %.2f | %.1fs |\n",
+		if r.Agent != "" && r.Agent != summary.Agent {
+			status += " (via " + r.Agent + ")"
+		}
+		fmt.Fprintf(sb, "| %s | %s %s | %.2f | %.2f | %.1fs |",
 			r.Task, statusIcon, status, r.Weight, r.WeightedScore, r.Duration)
+		switch {
+		case !hasCoverage:
+		case r.Coverage != nil:
+			fmt.Fprintf(sb, " %.1f%% |", *r.Coverage)
+		default:
+			sb.WriteString(" - |")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+}
+
+func writeReportVariants(sb *strings.Builder, summary EvalSummary) {
+	hasVariants := false
+	for _, r := range summary.Results {
+		if len(r.Variants) > 0 {
+			hasVariants = true
+			break
+		}
+	}
+	if !hasVariants {
+		return
+	}
+
+	sb.WriteString("## Task Variants\n\n")
+	sb.WriteString("| Task | Variant | Status | Duration |\n")
+	sb.WriteString("|------|---------|--------|----------|\n")
+	for _, r := range summary.Results {
+		for _, v := range r.Variants {
+			status := "❌ FAIL"
+			if v.Passed {
+				status = "✅ PASS"
+			}
+			fmt.Fprintf(sb, "| %s | %s | %s | %.1fs |\n", r.Task, v.Name, status, v.Duration)
+		}
 	}
 	sb.WriteString("\n")
 }
@@ -3686,6 +5660,18 @@ func getResultStatusDisplay(r EvalResult) (icon, text string) {
 	switch {
 	case r.Status == task.StatusIntegrityViolation:
 		return "🚫", "VIOLATION"
+	case r.ExpectedFail && r.Passed:
+		return "⚠️", "**XPASS**"
+	case r.ExpectedFail:
+		return "➖", "XFAIL (expected)"
+	case r.TimeoutOutcome == TimeoutPartialPass:
+		return "⏱️", "PASS (timed out, partial work)"
+	case r.TimeoutOutcome == TimeoutPartialFail:
+		return "⏱️", "FAIL (timed out, partial work)"
+	case r.TimeoutOutcome == TimeoutNoWork:
+		return "⏱️", "FAIL (timed out, no work)"
+	case r.FailureClass == FailureClassValidationOOM:
+		return "💥", "FAIL (out of memory)"
 	case r.Passed:
 		return "✅", "PASS"
 	default:
@@ -3693,6 +5679,34 @@ func getResultStatusDisplay(r EvalResult) (icon, text string) {
 	}
 }
 
+// expectationNote annotates console output for tasks marked
+// expected_status = "fail".
+func expectationNote(r EvalResult) string {
+	switch {
+	case !r.ExpectedFail:
+		return ""
+	case r.Passed:
+		return " ⚠ XPASS (expected to fail)"
+	default:
+		return " (xfail, expected)"
+	}
+}
+
+// writeReportUnexpectedPasses lists tasks marked expected_status = "fail"
+// that passed, so newly solvable tasks stand out.
+func writeReportUnexpectedPasses(sb *strings.Builder, summary EvalSummary) {
+	if len(summary.UnexpectedPasses) == 0 {
+		return
+	}
+
+	sb.WriteString("## ⚠️ Unexpected Passes (XPASS)\n\n")
+	sb.WriteString("These tasks are marked `expected_status = \"fail\"` but passed:\n\n")
+	for _, id := range summary.UnexpectedPasses {
+		fmt.Fprintf(sb, "- **%s**\n", id)
+	}
+	sb.WriteString("\n")
+}
+
 func writeReportExternalFailures(sb *strings.Builder, summary EvalSummary) {
 	if len(summary.ExternalFailures) == 0 {
 		return
@@ -3702,7 +5716,39 @@ func writeReportExternalFailures(sb *strings.Builder, summary EvalSummary) {
 	sb.WriteString("| Task | Class | Quota Retries | Infra Retries |\n")
 	sb.WriteString("|------|-------|---------------|---------------|\n")
 	for _, f := range summary.ExternalFailures {
-		fmt.Fprintf(sb, "| %s | %s | %d | %d |\n", f.Task, f.FailureClass, f.QuotaRetries, f.InfraRetries)
+		class := string(f.FailureClass)
+		if f.FailurePhase != "" {
+			class += " (" + failurePhaseLabel(f.FailurePhase) + ")"
+		}
+		fmt.Fprintf(sb, "| %s | %s | %d | %d |\n", f.Task, class, f.QuotaRetries, f.InfraRetries)
+	}
+	sb.WriteString("\n")
+}
+
+// writeReportRetryReasons lists how often each retry cause fired, most
+// frequent first.
+func writeReportRetryReasons(sb *strings.Builder, summary EvalSummary) {
+	if len(summary.RetryReasons) == 0 {
+		return
+	}
+
+	reasons := make([]string, 0, len(summary.RetryReasons))
+	for reason := range summary.RetryReasons {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		ci, cj := summary.RetryReasons[reasons[i]], summary.RetryReasons[reasons[j]]
+		if ci != cj {
+			return ci > cj
+		}
+		return reasons[i] < reasons[j]
+	})
+
+	sb.WriteString("## Retry Reasons\n\n")
+	sb.WriteString("| Reason | Retries |\n")
+	sb.WriteString("|--------|---------|\n")
+	for _, reason := range reasons {
+		fmt.Fprintf(sb, "| %s | %d |\n", reason, summary.RetryReasons[reason])
 	}
 	sb.WriteString("\n")
 }
@@ -3722,11 +5768,23 @@ func writeReportErrors(sb *strings.Builder, summary EvalSummary) {
 	for _, r := range summary.Results {
 		if r.Error != "" {
 			fmt.Fprintf(sb, "### %s\n\n", r.Task)
+			if r.FailurePhase != "" {
+				fmt.Fprintf(sb, "Failed at %s.\n\n", failurePhaseLabel(r.FailurePhase))
+			}
 			fmt.Fprintf(sb, "```\n%s\n```\n\n", r.Error)
 		}
 	}
 }
 
+// failurePhaseLabel describes where a validation run failed: during container
+// setup or while the tests were executing.
+func failurePhaseLabel(phase runner.Phase) string {
+	if phase == runner.PhaseExec {
+		return "test execution"
+	}
+	return string(phase)
+}
+
 func writeReportVerification(sb *strings.Builder, attestation *EvalAttestation) {
 	if attestation == nil {
 		return
@@ -3736,10 +5794,13 @@ func writeReportVerification(sb *strings.Builder, attestation *EvalAttestation)
 	fmt.Fprintf(sb, "- **Weight Version**: %s\n", attestation.Harness.WeightVersion)
 	fmt.Fprintf(sb, "- **Tasks Hash**: `%s`\n", attestation.Integrity.TasksHash)
 	fmt.Fprintf(sb, "- **Results Hash**: `%s`\n", attestation.Integrity.ResultsHash)
+	if attestation.Eval.PromptTemplateHash != "" {
+		fmt.Fprintf(sb, "- **Prompt Template Hash**: `%s`\n", attestation.Eval.PromptTemplateHash)
+	}
 	sb.WriteString("\n")
 }
 
-func parseAgentBehaviorMetrics(logPath, workspaceDir string) agentBehaviorMetrics {
+func parseAgentBehaviorMetrics(logPath, workspaceDir, runDir string, timestamps *agentTimestampFormat, tokenPatterns []*regexp.Regexp) agentBehaviorMetrics {
 	data, err := os.ReadFile(logPath)
 	if err != nil {
 		return agentBehaviorMetrics{}
@@ -3752,7 +5813,10 @@ func parseAgentBehaviorMetrics(logPath, workspaceDir string) agentBehaviorMetric
 	toolchainInstalls, toolchainConfident := countCommandMatches(commands, toolchainInstallPatterns)
 	outReads, outReadsConfident := countOutOfWorkspaceReads(commands, workspaceDir)
 	toolchainSearches := countToolchainSearches(commands, content)
-	skillsSignals := countSkillUsageSignals(lines, commands)
+	escapeAttempts := countEscapeAttempts(commands, content, runDir)
+	skillsSignals, skillNames := countSkillUsageSignals(lines, commands)
+	thinking, acting := segmentAgentTimeline(lines, timestamps)
+	tokens, tokensConfident := countTokenUsage(content, tokenPatterns)
 
 	// Fallback to broad line matching when command extraction fails.
 	if !selfConfident {
@@ -3778,16 +5842,36 @@ func parseAgentBehaviorMetrics(logPath, workspaceDir string) agentBehaviorMetric
 		OutOfWorkspaceReads:          outReads,
 		OutOfWorkspaceReadsConfident: outReadsConfident,
 		ToolchainSearchAttempts:      toolchainSearches,
+		EscapeAttempts:               escapeAttempts,
 		SkillsUsed:                   skillsSignals > 0,
 		SkillsUsageSignals:           skillsSignals,
+		SkillNames:                   skillNames,
+		ThinkingTime:                 thinking.Seconds(),
+		ActingTime:                   acting.Seconds(),
+		TotalTokens:                  tokens,
+		TotalTokensConfident:         tokensConfident,
 	}
 }
 
-func countSkillUsageSignals(lines, commands []string) int {
+// countSkillUsageSignals counts distinct skill usage signals in an agent log
+// and returns the lowercased names of the skills involved, from activations
+// (`skill "name"`) and reads under a skills/<name>/ directory.
+func countSkillUsageSignals(lines, commands []string) (int, []string) {
 	seen := make(map[string]struct{})
+	seenNames := make(map[string]bool)
+	var names []string
+	addName := func(name string) {
+		if !seenNames[name] {
+			seenNames[name] = true
+			names = append(names, name)
+		}
+	}
 
 	record := func(text string) {
 		for _, ref := range extractSkillArtifactRefs(text) {
+			if name := skillNameFromArtifact(ref); name != "" {
+				addName(name)
+			}
 			key := "artifact:" + strings.ToLower(ref)
 			if _, exists := seen[key]; exists {
 				continue
@@ -3803,6 +5887,7 @@ func countSkillUsageSignals(lines, commands []string) int {
 			if skillName == "" {
 				continue
 			}
+			addName(strings.ToLower(skillName))
 			key := "activation:" + strings.ToLower(skillName)
 			if _, exists := seen[key]; exists {
 				continue
@@ -3830,7 +5915,24 @@ func countSkillUsageSignals(lines, commands []string) int {
 		record(cmd)
 	}
 
-	return len(seen)
+	return len(seen), names
+}
+
+// skillNameFromArtifact returns the lowercased <name> of a skills/<name>/...
+// path, skipping grouping dirs such as .system, or "" when there is none.
+func skillNameFromArtifact(ref string) string {
+	parts := strings.Split(strings.ToLower(filepath.ToSlash(ref)), "/")
+	for i, part := range parts {
+		if part != "skills" {
+			continue
+		}
+		for _, name := range parts[i+1 : len(parts)-1] {
+			if name != "" && !strings.HasPrefix(name, ".") {
+				return name
+			}
+		}
+	}
+	return ""
 }
 
 func extractSkillArtifactRefs(text string) []string {
@@ -3986,6 +6088,20 @@ func countToolchainSearches(commands []string, content string) int {
 	return countMatchingLines(content, toolchainSearchPatterns)
 }
 
+// countEscapeAttempts counts commands matching escapeAttemptPatterns,
+// falling back to log lines when no commands could be extracted.
+func countEscapeAttempts(commands []string, content, runDir string) int {
+	patterns := escapeAttemptPatterns
+	if runDir != "" {
+		patterns = append(slices.Clip(patterns), regexp.MustCompile(escapeWritePrefix+regexp.QuoteMeta(filepath.ToSlash(runDir))+`(?:[/\s'"]|$)`))
+	}
+	if len(commands) > 0 {
+		count, _ := countCommandMatches(commands, patterns)
+		return count
+	}
+	return countMatchingLines(content, patterns)
+}
+
 func countMatchingLines(content string, patterns []*regexp.Regexp) int {
 	if content == "" {
 		return 0
@@ -4096,10 +6212,10 @@ func detectAuthError(logPath string) bool {
 // detectQuotaError checks if agent log contains rate limit or quota errors.
 // Returns (hasError, isRecoverable) where hasError indicates if any quota/rate
 // limit pattern was found, and isRecoverable indicates if the error is transient.
-func detectQuotaError(logPath string) (bool, bool) {
+func detectQuotaError(logPath string) (hasError, isRecoverable bool, pattern string) {
 	content, err := os.ReadFile(logPath)
 	if err != nil {
-		return false, false
+		return false, false, ""
 	}
 
 	lower := strings.ToLower(string(lastAttemptContent(content)))
@@ -4107,50 +6223,87 @@ func detectQuotaError(logPath string) (bool, bool) {
 	// Check for non-recoverable patterns first
 	for _, pattern := range nonRecoverableQuotaPatterns {
 		if strings.Contains(lower, pattern) {
-			return true, false // Error found, NOT recoverable
+			return true, false, pattern // Error found, NOT recoverable
 		}
 	}
 
 	// Check for recoverable patterns
 	for _, pattern := range recoverablePatterns {
 		if strings.Contains(lower, pattern) {
-			return true, true // Error found, IS recoverable
+			return true, true, pattern // Error found, IS recoverable
 		}
 	}
 
-	return false, false
+	return false, false, ""
 }
 
 // getRetryDelay returns the delay for the given quota retry attempt (1-indexed).
 func getRetryDelay(attempt int) time.Duration {
-	switch attempt {
-	case 1:
-		return quotaRetryDelay1
-	case 2:
-		return quotaRetryDelay2
-	case 3:
-		return quotaRetryDelay3
-	case 4:
-		return quotaRetryDelay4
-	default:
-		return quotaRetryDelay5
+	delays := defaultQuotaRetryDelays
+	if cfg != nil {
+		delays = retryDelays(cfg.Harness.Retry.QuotaDelays, delays)
 	}
+	return retryDelayAt(delays, attempt)
 }
 
 // getInfraRetryDelay returns the delay for the given infra retry attempt (1-indexed).
 func getInfraRetryDelay(attempt int) time.Duration {
-	switch attempt {
-	case 1:
-		return infraRetryDelay1
-	case 2:
-		return infraRetryDelay2
-	case 3:
-		return infraRetryDelay3
-	case 4:
-		return infraRetryDelay4
-	default:
-		return infraRetryDelay5
+	delays := defaultInfraRetryDelays
+	if cfg != nil {
+		delays = retryDelays(cfg.Harness.Retry.InfraDelays, delays)
+	}
+	return retryDelayAt(delays, attempt)
+}
+
+// retryJitter returns the fraction by which retry delays are randomized,
+// clamped to [0, 1].
+func retryJitter() float64 {
+	if cfg == nil {
+		return config.Default.Harness.Retry.Jitter
+	}
+	return min(max(cfg.Harness.Retry.Jitter, 0), 1)
+}
+
+// jitterDelay shifts d by up to fraction of itself either way, picking the
+// offset from u in [0, 1). The top-level math/rand source is seeded per
+// process, so tasks running in parallel spread their retries apart.
+func jitterDelay(d time.Duration, fraction, u float64) time.Duration {
+	return d + time.Duration((2*u-1)*fraction*float64(d))
+}
+
+// retryDelays converts a configured schedule in seconds, falling back to
+// defaults when it is empty or has a negative delay.
+func retryDelays(seconds []int, defaults []time.Duration) []time.Duration {
+	if len(seconds) == 0 || slices.ContainsFunc(seconds, func(s int) bool { return s < 0 }) {
+		return defaults
+	}
+	delays := make([]time.Duration, len(seconds))
+	for i, s := range seconds {
+		delays[i] = time.Duration(s) * time.Second
+	}
+	return delays
+}
+
+// retryDelayAt returns the delay for a 1-indexed attempt, reusing the last
+// delay once the schedule runs out.
+func retryDelayAt(delays []time.Duration, attempt int) time.Duration {
+	return delays[min(max(attempt, 1), len(delays))-1]
+}
+
+// quotaMaxRetries returns how many quota-failed attempts a task makes.
+func quotaMaxRetries() int {
+	if cfg != nil && cfg.Harness.Retry.QuotaMaxRetries > 0 {
+		return cfg.Harness.Retry.QuotaMaxRetries
+	}
+	return defaultQuotaMaxRetries
+}
+
+// infraMaxRetries returns how many infra-failed attempts a task makes.
+func infraMaxRetries() int {
+	if cfg != nil && cfg.Harness.Retry.InfraMaxRetries > 0 {
+		return cfg.Harness.Retry.InfraMaxRetries
 	}
+	return defaultInfraMaxRetries
 }
 
 // isInfraFailure checks if the agent log indicates an infrastructure failure
@@ -4228,30 +6381,55 @@ func hasModifiedFiles(dir string, cutoff time.Time) bool {
 }
 
 // saveRunConfig saves the eval configuration for resume capability.
-func saveRunConfig(outputDir string, allTasks []*task.Task) error {
+func saveRunConfig(outputDir string, spec RunSpec, useMCPTools bool, allTasks []*task.Task) error {
 	taskList := make([]string, len(allTasks))
 	for i, t := range allTasks {
 		taskList[i] = string(t.Language) + "/" + t.Slug
 	}
+	agentLogMax := evalAgentLogMax
 
 	runCfg := RunConfig{
-		Agent:          evalAgent,
-		Model:          evalModel,
-		Reasoning:      evalReasoning,
+		Agent:          spec.Agent,
+		Model:          spec.Model,
+		Reasoning:      spec.Reasoning,
+		AgentFallback:  evalAgentFallback,
 		Tier:           evalTier,
 		Difficulty:     evalDifficulty,
 		Lang:           evalLang,
+		SkipLangs:      evalSkipLangs,
 		Tasks:          evalTasks,
 		Timeout:        evalTimeout,
+		TimeoutGrace:   evalTimeoutGrace,
+		SystemPrompt:   evalSystemPrompt,
+		PromptTemplate: evalPromptTemplate,
+		AgentEnvFile:   evalAgentEnvFile,
 		Parallel:       evalParallel,
-		UseMCPTools:    evalUseMCPTools,
+		UseMCPTools:    useMCPTools,
 		UseSkills:      evalUseSkills,
+		Lint:           evalLint,
+		PromptBudget:   evalPromptBudget,
+		ReuseContainer: evalReuseContainer,
+		Robustness:     evalRobustness,
 		DisableMCP:     evalDisableMCP,
 		NoSandbox:      evalNoSandbox,
+		NoNetwork:      evalNoNetwork,
 		Legacy:         evalLegacy,
 		KeepWorkspaces: evalKeepWorkspaces,
+		Sample:         evalSample,
+		SampleUniform:  evalSampleUniform,
+		SampleSeed:     evalSampleSeed,
+		Shard:          evalShard,
+		MinFreeDiskMB:  evalMinFreeDiskMB,
+		Baselines:      evalBaselines,
+		ExportFormat:   evalExportFormat,
+		ReportChart:    evalReportChart,
+		FailFast:       evalFailFast,
 		TaskList:       taskList,
 		CreatedAt:      time.Now().Format(time.RFC3339),
+
+		AgentRunawayBytes: evalAgentRunaway,
+		TimeoutExplicit:   evalTimeoutExplicit,
+		AgentLogMaxBytes:  &agentLogMax,
 	}
 
 	data, err := json.MarshalIndent(runCfg, "", "  ")
@@ -4259,7 +6437,7 @@ func saveRunConfig(outputDir string, allTasks []*task.Task) error {
 		return fmt.Errorf("marshaling run config: %w", err)
 	}
 
-	return os.WriteFile(filepath.Join(outputDir, "run-config.json"), data, 0o644)
+	return writeFileAtomic(filepath.Join(outputDir, "run-config.json"), data, 0o644)
 }
 
 // loadRunConfig loads the eval configuration from a resume directory.
@@ -4282,22 +6460,50 @@ func applyRunConfig(runCfg *RunConfig) {
 	evalAgent = runCfg.Agent
 	evalModel = runCfg.Model
 	evalReasoning = runCfg.Reasoning
+	evalAgentFallback = runCfg.AgentFallback
 	evalTier = runCfg.Tier
 	evalDifficulty = runCfg.Difficulty
 	evalLang = runCfg.Lang
+	evalSkipLangs = runCfg.SkipLangs
 	evalTasks = runCfg.Tasks
 	evalTimeout = runCfg.Timeout
+	evalTimeoutGrace = runCfg.TimeoutGrace
+	evalTimeoutExplicit = runCfg.TimeoutExplicit
+	evalSystemPrompt = runCfg.SystemPrompt
+	evalPromptTemplate = runCfg.PromptTemplate
+	evalAgentEnvFile = runCfg.AgentEnvFile
 	evalParallel = runCfg.Parallel
 	evalUseMCPTools = runCfg.UseMCPTools
 	evalUseSkills = runCfg.UseSkills
+	evalLint = runCfg.Lint
+	evalPromptBudget = runCfg.PromptBudget
+	evalReuseContainer = runCfg.ReuseContainer
+	evalRobustness = runCfg.Robustness
 	evalDisableMCP = runCfg.DisableMCP
 	evalNoSandbox = runCfg.NoSandbox
+	evalNoNetwork = runCfg.NoNetwork
 	evalLegacy = runCfg.Legacy
 	evalKeepWorkspaces = runCfg.KeepWorkspaces
-}
-
-// findCompletedTasks returns a set of task slugs that have validation.log files.
+	evalSample = runCfg.Sample
+	evalSampleUniform = runCfg.SampleUniform
+	evalSampleSeed = runCfg.SampleSeed
+	evalShard = runCfg.Shard
+	evalMinFreeDiskMB = runCfg.MinFreeDiskMB
+	evalBaselines = runCfg.Baselines
+	evalExportFormat = runCfg.ExportFormat
+	evalReportChart = runCfg.ReportChart
+	evalFailFast = runCfg.FailFast
+	evalAgentRunaway = runCfg.AgentRunawayBytes
+	if runCfg.AgentLogMaxBytes != nil {
+		evalAgentLogMax = *runCfg.AgentLogMaxBytes
+	}
+}
+
+// findCompletedTasks returns a set of task slugs that have a complete
+// validation.log. Tasks whose log is empty or truncated are reported and left
+// out, so resume re-runs them.
 func findCompletedTasks(outputDir string) (map[string]bool, error) {
+	out := evalOutput()
 	completed := make(map[string]bool)
 
 	entries, err := os.ReadDir(outputDir)
@@ -4317,6 +6523,10 @@ func findCompletedTasks(outputDir string) (map[string]bool, error) {
 			name := entry.Name()
 			if idx := strings.Index(name, "-"); idx > 0 {
 				taskSlug := name[:idx] + "/" + name[idx+1:]
+				if !validationLogComplete(validationLog) {
+					fmt.Fprintf(out, " Warning: %s has an incomplete validation.log; it will be re-run\n", taskSlug)
+					continue
+				}
 				completed[taskSlug] = true
 			}
 		}
@@ -4371,6 +6581,7 @@ func prepareResumedTasks(
 	outputDir string,
 	completedTasks map[string]bool,
 ) ([]*task.Task, []*task.Task, error) {
+	out := evalOutput()
 	// Build task map for ordering from run config.
 	taskMap := make(map[string]*task.Task)
 	for _, t := range allTasks {
@@ -4391,7 +6602,7 @@ func prepareResumedTasks(
 		logger.Warn("some tasks from original run not found in current build",
 			"missing", missingTasks,
 			"count", len(missingTasks))
-		fmt.Printf(" Warning: %d task(s) from original run not found: %v\n",
+		fmt.Fprintf(out, " Warning: %d task(s) from original run not found: %v\n",
 			len(missingTasks), missingTasks)
 	}
 
@@ -4412,7 +6623,9 @@ func prepareResumedTasks(
 	return orderedTasks, tasksToRun, nil
 }
 
-// cleanIncompleteTaskDirs removes task directories that don't have validation.log.
+// cleanIncompleteTaskDirs removes task directories that don't have a
+// complete validation.log. Directories of tasks requeued by --retry-failed
+// still back a recorded result and are kept until the task runs again.
 func cleanIncompleteTaskDirs(outputDir string, completed map[string]bool, allTasks []*task.Task) error {
 	for _, t := range allTasks {
 		taskSlug := string(t.Language) + "/" + t.Slug
@@ -4422,6 +6635,9 @@ func cleanIncompleteTaskDirs(outputDir string, completed map[string]bool, allTas
 
 		// Task is incomplete, remove its directory if it exists.
 		taskDir := filepath.Join(outputDir, string(t.Language)+"-"+t.Slug)
+		if validationLogComplete(filepath.Join(taskDir, "validation.log")) {
+			continue
+		}
 		if _, err := os.Stat(taskDir); err == nil {
 			if err := os.RemoveAll(taskDir); err != nil {
 				return fmt.Errorf("removing incomplete task dir %s: %w", taskDir, err)
@@ -4455,9 +6671,9 @@ func checkInterrupted(ctx context.Context) bool {
 }
 
 // printResumeCommand prints the command to resume an interrupted eval.
-func printResumeCommand(outputDir string) {
-	fmt.Printf("\n\033[33m⚠ Evaluation interrupted. To resume, run:\033[0m\n")
-	fmt.Printf("  ./sanity eval --resume %s\n\n", outputDir)
+func printResumeCommand(w io.Writer, outputDir string) {
+	fmt.Fprintf(w, "\n\033[33m⚠ Evaluation interrupted. To resume, run:\033[0m\n")
+	fmt.Fprintf(w, "  ./sanity eval --resume %s\n\n", outputDir)
 }
 
 // initSandbox checks if bubblewrap sandboxing should be enabled.
@@ -4468,14 +6684,49 @@ func initSandbox() bool {
 		return false
 	}
 
-	if _, err := exec.LookPath("bwrap"); err != nil {
-		logger.Warn("bubblewrap (bwrap) not found, running agents without sandbox")
+	if _, err := exec.LookPath(sandboxBinary(cfg)); err != nil {
+		logger.Warn("bubblewrap (bwrap) not found, running agents without sandbox", "path", sandboxBinary(cfg))
 		return false
 	}
 
 	return true
 }
 
+// sandboxAllowNetwork reports whether sandboxed agents may use the network,
+// which both --no-network and [sandbox] allow_network = false deny.
+func sandboxAllowNetwork() bool {
+	if evalNoNetwork {
+		return false
+	}
+	return cfg == nil || cfg.Sandbox.AllowNetwork
+}
+
+// checkSandboxNetwork fails when network access is denied but the sandbox
+// that denies it is not active, rather than running agents with network.
+func checkSandboxNetwork() error {
+	if sandboxAllowNetwork() || evalSandboxActive {
+		return nil
+	}
+	return fmt.Errorf("network access is denied (--no-network or [sandbox] allow_network = false) but the bubblewrap sandbox is not active; install bwrap and drop --no-sandbox")
+}
+
+// sandboxExtraArgs returns [sandbox] extra_args, if any.
+func sandboxExtraArgs(c *config.Config) []string {
+	if c == nil {
+		return nil
+	}
+	return c.Sandbox.ExtraArgs
+}
+
+// sandboxBinary returns the bubblewrap binary to run, honoring
+// [sandbox] bwrap_path.
+func sandboxBinary(c *config.Config) string {
+	if c != nil && c.Sandbox.BwrapPath != "" {
+		return c.Sandbox.BwrapPath
+	}
+	return "bwrap"
+}
+
 // protectTasksDir makes the tasks/ directory read-only to prevent agents from
 // modifying embedded task source files during evaluation. Returns a restore
 // function that re-enables write permissions, or nil if protection was not needed.
@@ -4533,23 +6784,61 @@ func protectTasksDir() (restore func(), err error) {
 }
 
 func init() {
-	evalCmd.Flags().StringVar(&evalAgent, "agent", "", "agent to evaluate (see --help for list)")
+	evalCmd.Flags().StringVar(&evalAgent, "agent", "", "agent to evaluate (see --help for list; default: [harness] default_agent)")
 	evalCmd.Flags().StringVar(&evalModel, "model", "", "model to use (e.g., gemini-2.5-pro or google/gemini-2.5-flash)")
 	evalCmd.Flags().StringVar(&evalReasoning, "reasoning", "", "reasoning effort level (e.g., off, none, low, medium, high)")
+	evalCmd.Flags().StringVar(&evalAgentFallback, "agent-fallback", "", "comma-separated agent[:model] chain to retry a task with when the agent infra-fails")
 	evalCmd.Flags().StringVar(&evalTasks, "tasks", "", "comma-separated list of task slugs")
 	evalCmd.Flags().StringVar(&evalLang, "lang", "", "filter by language (go, rust, typescript)")
 	evalCmd.Flags().StringVar(&evalTier, "tier", "core", "filter by tier (core, extended, all)")
 	evalCmd.Flags().StringVar(&evalDifficulty, "difficulty", "", "filter by difficulty (comma-separated)")
 	evalCmd.Flags().IntVar(&evalTimeout, "timeout", 0, "timeout per task in seconds (default from config)")
+	evalCmd.Flags().Int64Var(&evalAgentLogMax, "agent-log-max-bytes", defaultAgentLogMaxBytes, "truncate agent.log once it reaches this size (0 = unlimited)")
+	evalCmd.Flags().Int64Var(&evalAgentRunaway, "agent-runaway-bytes", 0, "kill an agent that writes this much output without editing a file and fail the task as runaway (0 = off)")
+	evalCmd.Flags().IntVar(&evalMinFreeDiskMB, "min-free-disk-mb", 0, "stop the eval when the output directory has less free disk space (default from config, 0 = disabled)")
+	evalCmd.Flags().BoolVar(&evalLint, "lint", false, "run a per-language linter on passing solutions and record warning counts")
+	evalCmd.Flags().BoolVar(&evalRobustness, "robustness", false, "re-validate passing solutions against each task's robustness_tests and report the robustness pass rate separately")
+	evalCmd.Flags().BoolVar(&evalReuseContainer, "reuse-container", false, "validate all tasks of a language in one long-lived container instead of one container per task (faster, weaker isolation)")
+	evalCmd.Flags().StringVar(&evalPromptTemplate, "prompt-template", "", "render agent prompts from this Go text/template file instead of the built-in prompt")
+	evalCmd.Flags().IntVar(&evalPromptBudget, "prompt-budget-tokens", 0, "trim prompt boilerplate when the estimated prompt size exceeds this many tokens (0 = disabled)")
+	evalCmd.Flags().StringVar(&evalSkipLangs, "skip-langs", "", "comma-separated languages to exclude (e.g. kotlin,dart,zig)")
+	evalCmd.Flags().StringVar(&evalChangedSince, "changed-since", "", "only run tasks with files changed since this git ref (e.g. main), read from tasks/ or --tasks-dir")
+	evalCmd.Flags().StringVar(&evalOnlyNew, "only-new", "", "skip tasks already submitted for this agent/model, per a submitted.json record")
+	evalCmd.Flags().Lookup("only-new").NoOptDefVal = "submitted.json"
+	evalCmd.Flags().StringVar(&evalShard, "shard", "", "run only shard i of n (e.g. 1/4) of the selected tasks; combine shard results with merge-shards")
+	evalCmd.Flags().IntVar(&evalSample, "sample", 0, "run a random sample of N tasks from those selected, weighted toward higher-weight tasks (0 = all)")
+	evalCmd.Flags().BoolVar(&evalSampleUniform, "sample-uniform", false, "with --sample, give every task the same chance instead of weighting by difficulty")
+	evalCmd.Flags().Int64Var(&evalSampleSeed, "sample-seed", 0, "seed for --sample (default: time-based; the seed used is printed and saved in run-config.json)")
+	evalCmd.Flags().StringVar(&evalAgentEnvFile, "agent-env-file", "", "load agent environment variables from a dotenv file (overrides each agent's env_file; agent env still wins)")
+	evalCmd.Flags().StringVar(&evalSystemPrompt, "system-prompt", "", "system message passed via the agent's system_prompt_flag, separate from the task prompt")
+	evalCmd.Flags().IntVar(&evalTimeoutGrace, "timeout-grace", 0, "send SIGTERM this many seconds before the agent timeout, then SIGKILL at the deadline (0 = disabled)")
 	evalCmd.Flags().IntVar(&evalParallel, "parallel", 1, "run up to N tasks in parallel")
+	evalCmd.Flags().IntVar(&evalRunParallel, "run-parallel", 1, "run up to N agent/model/repeat combinations of a multi-run at once, each with its own runner")
 	evalCmd.Flags().StringVar(&evalOutputDir, "output", "", "output directory for results")
 	evalCmd.Flags().BoolVar(&evalKeepWorkspaces, "keep-workspaces", false, "keep workspace directories after evaluation")
+	evalCmd.Flags().BoolVar(&evalNoWarmup, "no-warmup", false, "skip pulling every needed image before the first task; pulls then count toward the first task of each language")
+	evalCmd.Flags().BoolVar(&evalUnderstandCosts, "i-understand-costs", false, "run agents that skip permission prompts without asking first (see [harness] confirm_dangerous_agents)")
+	evalCmd.Flags().BoolVar(&evalReportChart, "report-chart", false, "add a bar chart of task outcomes (pass, validation fail, timeout, integrity, external skip) to report.md")
+	evalCmd.Flags().StringVar(&evalExportFormat, "export-format", "", "also export results in another format: swebench (writes swebench.jsonl)")
+	evalCmd.Flags().BoolVar(&evalProgress, "progress", false, "show one status line updated in place instead of per-task banners (periodic lines when stdout is not a terminal)")
+	evalCmd.Flags().BoolVar(&evalJSONLogs, "json-logs", false, "stream per-task lifecycle events to stdout as NDJSON instead of banners (other output goes to stderr)")
+	evalCmd.Flags().BoolVar(&evalFailFast, "fail-fast", false, "stop after the first task that fails validation (not expected to fail), keep the partial results and exit with code 3")
+	evalCmd.Flags().BoolVar(&evalNotify, "notify", false, "ring the terminal bell and send an OSC 9 desktop notification when the eval finishes")
+	evalCmd.Flags().StringVar(&evalNotifyCommand, "notify-command", "", "shell command to run when the eval finishes; receives the output dir and pass rate as $1/$2 and SANITY_OUTPUT_DIR/SANITY_PASS_RATE")
+	evalCmd.Flags().BoolVar(&evalDebugWorkspaces, "debug-workspaces", false, "use deterministic temp workspace names (sanity-eval-<lang>-<slug>, under a per-run temp dir named after the run) instead of random ones")
 	evalCmd.Flags().BoolVar(&evalDryRun, "dry-run", false, "show what tasks would be run without executing")
+	evalCmd.Flags().StringVar(&evalOutputFormat, "output-format", "human", "--dry-run plan format: human or json")
 	evalCmd.Flags().BoolVar(&evalUseMCPTools, "use-mcp-tools", false, "inject MCP tool usage instructions into agent prompt")
+	evalCmd.Flags().BoolVar(&evalMCPAblation, "mcp-ablation", false, "run the agent with and without --use-mcp-tools and compare the results")
+	evalCmd.Flags().StringVar(&evalAgentVersions, "agent-versions", "", "comma-separated label=command versions of the agent to run and compare, e.g. stable=claude,beta=/opt/claude-beta/claude")
 	evalCmd.Flags().BoolVar(&evalUseSkills, "use-skills", false, "inject Agent Skills usage instructions into agent prompt")
 	evalCmd.Flags().BoolVar(&evalDisableMCP, "disable-mcp", false, "disable MCP tools for agents that support it (currently: opencode)")
 	evalCmd.Flags().BoolVar(&evalNoSandbox, "no-sandbox", false, "disable bubblewrap sandbox for agent processes")
+	evalCmd.Flags().BoolVar(&evalNoNetwork, "no-network", false, "deny sandboxed agents all network access, host loopback included (for agents that run their model in-process)")
 	evalCmd.Flags().BoolVar(&evalLegacy, "legacy", false, "expose hidden tests to agent during workspace init (pre-v1.6.0 behavior)")
 	evalCmd.Flags().StringVar(&evalResume, "resume", "", "resume eval from existing output directory")
+	evalCmd.Flags().BoolVar(&evalRetryFailed, "retry-failed", false, "with --resume, also re-run tasks that failed validation (passes are kept)")
+	evalCmd.Flags().StringArrayVar(&evalBaselines, "baseline", nil, "reference run to show in the report as name=run-dir, e.g. reference=./eval-results/solutions (repeatable)")
+	evalCmd.Flags().StringVar(&evalVerifyOnly, "verify-only", "", "re-run only validation against the preserved solutions of a --keep-workspaces run and check the recorded results reproduce")
 	evalCmd.Flags().IntVar(&evalRepeat, "repeat", 1, "repeat each configuration N times for statistical analysis")
 }
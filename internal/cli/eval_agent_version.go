@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/lemon07r/sanityharness/internal/config"
+)
+
+// agentVersionTimeout bounds how long an agent's version command may run.
+const agentVersionTimeout = 15 * time.Second
+
+// detectAgentVersions runs the version command of each distinct agent in
+// specs once and returns the output keyed by agent name. Agents whose
+// version cannot be determined are logged and left out.
+func detectAgentVersions(ctx context.Context, specs []RunSpec) map[string]string {
+	if evalDryRun || cfg == nil {
+		return nil
+	}
+	versions := make(map[string]string)
+	for _, spec := range specs {
+		if _, done := versions[spec.Agent]; done {
+			continue
+		}
+		agentCfg := specAgentConfig(spec)
+		if agentCfg == nil {
+			continue
+		}
+		version, err := agentVersion(ctx, agentCfg, spec.Agent)
+		if err != nil {
+			logger.Warn("could not determine agent version", "agent", spec.Agent, "error", err)
+			continue
+		}
+		versions[spec.Agent] = version
+	}
+	if len(versions) == 0 {
+		return nil
+	}
+	return versions
+}
+
+// agentVersion runs the agent's version command and returns its trimmed
+// output, limited to the first line.
+func agentVersion(ctx context.Context, agentCfg *config.AgentConfig, agentName string) (string, error) {
+	argv := agentVersionCommand(agentCfg)
+	if len(argv) == 0 {
+		return "", errors.New("no version command")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, agentVersionTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	fileEnv, _ := agentFileEnv(agentCfg)
+	cmd.Env = buildAgentEnv(agentCfg.Env, fileEnv, false, false, agentName)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", strings.Join(argv, " "), err)
+	}
+	version, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	version = strings.TrimSpace(version)
+	if version == "" {
+		return "", fmt.Errorf("%s printed nothing", strings.Join(argv, " "))
+	}
+	return version, nil
+}
+
+// agentVersionCommand returns the configured version command, defaulting to
+// "<command> --version".
+func agentVersionCommand(agentCfg *config.AgentConfig) []string {
+	if len(agentCfg.VersionCommand) > 0 {
+		return agentCfg.VersionCommand
+	}
+	if agentCfg.Command == "" {
+		return nil
+	}
+	return []string{agentCfg.Command, "--version"}
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lemon07r/sanityharness/internal/config"
+)
+
+// agentVersionSpecs expands the single spec of an --agent-versions run into
+// one run per "label=command" entry of value. Each run uses the agent's
+// configuration with its command replaced and is labeled by version, so the
+// multi-run comparison shows the versions side by side.
+func agentVersionSpecs(specs []RunSpec, value string) ([]RunSpec, error) {
+	if len(specs) != 1 {
+		return nil, fmt.Errorf("--agent-versions compares versions of one agent; got %d agents", len(specs))
+	}
+	if cfg != nil {
+		if agentCfg := cfg.GetAgent(specs[0].Agent); agentCfg != nil && agentCfg.ShellCommand != "" {
+			return nil, fmt.Errorf("--agent-versions replaces the agent's command, but %q uses shell_command", specs[0].Agent)
+		}
+	}
+	var out []RunSpec
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(value, ",") {
+		label, command, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		label, command = strings.TrimSpace(label), strings.TrimSpace(command)
+		if !ok || label == "" || command == "" {
+			return nil, fmt.Errorf("invalid --agent-versions entry %q (want label=command)", entry)
+		}
+		if seen[label] {
+			return nil, fmt.Errorf("duplicate --agent-versions label %q", label)
+		}
+		seen[label] = true
+		spec := specs[0]
+		spec.Label, spec.Command = label, command
+		out = append(out, spec)
+	}
+	if len(out) < 2 {
+		return nil, fmt.Errorf("--agent-versions needs at least two versions to compare")
+	}
+	return out, nil
+}
+
+// specAgentConfig returns spec's agent configuration with its command
+// override applied, or nil for an unknown agent. A version_command that runs
+// the original command runs the override instead.
+func specAgentConfig(spec RunSpec) *config.AgentConfig {
+	if cfg == nil {
+		return nil
+	}
+	agentCfg := cfg.GetAgent(spec.Agent)
+	if agentCfg == nil || spec.Command == "" {
+		return agentCfg
+	}
+	overridden := *agentCfg
+	overridden.Command = spec.Command
+	if len(agentCfg.VersionCommand) > 0 && agentCfg.VersionCommand[0] == agentCfg.Command {
+		overridden.VersionCommand = append([]string{spec.Command}, agentCfg.VersionCommand[1:]...)
+	}
+	return &overridden
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	resultpkg "github.com/lemon07r/sanityharness/internal/result"
+)
+
+// ValidationAttempt is one validation attempt of a task whose validation ran
+// more than once.
+type ValidationAttempt struct {
+	Number   int     `json:"number"`
+	ExitCode int     `json:"exit_code"`
+	Passed   bool    `json:"passed"`
+	Duration float64 `json:"duration_seconds"`
+}
+
+// validationAttempts returns the attempts of session, or nil when validation
+// ran at most once and the final verdict tells the whole story.
+func validationAttempts(session *resultpkg.Session) []ValidationAttempt {
+	if session == nil || len(session.Attempts) < 2 {
+		return nil
+	}
+	attempts := make([]ValidationAttempt, 0, len(session.Attempts))
+	for _, a := range session.Attempts {
+		attempts = append(attempts, ValidationAttempt{
+			Number:   a.Number,
+			ExitCode: a.ExitCode,
+			Passed:   a.Passed,
+			Duration: a.Duration.Seconds(),
+		})
+	}
+	return attempts
+}
+
+// withAttemptLines prefixes a validation log's output with one HARNESS line
+// per attempt; the output itself is the final attempt's.
+func withAttemptLines(rawOutput string, attempts []ValidationAttempt) string {
+	if len(attempts) == 0 {
+		return rawOutput
+	}
+	var sb strings.Builder
+	for _, a := range attempts {
+		fmt.Fprintf(&sb, "HARNESS: attempt %d/%d exit_code=%d passed=%t duration_seconds=%.3f\n",
+			a.Number, len(attempts), a.ExitCode, a.Passed, a.Duration)
+	}
+	sb.WriteString("\n")
+	sb.WriteString(rawOutput)
+	return sb.String()
+}
+
+// flakyAttempts reports whether attempts disagree on the outcome.
+func flakyAttempts(attempts []ValidationAttempt) bool {
+	for _, a := range attempts[1:] {
+		if a.Passed != attempts[0].Passed {
+			return true
+		}
+	}
+	return false
+}
+
+// writeReportValidationAttempts lists the attempts of tasks whose validation
+// attempts disagreed, e.g. a race that failed once and then passed.
+func writeReportValidationAttempts(sb *strings.Builder, summary EvalSummary) {
+	var flaky []EvalResult
+	for _, r := range summary.Results {
+		if len(r.ValidationAttempts) > 1 && flakyAttempts(r.ValidationAttempts) {
+			flaky = append(flaky, r)
+		}
+	}
+	if len(flaky) == 0 {
+		return
+	}
+
+	sb.WriteString("## Flaky Validation\n\n")
+	sb.WriteString("Tasks whose validation attempts disagreed:\n\n")
+	sb.WriteString("| Task | Attempts |\n")
+	sb.WriteString("|------|----------|\n")
+	for _, r := range flaky {
+		steps := make([]string, 0, len(r.ValidationAttempts))
+		for _, a := range r.ValidationAttempts {
+			icon := "❌"
+			if a.Passed {
+				icon = "✅"
+			}
+			steps = append(steps, fmt.Sprintf("%d: %s exit %d (%.1fs)", a.Number, icon, a.ExitCode, a.Duration))
+		}
+		fmt.Fprintf(sb, "| %s | %s |\n", r.Task, strings.Join(steps, " → "))
+	}
+	sb.WriteString("\n")
+}
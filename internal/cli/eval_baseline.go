@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BaselineScore is a reference run's score restricted to the tasks of the
+// current run, e.g. a known-good solution set or untouched stubs. It frames
+// the agent's score in the report.
+type BaselineScore struct {
+	Name             string  `json:"name"`
+	Source           string  `json:"source"`
+	Passed           int     `json:"passed"`
+	Covered          int     `json:"covered"` // Current-run tasks present in the baseline
+	Total            int     `json:"total"`
+	PassRate         float64 `json:"pass_rate"`
+	WeightedPassRate float64 `json:"weighted_pass_rate"`
+}
+
+// parseBaselineFlag splits a --baseline value of the form name=run-dir.
+func parseBaselineFlag(value string) (name, dir string, err error) {
+	name, dir, ok := strings.Cut(value, "=")
+	name, dir = strings.TrimSpace(name), strings.TrimSpace(dir)
+	if !ok || name == "" || dir == "" {
+		return "", "", fmt.Errorf("invalid --baseline %q (want name=run-dir)", value)
+	}
+	return name, dir, nil
+}
+
+// loadBaselineScores scores each --baseline run against the tasks in results.
+// Baselines that cannot be loaded are skipped with a warning.
+func loadBaselineScores(specs []string, results []EvalResult) []BaselineScore {
+	var scores []BaselineScore
+	for _, spec := range specs {
+		name, dir, err := parseBaselineFlag(spec)
+		if err != nil {
+			logger.Warn("skipping baseline", "error", err)
+			continue
+		}
+		baseline, err := loadSummaryFromDir(dir)
+		if err != nil {
+			logger.Warn("skipping baseline", "name", name, "dir", dir, "error", err)
+			continue
+		}
+		scores = append(scores, scoreBaseline(name, dir, baseline.Results, results))
+	}
+	return scores
+}
+
+// scoreBaseline computes a baseline's pass rate and weighted pass rate over
+// the current run's tasks. Tasks missing from the baseline count as failed.
+func scoreBaseline(name, source string, baseline, results []EvalResult) BaselineScore {
+	byTask := make(map[string]EvalResult, len(baseline))
+	for _, r := range baseline {
+		byTask[r.Task] = r
+	}
+
+	score := BaselineScore{Name: name, Source: source, Total: len(results)}
+	var weighted, maxWeighted float64
+	for _, r := range results {
+		maxWeighted += r.Weight
+		b, ok := byTask[r.Task]
+		if !ok {
+			continue
+		}
+		score.Covered++
+		if b.Passed {
+			score.Passed++
+			weighted += r.Weight
+		}
+	}
+	if score.Total > 0 {
+		score.PassRate = float64(score.Passed) / float64(score.Total) * 100
+	}
+	if maxWeighted > 0 {
+		score.WeightedPassRate = weighted / maxWeighted * 100
+	}
+	return score
+}
+
+func writeReportBaselines(sb *strings.Builder, summary EvalSummary) {
+	if len(summary.Baselines) == 0 {
+		return
+	}
+
+	sb.WriteString("## Baseline Comparison\n\n")
+	sb.WriteString("Reference runs scored on the same tasks as this run.\n\n")
+	sb.WriteString("| Run | Passed | Pass Rate | Weighted Pass Rate | Coverage |\n")
+	sb.WriteString("|-----|--------|-----------|--------------------|----------|\n")
+	fmt.Fprintf(sb, "| **%s (this run)** | %d/%d | %.1f%% | %.1f%% | - |\n",
+		summary.Agent, summary.Passed, summary.Total, summary.PassRate, summary.WeightedPassRate)
+	for _, b := range summary.Baselines {
+		fmt.Fprintf(sb, "| %s | %d/%d | %.1f%% | %.1f%% | %d/%d |\n",
+			b.Name, b.Passed, b.Total, b.PassRate, b.WeightedPassRate, b.Covered, b.Total)
+	}
+	sb.WriteString("\n")
+}
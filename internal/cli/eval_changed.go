@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/lemon07r/sanityharness/internal/task"
+)
+
+// changedTaskIDs returns the IDs of tasks under dir (laid out as
+// <lang>/<slug>/...) with files that differ from gitRef: committed since the
+// ref, modified in the working tree, or untracked and not ignored.
+func changedTaskIDs(dir, gitRef string) (map[string]bool, error) {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("--changed-since: tasks directory %s not found (run from the repository root or pass --tasks-dir)", dir)
+	}
+	if _, err := gitOutput(dir, "rev-parse", "--is-inside-work-tree"); err != nil {
+		return nil, fmt.Errorf("--changed-since: %s is not in a git repository", dir)
+	}
+	if _, err := gitOutput(dir, "rev-parse", "--verify", "--quiet", gitRef+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("--changed-since: unknown git ref %q", gitRef)
+	}
+
+	changed, err := gitOutput(dir, "diff", "--name-only", "-z", "--relative", gitRef, "--", ".")
+	if err != nil {
+		return nil, fmt.Errorf("--changed-since: git diff %s: %w", gitRef, err)
+	}
+	untracked, err := gitOutput(dir, "ls-files", "-z", "--others", "--exclude-standard", "--", ".")
+	if err != nil {
+		return nil, fmt.Errorf("--changed-since: listing untracked files: %w", err)
+	}
+	return taskIDsFromPaths(strings.Split(changed+untracked, "\x00")), nil
+}
+
+// taskIDsFromPaths maps slash-separated paths relative to the tasks directory
+// to the IDs of the tasks containing them. Paths outside a task directory,
+// such as tasks/embed.go, are ignored.
+func taskIDsFromPaths(paths []string) map[string]bool {
+	ids := make(map[string]bool)
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		parts := strings.SplitN(path.Clean(p), "/", 3)
+		if len(parts) == 3 {
+			ids[parts[0]+"/"+parts[1]] = true
+		}
+	}
+	return ids
+}
+
+// filterChangedTasks keeps the tasks whose IDs are in changed.
+func filterChangedTasks(tasks []*task.Task, changed map[string]bool) []*task.Task {
+	var selected []*task.Task
+	for _, t := range tasks {
+		if changed[t.ID()] {
+			selected = append(selected, t)
+		}
+	}
+	return selected
+}
+
+// gitOutput runs git in dir and returns its stdout.
+func gitOutput(dir string, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return string(out), nil
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lemon07r/sanityharness/internal/task"
+)
+
+func TestTaskIDsFromPaths(t *testing.T) {
+	t.Parallel()
+
+	got := taskIDsFromPaths([]string{
+		"go/bank-account/task.toml",
+		"go/bank-account/bank_account_test.go",
+		"rust/regex-lite/src/lib.rs",
+		"embed.go",
+		"go/README.md",
+		"",
+	})
+	want := map[string]bool{"go/bank-account": true, "rust/regex-lite": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("taskIDsFromPaths() = %v, want %v", got, want)
+	}
+}
+
+func TestChangedTaskIDs(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(rel, content string) {
+		t.Helper()
+		p := filepath.Join(repo, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	write("tasks/go/bank-account/task.toml", "v1")
+	write("tasks/go/react/task.toml", "v1")
+	write("tasks/rust/regex-lite/task.toml", "v1")
+	write("README.md", "v1")
+	git("add", "-A")
+	git("commit", "-q", "-m", "base")
+	git("tag", "base")
+
+	write("tasks/go/react/react_test.go", "v2") // committed after the ref
+	write("README.md", "v2")                    // outside tasks/
+	git("add", "-A")
+	git("commit", "-q", "-m", "edit")
+	write("tasks/rust/regex-lite/task.toml", "v2") // uncommitted edit
+	write("tasks/zig/new-task/task.toml", "v1")    // untracked
+
+	got, err := changedTaskIDs(filepath.Join(repo, "tasks"), "base")
+	if err != nil {
+		t.Fatalf("changedTaskIDs() error = %v", err)
+	}
+	want := map[string]bool{"go/react": true, "rust/regex-lite": true, "zig/new-task": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changedTaskIDs() = %v, want %v", got, want)
+	}
+
+	suite := []*task.Task{
+		{Slug: "bank-account", Language: task.Go},
+		{Slug: "react", Language: task.Go},
+		{Slug: "regex-lite", Language: task.Rust},
+	}
+	if selected := filterChangedTasks(suite, got); len(selected) != 2 || selected[0].Slug != "react" || selected[1].Slug != "regex-lite" {
+		t.Errorf("filterChangedTasks() = %v", selected)
+	}
+
+	if _, err := changedTaskIDs(filepath.Join(repo, "tasks"), "no-such-ref"); err == nil || !strings.Contains(err.Error(), "unknown git ref") {
+		t.Errorf("bad ref error = %v", err)
+	}
+	if _, err := changedTaskIDs(t.TempDir(), "base"); err == nil || !strings.Contains(err.Error(), "not in a git repository") {
+		t.Errorf("non-repo error = %v", err)
+	}
+	if _, err := changedTaskIDs(filepath.Join(repo, "missing"), "base"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("missing dir error = %v", err)
+	}
+}
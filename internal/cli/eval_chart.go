@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// outcomeChartWidth is the bar length, in cells, of a 100% outcome.
+const outcomeChartWidth = 40
+
+// reportOutcome is one bar of the --report-chart outcome distribution.
+type reportOutcome struct {
+	label string
+	tasks int
+}
+
+// outcomeDistribution buckets every task of the run by headline outcome:
+// passed, failed validation, timed out (agent or validation), integrity
+// violation, or skipped for an external (auth, quota, infra) failure.
+func outcomeDistribution(summary EvalSummary) []reportOutcome {
+	var passed, validationFailed, timedOut, integrity int
+	for _, r := range summary.Results {
+		switch {
+		case r.Passed:
+			passed++
+		case r.FailureClass == FailureClassIntegrity:
+			integrity++
+		case r.AgentTimedOut || r.FailureClass == FailureClassValidationTimeout:
+			timedOut++
+		default:
+			validationFailed++
+		}
+	}
+	return []reportOutcome{
+		{label: "Passed", tasks: passed},
+		{label: "Validation failed", tasks: validationFailed},
+		{label: "Timed out", tasks: timedOut},
+		{label: "Integrity violation", tasks: integrity},
+		{label: "External skip", tasks: len(summary.ExternalFailures)},
+	}
+}
+
+// writeOutcomeChart draws outcomes as a Unicode bar chart in a code block,
+// each bar scaled to its share of all tasks.
+func writeOutcomeChart(sb *strings.Builder, outcomes []reportOutcome) {
+	total := 0
+	for _, o := range outcomes {
+		total += o.tasks
+	}
+	if total == 0 {
+		return
+	}
+
+	sb.WriteString("\n```\n")
+	for _, o := range outcomes {
+		share := float64(o.tasks) / float64(total)
+		filled := int(share*outcomeChartWidth + 0.5)
+		if o.tasks > 0 && filled == 0 {
+			filled = 1 // Keep rare outcomes visible.
+		}
+		fmt.Fprintf(sb, "%-19s %s%s %4d %5.1f%%\n",
+			o.label, strings.Repeat("█", filled), strings.Repeat("░", outcomeChartWidth-filled), o.tasks, share*100)
+	}
+	sb.WriteString("```\n")
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// dangerousAgentFlags are agent arguments that let an agent act without
+// asking, so a run starts spending from the agent's account right away.
+var dangerousAgentFlags = []string{
+	"--yolo",
+	"--auto",
+	"--dangerously-skip-permissions",
+	"--dangerously-bypass-approvals-and-sandbox",
+	"--dangerously-allow-all",
+	"--allow-all-tools",
+	"--skip-permissions-unsafe",
+}
+
+// confirmedAgents holds the dangerous agents confirmed so far in this
+// session, so each is asked about only on first use.
+var confirmedAgents = make(map[string]bool)
+
+// errDangerousAgentsDeclined is returned when the user answers no to the
+// dangerous agent prompt.
+var errDangerousAgentsDeclined = errors.New("eval cancelled")
+
+// dangerousAgents returns "agent (flag)" for each distinct agent in specs
+// whose resolved command includes a dangerousAgentFlags entry. The command is
+// built the way the run builds it, so --agent-versions overrides, model and
+// reasoning flags and shell_command templates are all checked.
+func dangerousAgents(specs []RunSpec) []string {
+	var found []string
+	for _, spec := range specs {
+		agentCfg := specAgentConfig(spec)
+		if agentCfg == nil {
+			continue
+		}
+		cmd := buildAgentCommand(context.Background(), agentCfg, "", spec.Model, spec.Reasoning, "", false, false, spec.Agent)
+		if flag := dangerousFlag(cmd.Args); flag != "" {
+			entry := fmt.Sprintf("%s (%s)", spec.Agent, flag)
+			if !slices.Contains(found, entry) {
+				found = append(found, entry)
+			}
+		}
+	}
+	return found
+}
+
+// dangerousFlag returns the first dangerousAgentFlags entry in args, or "".
+// Arguments are split on whitespace so a shell_command passed to sh -c is
+// checked word by word, and "--flag=value" forms match too.
+func dangerousFlag(args []string) string {
+	for _, arg := range args {
+		for _, word := range strings.Fields(arg) {
+			word = strings.Trim(word, `'"`)
+			name, _, _ := strings.Cut(word, "=")
+			if slices.Contains(dangerousAgentFlags, name) {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// checkDangerousAgents confirms the dangerous agents among specs before a
+// new eval or batch starts. Agents already confirmed in this session are not
+// asked about again.
+func checkDangerousAgents(specs []RunSpec) error {
+	if evalUnderstandCosts || !cfg.Harness.ConfirmDangerousAgents {
+		return nil
+	}
+	var agents []string
+	for _, a := range dangerousAgents(specs) {
+		if !confirmedAgents[a] {
+			agents = append(agents, a)
+		}
+	}
+	if err := confirmDangerousAgents(agents, os.Stdin, os.Stderr, isTerminal(os.Stdin)); err != nil {
+		return err
+	}
+	for _, a := range agents {
+		confirmedAgents[a] = true
+	}
+	return nil
+}
+
+// confirmDangerousAgents asks on out whether to run the listed agents and
+// reads the answer from in. Without a terminal it prints a warning and
+// proceeds, so scripted and CI runs are not blocked.
+func confirmDangerousAgents(agents []string, in io.Reader, out io.Writer, interactive bool) error {
+	if len(agents) == 0 {
+		return nil
+	}
+	if !interactive {
+		fmt.Fprintf(out, "Warning: %s run without permission prompts and may spend from your subscription "+
+			"(pass --i-understand-costs or set [harness] confirm_dangerous_agents = false to silence this)\n",
+			strings.Join(agents, ", "))
+		return nil
+	}
+
+	fmt.Fprintf(out, "The following agents run without permission prompts and start spending from your subscription immediately:\n\n")
+	for _, a := range agents {
+		fmt.Fprintf(out, "  %s\n", a)
+	}
+	fmt.Fprintf(out, "\nContinue? [y/N] ")
+	response, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("reading response: %w", err)
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	if response != "y" && response != "yes" {
+		return errDangerousAgentsDeclined
+	}
+	return nil
+}
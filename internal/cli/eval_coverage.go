@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+
+	resultpkg "github.com/lemon07r/sanityharness/internal/result"
+	"github.com/lemon07r/sanityharness/internal/task"
+)
+
+// coverageCommand enables coverage in a language's validation command: flags
+// are inserted after the first argument equal to after, and percent matches
+// the reported statement or line coverage in its first group.
+type coverageCommand struct {
+	after   string
+	flags   []string
+	percent *regexp.Regexp
+}
+
+// coverageCommands maps languages to their coverage flags. Tasks in other
+// languages ignore coverage = true.
+var coverageCommands = map[task.Language]coverageCommand{
+	task.Go: {
+		after:   "test",
+		flags:   []string{"-cover"},
+		percent: regexp.MustCompile(`coverage: (\d+(?:\.\d+)?)% of statements`),
+	},
+	task.TypeScript: {
+		after:   "--test",
+		flags:   []string{"--experimental-test-coverage"},
+		percent: regexp.MustCompile(`(?m)^\S*\s*all files\s*\|\s*(\d+(?:\.\d+)?)`),
+	},
+}
+
+// withCoverageFlags returns cmd with the task language's coverage flags
+// inserted, and false when the task does not enable coverage or cmd has no
+// place for them.
+func withCoverageFlags(t *task.Task, cmd []string) ([]string, bool) {
+	if !t.Coverage {
+		return cmd, false
+	}
+	cc, ok := coverageCommands[t.Language]
+	if !ok {
+		return cmd, false
+	}
+	i := slices.Index(cmd, cc.after)
+	if i < 0 {
+		return cmd, false
+	}
+	covered := make([]string, 0, len(cmd)+len(cc.flags))
+	covered = append(covered, cmd[:i+1]...)
+	covered = append(covered, cc.flags...)
+	return append(covered, cmd[i+1:]...), true
+}
+
+// parseCoverage returns the coverage percentage reported in validation
+// output. Output covering several packages reports their mean.
+func parseCoverage(lang task.Language, output string) (float64, bool) {
+	cc, ok := coverageCommands[lang]
+	if !ok {
+		return 0, false
+	}
+	matches := cc.percent.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return 0, false
+	}
+	var sum float64
+	for _, m := range matches {
+		pct, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return 0, false
+		}
+		sum += pct
+	}
+	return sum / float64(len(matches)), true
+}
+
+// applyCoverage records the coverage reported by a validation session of a
+// task with coverage enabled. Results keep the first coverage found.
+func applyCoverage(result *EvalResult, t *task.Task, session *resultpkg.Session) {
+	if !t.Coverage || result.Coverage != nil {
+		return
+	}
+	rawOutput, _, _, ok := lastSessionAttempt(session)
+	if !ok {
+		return
+	}
+	if pct, found := parseCoverage(t.Language, rawOutput); found {
+		result.Coverage = &pct
+	}
+}
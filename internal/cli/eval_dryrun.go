@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lemon07r/sanityharness/internal/task"
+)
+
+// DryRunPlan is what --dry-run --output-format json prints: the runs and
+// the tasks each run would execute, in execution order.
+type DryRunPlan struct {
+	Runs   []RunSpec    `json:"runs"`
+	Repeat int          `json:"repeat"`
+	Tasks  []DryRunTask `json:"tasks"`
+
+	// Wall-clock estimates for the whole eval, in seconds (see
+	// estimateDryRunSeconds).
+	EstimatedSeconds int `json:"estimated_seconds"`
+	WorstCaseSeconds int `json:"worst_case_seconds"`
+}
+
+// DryRunTask is one planned task. TimeoutSeconds is the agent timeout the
+// first run's agent would get for it.
+type DryRunTask struct {
+	ID             string  `json:"id"`
+	Language       string  `json:"language"`
+	Tier           string  `json:"tier"`
+	Difficulty     string  `json:"difficulty"`
+	TimeoutSeconds int     `json:"timeout_seconds"`
+	Weight         float64 `json:"weight"`
+}
+
+// validateOutputFormat checks an eval --output-format value.
+func validateOutputFormat(format string) error {
+	switch format {
+	case "human", "json":
+		return nil
+	default:
+		return fmt.Errorf("invalid --output-format %q (valid: human, json)", format)
+	}
+}
+
+// buildDryRunPlan resolves the planned tasks' timeouts and weights.
+// timeoutSeconds is the run-level --timeout.
+func buildDryRunPlan(specs []RunSpec, tasks []*task.Task, timeoutSeconds, repeat int) DryRunPlan {
+	agentMin := 0
+	if len(specs) > 0 && cfg != nil {
+		if agentCfg := cfg.GetAgent(specs[0].Agent); agentCfg != nil {
+			agentMin = agentCfg.MinTimeout(specs[0].Reasoning)
+		}
+	}
+
+	plan := DryRunPlan{Runs: specs, Repeat: repeat, Tasks: make([]DryRunTask, 0, len(tasks))}
+	for _, t := range tasks {
+		plan.Tasks = append(plan.Tasks, DryRunTask{
+			ID:             t.ID(),
+			Language:       string(t.Language),
+			Tier:           t.Tier,
+			Difficulty:     t.Difficulty,
+			TimeoutSeconds: int(resolveAgentTimeout(taskTimeoutSeconds(timeoutSeconds, evalTimeoutExplicit, t), agentMin, t.AgentTimeout) / time.Second),
+			Weight:         task.ComputeWeight(t).Base,
+		})
+	}
+	return plan
+}
+
+// estimateDryRunSeconds estimates the eval's wall-clock time from the
+// planned agent timeouts. The optimistic estimate assumes every task runs
+// to its timeout, parallel tasks at a time, and never less than the
+// longest task; the worst case also adds every quota and infra retry delay,
+// at maximum jitter, to every task. The attempt that exhausts a retry limit
+// ends the task, so only the delays before retries count. Runs of a multi-run are
+// sequential unless runParallel allows several at once.
+func estimateDryRunSeconds(plan DryRunPlan, parallel, runParallel int) (optimistic, worstCase int) {
+	if len(plan.Tasks) == 0 {
+		return 0, 0
+	}
+	var retryDelays time.Duration
+	for attempt := 1; attempt < quotaMaxRetries(); attempt++ {
+		retryDelays += getRetryDelay(attempt)
+	}
+	for attempt := 1; attempt < infraMaxRetries(); attempt++ {
+		retryDelays += getInfraRetryDelay(attempt)
+	}
+	retrySeconds := int(jitterDelay(retryDelays, retryJitter(), 1) / time.Second)
+
+	parallel = max(parallel, 1)
+	var sum, longest int
+	for _, t := range plan.Tasks {
+		sum += t.TimeoutSeconds
+		longest = max(longest, t.TimeoutSeconds)
+	}
+	perRun := max((sum+parallel-1)/parallel, longest)
+	perRunWorst := max((sum+len(plan.Tasks)*retrySeconds+parallel-1)/parallel, longest+retrySeconds)
+
+	runs := max(len(plan.Runs), 1) * max(plan.Repeat, 1)
+	concurrentRuns := min(max(runParallel, 1), runs)
+	batches := (runs + concurrentRuns - 1) / concurrentRuns
+	return perRun * batches, perRunWorst * batches
+}
+
+// writeDryRunPlanJSON writes plan to w as indented JSON.
+func writeDryRunPlanJSON(w io.Writer, plan DryRunPlan) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(plan)
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lemon07r/sanityharness/internal/config"
+)
+
+// envKeyPattern matches a valid environment variable name in an env file.
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// minRedactedValueLen is the shortest env file value redacted from agent
+// logs; shorter values (flags, small numbers) would blank out ordinary text.
+const minRedactedValueLen = 8
+
+var (
+	envFileCacheMu sync.Mutex
+	envFileCache   = map[string]map[string]string{}
+)
+
+// parseEnvFile parses dotenv-style KEY=VALUE lines. Blank lines, # comments
+// and an "export " prefix are allowed; values may be wrapped in single or
+// double quotes, and double-quoted values unescape \n, \" and \\.
+func parseEnvFile(data []byte) (map[string]string, error) {
+	env := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !envKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("line %d: want KEY=VALUE", lineNo)
+		}
+		value = strings.TrimSpace(value)
+		switch {
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			value = strings.NewReplacer(`\n`, "\n", `\"`, `"`, `\\`, `\`).Replace(value[1 : len(value)-1])
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		default:
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+		}
+		env[key] = value
+	}
+	return env, scanner.Err()
+}
+
+// agentFileEnv returns the variables of the agent's env file: --agent-env-file
+// when set, else the agent's env_file. Files are read once per run.
+func agentFileEnv(agentCfg *config.AgentConfig) (map[string]string, error) {
+	path := evalAgentEnvFile
+	if path == "" && agentCfg != nil {
+		path = agentCfg.EnvFile
+	}
+	if path == "" {
+		return nil, nil
+	}
+
+	envFileCacheMu.Lock()
+	defer envFileCacheMu.Unlock()
+	if env, ok := envFileCache[path]; ok {
+		return env, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading env file: %w", err)
+	}
+	env, err := parseEnvFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing env file %s: %w", path, err)
+	}
+	envFileCache[path] = env
+	return env, nil
+}
+
+// redactEnvValues replaces the values of env in the file at path with
+// [REDACTED:<key>], so credentials an agent echoes do not end up in its log.
+// Longer values are replaced first in case one contains another.
+func redactEnvValues(path string, env map[string]string) {
+	var keys []string
+	for k, v := range env {
+		if len(v) >= minRedactedValueLen {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(env[keys[i]]) != len(env[keys[j]]) {
+			return len(env[keys[i]]) > len(env[keys[j]])
+		}
+		return keys[i] < keys[j]
+	})
+	redacted := data
+	for _, k := range keys {
+		redacted = bytes.ReplaceAll(redacted, []byte(env[k]), []byte("[REDACTED:"+k+"]"))
+	}
+	if !bytes.Equal(redacted, data) {
+		_ = os.WriteFile(path, redacted, 0o644)
+	}
+}
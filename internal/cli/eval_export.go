@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+)
+
+// Export formats accepted by --export-format.
+const exportFormatSWEBench = "swebench"
+
+// swebenchExportFile is the run-directory file --export-format swebench writes.
+const swebenchExportFile = "swebench.jsonl"
+
+// SWEBenchResult is one line of swebench.jsonl. The first three fields follow
+// the SWE-bench evaluation report; the rest carry SanityHarness context:
+//
+//	instance_id        <- EvalResult.Task (e.g. "go/bank-account")
+//	model_name_or_path <- "<agent>/<model>", or the agent alone without --model
+//	resolved           <- EvalResult.Passed
+type SWEBenchResult struct {
+	InstanceID      string       `json:"instance_id"`
+	ModelNameOrPath string       `json:"model_name_or_path"`
+	Resolved        bool         `json:"resolved"`
+	Language        string       `json:"language"`
+	Tier            string       `json:"tier,omitempty"`
+	Difficulty      string       `json:"difficulty,omitempty"`
+	FailureClass    FailureClass `json:"failure_class,omitempty"`
+	WeightedScore   float64      `json:"weighted_score"`
+	Duration        float64      `json:"duration_seconds"`
+}
+
+// validateExportFormat checks an --export-format value.
+func validateExportFormat(format string) error {
+	switch format {
+	case "", exportFormatSWEBench:
+		return nil
+	default:
+		return fmt.Errorf("invalid --export-format %q (valid: %s)", format, exportFormatSWEBench)
+	}
+}
+
+// swebenchResults maps a summary to SWE-bench-style records, one per task
+// result in summary order.
+func swebenchResults(summary EvalSummary) []SWEBenchResult {
+	model := summary.Agent
+	if summary.Model != "" {
+		model += "/" + summary.Model
+	}
+	records := make([]SWEBenchResult, 0, len(summary.Results))
+	for _, r := range summary.Results {
+		records = append(records, SWEBenchResult{
+			InstanceID:      r.Task,
+			ModelNameOrPath: model,
+			Resolved:        r.Passed,
+			Language:        r.Language,
+			Tier:            r.Tier,
+			Difficulty:      r.Difficulty,
+			FailureClass:    r.FailureClass,
+			WeightedScore:   r.WeightedScore,
+			Duration:        r.Duration,
+		})
+	}
+	return records
+}
+
+// writeSWEBenchExport writes swebench.jsonl to outputDir and returns its path.
+func writeSWEBenchExport(outputDir string, summary EvalSummary) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, rec := range swebenchResults(summary) {
+		if err := enc.Encode(rec); err != nil {
+			return "", fmt.Errorf("encoding %s: %w", rec.InstanceID, err)
+		}
+	}
+	path := filepath.Join(outputDir, swebenchExportFile)
+	if err := writeFileAtomic(path, buf.Bytes(), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,38 @@
+package cli
+
+import "fmt"
+
+// failFastExitCode is the exit code of an eval that --fail-fast stopped.
+const failFastExitCode = 3
+
+// isFailFastTrigger reports whether a scored result stops a --fail-fast
+// run: it failed and was not expected to. Resumable external failures never
+// reach this check.
+func isFailFastTrigger(r EvalResult) bool {
+	return !r.Passed && !r.ExpectedFail
+}
+
+// failFastError returns the exit error for an eval one of whose runs
+// --fail-fast stopped, or nil when fail-fast never triggered.
+func failFastError(summaries ...*EvalSummary) error {
+	for _, s := range summaries {
+		if s != nil && s.FailFastTask != "" {
+			return &exitError{code: failFastExitCode}
+		}
+	}
+	return nil
+}
+
+// stopMultiRunOnFailFast returns the fail-fast exit error once a run of a
+// multi-run session has stopped under --fail-fast, after telling the user
+// how to resume the runs it skipped. It returns nil otherwise.
+func stopMultiRunOnFailFast(umbrellaDir string, tracker *multiRunTracker) error {
+	out := evalOutput()
+	taskID := tracker.failFastTask()
+	if taskID == "" {
+		return nil
+	}
+	fmt.Fprintf(out, "\n --fail-fast: %s failed; remaining runs were not started\n", taskID)
+	printMultiRunResumeCommand(out, umbrellaDir)
+	return &exitError{code: failFastExitCode}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// failureArtifacts are the per-task files worth opening when triaging a
+// failed task, in the order they are listed in failures.md.
+var failureArtifacts = []string{
+	"validation.log",
+	"container.log",
+	"agent.log",
+	"tree.txt",
+	"integrity.json",
+	"integrity-diff",
+	"integrity-files",
+	"sandbox.json",
+}
+
+// failedResults returns the results that failed, excluding expected
+// failures (XFAIL), which need no triage.
+func failedResults(results []EvalResult) []EvalResult {
+	var failed []EvalResult
+	for _, r := range results {
+		if !r.Passed && !r.ExpectedFail {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// generateFailuresReport renders failures.md: the task results and errors
+// sections of report.md restricted to failed tasks, plus the artifacts each
+// failed task left in outputDir. It returns "" when nothing failed.
+func generateFailuresReport(summary EvalSummary, outputDir string) string {
+	failed := failedResults(summary.Results)
+	if len(failed) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# Failed Tasks\n\n")
+	fmt.Fprintf(&sb, "%d of %d tasks failed for **%s**. See report.md for the full run.\n\n",
+		len(failed), summary.Total, summary.Agent)
+
+	filtered := summary
+	filtered.Results = failed
+	writeReportTaskResults(&sb, filtered)
+	writeReportErrors(&sb, filtered)
+	writeReportFailureArtifacts(&sb, failed, outputDir)
+	sb.WriteString("---\n")
+	fmt.Fprintf(&sb, "*Generated by SanityHarness on %s*\n", summary.Timestamp)
+
+	return sb.String()
+}
+
+// writeReportFailureArtifacts lists, per failed task, the artifact files
+// present in its task output directory.
+func writeReportFailureArtifacts(sb *strings.Builder, failed []EvalResult, outputDir string) {
+	sb.WriteString("## Artifacts\n\n")
+	for _, r := range failed {
+		taskDir := strings.Replace(r.Task, "/", "-", 1)
+		var present []string
+		for _, name := range failureArtifacts {
+			if _, err := os.Stat(filepath.Join(outputDir, taskDir, name)); err == nil {
+				present = append(present, fmt.Sprintf("[%s](%s/%s)", name, taskDir, name))
+			}
+		}
+		if len(present) == 0 {
+			fmt.Fprintf(sb, "- **%s**: none\n", r.Task)
+			continue
+		}
+		fmt.Fprintf(sb, "- **%s**: %s\n", r.Task, strings.Join(present, ", "))
+	}
+	sb.WriteString("\n")
+}
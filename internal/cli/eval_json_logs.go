@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Lifecycle events written by --json-logs.
+const (
+	eventTaskStarted        = "task_started"
+	eventAgentFinished      = "agent_finished"
+	eventValidationFinished = "validation_finished"
+	eventTaskResult         = "task_result"
+)
+
+// evalEvent is one NDJSON line of --json-logs output.
+type evalEvent struct {
+	Event        string  `json:"event"`
+	Time         string  `json:"time"`
+	Task         string  `json:"task"`
+	Agent        string  `json:"agent,omitempty"`
+	Model        string  `json:"model,omitempty"`
+	Attempt      int     `json:"attempt,omitempty"`
+	Passed       *bool   `json:"passed,omitempty"`
+	FailureClass string  `json:"failure_class,omitempty"`
+	Duration     float64 `json:"duration_seconds,omitempty"`
+	Error        string  `json:"error,omitempty"`
+}
+
+// eventLog writes evalEvents as NDJSON. Writes are serialized, so parallel
+// tasks never interleave lines. A nil eventLog discards events.
+type eventLog struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// evalEvents is the --json-logs event stream, nil when the flag is off.
+var evalEvents *eventLog
+
+// evalOutput returns where eval prints its human-readable output: stdout,
+// or stderr under --json-logs so stdout carries only NDJSON events.
+func evalOutput() *os.File {
+	if evalJSONLogs {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+func newEventLog(w io.Writer) *eventLog {
+	return &eventLog{enc: json.NewEncoder(w)}
+}
+
+func (l *eventLog) emit(e evalEvent) {
+	if l == nil {
+		return
+	}
+	e.Time = time.Now().UTC().Format(time.RFC3339Nano)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_ = l.enc.Encode(e)
+}
+
+// emitAgentFinished records the end of a task's agent run. Attempt counts
+// the agent's quota and infra retries.
+func (l *eventLog) emitAgentFinished(result EvalResult, agent, model string) {
+	l.emit(evalEvent{
+		Event:        eventAgentFinished,
+		Task:         result.Task,
+		Agent:        agent,
+		Model:        model,
+		Attempt:      1 + result.QuotaRetries + result.InfraRetries,
+		FailureClass: string(result.FailureClass),
+		Duration:     result.AgentTime,
+		Error:        result.Error,
+	})
+}
+
+// emitValidationFinished records the end of a task's validation. Attempt is
+// the number of validation attempts.
+func (l *eventLog) emitValidationFinished(result EvalResult) {
+	passed := result.Passed
+	l.emit(evalEvent{
+		Event:    eventValidationFinished,
+		Task:     result.Task,
+		Attempt:  result.Attempts,
+		Passed:   &passed,
+		Duration: result.ValidateTime,
+		Error:    result.Error,
+	})
+}
+
+// emitTaskResult records a task's final result, produced by agent.
+func (l *eventLog) emitTaskResult(result EvalResult, agent string) {
+	passed := result.Passed
+	l.emit(evalEvent{
+		Event:        eventTaskResult,
+		Task:         result.Task,
+		Agent:        agent,
+		Attempt:      result.Attempts,
+		Passed:       &passed,
+		FailureClass: string(result.FailureClass),
+		Duration:     result.Duration,
+		Error:        result.Error,
+	})
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// junitTestSuites is the root of junit.xml. A run is one test suite with a
+// test case per task.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+}
+
+// buildJUnitReport maps a run's results to JUnit test cases. Failed tasks
+// become failures typed by failure class; integrity violations carry the
+// modified-file list as their message. Expected failures and tasks skipped
+// for external reasons (quota, auth, infra) become skipped test cases.
+func buildJUnitReport(summary EvalSummary) junitTestSuites {
+	suite := junitTestSuite{
+		Name:      summary.Agent + "/" + summary.Model,
+		Time:      junitSeconds(summary.Duration),
+		Timestamp: summary.Timestamp,
+	}
+	if summary.RunLabel != "" {
+		suite.Name += " [" + summary.RunLabel + "]"
+	}
+
+	for _, r := range summary.Results {
+		tc := junitTestCase{Name: r.Task, Classname: r.Language, Time: junitSeconds(r.Duration)}
+		switch {
+		case r.Passed:
+		case r.ExpectedFail:
+			tc.Skipped = &junitMessage{Message: "expected failure"}
+		default:
+			message := r.Error
+			if message == "" {
+				message = "validation failed"
+			}
+			failureType := string(r.FailureClass)
+			if failureType == "" || r.FailureClass == FailureClassNone {
+				failureType = string(r.Status)
+			}
+			tc.Failure = &junitMessage{Message: message, Type: failureType}
+		}
+		suite.add(tc)
+	}
+	for _, ef := range summary.ExternalFailures {
+		lang, _, _ := strings.Cut(ef.Task, "/")
+		message := "skipped: " + string(ef.FailureClass)
+		if ef.Error != "" {
+			message += ": " + ef.Error
+		}
+		suite.add(junitTestCase{
+			Name:      ef.Task,
+			Classname: lang,
+			Time:      junitSeconds(0),
+			Skipped:   &junitMessage{Message: message, Type: string(ef.FailureClass)},
+		})
+	}
+
+	return junitTestSuites{
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+}
+
+func (s *junitTestSuite) add(tc junitTestCase) {
+	s.Tests++
+	if tc.Failure != nil {
+		s.Failures++
+	}
+	if tc.Skipped != nil {
+		s.Skipped++
+	}
+	s.Cases = append(s.Cases, tc)
+}
+
+func junitSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
+
+// writeJUnitReport writes junit.xml to outputDir and returns its path.
+func writeJUnitReport(outputDir string, summary EvalSummary) (string, error) {
+	data, err := xml.MarshalIndent(buildJUnitReport(summary), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(outputDir, "junit.xml")
+	data = append([]byte(xml.Header), append(data, '\n')...)
+	return path, writeFileAtomic(path, data, 0o644)
+}
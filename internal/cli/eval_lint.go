@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/lemon07r/sanityharness/internal/runner"
+	"github.com/lemon07r/sanityharness/internal/task"
+)
+
+// lintCommand is a per-language linter run in the task container by --lint,
+// with a pattern matching one line per reported warning.
+type lintCommand struct {
+	cmd     []string
+	warning *regexp.Regexp
+}
+
+// lintCommands maps languages to their linter. Languages without an entry
+// are not linted.
+var lintCommands = map[task.Language]lintCommand{
+	task.Go: {
+		cmd:     []string{"go", "vet", "./..."},
+		warning: regexp.MustCompile(`(?m)^\S+\.go:\d+:\d+: `),
+	},
+	task.Rust: {
+		cmd:     []string{"cargo", "clippy", "--all-targets", "--quiet", "--message-format=short"},
+		warning: regexp.MustCompile(`(?m)^\S+\.rs:\d+:\d+: (warning|error)`),
+	},
+	task.TypeScript: {
+		cmd:     []string{"npx", "--no-install", "eslint", "--format", "unix", "."},
+		warning: regexp.MustCompile(`(?m)^\S+:\d+:\d+: `),
+	},
+	task.Dart: {
+		cmd:     []string{"dart", "analyze", "--format=machine"},
+		warning: regexp.MustCompile(`(?m)^(INFO|WARNING|ERROR)\|`),
+	},
+}
+
+// countLintWarnings counts the warnings reported in linter output.
+func countLintWarnings(lang task.Language, output string) int {
+	lc, ok := lintCommands[lang]
+	if !ok {
+		return 0
+	}
+	return len(lc.warning.FindAllStringIndex(output, -1))
+}
+
+// runLint runs the task language's linter over a passing solution and
+// records the warning count on result. Lint output goes to lint.log; lint
+// never affects pass/fail or scoring.
+func runLint(
+	ctx context.Context,
+	r *runner.Runner,
+	t *task.Task,
+	workspaceDir, taskOutputDir string,
+	timeout int,
+	result *EvalResult,
+) {
+	lc, ok := lintCommands[t.Language]
+	if !ok {
+		return
+	}
+
+	session, err := r.Run(ctx, runner.RunOptions{
+		Task:              t,
+		WorkspaceDir:      workspaceDir,
+		Timeout:           timeout,
+		MaxAttempts:       1,
+		ValidationCommand: lc.cmd,
+		Quiet:             true,
+	})
+	rawOutput, exitCode, _, hasAttempt := lastSessionAttempt(session)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "$ %s\n%s\n", strings.Join(lc.cmd, " "), rawOutput)
+	if writeErr := os.WriteFile(filepath.Join(taskOutputDir, "lint.log"), []byte(sb.String()), 0o644); writeErr != nil {
+		logger.Debug("failed to write lint log", "task", t.ID(), "error", writeErr)
+	}
+
+	switch {
+	case err != nil:
+		result.LintError = err.Error()
+		return
+	case !hasAttempt:
+		result.LintError = "linter produced no result"
+		return
+	}
+
+	warnings := countLintWarnings(t.Language, rawOutput)
+	if warnings == 0 && exitCode != 0 {
+		// Nonzero exit with nothing we recognize usually means the linter
+		// is missing from the image.
+		result.LintError = fmt.Sprintf("linter exited with code %d", exitCode)
+		return
+	}
+	result.Linted = true
+	result.LintWarnings = warnings
+}
+
+func writeReportLint(sb *strings.Builder, summary EvalSummary) {
+	if !summary.Lint {
+		return
+	}
+
+	sb.WriteString("## Code Quality (Lint)\n\n")
+	avg := 0.0
+	if summary.LintedTasks > 0 {
+		avg = float64(summary.TotalLintWarnings) / float64(summary.LintedTasks)
+	}
+	fmt.Fprintf(sb, "- **Linted tasks**: %d\n", summary.LintedTasks)
+	fmt.Fprintf(sb, "- **Total warnings**: %d (%.1f per linted task)\n\n", summary.TotalLintWarnings, avg)
+
+	var rows []string
+	for _, r := range summary.Results {
+		switch {
+		case r.LintError != "":
+			rows = append(rows, fmt.Sprintf("| %s | - | %s |", r.Task, r.LintError))
+		case r.Linted && r.LintWarnings > 0:
+			rows = append(rows, fmt.Sprintf("| %s | %d | |", r.Task, r.LintWarnings))
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+	sb.WriteString("| Task | Warnings | Note |\n")
+	sb.WriteString("|------|----------|------|\n")
+	sb.WriteString(strings.Join(rows, "\n"))
+	sb.WriteString("\n\n")
+}
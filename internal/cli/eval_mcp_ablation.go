@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Run labels of the two halves of an --mcp-ablation experiment.
+const (
+	mcpAblationOn  = "mcp"
+	mcpAblationOff = "no-mcp"
+)
+
+// MCPAblation is the effect of --use-mcp-tools measured by --mcp-ablation.
+// Deltas are MCP minus no-MCP; with --repeat, each side is the mean of its
+// runs.
+type MCPAblation struct {
+	MCPWeightedScore   float64           `json:"mcp_weighted_score"`
+	NoMCPWeightedScore float64           `json:"no_mcp_weighted_score"`
+	WeightedScoreDelta float64           `json:"weighted_score_delta"`
+	MCPPassRate        float64           `json:"mcp_pass_rate"`
+	NoMCPPassRate      float64           `json:"no_mcp_pass_rate"`
+	PassRateDelta      float64           `json:"pass_rate_delta"`
+	Tasks              []MCPAblationTask `json:"tasks,omitempty"`
+}
+
+// MCPAblationTask is a task whose pass rate differs with MCP tools.
+type MCPAblationTask struct {
+	Task          string  `json:"task"`
+	MCPPassRate   float64 `json:"mcp_pass_rate"`
+	NoMCPPassRate float64 `json:"no_mcp_pass_rate"`
+	Delta         float64 `json:"delta"`
+}
+
+// mcpAblationSpecs expands the single spec of an --mcp-ablation run into a
+// run with --use-mcp-tools and one without.
+func mcpAblationSpecs(specs []RunSpec) ([]RunSpec, error) {
+	if len(specs) != 1 {
+		return nil, fmt.Errorf("--mcp-ablation compares one agent with itself; got %d agents", len(specs))
+	}
+	on, off := true, false
+	withMCP, withoutMCP := specs[0], specs[0]
+	withMCP.Label, withMCP.MCPTools = mcpAblationOn, &on
+	withoutMCP.Label, withoutMCP.MCPTools = mcpAblationOff, &off
+	return []RunSpec{withMCP, withoutMCP}, nil
+}
+
+// mcpAblation compares the "mcp" and "no-mcp" runs among summaries. It
+// returns nil unless both are present.
+func mcpAblation(summaries []EvalSummary) *MCPAblation {
+	var on, off []EvalSummary
+	for _, s := range summaries {
+		switch s.RunLabel {
+		case mcpAblationOn:
+			on = append(on, s)
+		case mcpAblationOff:
+			off = append(off, s)
+		}
+	}
+	if len(on) == 0 || len(off) == 0 {
+		return nil
+	}
+
+	a := &MCPAblation{
+		MCPWeightedScore:   mean(summaryValues(on, func(s EvalSummary) float64 { return s.WeightedScore })),
+		NoMCPWeightedScore: mean(summaryValues(off, func(s EvalSummary) float64 { return s.WeightedScore })),
+		MCPPassRate:        mean(summaryValues(on, func(s EvalSummary) float64 { return s.PassRate })),
+		NoMCPPassRate:      mean(summaryValues(off, func(s EvalSummary) float64 { return s.PassRate })),
+	}
+	a.WeightedScoreDelta = a.MCPWeightedScore - a.NoMCPWeightedScore
+	a.PassRateDelta = a.MCPPassRate - a.NoMCPPassRate
+
+	onRates, offRates := taskPassRates(on), taskPassRates(off)
+	for name, onRate := range onRates {
+		offRate, ok := offRates[name]
+		if !ok || onRate == offRate {
+			continue
+		}
+		a.Tasks = append(a.Tasks, MCPAblationTask{
+			Task: name, MCPPassRate: onRate, NoMCPPassRate: offRate, Delta: onRate - offRate,
+		})
+	}
+	sort.Slice(a.Tasks, func(i, j int) bool {
+		if a.Tasks[i].Delta != a.Tasks[j].Delta {
+			return a.Tasks[i].Delta > a.Tasks[j].Delta
+		}
+		return a.Tasks[i].Task < a.Tasks[j].Task
+	})
+	return a
+}
+
+func summaryValues(summaries []EvalSummary, value func(EvalSummary) float64) []float64 {
+	vals := make([]float64, 0, len(summaries))
+	for _, s := range summaries {
+		vals = append(vals, value(s))
+	}
+	return vals
+}
+
+// taskPassRates returns each task's pass rate (0-100) across summaries.
+func taskPassRates(summaries []EvalSummary) map[string]float64 {
+	passed := make(map[string]int)
+	total := make(map[string]int)
+	for _, s := range summaries {
+		for _, r := range s.Results {
+			total[r.Task]++
+			if r.Passed {
+				passed[r.Task]++
+			}
+		}
+	}
+	rates := make(map[string]float64, len(total))
+	for name, n := range total {
+		rates[name] = float64(passed[name]) / float64(n) * 100
+	}
+	return rates
+}
+
+// writeMCPAblationReport appends the MCP ablation section to a comparison
+// report: the overall effect and the tasks MCP tools helped or hurt.
+func writeMCPAblationReport(sb *strings.Builder, a *MCPAblation) {
+	fmt.Fprintf(sb, "### MCP Ablation\n\n")
+	fmt.Fprintf(sb, "| Metric | MCP | No MCP | Δ |\n")
+	fmt.Fprintf(sb, "|--------|-----|--------|---|\n")
+	fmt.Fprintf(sb, "| Weighted Score | %.2f | %.2f | %+.2f |\n", a.MCPWeightedScore, a.NoMCPWeightedScore, a.WeightedScoreDelta)
+	fmt.Fprintf(sb, "| Pass Rate | %.1f%% | %.1f%% | %+.1f%% |\n", a.MCPPassRate, a.NoMCPPassRate, a.PassRateDelta)
+	sb.WriteString("\n")
+
+	if len(a.Tasks) == 0 {
+		sb.WriteString("No task changed outcome with MCP tools.\n\n")
+		return
+	}
+	fmt.Fprintf(sb, "| Task | MCP | No MCP | Δ |\n")
+	fmt.Fprintf(sb, "|------|-----|--------|---|\n")
+	for _, t := range a.Tasks {
+		fmt.Fprintf(sb, "| %s | %.0f%% | %.0f%% | %+.0f%% |\n", t.Task, t.MCPPassRate, t.NoMCPPassRate, t.Delta)
+	}
+	sb.WriteString("\n")
+}
@@ -1,8 +1,11 @@
 package cli
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"path/filepath"
@@ -18,9 +21,14 @@ import (
 // runResult tracks the outcome of a single run in a multi-run session.
 type runResult struct {
 	spec    RunSpec
+	specIdx int
 	repeat  int
 	summary *EvalSummary
 	err     error
+
+	// interrupted is set when the session was interrupted while the run
+	// executed, so it stopped short and resumes rather than counting as done.
+	interrupted bool
 }
 
 // MultiRunConfig is persisted as multi-run-config.json in the umbrella directory.
@@ -62,6 +70,13 @@ type RepeatStats struct {
 	MaxWeightedScore    float64            `json:"max_weighted_score"`
 	MeanDuration        float64            `json:"mean_duration_seconds"`
 	TaskConsistency     map[string]float64 `json:"task_consistency"`
+
+	// 95% confidence intervals of the means (Student's t), zero with
+	// fewer than two runs. The pass rate interval is clamped to [0, 100].
+	CI95PassRateLow       float64 `json:"ci95_pass_rate_low"`
+	CI95PassRateHigh      float64 `json:"ci95_pass_rate_high"`
+	CI95WeightedScoreLow  float64 `json:"ci95_weighted_score_low"`
+	CI95WeightedScoreHigh float64 `json:"ci95_weighted_score_high"`
 }
 
 // Comparison holds a side-by-side comparison of multiple eval runs.
@@ -70,6 +85,11 @@ type Comparison struct {
 	TaskMatrix map[string]map[string]string `json:"task_matrix"`
 	BestRun    string                       `json:"best_run"`
 	BestScore  float64                      `json:"best_weighted_score"`
+
+	// MCPAblation is set for --mcp-ablation runs.
+	MCPAblation *MCPAblation `json:"mcp_ablation,omitempty"`
+	// Baseline is set when runs are compared to a baseline model's run.
+	Baseline *BaselineRelative `json:"baseline,omitempty"`
 }
 
 // ComparisonRun is one entry in a comparison table.
@@ -86,6 +106,10 @@ type ComparisonRun struct {
 	Total               int     `json:"total"`
 	Duration            float64 `json:"duration_seconds"`
 	IntegrityViolations int     `json:"integrity_violations"`
+
+	// RelativeScore is the run's score relative to a baseline model's run
+	// (sanity compare --compare-baseline-model).
+	RelativeScore *float64 `json:"relative_score,omitempty"`
 }
 
 // broadcastOrSplit splits a comma-separated string into N values.
@@ -125,6 +149,9 @@ func multiRunSubdir(umbrella string, spec RunSpec, specIdx, rep, totalRepeats in
 	if spec.Model != "" {
 		name += "-" + sanitizeModel(spec.Model)
 	}
+	if spec.Label != "" {
+		name += "-" + spec.Label
+	}
 	if totalRepeats > 1 {
 		return filepath.Join(umbrella, name, fmt.Sprintf("run-%d", rep))
 	}
@@ -140,7 +167,7 @@ func writeMultiRunConfig(umbrellaDir string, specs []RunSpec, shared SharedConfi
 		CreatedAt: time.Now().Format(time.RFC3339),
 	}
 	data, _ := json.MarshalIndent(cfg, "", "  ")
-	_ = os.WriteFile(filepath.Join(umbrellaDir, "multi-run-config.json"), data, 0o644)
+	_ = writeFileAtomic(filepath.Join(umbrellaDir, "multi-run-config.json"), data, 0o644)
 }
 
 // updateMultiRunState writes the current state of all runs to multi-run-state.json.
@@ -151,22 +178,25 @@ func updateMultiRunState(umbrellaDir string, results []runResult, specs []RunSpe
 		Specs:  specs,
 	}
 
-	// Build a set of completed runs.
-	completed := make(map[string]bool)
+	// Only runs that have returned a result are done; runs still executing
+	// under --run-parallel stay pending. Runs are keyed by spec index, since
+	// specs may share an agent (--mcp-ablation, --agent-versions).
+	statuses := make(map[[2]int]string)
 	for _, rr := range results {
-		key := fmt.Sprintf("%s-%d", rr.spec.Agent, rr.repeat)
-		if rr.summary != nil || rr.err != nil {
-			completed[key] = true
+		switch {
+		case rr.interrupted:
+			statuses[[2]int{rr.specIdx, rr.repeat}] = "interrupted"
+		case rr.summary != nil || rr.err != nil:
+			statuses[[2]int{rr.specIdx, rr.repeat}] = "completed"
 		}
 	}
 
 	for specIdx, spec := range specs {
 		for rep := 1; rep <= repeat; rep++ {
 			dir := multiRunSubdir("", spec, specIdx, rep, repeat)
-			key := fmt.Sprintf("%s-%d", spec.Agent, rep)
 			status := "pending"
-			if completed[key] {
-				status = "completed"
+			if st, ok := statuses[[2]int{specIdx, rep}]; ok {
+				status = st
 			}
 			state.Runs = append(state.Runs, MultiRunItem{
 				SpecIndex: specIdx,
@@ -179,16 +209,17 @@ func updateMultiRunState(umbrellaDir string, results []runResult, specs []RunSpe
 
 	// If interrupted, mark the last non-completed run as interrupted.
 	if interrupted {
-		markInterruptedRun(state.Runs, specs, results)
+		markInterruptedRun(state.Runs, results)
 	}
 
 	data, _ := json.MarshalIndent(state, "", "  ")
-	_ = os.WriteFile(filepath.Join(umbrellaDir, "multi-run-state.json"), data, 0o644)
+	_ = writeFileAtomic(filepath.Join(umbrellaDir, "multi-run-state.json"), data, 0o644)
+	writeStatusMatrix(umbrellaDir, state)
 }
 
 // markInterruptedRun finds the run just before the first pending one and marks it
 // as interrupted if the corresponding result had an error.
-func markInterruptedRun(runs []MultiRunItem, specs []RunSpec, results []runResult) {
+func markInterruptedRun(runs []MultiRunItem, results []runResult) {
 	for i := range runs {
 		if runs[i].Status != "pending" || i == 0 {
 			continue
@@ -197,7 +228,7 @@ func markInterruptedRun(runs []MultiRunItem, specs []RunSpec, results []runResul
 			break
 		}
 		for _, rr := range results {
-			if rr.spec.Agent == specs[runs[i-1].SpecIndex].Agent &&
+			if rr.specIdx == runs[i-1].SpecIndex &&
 				rr.repeat == runs[i-1].Repeat &&
 				rr.err != nil {
 				runs[i-1].Status = "interrupted"
@@ -214,7 +245,10 @@ func isMultiRunDir(dir string) bool {
 }
 
 // resumeMultiRun resumes a multi-run session from its umbrella directory.
-func resumeMultiRun(resumeDir string) error {
+// A repeat count above the original tops up the session with additional
+// repeats; zero keeps the original count.
+func resumeMultiRun(resumeDir string, repeat int) error {
+	out := evalOutput()
 	// Load multi-run config.
 	cfgData, err := os.ReadFile(filepath.Join(resumeDir, "multi-run-config.json"))
 	if err != nil {
@@ -235,32 +269,54 @@ func resumeMultiRun(resumeDir string) error {
 		return fmt.Errorf("parsing multi-run state: %w", err)
 	}
 
+	if repeat > 0 && repeat != mrCfg.Repeat {
+		prevRepeat := mrCfg.Repeat
+		if err := extendMultiRunRepeats(&mrCfg, &state, repeat); err != nil {
+			return err
+		}
+		// Later resumes read the raised repeat count back, so failing to
+		// persist it must stop the top-up.
+		data, err := json.MarshalIndent(mrCfg, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling multi-run config: %w", err)
+		}
+		if err := writeFileAtomic(filepath.Join(resumeDir, "multi-run-config.json"), data, 0o644); err != nil {
+			return fmt.Errorf("writing multi-run config: %w", err)
+		}
+		if data, err = json.MarshalIndent(state, "", "  "); err != nil {
+			return fmt.Errorf("marshaling multi-run state: %w", err)
+		}
+		if err := writeFileAtomic(filepath.Join(resumeDir, "multi-run-state.json"), data, 0o644); err != nil {
+			return fmt.Errorf("writing multi-run state: %w", err)
+		}
+		fmt.Fprintf(out, " Topping up repeats: %d -> %d\n", prevRepeat, repeat)
+	}
+
 	// Restore shared config globals for runner creation.
 	shared := mrCfg.Shared
 	restoreSharedConfigGlobals(shared)
 
 	// Create runner.
-	r, err := newRunnerFromConfig()
+	r, err := newSharedRunner(shared)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = r.Close() }()
 
-	if shared.Legacy {
-		r.LegacyHiddenTests = true
-	}
-
 	// Load and filter tasks.
 	allTasks, err := r.ListTasks()
 	if err != nil {
 		return fmt.Errorf("listing tasks: %w", err)
 	}
-	allTasks = filterTasksForShared(allTasks, shared)
+	allTasks = filterTasksForShared(allTasks, &shared)
 	if len(allTasks) == 0 {
 		return fmt.Errorf("no tasks match the specified filters")
 	}
 
 	evalSandboxActive = initSandbox()
+	if err := checkSandboxNetwork(); err != nil {
+		return err
+	}
 
 	if restoreFn, err := protectTasksDir(); err != nil {
 		logger.Warn("failed to protect tasks directory", "error", err)
@@ -273,8 +329,61 @@ func resumeMultiRun(resumeDir string) error {
 
 	timestamp := time.Now().Format("2006-01-02T150405")
 
-	var allSummaries []runResult
+	completed, jobs, items := planMultiRunResume(resumeDir, mrCfg, state)
+
+	printRunParallelNotice(out, len(jobs), shared.Parallel)
+	setRunGlobals(shared)
+	tracker := newMultiRunTracker(resumeDir, mrCfg.Specs, mrCfg.Repeat, completed)
+	interrupted := runMultiRunJobs(interruptCtx, jobs, evalRunParallel, tracker, func(job multiRunJob) runResult {
+		runR := r
+		if evalRunParallel > 1 {
+			var err error
+			if runR, err = newSharedRunner(shared); err != nil {
+				return runResult{spec: job.spec, specIdx: job.specIdx, repeat: job.repeat, err: err}
+			}
+			defer func() { _ = runR.Close() }()
+		}
+
+		// For interrupted runs, use single-run resume logic.
+		resumeState := prepareInterruptedResume(items[[2]int{job.specIdx, job.repeat}], job.dir)
+
+		summary, _, runErr := evalRunSingle(
+			interruptCtx, job.spec, shared, allTasks, allTasks,
+			job.dir, timestamp, runR, resumeState.isResuming,
+			resumeState.previousResults, resumeState.previousExternalFailures,
+			resumeState.completedTasks,
+			resumeState.prevAttestation, resumeState.runCfg,
+		)
+		return runResult{
+			spec: job.spec, specIdx: job.specIdx, repeat: job.repeat, summary: summary, err: runErr,
+			interrupted: checkInterrupted(interruptCtx),
+		}
+	})
+	if interrupted {
+		printMultiRunResumeCommand(out, resumeDir)
+		return nil
+	}
+	if stopped := stopMultiRunOnFailFast(resumeDir, tracker); stopped != nil {
+		return stopped
+	}
+	allSummaries := tracker.summaries()
+
+	writeMultiRunOutputs(resumeDir, mrCfg, allSummaries)
+
+	fmt.Fprintf(out, "\n Multi-run results saved to: %s\n\n", resumeDir)
+	notifyCompletion(resumeDir, meanPassRate(allSummaries))
+	return nil
+}
+
+// planMultiRunResume splits the runs of a multi-run session into completed
+// ones, whose summaries are loaded from resumeDir, and jobs still to run. The
+// returned items are the state entries of those jobs by spec index and repeat.
+func planMultiRunResume(resumeDir string, mrCfg MultiRunConfig, state MultiRunState) ([]runResult, []multiRunJob, map[[2]int]MultiRunItem) {
+	var completed []runResult
+	var jobs []multiRunJob
+	items := make(map[[2]int]MultiRunItem, len(state.Runs))
 	for _, item := range state.Runs {
+		spec := mrCfg.Specs[item.SpecIndex]
 		if item.Status == "completed" {
 			// Load existing summary.
 			runDir := filepath.Join(resumeDir, item.Dir)
@@ -282,41 +391,56 @@ func resumeMultiRun(resumeDir string) error {
 			if err != nil {
 				logger.Warn("failed to load completed run summary", "dir", runDir, "error", err)
 			}
-			allSummaries = append(allSummaries, runResult{
-				spec:    mrCfg.Specs[item.SpecIndex],
+			completed = append(completed, runResult{
+				spec:    spec,
+				specIdx: item.SpecIndex,
 				repeat:  item.Repeat,
 				summary: prevSummary,
 			})
 			continue
 		}
+		items[[2]int{item.SpecIndex, item.Repeat}] = item
+		jobs = append(jobs, multiRunJob{specIdx: item.SpecIndex, spec: spec, repeat: item.Repeat, dir: filepath.Join(resumeDir, item.Dir)})
+	}
+	return completed, jobs, items
+}
 
-		if checkInterrupted(interruptCtx) {
-			updateMultiRunState(resumeDir, allSummaries, mrCfg.Specs, mrCfg.Repeat, true)
-			printMultiRunResumeCommand(resumeDir)
-			return nil
-		}
-
-		spec := mrCfg.Specs[item.SpecIndex]
-		runDir := filepath.Join(resumeDir, item.Dir)
-
-		// For interrupted runs, use single-run resume logic.
-		resumeState := prepareInterruptedResume(item, runDir)
+// extendMultiRunRepeats raises the repeat count of a multi-run session,
+// adding pending runs for the new repeats while keeping the status of
+// existing ones. Sessions created without --repeat use a different directory
+// layout and cannot be extended.
+func extendMultiRunRepeats(mrCfg *MultiRunConfig, state *MultiRunState, repeat int) error {
+	if repeat < mrCfg.Repeat {
+		return fmt.Errorf("cannot reduce --repeat from %d to %d on resume", mrCfg.Repeat, repeat)
+	}
+	if mrCfg.Repeat <= 1 {
+		return fmt.Errorf("cannot add repeats to a multi-run session created without --repeat")
+	}
 
-		summary, _, runErr := evalRunSingle(
-			interruptCtx, spec, shared, allTasks, allTasks,
-			runDir, timestamp, r, resumeState.isResuming,
-			resumeState.previousResults, resumeState.previousExternalFailures,
-			resumeState.completedTasks,
-			resumeState.prevAttestation, resumeState.runCfg,
-		)
-		rr := runResult{spec: spec, repeat: item.Repeat, summary: summary, err: runErr}
-		allSummaries = append(allSummaries, rr)
-		updateMultiRunState(resumeDir, allSummaries, mrCfg.Specs, mrCfg.Repeat, false)
+	existing := make(map[[2]int]MultiRunItem, len(state.Runs))
+	for _, item := range state.Runs {
+		existing[[2]int{item.SpecIndex, item.Repeat}] = item
 	}
 
-	writeMultiRunOutputs(resumeDir, mrCfg, allSummaries)
+	runs := make([]MultiRunItem, 0, len(mrCfg.Specs)*repeat)
+	for specIdx, spec := range mrCfg.Specs {
+		for rep := 1; rep <= repeat; rep++ {
+			if item, ok := existing[[2]int{specIdx, rep}]; ok {
+				runs = append(runs, item)
+				continue
+			}
+			runs = append(runs, MultiRunItem{
+				SpecIndex: specIdx,
+				Repeat:    rep,
+				Dir:       multiRunSubdir("", spec, specIdx, rep, repeat),
+				Status:    "pending",
+			})
+		}
+	}
 
-	fmt.Printf("\n Multi-run results saved to: %s\n\n", resumeDir)
+	mrCfg.Repeat = repeat
+	state.Repeat = repeat
+	state.Runs = runs
 	return nil
 }
 
@@ -332,6 +456,7 @@ type interruptedResumeState struct {
 
 // prepareInterruptedResume loads resume state for an interrupted multi-run item.
 func prepareInterruptedResume(item MultiRunItem, runDir string) interruptedResumeState {
+	out := evalOutput()
 	if item.Status != "interrupted" {
 		return interruptedResumeState{}
 	}
@@ -342,7 +467,11 @@ func prepareInterruptedResume(item MultiRunItem, runDir string) interruptedResum
 	completedTasks, _ := findCompletedTasks(runDir)
 	var previousResults []EvalResult
 	var previousExternalFailures []ExternalFailure
-	if prevSummary, _ := loadPreviousSummary(runDir); prevSummary != nil {
+	prevSummary, _ := loadResumeSummary(runDir)
+	if dropped := dropUnrecordedTasks(completedTasks, prevSummary); len(dropped) > 0 {
+		fmt.Fprintf(out, " Warning: %d completed task(s) have no recorded result and will be re-run: %v\n", len(dropped), dropped)
+	}
+	if prevSummary != nil {
 		previousResults = prevSummary.Results
 		previousExternalFailures = prevSummary.ExternalFailures
 	}
@@ -370,6 +499,7 @@ func writeMultiRunOutputs(dir string, mrCfg MultiRunConfig, allSummaries []runRe
 			comparison := generateComparison(summaries)
 			writeComparisonJSON(dir, comparison)
 			writeComparisonMarkdown(dir, comparison)
+			writeComparisonCSV(dir, comparison)
 		}
 	}
 	if mrCfg.Repeat > 1 {
@@ -380,28 +510,43 @@ func writeMultiRunOutputs(dir string, mrCfg MultiRunConfig, allSummaries []runRe
 // restoreSharedConfigGlobals sets the global eval flags from a SharedConfig,
 // used when resuming a multi-run session.
 func restoreSharedConfigGlobals(shared SharedConfig) {
+	evalAgentFallback = shared.AgentFallback
 	evalTier = shared.Tier
 	evalDifficulty = shared.Difficulty
 	evalLang = shared.Lang
+	evalSkipLangs = shared.SkipLangs
 	evalTasks = shared.Tasks
 	evalTimeout = shared.Timeout
+	evalTimeoutExplicit = shared.TimeoutExplicit
+	evalTimeoutGrace = shared.TimeoutGrace
+	evalSystemPrompt = shared.SystemPrompt
+	evalPromptTemplate = shared.PromptTemplate
 	evalParallel = shared.Parallel
 	evalKeepWorkspaces = shared.KeepWorkspaces
 	evalUseMCPTools = shared.UseMCPTools
 	evalUseSkills = shared.UseSkills
+	evalLint = shared.Lint
+	evalPromptBudget = shared.PromptBudget
+	evalReuseContainer = shared.ReuseContainer
+	evalRobustness = shared.Robustness
 	evalDisableMCP = shared.DisableMCP
 	evalNoSandbox = shared.NoSandbox
+	evalNoNetwork = shared.NoNetwork
 	evalLegacy = shared.Legacy
+	evalAgentRunaway = shared.AgentRunawayBytes
+	if shared.AgentLogMaxBytes != nil {
+		evalAgentLogMax = *shared.AgentLogMaxBytes
+	}
 }
 
 // printMultiRunResumeCommand prints the command to resume a multi-run session.
-func printMultiRunResumeCommand(umbrellaDir string) {
-	fmt.Println()
-	fmt.Println("\033[33m━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\033[0m")
-	fmt.Println("\033[33m ⚠ Multi-run interrupted. To resume:\033[0m")
-	fmt.Printf("   ./sanity eval --resume %s\n", umbrellaDir)
-	fmt.Println("\033[33m━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\033[0m")
-	fmt.Println()
+func printMultiRunResumeCommand(w io.Writer, umbrellaDir string) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "\033[33m━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\033[0m")
+	fmt.Fprintln(w, "\033[33m ⚠ Multi-run interrupted. To resume:\033[0m")
+	fmt.Fprintf(w, "   ./sanity eval --resume %s\n", umbrellaDir)
+	fmt.Fprintln(w, "\033[33m━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\033[0m")
+	fmt.Fprintln(w)
 }
 
 // generateComparison creates a side-by-side comparison of multiple eval summaries.
@@ -415,6 +560,9 @@ func generateComparison(summaries []EvalSummary) Comparison {
 		if s.Model != "" && s.Model != "unknown" {
 			id += "/" + s.Model
 		}
+		if s.RunLabel != "" {
+			id = s.RunLabel
+		}
 
 		run := ComparisonRun{
 			ID:                  id,
@@ -448,6 +596,7 @@ func generateComparison(summaries []EvalSummary) Comparison {
 			}
 		}
 	}
+	c.MCPAblation = mcpAblation(summaries)
 
 	return c
 }
@@ -455,7 +604,7 @@ func generateComparison(summaries []EvalSummary) Comparison {
 // writeComparisonJSON writes comparison.json to the umbrella directory.
 func writeComparisonJSON(dir string, c Comparison) {
 	data, _ := json.MarshalIndent(c, "", "  ")
-	_ = os.WriteFile(filepath.Join(dir, "comparison.json"), data, 0o644)
+	_ = writeFileAtomic(filepath.Join(dir, "comparison.json"), data, 0o644)
 }
 
 // writeComparisonMarkdown writes comparison-report.md to the umbrella directory.
@@ -464,6 +613,81 @@ func writeComparisonMarkdown(dir string, c Comparison) {
 	_ = os.WriteFile(filepath.Join(dir, "comparison-report.md"), []byte(report), 0o644)
 }
 
+// writeComparisonCSV writes comparison.csv (one row per run) and
+// comparison-matrix.csv (tasks × runs) for spreadsheet ingestion.
+func writeComparisonCSV(dir string, c Comparison) {
+	writeCSVFile(filepath.Join(dir, "comparison.csv"), comparisonRunRecords(c))
+	writeCSVFile(filepath.Join(dir, "comparison-matrix.csv"), comparisonMatrixRecords(c))
+}
+
+func writeCSVFile(path string, records [][]string) {
+	var buf bytes.Buffer
+	if err := csv.NewWriter(&buf).WriteAll(records); err != nil {
+		logger.Warn("failed to write csv", "path", path, "error", err)
+		return
+	}
+	if err := writeFileAtomic(path, buf.Bytes(), 0o644); err != nil {
+		logger.Warn("failed to write csv", "path", path, "error", err)
+	}
+}
+
+// comparisonRunRecords returns the comparison.csv header and one row per run.
+func comparisonRunRecords(c Comparison) [][]string {
+	records := [][]string{{
+		"agent", "model", "reasoning", "pass_rate", "weighted_pass_rate", "weighted_score",
+		"passed", "failed", "total", "duration_seconds", "integrity_violations",
+	}}
+	for _, r := range c.Runs {
+		records = append(records, []string{
+			r.Agent,
+			r.Model,
+			r.Reasoning,
+			fmt.Sprintf("%.2f", r.PassRate),
+			fmt.Sprintf("%.2f", r.WeightedPassRate),
+			fmt.Sprintf("%.2f", r.WeightedScore),
+			fmt.Sprintf("%d", r.Passed),
+			fmt.Sprintf("%d", r.Failed),
+			fmt.Sprintf("%d", r.Total),
+			fmt.Sprintf("%.1f", r.Duration),
+			fmt.Sprintf("%d", r.IntegrityViolations),
+		})
+	}
+	return records
+}
+
+// comparisonMatrixRecords returns the task matrix with tasks as rows and runs
+// as columns. Cells are "pass" or "fail", or empty when a run has no result
+// for the task.
+func comparisonMatrixRecords(c Comparison) [][]string {
+	header := []string{"task"}
+	for _, r := range c.Runs {
+		header = append(header, r.ID)
+	}
+	records := [][]string{header}
+
+	taskNames := make([]string, 0, len(c.TaskMatrix))
+	for name := range c.TaskMatrix {
+		taskNames = append(taskNames, name)
+	}
+	sort.Strings(taskNames)
+
+	for _, name := range taskNames {
+		row := []string{name}
+		for _, r := range c.Runs {
+			switch c.TaskMatrix[name][r.ID] {
+			case "✅":
+				row = append(row, "pass")
+			case "❌":
+				row = append(row, "fail")
+			default:
+				row = append(row, "")
+			}
+		}
+		records = append(records, row)
+	}
+	return records
+}
+
 // buildComparisonReport builds a human-readable comparison report as a string.
 func buildComparisonReport(c Comparison) string {
 	var sb strings.Builder
@@ -519,6 +743,13 @@ func buildComparisonReport(c Comparison) string {
 		sb.WriteString("\n")
 	}
 
+	if c.MCPAblation != nil {
+		writeMCPAblationReport(&sb, c.MCPAblation)
+	}
+	if c.Baseline != nil {
+		writeBaselineRelativeReport(&sb, c)
+	}
+
 	return sb.String()
 }
 
@@ -530,7 +761,7 @@ func writeRepeatStats(umbrellaDir string, specs []RunSpec, results []runResult,
 		var summaries []*EvalSummary
 		for _, rr := range results {
 			if rr.spec.Agent == spec.Agent && rr.spec.Model == spec.Model &&
-				rr.spec.Reasoning == spec.Reasoning && rr.summary != nil {
+				rr.spec.Reasoning == spec.Reasoning && rr.spec.Label == spec.Label && rr.summary != nil {
 				summaries = append(summaries, rr.summary)
 			}
 		}
@@ -542,7 +773,7 @@ func writeRepeatStats(umbrellaDir string, specs []RunSpec, results []runResult,
 
 	// Write JSON.
 	data, _ := json.MarshalIndent(allStats, "", "  ")
-	_ = os.WriteFile(filepath.Join(umbrellaDir, "repeat-stats.json"), data, 0o644)
+	_ = writeFileAtomic(filepath.Join(umbrellaDir, "repeat-stats.json"), data, 0o644)
 
 	// Write Markdown.
 	report := buildRepeatReport(allStats)
@@ -558,14 +789,25 @@ func buildRepeatReport(allStats []RepeatStats) string {
 		if stats.Config.Model != "" {
 			label += " / " + stats.Config.Model
 		}
+		if stats.Config.Label != "" {
+			label += " (" + stats.Config.Label + ")"
+		}
 		fmt.Fprintf(&sb, "### Repeat Analysis — %s (%d runs)\n\n", label, stats.Runs)
-		fmt.Fprintf(&sb, "| Metric | Mean | Std Dev | Min | Max |\n")
-		fmt.Fprintf(&sb, "|--------|------|---------|-----|-----|\n")
-		fmt.Fprintf(&sb, "| Pass Rate | %.1f%% | ±%.1f%% | %.1f%% | %.1f%% |\n",
-			stats.MeanPassRate, stats.StdDevPassRate, stats.MinPassRate, stats.MaxPassRate)
-		fmt.Fprintf(&sb, "| Weighted Score | %.2f | ±%.2f | %.2f | %.2f |\n",
-			stats.MeanWeightedScore, stats.StdDevWeightedScore, stats.MinWeightedScore, stats.MaxWeightedScore)
-		fmt.Fprintf(&sb, "| Duration | %s | — | — | — |\n", formatDuration(stats.MeanDuration))
+		ci := func(format string, low, high float64) string {
+			if stats.Runs < 2 {
+				return "—"
+			}
+			return fmt.Sprintf(format, low) + " – " + fmt.Sprintf(format, high)
+		}
+		fmt.Fprintf(&sb, "| Metric | Mean | Std Dev | Min | Max | 95%% CI |\n")
+		fmt.Fprintf(&sb, "|--------|------|---------|-----|-----|--------|\n")
+		fmt.Fprintf(&sb, "| Pass Rate | %.1f%% | ±%.1f%% | %.1f%% | %.1f%% | %s |\n",
+			stats.MeanPassRate, stats.StdDevPassRate, stats.MinPassRate, stats.MaxPassRate,
+			ci("%.1f%%", stats.CI95PassRateLow, stats.CI95PassRateHigh))
+		fmt.Fprintf(&sb, "| Weighted Score | %.2f | ±%.2f | %.2f | %.2f | %s |\n",
+			stats.MeanWeightedScore, stats.StdDevWeightedScore, stats.MinWeightedScore, stats.MaxWeightedScore,
+			ci("%.2f", stats.CI95WeightedScoreLow, stats.CI95WeightedScoreHigh))
+		fmt.Fprintf(&sb, "| Duration | %s | — | — | — | — |\n", formatDuration(stats.MeanDuration))
 		sb.WriteString("\n")
 
 		// Task consistency sorted by flakiness.
@@ -627,7 +869,7 @@ func computeRepeatStats(spec RunSpec, summaries []*EvalSummary) RepeatStats {
 		taskConsistency[tk] = float64(taskPassCounts[tk]) / float64(total) * 100.0
 	}
 
-	return RepeatStats{
+	stats := RepeatStats{
 		Config:              spec,
 		Runs:                len(summaries),
 		PassRates:           passRates,
@@ -642,10 +884,18 @@ func computeRepeatStats(spec RunSpec, summaries []*EvalSummary) RepeatStats {
 		MeanDuration:        mean(durations),
 		TaskConsistency:     taskConsistency,
 	}
+	if stats.Runs >= 2 {
+		passCI := meanConfidenceInterval(stats.MeanPassRate, stats.StdDevPassRate, stats.Runs)
+		scoreCI := meanConfidenceInterval(stats.MeanWeightedScore, stats.StdDevWeightedScore, stats.Runs)
+		stats.CI95PassRateLow, stats.CI95PassRateHigh = math.Max(passCI[0], 0), math.Min(passCI[1], 100)
+		stats.CI95WeightedScoreLow, stats.CI95WeightedScoreHigh = scoreCI[0], scoreCI[1]
+	}
+	return stats
 }
 
-// filterTasksForShared applies shared config filters to a task list.
-func filterTasksForShared(allTasks []*task.Task, shared SharedConfig) []*task.Task {
+// filterTasksForShared applies shared config filters to a task list and
+// records how many tasks SkipLangs dropped.
+func filterTasksForShared(allTasks []*task.Task, shared *SharedConfig) []*task.Task {
 	result := allTasks
 
 	if shared.Tasks != "" {
@@ -654,12 +904,30 @@ func filterTasksForShared(allTasks []*task.Task, shared SharedConfig) []*task.Ta
 	if shared.Lang != "" {
 		result = filterByLanguage(result, shared.Lang)
 	}
+	if shared.SkipLangs != "" {
+		if skipped, err := parseSkipLangs(shared.SkipLangs); err == nil {
+			result, shared.SkippedLangTasks = filterSkipLangs(result, skipped)
+		}
+	}
 	if shared.Difficulty != "" {
 		result = filterByDifficulty(result, shared.Difficulty)
 	}
 	if shared.Tier != "" && shared.Tier != "all" {
 		result = filterByTier(result, shared.Tier)
 	}
+	if len(shared.TaskList) > 0 {
+		selected := make(map[string]bool, len(shared.TaskList))
+		for _, id := range shared.TaskList {
+			selected[id] = true
+		}
+		var filtered []*task.Task
+		for _, t := range result {
+			if selected[t.ID()] {
+				filtered = append(filtered, t)
+			}
+		}
+		result = filtered
+	}
 
 	return result
 }
@@ -701,6 +969,42 @@ func filterByLanguage(tasks []*task.Task, langStr string) []*task.Task {
 	return filtered
 }
 
+// splitSkipLangs splits a comma-separated --skip-langs value.
+func splitSkipLangs(value string) []string {
+	var langs []string
+	for _, tok := range strings.Split(value, ",") {
+		if tok = strings.TrimSpace(tok); tok != "" {
+			langs = append(langs, tok)
+		}
+	}
+	return langs
+}
+
+// parseSkipLangs parses a comma-separated --skip-langs value into languages.
+func parseSkipLangs(value string) (map[task.Language]bool, error) {
+	skip := make(map[task.Language]bool)
+	for _, tok := range splitSkipLangs(value) {
+		lang, err := task.ParseLanguage(tok)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --skip-langs entry: %w", err)
+		}
+		skip[lang] = true
+	}
+	return skip, nil
+}
+
+// filterSkipLangs drops tasks in the skipped languages and returns how many
+// were dropped.
+func filterSkipLangs(tasks []*task.Task, skip map[task.Language]bool) ([]*task.Task, int) {
+	var filtered []*task.Task
+	for _, t := range tasks {
+		if !skip[t.Language] {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered, len(tasks) - len(filtered)
+}
+
 // filterByDifficulty filters tasks to those matching comma-separated difficulty levels.
 func filterByDifficulty(tasks []*task.Task, difficulty string) []*task.Task {
 	want := make(map[string]bool)
@@ -1,10 +1,20 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/lemon07r/sanityharness/internal/task"
 )
 
 func TestBroadcastOrSplit(t *testing.T) {
@@ -95,6 +105,11 @@ func TestMultiRunSubdir(t *testing.T) {
 			RunSpec{Agent: "opencode", Model: "google/gemini-2.5-pro"}, 0, 1, 1,
 			filepath.Join("/umbrella", "opencode-google-gemini-2.5-pro"),
 		},
+		{
+			"labeled run",
+			RunSpec{Agent: "codex", Model: "gpt-5.2", Label: "no-mcp"}, 1, 1, 1,
+			filepath.Join("/umbrella", "codex-gpt-5.2-no-mcp"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -202,6 +217,17 @@ func TestComputeRepeatStats(t *testing.T) {
 	if stats.TaskConsistency["go/b"] != 50 {
 		t.Errorf("TaskConsistency[go/b] = %v, want 50", stats.TaskConsistency["go/b"])
 	}
+	// Two runs: sample stddev 14.14, t(1) = 12.706, margin = 127.06, which
+	// the pass rate clamps to [0, 100].
+	if stats.CI95PassRateLow != 0 || stats.CI95PassRateHigh != 100 {
+		t.Errorf("pass rate CI = [%v, %v], want [0, 100]", stats.CI95PassRateLow, stats.CI95PassRateHigh)
+	}
+	if math.Abs(stats.CI95WeightedScoreLow-(12-25.412)) > 0.01 || math.Abs(stats.CI95WeightedScoreHigh-(12+25.412)) > 0.01 {
+		t.Errorf("weighted score CI = [%v, %v], want 12 ± 25.41", stats.CI95WeightedScoreLow, stats.CI95WeightedScoreHigh)
+	}
+	if report := buildRepeatReport([]RepeatStats{stats}); !strings.Contains(report, "| 95% CI |") || !strings.Contains(report, "0.0% – 100.0%") {
+		t.Errorf("report missing CI column:\n%s", report)
+	}
 }
 
 func TestGenerateComparison(t *testing.T) {
@@ -256,3 +282,626 @@ func TestFormatDuration(t *testing.T) {
 		}
 	}
 }
+
+func TestComparisonCSVRecords(t *testing.T) {
+	c := generateComparison([]EvalSummary{
+		{
+			Agent: "a1", Model: "m1", PassRate: 50, WeightedScore: 1.5,
+			Passed: 1, Failed: 1, Total: 2, Duration: 12.34,
+			Results: []EvalResult{{Task: "go/y", Passed: true}, {Task: "go/x", Passed: false}},
+		},
+		{
+			Agent: "a2", Model: "unknown", Reasoning: "high", PassRate: 100,
+			Passed: 1, Total: 1,
+			Results: []EvalResult{{Task: "go/x", Passed: true}},
+		},
+	})
+
+	runs := comparisonRunRecords(c)
+	if len(runs) != 3 {
+		t.Fatalf("run records = %d, want 3", len(runs))
+	}
+	if got := strings.Join(runs[1], ","); got != "a1,m1,,50.00,0.00,1.50,1,1,2,12.3,0" {
+		t.Errorf("run row = %q", got)
+	}
+	if runs[2][2] != "high" {
+		t.Errorf("reasoning = %q, want high", runs[2][2])
+	}
+
+	matrix := comparisonMatrixRecords(c)
+	want := [][]string{
+		{"task", "a1/m1", "a2"},
+		{"go/x", "fail", "pass"},
+		{"go/y", "pass", ""},
+	}
+	if len(matrix) != len(want) {
+		t.Fatalf("matrix = %v, want %v", matrix, want)
+	}
+	for i := range want {
+		if strings.Join(matrix[i], ",") != strings.Join(want[i], ",") {
+			t.Errorf("matrix[%d] = %v, want %v", i, matrix[i], want[i])
+		}
+	}
+}
+
+func TestFilterTasksForSharedSkipLangs(t *testing.T) {
+	t.Parallel()
+
+	tasks := []*task.Task{
+		{Slug: "a", Language: task.Go, Tier: "core"},
+		{Slug: "b", Language: task.Kotlin, Tier: "core"},
+		{Slug: "c", Language: task.Dart, Tier: "core"},
+		{Slug: "d", Language: task.Rust, Tier: "core"},
+	}
+	shared := SharedConfig{SkipLangs: "kotlin, dart"}
+
+	got := filterTasksForShared(tasks, &shared)
+	if len(got) != 2 || got[0].Slug != "a" || got[1].Slug != "d" {
+		t.Fatalf("filterTasksForShared() = %d tasks, want go/a and rust/d", len(got))
+	}
+	if shared.SkippedLangTasks != 2 {
+		t.Fatalf("SkippedLangTasks = %d, want 2", shared.SkippedLangTasks)
+	}
+
+	if _, err := parseSkipLangs("go,cobol"); err == nil {
+		t.Fatal("parseSkipLangs() with unknown language should fail")
+	}
+}
+
+func TestMultiRunResumeKeepsSample(t *testing.T) {
+	t.Parallel()
+
+	var suite []*task.Task
+	for i := range 10 {
+		suite = append(suite, &task.Task{Slug: fmt.Sprintf("task-%d", i), Language: task.Go, Tier: "core"})
+	}
+	sampled := sampleTasks(suite, 3, true, 42)
+	shared := SharedConfig{Tier: "core"}
+	for _, tk := range sampled {
+		shared.TaskList = append(shared.TaskList, tk.ID())
+	}
+
+	dir := t.TempDir()
+	writeMultiRunConfig(dir, []RunSpec{{Agent: "codex"}}, shared, 3)
+	data, err := os.ReadFile(filepath.Join(dir, "multi-run-config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mrCfg MultiRunConfig
+	if err := json.Unmarshal(data, &mrCfg); err != nil {
+		t.Fatal(err)
+	}
+
+	got := filterTasksForShared(suite, &mrCfg.Shared)
+	if len(got) != len(sampled) {
+		t.Fatalf("resumed tasks = %d, want the %d sampled", len(got), len(sampled))
+	}
+	for _, tk := range got {
+		if !slices.Contains(shared.TaskList, tk.ID()) {
+			t.Errorf("resumed task %s was not sampled", tk.ID())
+		}
+	}
+}
+
+func TestExtendMultiRunRepeats(t *testing.T) {
+	t.Parallel()
+
+	specs := []RunSpec{{Agent: "a"}, {Agent: "b", Model: "m"}}
+	mrCfg := MultiRunConfig{Specs: specs, Repeat: 2}
+	state := MultiRunState{Repeat: 2, Specs: specs}
+	for specIdx, spec := range specs {
+		for rep := 1; rep <= 2; rep++ {
+			state.Runs = append(state.Runs, MultiRunItem{
+				SpecIndex: specIdx,
+				Repeat:    rep,
+				Dir:       multiRunSubdir("", spec, specIdx, rep, 2),
+				Status:    "completed",
+			})
+		}
+	}
+
+	if err := extendMultiRunRepeats(&mrCfg, &state, 3); err != nil {
+		t.Fatalf("extendMultiRunRepeats: %v", err)
+	}
+	if mrCfg.Repeat != 3 || state.Repeat != 3 {
+		t.Fatalf("repeat = %d/%d, want 3", mrCfg.Repeat, state.Repeat)
+	}
+	if len(state.Runs) != 6 {
+		t.Fatalf("runs = %d, want 6", len(state.Runs))
+	}
+	var pending []string
+	for _, item := range state.Runs {
+		if item.Status == "pending" {
+			pending = append(pending, item.Dir)
+		}
+	}
+	want := []string{filepath.Join("a", "run-3"), filepath.Join("b-m", "run-3")}
+	if strings.Join(pending, ",") != strings.Join(want, ",") {
+		t.Errorf("pending runs = %v, want %v", pending, want)
+	}
+
+	if err := extendMultiRunRepeats(&mrCfg, &state, 2); err == nil {
+		t.Error("expected error when reducing repeat count")
+	}
+	single := MultiRunConfig{Specs: specs, Repeat: 1}
+	if err := extendMultiRunRepeats(&single, &MultiRunState{}, 3); err == nil {
+		t.Error("expected error when extending a session created without --repeat")
+	}
+}
+
+// readMultiRunState reads multi-run-state.json from dir.
+func readMultiRunState(t *testing.T, dir string) MultiRunState {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "multi-run-state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state MultiRunState
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatalf("multi-run-state.json: %v", err)
+	}
+	return state
+}
+
+func TestMultiRunResumeSharedAgent(t *testing.T) {
+	t.Parallel()
+
+	// --mcp-ablation runs the same agent twice.
+	specs, err := mcpAblationSpecs([]RunSpec{{Agent: "codex", Model: "gpt-5.2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(specs) != 2 || specs[0].Agent != specs[1].Agent {
+		t.Fatalf("ablation specs = %+v, want two runs of one agent", specs)
+	}
+
+	dir := t.TempDir()
+	tracker := newMultiRunTracker(dir, specs, 1, nil)
+	tracker.record(runResult{spec: specs[0], specIdx: 0, repeat: 1, summary: &EvalSummary{Agent: "codex"}})
+	tracker.interrupt()
+
+	state := readMultiRunState(t, dir)
+	if got := []string{state.Runs[0].Status, state.Runs[1].Status}; !slices.Equal(got, []string{"completed", "pending"}) {
+		t.Fatalf("statuses = %v, want [completed pending]", got)
+	}
+
+	completed, jobs, _ := planMultiRunResume(dir, MultiRunConfig{Specs: specs, Repeat: 1}, state)
+	if len(completed) != 1 || completed[0].specIdx != 0 {
+		t.Errorf("completed = %+v, want spec 0", completed)
+	}
+	if len(jobs) != 1 || jobs[0].specIdx != 1 || jobs[0].spec.Label != specs[1].Label {
+		t.Errorf("jobs = %+v, want the %s run", jobs, specs[1].Label)
+	}
+}
+
+func TestMultiRunResumeAgentVersions(t *testing.T) {
+	t.Parallel()
+
+	specs, err := agentVersionSpecs([]RunSpec{{Agent: "claude"}}, "stable=claude,beta=claude-beta,nightly=claude-nightly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	const repeat = 2
+	tracker := newMultiRunTracker(dir, specs, repeat, nil)
+	tracker.record(runResult{spec: specs[1], specIdx: 1, repeat: 1, summary: &EvalSummary{}})
+	tracker.record(runResult{spec: specs[1], specIdx: 1, repeat: 2, summary: &EvalSummary{}})
+	tracker.record(runResult{spec: specs[0], specIdx: 0, repeat: 1, summary: &EvalSummary{}})
+
+	state := readMultiRunState(t, dir)
+	_, jobs, _ := planMultiRunResume(dir, MultiRunConfig{Specs: specs, Repeat: repeat}, state)
+	var got []string
+	for _, job := range jobs {
+		got = append(got, fmt.Sprintf("%s/%d", job.spec.Label, job.repeat))
+	}
+	if want := []string{"stable/2", "nightly/1", "nightly/2"}; !slices.Equal(got, want) {
+		t.Errorf("runs left to resume = %v, want %v", got, want)
+	}
+}
+
+func TestMultiRunStateInFlightRuns(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	specs := []RunSpec{{Agent: "codex"}, {Agent: "codex", Model: "m"}, {Agent: "claude"}}
+	ctx, cancel := context.WithCancel(context.Background())
+	jobs := []multiRunJob{{specIdx: 0, spec: specs[0], repeat: 1}, {specIdx: 1, spec: specs[1], repeat: 1}, {specIdx: 2, spec: specs[2], repeat: 1}}
+
+	started := make(chan struct{})
+	tracker := newMultiRunTracker(dir, specs, 1, nil)
+	interrupted := runMultiRunJobs(ctx, jobs, 2, tracker, func(job multiRunJob) runResult {
+		rr := runResult{spec: job.spec, specIdx: job.specIdx, repeat: job.repeat, summary: &EvalSummary{}}
+		switch job.specIdx {
+		case 0:
+			// Finishes just as the session is interrupted, while spec 1
+			// is still running.
+			<-started
+			cancel()
+			return rr
+		case 1:
+			close(started)
+			var state MultiRunState
+			for len(state.Runs) == 0 || state.Runs[0].Status != "completed" {
+				time.Sleep(time.Millisecond)
+				data, _ := os.ReadFile(filepath.Join(dir, "multi-run-state.json"))
+				_ = json.Unmarshal(data, &state)
+			}
+			if st := state.Runs[1].Status; st != "pending" {
+				t.Errorf("in-flight run status = %q, want pending", st)
+			}
+			rr.interrupted = checkInterrupted(ctx)
+			return rr
+		}
+		t.Errorf("spec %d started after the interrupt", job.specIdx)
+		return rr
+	})
+	if !interrupted {
+		t.Fatal("runMultiRunJobs() did not report the interrupt")
+	}
+
+	state := readMultiRunState(t, dir)
+	var got []string
+	for _, item := range state.Runs {
+		got = append(got, item.Status)
+	}
+	if want := []string{"completed", "interrupted", "pending"}; !slices.Equal(got, want) {
+		t.Errorf("statuses = %v, want %v", got, want)
+	}
+}
+
+func TestRunMultiRunJobsConcurrentState(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	specs := []RunSpec{{Agent: "a"}, {Agent: "b"}, {Agent: "c"}}
+	const repeat = 3
+	var jobs []multiRunJob
+	for specIdx, spec := range specs {
+		for rep := 1; rep <= repeat; rep++ {
+			jobs = append(jobs, multiRunJob{specIdx: specIdx, spec: spec, repeat: rep})
+		}
+	}
+
+	var running, peak atomic.Int32
+	tracker := newMultiRunTracker(dir, specs, repeat, nil)
+	interrupted := runMultiRunJobs(context.Background(), jobs, 4, tracker, func(job multiRunJob) runResult {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Duration(len(jobs)-job.specIdx*repeat-job.repeat) * time.Millisecond)
+		running.Add(-1)
+		return runResult{spec: job.spec, specIdx: job.specIdx, repeat: job.repeat, summary: &EvalSummary{Agent: job.spec.Agent}}
+	})
+	if interrupted {
+		t.Fatal("runMultiRunJobs() reported an interrupt")
+	}
+	if p := peak.Load(); p > 4 {
+		t.Fatalf("peak concurrent runs = %d, want at most 4", p)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "multi-run-state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state MultiRunState
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatalf("multi-run-state.json: %v", err)
+	}
+	if len(state.Runs) != len(jobs) {
+		t.Fatalf("state has %d runs, want %d", len(state.Runs), len(jobs))
+	}
+	for _, item := range state.Runs {
+		if item.Status != "completed" {
+			t.Errorf("run %s status = %q, want completed", item.Dir, item.Status)
+		}
+	}
+
+	summaries := tracker.summaries()
+	if len(summaries) != len(jobs) {
+		t.Fatalf("summaries = %d, want %d", len(summaries), len(jobs))
+	}
+	for i, rr := range summaries {
+		if rr.specIdx != jobs[i].specIdx || rr.repeat != jobs[i].repeat {
+			t.Fatalf("summaries[%d] = spec %d repeat %d, want spec %d repeat %d", i, rr.specIdx, rr.repeat, jobs[i].specIdx, jobs[i].repeat)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tracker = newMultiRunTracker(t.TempDir(), specs, repeat, nil)
+	ran := false
+	if !runMultiRunJobs(ctx, jobs, 2, tracker, func(multiRunJob) runResult { ran = true; return runResult{} }) || ran {
+		t.Fatalf("interrupted session: ran=%v, want no runs and an interrupt", ran)
+	}
+}
+
+func TestRunMultiRunJobsStopsOnFailFast(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	specs := []RunSpec{{Agent: "a"}, {Agent: "b"}}
+	jobs := []multiRunJob{
+		{specIdx: 0, spec: specs[0], repeat: 1},
+		{specIdx: 0, spec: specs[0], repeat: 2},
+		{specIdx: 1, spec: specs[1], repeat: 1},
+		{specIdx: 1, spec: specs[1], repeat: 2},
+	}
+
+	tracker := newMultiRunTracker(dir, specs, 2, nil)
+	var ran []string
+	interrupted := runMultiRunJobs(context.Background(), jobs, 1, tracker, func(job multiRunJob) runResult {
+		ran = append(ran, fmt.Sprintf("%s/%d", job.spec.Agent, job.repeat))
+		summary := &EvalSummary{Agent: job.spec.Agent}
+		if job.repeat == 2 {
+			summary.FailFastTask = "go/bank-account"
+		}
+		return runResult{spec: job.spec, specIdx: job.specIdx, repeat: job.repeat, summary: summary}
+	})
+	if interrupted {
+		t.Fatal("runMultiRunJobs() reported an interrupt")
+	}
+	if want := []string{"a/1", "a/2"}; !slices.Equal(ran, want) {
+		t.Fatalf("ran = %v, want %v", ran, want)
+	}
+	if got := tracker.failFastTask(); got != "go/bank-account" {
+		t.Fatalf("failFastTask() = %q, want go/bank-account", got)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "multi-run-state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state MultiRunState
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatalf("multi-run-state.json: %v", err)
+	}
+	var got []string
+	for _, item := range state.Runs {
+		got = append(got, item.Status)
+	}
+	if want := []string{"completed", "interrupted", "pending", "pending"}; !slices.Equal(got, want) {
+		t.Errorf("statuses = %v, want %v", got, want)
+	}
+
+	var exitErr *exitError
+	if err := stopMultiRunOnFailFast(dir, tracker); !errors.As(err, &exitErr) || exitErr.code != failFastExitCode {
+		t.Fatalf("stopMultiRunOnFailFast() = %v, want exit code %d", err, failFastExitCode)
+	}
+	if err := stopMultiRunOnFailFast(dir, newMultiRunTracker(dir, specs, 2, nil)); err != nil {
+		t.Fatalf("stopMultiRunOnFailFast() without a fail-fast run = %v", err)
+	}
+}
+
+func TestParseShard(t *testing.T) {
+	t.Parallel()
+
+	shard, shards, err := parseShard("2/4")
+	if err != nil || shard != 2 || shards != 4 {
+		t.Fatalf("parseShard(2/4) = %d, %d, %v", shard, shards, err)
+	}
+	for _, value := range []string{"", "2", "0/4", "5/4", "1/0", "a/b"} {
+		if _, _, err := parseShard(value); err == nil {
+			t.Fatalf("parseShard(%q) expected error", value)
+		}
+	}
+}
+
+func TestShardTasks(t *testing.T) {
+	t.Parallel()
+
+	var tasks []*task.Task
+	for _, slug := range []string{"e", "b", "g", "a", "d", "c", "f"} {
+		tasks = append(tasks, &task.Task{Slug: slug, Language: task.Go})
+	}
+
+	seen := make(map[string]int)
+	for shard := 1; shard <= 3; shard++ {
+		selected := shardTasks(tasks, shard, 3)
+		pos := -1
+		for _, sel := range selected {
+			seen[sel.ID()]++
+			idx := slices.Index(tasks, sel)
+			if idx < pos {
+				t.Fatalf("shard %d does not keep input order", shard)
+			}
+			pos = idx
+		}
+	}
+	if len(seen) != len(tasks) {
+		t.Fatalf("shards cover %d tasks, want %d", len(seen), len(tasks))
+	}
+	for id, n := range seen {
+		if n != 1 {
+			t.Fatalf("task %s is in %d shards", id, n)
+		}
+	}
+	got := shardTasks(tasks, 1, 3)
+	if len(got) != 3 || got[0].ID() != "go/g" || got[1].ID() != "go/a" || got[2].ID() != "go/d" {
+		t.Fatalf("shard 1/3 = %v, want go/g, go/a, go/d", got)
+	}
+}
+
+func TestResolveOutputTemplate(t *testing.T) {
+	t.Parallel()
+
+	spec := RunSpec{Agent: "opencode", Model: "openrouter/kimi-k2"}
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"", filepath.Join("eval-results", "2026-01-07T120000-opencode")},
+		{"{agent}/{model}/{date}", filepath.Join("eval-results", "opencode", "openrouter-kimi-k2", "2026-01-07")},
+		{"{agent}-{reasoning}", filepath.Join("eval-results", "opencode-default")},
+	}
+	for _, tc := range tests {
+		got, err := resolveOutputTemplate(tc.template, spec, "2026-01-07T120000")
+		if err != nil || got != tc.want {
+			t.Fatalf("resolveOutputTemplate(%q) = %q, %v, want %q", tc.template, got, err, tc.want)
+		}
+	}
+
+	got, err := resolveOutputTemplate("{agent}/{uuid}", spec, "2026-01-07T120000")
+	if err != nil {
+		t.Fatalf("resolveOutputTemplate({uuid}) error = %v", err)
+	}
+	if id := filepath.Base(got); len(id) != 36 || strings.Count(id, "-") != 4 {
+		t.Fatalf("uuid segment = %q, want a UUID", id)
+	}
+
+	for _, bad := range []string{"{agent}/{host}", "../{agent}", "/tmp/{agent}"} {
+		if _, err := resolveOutputTemplate(bad, spec, "2026-01-07T120000"); err == nil {
+			t.Fatalf("resolveOutputTemplate(%q) expected error", bad)
+		}
+	}
+}
+
+func TestAgentVersionSpecs(t *testing.T) {
+	t.Parallel()
+
+	specs, err := agentVersionSpecs([]RunSpec{{Agent: "claude", Model: "opus"}}, "stable=claude, beta=/opt/claude-beta/claude")
+	if err != nil {
+		t.Fatalf("agentVersionSpecs() error = %v", err)
+	}
+	want := []RunSpec{
+		{Agent: "claude", Model: "opus", Label: "stable", Command: "claude"},
+		{Agent: "claude", Model: "opus", Label: "beta", Command: "/opt/claude-beta/claude"},
+	}
+	if !slices.Equal(specs, want) {
+		t.Fatalf("agentVersionSpecs() = %+v, want %+v", specs, want)
+	}
+
+	for _, value := range []string{"stable=claude", "stable=claude,stable=claude-beta", "stable=claude,beta", "=claude,beta=x"} {
+		if _, err := agentVersionSpecs([]RunSpec{{Agent: "claude"}}, value); err == nil {
+			t.Fatalf("agentVersionSpecs(%q): want error", value)
+		}
+	}
+	if _, err := agentVersionSpecs([]RunSpec{{Agent: "claude"}, {Agent: "codex"}}, "a=x,b=y"); err == nil {
+		t.Fatal("agentVersionSpecs() with two agents: want error")
+	}
+}
+
+func TestMCPAblation(t *testing.T) {
+	t.Parallel()
+
+	specs, err := mcpAblationSpecs([]RunSpec{{Agent: "codex", Model: "gpt-5.2"}})
+	if err != nil {
+		t.Fatalf("mcpAblationSpecs() error = %v", err)
+	}
+	if len(specs) != 2 || specs[0].Label != "mcp" || !*specs[0].MCPTools || specs[1].Label != "no-mcp" || *specs[1].MCPTools {
+		t.Fatalf("mcpAblationSpecs() = %+v, want mcp and no-mcp runs", specs)
+	}
+	if _, err := mcpAblationSpecs([]RunSpec{{Agent: "codex"}, {Agent: "gemini"}}); err == nil {
+		t.Fatal("mcpAblationSpecs() with two agents: want error")
+	}
+
+	c := generateComparison([]EvalSummary{
+		{
+			Agent: "codex", Model: "gpt-5.2", RunLabel: "mcp", PassRate: 50, WeightedScore: 12,
+			Results: []EvalResult{{Task: "go/x", Passed: true}, {Task: "go/y", Passed: false}, {Task: "go/z", Passed: true}},
+		},
+		{
+			Agent: "codex", Model: "gpt-5.2", RunLabel: "no-mcp", PassRate: 25, WeightedScore: 9.5,
+			Results: []EvalResult{{Task: "go/x", Passed: false}, {Task: "go/y", Passed: true}, {Task: "go/z", Passed: true}},
+		},
+	})
+	if c.Runs[0].ID != "mcp" || c.Runs[1].ID != "no-mcp" {
+		t.Fatalf("run IDs = %q, %q, want mcp, no-mcp", c.Runs[0].ID, c.Runs[1].ID)
+	}
+	a := c.MCPAblation
+	if a == nil {
+		t.Fatal("MCPAblation = nil, want ablation results")
+	}
+	if a.WeightedScoreDelta != 2.5 || a.PassRateDelta != 25 {
+		t.Errorf("deltas = %v, %v, want 2.5, 25", a.WeightedScoreDelta, a.PassRateDelta)
+	}
+	want := []MCPAblationTask{
+		{Task: "go/x", MCPPassRate: 100, NoMCPPassRate: 0, Delta: 100},
+		{Task: "go/y", MCPPassRate: 0, NoMCPPassRate: 100, Delta: -100},
+	}
+	if !slices.Equal(a.Tasks, want) {
+		t.Errorf("Tasks = %+v, want %+v", a.Tasks, want)
+	}
+	if report := buildComparisonReport(c); !strings.Contains(report, "### MCP Ablation") || !strings.Contains(report, "| Weighted Score | 12.00 | 9.50 | +2.50 |") {
+		t.Errorf("report missing MCP ablation section:\n%s", report)
+	}
+
+	if c := generateComparison([]EvalSummary{{Agent: "a1"}, {Agent: "a2"}}); c.MCPAblation != nil {
+		t.Errorf("MCPAblation = %+v for unlabeled runs, want nil", c.MCPAblation)
+	}
+}
+
+func TestApplyBaselineRelative(t *testing.T) {
+	t.Parallel()
+
+	summaries := []EvalSummary{{
+		Agent: "codex", Model: "gpt-5.2",
+		Results: []EvalResult{
+			{Task: "go/x", WeightedScore: 2}, {Task: "go/y", WeightedScore: 3}, {Task: "go/new", WeightedScore: 5},
+		},
+	}}
+	baseline := EvalSummary{
+		Agent: "gemini",
+		Results: []EvalResult{
+			{Task: "go/x", WeightedScore: 1}, {Task: "go/y", WeightedScore: 3}, {Task: "go/z", WeightedScore: 2},
+		},
+	}
+	c := generateComparison(summaries)
+	applyBaselineRelative(&c, summaries, baseline, "./ref")
+
+	if got := c.Runs[0].RelativeScore; got == nil || *got != 1.25 {
+		t.Fatalf("RelativeScore = %v, want 1.25 over the shared tasks", got)
+	}
+	if got := c.Baseline.Tasks["go/x"]["codex/gpt-5.2"]; got != 2 {
+		t.Errorf("go/x relative = %v, want 2", got)
+	}
+	if _, ok := c.Baseline.Tasks["go/new"]; ok {
+		t.Error("task missing from the baseline has a relative score")
+	}
+	if report := buildComparisonReport(c); !strings.Contains(report, "| codex/gpt-5.2 | 1.25× |") {
+		t.Errorf("report missing relative score:\n%s", report)
+	}
+
+	c = generateComparison(summaries)
+	applyBaselineRelative(&c, summaries, EvalSummary{Results: []EvalResult{{Task: "go/x"}}}, "./ref")
+	if c.Runs[0].RelativeScore != nil {
+		t.Errorf("RelativeScore = %v against a baseline that scored nothing, want unset", *c.Runs[0].RelativeScore)
+	}
+}
+
+func TestBuildStatusMatrix(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"a/run-1", "a/run-2"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a/run-1/summary.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	state := MultiRunState{
+		ID:     "multi",
+		Repeat: 2,
+		Specs:  []RunSpec{{Agent: "a"}, {Agent: "b", Model: "m"}},
+		Runs: []MultiRunItem{
+			{SpecIndex: 0, Repeat: 1, Dir: "a/run-1", Status: "completed"},
+			{SpecIndex: 0, Repeat: 2, Dir: "a/run-2", Status: "completed"},
+			{SpecIndex: 1, Repeat: 1, Dir: "b-m/run-1", Status: "interrupted"},
+			{SpecIndex: 1, Repeat: 2, Dir: "b-m/run-2", Status: "pending"},
+		},
+	}
+	got := buildStatusMatrix(dir, state)
+	for _, want := range []string{
+		"| a | ✅ | ❌ |",
+		"| b / m | ⏸ | — |",
+		"2 of 4 runs resumable",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("status matrix missing %q:\n%s", want, got)
+		}
+	}
+}
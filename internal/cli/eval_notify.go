@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// notifyCommandTimeout bounds how long --notify-command may run.
+const notifyCommandTimeout = 30 * time.Second
+
+// notifyCompletion signals that an eval has finished: a terminal
+// notification with --notify and the user's command with --notify-command.
+// The command receives the output directory and headline pass rate both as
+// positional arguments ($1, $2) and as SANITY_OUTPUT_DIR / SANITY_PASS_RATE.
+// Failures are logged as warnings and never fail the eval.
+func notifyCompletion(outputDir string, passRate float64) {
+	rate := fmt.Sprintf("%.1f", passRate)
+	if evalNotify {
+		notifyTerminal(fmt.Sprintf("SanityHarness eval done: %s%% pass rate (%s)", rate, outputDir))
+	}
+	if evalNotifyCommand == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notifyCommandTimeout)
+	defer cancel()
+	cmd := notifyShellCommand(ctx, evalNotifyCommand, outputDir, rate)
+	cmd.Env = append(os.Environ(), "SANITY_OUTPUT_DIR="+outputDir, "SANITY_PASS_RATE="+rate)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		logger.Warn("notify command failed", "command", evalNotifyCommand, "error", err)
+	}
+}
+
+// notifyShellCommand runs command through the platform shell with the output
+// directory and pass rate as positional arguments.
+func notifyShellCommand(ctx context.Context, command, outputDir, rate string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command, outputDir, rate)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command, "sanity-notify", outputDir, rate)
+}
+
+// notifyTerminal rings the bell and emits an OSC 9 desktop notification when
+// stderr is a terminal. Terminals without OSC 9 support ignore the sequence.
+func notifyTerminal(message string) {
+	if !isTerminal(os.Stderr) {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\033]9;%s\007\a", message)
+}
+
+// meanPassRate returns the mean pass rate of the completed runs of a
+// multi-run session.
+func meanPassRate(results []runResult) float64 {
+	var rates []float64
+	for _, rr := range results {
+		if rr.summary != nil {
+			rates = append(rates, rr.summary.PassRate)
+		}
+	}
+	return mean(rates)
+}
@@ -1,10 +1,15 @@
 package cli
 
 import (
+	"embed"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -108,6 +113,85 @@ func TestGenerateLeaderboardSubmissionIncludesRunMetadata(t *testing.T) {
 	}
 }
 
+// TestRunConfigAgentLogLimits is not parallel: it sets the eval globals.
+func TestRunConfigAgentLogLimits(t *testing.T) {
+	savedMax, savedRunaway := evalAgentLogMax, evalAgentRunaway
+	t.Cleanup(func() { evalAgentLogMax, evalAgentRunaway = savedMax, savedRunaway })
+
+	restore := func(data string) {
+		var runCfg RunConfig
+		if err := json.Unmarshal([]byte(data), &runCfg); err != nil {
+			t.Fatal(err)
+		}
+		evalAgentLogMax, evalAgentRunaway = defaultAgentLogMaxBytes, 0
+		applyRunConfig(&runCfg)
+	}
+
+	restore(`{"agent":"codex","agent_log_max_bytes":0,"agent_runaway_bytes":20000000}`)
+	if evalAgentLogMax != 0 || evalAgentRunaway != 20000000 {
+		t.Fatalf("restored log max %d runaway %d, want 0 (unlimited) and 20000000", evalAgentLogMax, evalAgentRunaway)
+	}
+	restore(`{"agent":"codex"}`)
+	if evalAgentLogMax != defaultAgentLogMaxBytes || evalAgentRunaway != 0 {
+		t.Fatalf("old run config restored log max %d runaway %d, want the defaults", evalAgentLogMax, evalAgentRunaway)
+	}
+
+	evalAgentLogMax, evalAgentRunaway = 1<<20, 5000
+	shared := sharedConfigFromGlobals()
+	evalAgentLogMax, evalAgentRunaway = defaultAgentLogMaxBytes, 0
+	restoreSharedConfigGlobals(shared)
+	if evalAgentLogMax != 1<<20 || evalAgentRunaway != 5000 {
+		t.Fatalf("multi-run restored log max %d runaway %d, want 1048576 and 5000", evalAgentLogMax, evalAgentRunaway)
+	}
+}
+
+// TestRunConfigRoundTrip is not parallel: it sets the eval globals.
+func TestRunConfigRoundTrip(t *testing.T) {
+	savedMinFree, savedBaselines := evalMinFreeDiskMB, evalBaselines
+	savedFailFast := evalFailFast
+	savedReportChart := evalReportChart
+	savedExportFormat := evalExportFormat
+	t.Cleanup(func() { evalMinFreeDiskMB, evalBaselines = savedMinFree, savedBaselines })
+	t.Cleanup(func() { evalFailFast = savedFailFast })
+	t.Cleanup(func() { evalReportChart = savedReportChart })
+	t.Cleanup(func() { evalExportFormat = savedExportFormat })
+
+	evalMinFreeDiskMB = 2048
+	evalBaselines = []string{"reference=./eval-results/solutions"}
+	evalFailFast = true
+	evalReportChart = true
+	evalExportFormat = "swebench"
+	outputDir := t.TempDir()
+	if err := saveRunConfig(outputDir, RunSpec{Agent: "codex"}, false, nil); err != nil {
+		t.Fatalf("saveRunConfig: %v", err)
+	}
+	runCfg, err := loadRunConfig(outputDir)
+	if err != nil {
+		t.Fatalf("loadRunConfig: %v", err)
+	}
+
+	evalMinFreeDiskMB, evalBaselines = 0, nil
+	evalFailFast = false
+	evalReportChart = false
+	evalExportFormat = ""
+	applyRunConfig(runCfg)
+	if evalMinFreeDiskMB != 2048 {
+		t.Fatalf("restored min free disk %d, want 2048", evalMinFreeDiskMB)
+	}
+	if !slices.Equal(evalBaselines, []string{"reference=./eval-results/solutions"}) {
+		t.Fatalf("restored baselines %v", evalBaselines)
+	}
+	if !evalFailFast {
+		t.Fatalf("restored fail_fast %v, want true", evalFailFast)
+	}
+	if !evalReportChart {
+		t.Fatalf("restored report_chart %v, want true", evalReportChart)
+	}
+	if evalExportFormat != "swebench" {
+		t.Fatalf("restored export_format %v, want swebench", evalExportFormat)
+	}
+}
+
 func TestRunConfigMarshalIncludesFalseFlags(t *testing.T) {
 	t.Parallel()
 
@@ -212,6 +296,193 @@ func TestEvalSummaryMarshalIncludesZeroAuditFields(t *testing.T) {
 	}
 }
 
+func TestEvalResultMarshalIncludesValidationCommand(t *testing.T) {
+	t.Parallel()
+
+	tk := &task.Task{
+		Language:   task.TypeScript,
+		Files:      task.TaskFiles{HiddenTest: []string{"hidden.test.ts.txt"}},
+		Validation: task.Validation{Command: "npx", Args: []string{"vitest", "run"}},
+	}
+	_, effective, _ := buildValidationCommands(tk)
+	data, err := json.Marshal(EvalResult{Task: "typescript/demo", ValidationCommand: effective})
+	if err != nil {
+		t.Fatalf("marshal result: %v", err)
+	}
+	want := `"validation_command":["npx","vitest","run","hidden.test.ts"]`
+	if !strings.Contains(string(data), want) {
+		t.Fatalf("expected result json to include %s, got: %s", want, data)
+	}
+}
+
+func TestGenerateFailuresReport(t *testing.T) {
+	t.Parallel()
+
+	outputDir := t.TempDir()
+	taskDir := filepath.Join(outputDir, "go-bank-account")
+	if err := os.MkdirAll(taskDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(taskDir, "validation.log"), []byte("FAIL"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	summary := EvalSummary{
+		Agent: "codex",
+		Total: 3,
+		Results: []EvalResult{
+			{Task: "go/bank-account", Passed: false, Error: "TestDeposit failed"},
+			{Task: "go/react", Passed: true},
+			{Task: "rust/forth", Passed: false, ExpectedFail: true},
+		},
+	}
+	report := generateFailuresReport(summary, outputDir)
+	for _, want := range []string{
+		"1 of 3 tasks failed",
+		"| go/bank-account |",
+		"TestDeposit failed",
+		"[validation.log](go-bank-account/validation.log)",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("failures report missing %q:\n%s", want, report)
+		}
+	}
+	for _, unwanted := range []string{"go/react", "rust/forth", "agent.log"} {
+		if strings.Contains(report, unwanted) {
+			t.Errorf("failures report should not contain %q:\n%s", unwanted, report)
+		}
+	}
+
+	summary.Results = summary.Results[1:]
+	if got := generateFailuresReport(summary, outputDir); got != "" {
+		t.Fatalf("expected empty failures report without failures, got:\n%s", got)
+	}
+}
+
+func TestWriteSWEBenchExport(t *testing.T) {
+	t.Parallel()
+
+	summary := EvalSummary{
+		Agent: "codex",
+		Model: "gpt-5",
+		Results: []EvalResult{
+			{Task: "go/bank-account", Language: "go", Tier: "core", Passed: true, WeightedScore: 1.5, Duration: 12},
+			{Task: "rust/regex-lite", Language: "rust", Passed: false, FailureClass: FailureClassValidationError, Duration: 30},
+		},
+	}
+	dir := t.TempDir()
+	path, err := writeSWEBenchExport(dir, summary)
+	if err != nil {
+		t.Fatalf("writeSWEBenchExport() error = %v", err)
+	}
+	if path != filepath.Join(dir, "swebench.jsonl") {
+		t.Fatalf("path = %q", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), data)
+	}
+
+	var first map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	for key, want := range map[string]any{
+		"instance_id":        "go/bank-account",
+		"model_name_or_path": "codex/gpt-5",
+		"resolved":           true,
+		"language":           "go",
+		"weighted_score":     1.5,
+	} {
+		if first[key] != want {
+			t.Errorf("%s = %v, want %v", key, first[key], want)
+		}
+	}
+
+	var second SWEBenchResult
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if second.Resolved || second.FailureClass != FailureClassValidationError {
+		t.Fatalf("second = %+v, want unresolved validation failure", second)
+	}
+
+	if err := validateExportFormat("swebench"); err != nil {
+		t.Fatalf("validateExportFormat(swebench) error = %v", err)
+	}
+	if err := validateExportFormat("csv"); err == nil {
+		t.Fatal("validateExportFormat(csv) error = nil")
+	}
+}
+
+func TestProgressLine(t *testing.T) {
+	t.Parallel()
+
+	var plain strings.Builder
+	p := &progressLine{out: &plain, total: 3, start: time.Now(), lastPrint: time.Now()}
+	p.record(true, false)
+	p.record(false, true)
+	if plain.Len() != 0 {
+		t.Fatalf("non-terminal progress printed before the interval: %q", plain.String())
+	}
+	p.printf(" ✗ go/react FAILED\n")
+	p.record(false, false)
+	want := " ✗ go/react FAILED\n [3/3] passed=1 failed=1 skipped=1 elapsed=0s\n"
+	if plain.String() != want {
+		t.Fatalf("non-terminal output = %q, want %q", plain.String(), want)
+	}
+
+	var tty strings.Builder
+	p = &progressLine{out: &tty, tty: true, total: 2, start: time.Now()}
+	p.startTask("go/bank-account")
+	p.printf(" ✗ go/react FAILED\n")
+	p.record(true, false)
+	p.finish()
+	got := tty.String()
+	for _, want := range []string{
+		"\r\033[K [0/2] passed=0 failed=0 skipped=0 elapsed=0s running=go/bank-account",
+		"\r\033[K ✗ go/react FAILED\n",
+		"\r\033[K [1/2] passed=1 failed=0 skipped=0 elapsed=0s\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("terminal output missing %q in %q", want, got)
+		}
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "summary.json")
+	if err := os.WriteFile(path, []byte(`{"old":true}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFileAtomic(path, []byte(`{"new":true}`), 0o644); err != nil {
+		t.Fatalf("writeFileAtomic() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != `{"new":true}` {
+		t.Fatalf("summary.json = %q, %v; want new content", data, err)
+	}
+	if info, err := os.Stat(path); err != nil || (runtime.GOOS != "windows" && info.Mode().Perm() != 0o644) {
+		t.Fatalf("summary.json mode = %v, %v; want 0644", info.Mode(), err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("dir entries = %v, %v; want only summary.json", entries, err)
+	}
+
+	if err := writeFileAtomic(filepath.Join(dir, "missing", "x.json"), nil, 0o644); err == nil {
+		t.Fatal("expected error writing into a missing directory")
+	}
+}
+
 func TestWriteAgentTimeoutFooter(t *testing.T) {
 	t.Parallel()
 
@@ -220,7 +491,7 @@ func TestWriteAgentTimeoutFooter(t *testing.T) {
 	if err != nil {
 		t.Fatalf("open log file: %v", err)
 	}
-	writeAgentTimeoutFooter(logFile, 1, 120*time.Second, 121*time.Second)
+	writeAgentTimeoutFooter(logFile, 1, 120*time.Second, 0, 121*time.Second)
 	_ = logFile.Close()
 
 	data, err := os.ReadFile(path)
@@ -236,6 +507,158 @@ func TestWriteAgentTimeoutFooter(t *testing.T) {
 	}
 }
 
+func TestCappedLogWriter(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "agent.log")
+	if err := os.WriteFile(path, []byte("retry-1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	logFile, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("open log file: %v", err)
+	}
+	w := newCappedLogWriter(logFile, 12)
+	for _, chunk := range []string{"abc", "defghij", "more output"} {
+		if n, err := w.Write([]byte(chunk)); err != nil || n != len(chunk) {
+			t.Fatalf("Write(%q) = %d, %v; want %d, nil", chunk, n, err, len(chunk))
+		}
+	}
+	_ = logFile.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	want := "retry-1\nabcd\nHARNESS: log truncated at 12 bytes\n"
+	if string(data) != want {
+		t.Fatalf("log = %q, want %q", data, want)
+	}
+	if !w.truncated {
+		t.Error("truncated = false, want true")
+	}
+}
+
+func TestRunawayWriter(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	var out strings.Builder
+	kills := 0
+	w := newRunawayWriter(&out, 10, workspace, func() { kills++ })
+
+	_, _ = w.Write([]byte("thinking"))
+	edited := filepath.Join(workspace, "bank_account.go")
+	if err := os.WriteFile(edited, []byte("package bank\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Second)
+	if err := os.Chtimes(edited, future, future); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = w.Write([]byte("editing"))
+	if w.tripped.Load() || kills != 0 {
+		t.Fatal("agent that edited a file was killed as runaway")
+	}
+
+	w.checkpoint = future
+	for range 3 {
+		if n, err := w.Write([]byte("again")); err != nil || n != 5 {
+			t.Fatalf("Write() = %d, %v; want 5, nil", n, err)
+		}
+	}
+	if !w.tripped.Load() || kills != 1 {
+		t.Fatalf("tripped=%v kills=%d, want runaway killed once", w.tripped.Load(), kills)
+	}
+	if out.String() != "thinkingeditingagainagainagain" {
+		t.Fatalf("output = %q, want all writes passed through", out.String())
+	}
+
+	var quota, infra, timeouts int
+	var result agentExecutionResult
+	decision := classifyAttempt(agentAttemptResult{runaway: true}, false, filepath.Join(workspace, "agent.log"), workspace, time.Time{}, &quota, &infra, &timeouts, &result)
+	if !decision.done || !result.runaway || result.failureClass != FailureClassRunaway {
+		t.Fatalf("classifyAttempt() = %+v, runaway=%v class=%q; want done as runaway", decision, result.runaway, result.failureClass)
+	}
+}
+
+func TestClassifyAttemptRefusal(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	logPath := filepath.Join(t.TempDir(), "agent.log")
+	if err := os.WriteFile(logPath, []byte("I can't help with that.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	readyAt := time.Now()
+
+	var quota, infra, timeouts int
+	var result agentExecutionResult
+	decision := classifyAttempt(agentAttemptResult{}, false, logPath, workspace, readyAt, &quota, &infra, &timeouts, &result)
+	if !decision.done || result.refusal == "" || result.failureClass != FailureClassRefusal {
+		t.Fatalf("classifyAttempt() = %+v, refusal=%q class=%q; want done as refusal", decision, result.refusal, result.failureClass)
+	}
+
+	edited := filepath.Join(workspace, "bank_account.go")
+	if err := os.WriteFile(edited, []byte("package bank\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	future := readyAt.Add(time.Second)
+	if err := os.Chtimes(edited, future, future); err != nil {
+		t.Fatal(err)
+	}
+	result = agentExecutionResult{}
+	classifyAttempt(agentAttemptResult{}, false, logPath, workspace, readyAt, &quota, &infra, &timeouts, &result)
+	if result.refusal != "" || result.failureClass != FailureClassNone {
+		t.Fatalf("agent that edited files classified as refusal=%q class=%q", result.refusal, result.failureClass)
+	}
+}
+
+func TestWriteAgentTimeoutFooterIncludesGrace(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "agent.log")
+	logFile, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("open log file: %v", err)
+	}
+	writeAgentTimeoutFooter(logFile, 0, 120*time.Second, 15*time.Second, 110*time.Second)
+	_ = logFile.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	if !strings.Contains(string(data), "grace_seconds=15.000") {
+		t.Fatalf("expected grace field in footer, got: %s", data)
+	}
+}
+
+func TestAgentGracePeriod(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		grace   int
+		want    time.Duration
+	}{
+		{name: "disabled", timeout: 600 * time.Second, grace: 0, want: 0},
+		{name: "negative", timeout: 600 * time.Second, grace: -5, want: 0},
+		{name: "within timeout", timeout: 600 * time.Second, grace: 30, want: 30 * time.Second},
+		{name: "equal to timeout", timeout: 30 * time.Second, grace: 30, want: 0},
+		{name: "exceeds timeout", timeout: 30 * time.Second, grace: 60, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := agentGracePeriod(tt.timeout, tt.grace); got != tt.want {
+				t.Fatalf("agentGracePeriod(%v, %d) = %v, want %v", tt.timeout, tt.grace, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestWriteValidationLog(t *testing.T) {
 	t.Parallel()
 
@@ -299,6 +722,77 @@ func TestHashFilesReturnsEmptyWhenNoFilesPresent(t *testing.T) {
 	}
 }
 
+func TestCopyDirContentsSkipsVCSDirs(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	dst := t.TempDir()
+	for path, content := range map[string]string{
+		"main.go":            "package main\n",
+		"pkg/util.go":        "package pkg\n",
+		".git/HEAD":          "ref: refs/heads/main\n",
+		"pkg/.hg/dirstate":   "x",
+		"pkg/.gitkeep":       "",
+		"vendor/.git/config": "[core]\n",
+	} {
+		full := filepath.Join(src, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := copyDirContents(src, dst); err != nil {
+		t.Fatalf("copyDirContents() error = %v", err)
+	}
+	for _, want := range []string{"main.go", "pkg/util.go", "pkg/.gitkeep"} {
+		if _, err := os.Stat(filepath.Join(dst, want)); err != nil {
+			t.Errorf("expected %s to be copied: %v", want, err)
+		}
+	}
+	for _, skipped := range []string{".git", "pkg/.hg", "vendor/.git"} {
+		if _, err := os.Stat(filepath.Join(dst, skipped)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be skipped, stat err = %v", skipped, err)
+		}
+	}
+}
+
+func TestCopyDirContentsSkippingHarnessArtifacts(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	dst := t.TempDir()
+	for _, name := range []string{"sandbox.json", "agent.log", "main.go", "sub/sandbox.json"} {
+		full := filepath.Join(src, name)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("forged"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dst, "sandbox.json"), []byte("audit"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := copyDirContentsSkipping(src, dst, isEvalOutputFile); err != nil {
+		t.Fatalf("copyDirContentsSkipping() error = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dst, "sandbox.json")); string(data) != "audit" {
+		t.Errorf("sandbox.json = %q, want the harness audit kept", data)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "agent.log")); !os.IsNotExist(err) {
+		t.Errorf("agent.log should not be copied, stat err = %v", err)
+	}
+	for _, want := range []string{"main.go", "sub/sandbox.json"} {
+		if _, err := os.Stat(filepath.Join(dst, want)); err != nil {
+			t.Errorf("expected %s to be copied: %v", want, err)
+		}
+	}
+}
+
 func TestWriteIntegrityViolationArtifacts(t *testing.T) {
 	t.Parallel()
 
@@ -374,3 +868,597 @@ func TestWriteIntegrityViolationArtifacts(t *testing.T) {
 		t.Fatal("diff artifact is empty")
 	}
 }
+
+func TestDiskSpaceError(t *testing.T) {
+	t.Parallel()
+
+	if err := diskSpaceError("out", 2048*1024*1024, 1024); err != nil {
+		t.Fatalf("expected no error above threshold, got %v", err)
+	}
+	err := diskSpaceError("out", 512*1024*1024, 1024)
+	if err == nil {
+		t.Fatal("expected error below threshold")
+	}
+	if !strings.Contains(err.Error(), "insufficient disk space") || !strings.Contains(err.Error(), "512 MB free") {
+		t.Fatalf("unexpected error message: %v", err)
+	}
+}
+
+func TestDetectDisallowedNewFiles(t *testing.T) {
+	t.Parallel()
+
+	taskDef := &task.Task{
+		Slug:          "merge-sort",
+		Language:      task.Go,
+		EditableFiles: []string{"helpers/*.go"},
+		NoNewFiles:    true,
+		Files: task.TaskFiles{
+			Stub:    []string{"sort.go.txt"},
+			Test:    []string{"sort_test.go.txt"},
+			Support: []string{"go.mod.txt"},
+		},
+	}
+
+	workspaceDir := t.TempDir()
+	for _, rel := range []string{
+		"sort.go",
+		"sort_test.go",
+		"go.mod",
+		"helpers/heap.go",
+		"extra.go",
+		"node_modules/pkg/index.js",
+		".cache/state",
+		"go.sum",
+		"Cargo.lock",
+		"sub/pubspec.lock",
+	} {
+		p := filepath.Join(workspaceDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(p, []byte("x"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+
+	created, err := detectDisallowedNewFiles(taskDef, workspaceDir)
+	if err != nil {
+		t.Fatalf("detectDisallowedNewFiles() error = %v", err)
+	}
+	if len(created) != 1 || created[0] != "extra.go" {
+		t.Fatalf("created = %v, want [extra.go]", created)
+	}
+}
+
+func TestDetectModifiedTaskFilesRegenerable(t *testing.T) {
+	t.Parallel()
+
+	tasksRoot := t.TempDir()
+	taskDir := filepath.Join(tasksRoot, "go", "demo")
+	if err := os.MkdirAll(taskDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	canonical := map[string]string{
+		"demo.go.txt":      "package demo\n",
+		"demo_test.go.txt": "package demo\n\nfunc TestDemo(t *testing.T) {}\n",
+		"go.mod.txt":       "module demo\n",
+		"go.sum.txt":       "",
+	}
+	for name, content := range canonical {
+		if err := os.WriteFile(filepath.Join(taskDir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	loader := task.NewLoader(embed.FS{}, tasksRoot)
+	taskDef := &task.Task{
+		Slug:     "demo",
+		Language: task.Go,
+		Files: task.TaskFiles{
+			Stub:        []string{"demo.go.txt"},
+			Test:        []string{"demo_test.go.txt"},
+			Support:     []string{"go.mod.txt", "go.sum.txt"},
+			Regenerable: []string{"go.sum.txt"},
+		},
+	}
+
+	writeWorkspace := func(t *testing.T, overrides map[string]string) string {
+		t.Helper()
+		dir := t.TempDir()
+		for name, content := range canonical {
+			if override, ok := overrides[task.StripTxtExtension(name)]; ok {
+				content = override
+			}
+			if err := os.WriteFile(filepath.Join(dir, task.StripTxtExtension(name)), []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return dir
+	}
+
+	tests := []struct {
+		name      string
+		overrides map[string]string
+		want      []string
+	}{
+		{name: "regenerated go.sum", overrides: map[string]string{"go.sum": "example.com/dep v1.0.0 h1:abc=\n"}},
+		{name: "edited test file", overrides: map[string]string{"go.sum": "regenerated\n", "demo_test.go": "package demo\n"}, want: []string{"demo_test.go"}},
+		{name: "edited support file", overrides: map[string]string{"go.mod": "module other\n"}, want: []string{"go.mod"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			modified, err := detectModifiedTaskFiles(loader, taskDef, writeWorkspace(t, tc.overrides))
+			if err != nil {
+				t.Fatalf("detectModifiedTaskFiles() error = %v", err)
+			}
+			if !slices.Equal(modified, tc.want) {
+				t.Fatalf("modified = %v, want %v", modified, tc.want)
+			}
+		})
+	}
+}
+
+func TestWriteWorkspaceTree(t *testing.T) {
+	t.Parallel()
+
+	workspaceDir := t.TempDir()
+	files := map[string]string{
+		"main.go":                   "package main\n",
+		"pkg/helper.go":             "package pkg",
+		"node_modules/a/index.js":   "x",
+		"node_modules/b/index.js":   "yy",
+		"node_modules/b/readme.txt": "zzz",
+	}
+	for rel, content := range files {
+		p := filepath.Join(workspaceDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+
+	treePath := filepath.Join(t.TempDir(), "tree.txt")
+	writeWorkspaceTree(workspaceDir, treePath)
+
+	data, err := os.ReadFile(treePath)
+	if err != nil {
+		t.Fatalf("read tree: %v", err)
+	}
+	got := string(data)
+	for _, want := range []string{
+		"        13  main.go\n",
+		"         -  pkg/\n",
+		"        11  pkg/helper.go\n",
+		"         6  node_modules/ (3 files, not listed)\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("tree missing %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "index.js") {
+		t.Errorf("tree should not list node_modules contents, got:\n%s", got)
+	}
+}
+
+func TestCreateAgentWorkDirDeterministic(t *testing.T) {
+	t.Parallel()
+
+	tk := &task.Task{Slug: "debug-workspace-test", Language: task.Go}
+	root := t.TempDir()
+	want := filepath.Join(root, "sanity-eval-go-debug-workspace-test")
+
+	if err := os.MkdirAll(want, 0o755); err != nil {
+		t.Fatalf("mkdir stale workspace: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(want, "stale.txt"), []byte("old"), 0o644); err != nil {
+		t.Fatalf("write stale file: %v", err)
+	}
+
+	dir, err := createAgentWorkDir(tk, root)
+	if err != nil {
+		t.Fatalf("createAgentWorkDir() error = %v", err)
+	}
+	if dir != want {
+		t.Fatalf("createAgentWorkDir() = %q, want %q", dir, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "stale.txt")); !os.IsNotExist(err) {
+		t.Fatalf("stale file should be removed, stat err = %v", err)
+	}
+
+	random, err := createAgentWorkDir(tk, "")
+	if err != nil {
+		t.Fatalf("createAgentWorkDir(random) error = %v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(random) })
+	if random == want || !strings.HasPrefix(filepath.Base(random), "sanity-eval-go-debug-workspace-test-") {
+		t.Fatalf("random workspace = %q, want a suffixed name", random)
+	}
+}
+
+func TestDebugWorkspaceRunID(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct{ dir, want string }{
+		{"eval-results/gemini-2026-01-01T000000", "gemini-2026-01-01T000000"},
+		{"eval-results/batch-ts/claude-opus/run-2", "batch-ts-claude-opus-run-2"},
+		{"/home/u/eval-results/multi-ts/codex", "multi-ts-codex"},
+		{"out", "out"},
+	} {
+		if got := debugWorkspaceRunID(tc.dir); got != tc.want {
+			t.Errorf("debugWorkspaceRunID(%q) = %q, want %q", tc.dir, got, tc.want)
+		}
+	}
+}
+
+func TestBuildRunManifest(t *testing.T) {
+	t.Parallel()
+
+	outputDir := t.TempDir()
+	files := map[string]string{
+		"summary.json":                           "{}",
+		"report.md":                              "# Report",
+		"manifest.json":                          "stale",
+		"go-bank-account/agent.log":              "agent output",
+		"go-bank-account/validation.log":         "PASS",
+		"go-bank-account/integrity-diff/a.diff":  "diff",
+		"go-bank-account/bank_account.go":        "package bank",
+		"go-bank-account/vendor/pkg/internal.go": "package pkg",
+	}
+	for rel, content := range files {
+		p := filepath.Join(outputDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+
+	manifest, err := buildRunManifest(outputDir)
+	if err != nil {
+		t.Fatalf("buildRunManifest() error = %v", err)
+	}
+
+	var got []string
+	for _, a := range manifest.Artifacts {
+		got = append(got, a.Path)
+		if a.Path == "report.md" && (a.Size != 8 || a.Hash != hashBytes([]byte("# Report"))) {
+			t.Errorf("report.md artifact = %+v", a)
+		}
+	}
+	want := []string{
+		"go-bank-account/agent.log",
+		"go-bank-account/integrity-diff/a.diff",
+		"go-bank-account/validation.log",
+		"report.md",
+		"summary.json",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("artifacts = %v, want %v", got, want)
+	}
+}
+
+func TestStubsUntouched(t *testing.T) {
+	t.Parallel()
+
+	loader := task.NewLoader(tasks.FS, tasksDir)
+	taskDef, err := loader.Load("flow-processor")
+	if err != nil {
+		t.Fatalf("load task: %v", err)
+	}
+
+	workspaceDir := t.TempDir()
+	for _, f := range taskDef.Files.Stub {
+		data, err := loader.ReadTaskFile(taskDef, f)
+		if err != nil {
+			t.Fatalf("read stub: %v", err)
+		}
+		p := filepath.Join(workspaceDir, task.StripTxtExtension(f))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(p, data, 0o644); err != nil {
+			t.Fatalf("write stub: %v", err)
+		}
+	}
+	if !stubsUntouched(loader, taskDef, workspaceDir) {
+		t.Fatal("fresh workspace should have untouched stubs")
+	}
+
+	stub := filepath.Join(workspaceDir, task.StripTxtExtension(taskDef.Files.Stub[0]))
+	if err := os.WriteFile(stub, []byte("// edited\n"), 0o644); err != nil {
+		t.Fatalf("write stub: %v", err)
+	}
+	if stubsUntouched(loader, taskDef, workspaceDir) {
+		t.Fatal("edited stub should be detected")
+	}
+}
+
+func TestTimeoutOutcome(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		r    EvalResult
+		want TimeoutOutcome
+	}{
+		{name: "no timeout", r: EvalResult{Passed: true}, want: ""},
+		{name: "partial pass", r: EvalResult{AgentTimedOut: true, Passed: true}, want: TimeoutPartialPass},
+		{name: "partial fail", r: EvalResult{AgentTimedOut: true}, want: TimeoutPartialFail},
+		{name: "no work", r: EvalResult{AgentTimedOut: true, stubsUntouched: true}, want: TimeoutNoWork},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := timeoutOutcome(tc.r); got != tc.want {
+				t.Errorf("timeoutOutcome() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResumeRepairsCorruptArtifacts(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go-complete":  "ok\n\nHARNESS: validation command=\"go test\" exit_code=0 duration_seconds=1.000 timed_out=false\n",
+		"go-truncated": "--- PASS: TestDeposit (0.00s)\nok  \tbank",
+		"go-empty":     "",
+		"go-missing":   "",
+	} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if name == "go-missing" {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, "validation.log"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	completed, err := findCompletedTasks(dir)
+	if err != nil {
+		t.Fatalf("findCompletedTasks() error = %v", err)
+	}
+	if len(completed) != 1 || !completed["go/complete"] {
+		t.Fatalf("completed = %v, want only go/complete", completed)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "summary.json"), []byte(`{"agent": "codex", "results": [{"task": "go/comp`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	prev, err := loadResumeSummary(dir)
+	if err != nil || prev != nil {
+		t.Fatalf("loadResumeSummary() = %v, %v, want nil, nil for a truncated summary", prev, err)
+	}
+	if dropped := dropUnrecordedTasks(completed, prev); len(dropped) != 1 || dropped[0] != "go/complete" || len(completed) != 0 {
+		t.Fatalf("dropUnrecordedTasks() = %v, completed = %v", dropped, completed)
+	}
+
+	completed = map[string]bool{"go/a": true, "go/b": true}
+	prev = &EvalSummary{Results: []EvalResult{{Task: "go/a"}}}
+	if dropped := dropUnrecordedTasks(completed, prev); len(dropped) != 1 || dropped[0] != "go/b" || !completed["go/a"] {
+		t.Fatalf("dropUnrecordedTasks() = %v, completed = %v", dropped, completed)
+	}
+}
+
+func TestRetryFailedResume(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"go-pass", "go-fail", "go-xfail", "go-crashed"} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	suite := []*task.Task{
+		{Slug: "pass", Language: task.Go},
+		{Slug: "fail", Language: task.Go},
+		{Slug: "xfail", Language: task.Go},
+		{Slug: "crashed", Language: task.Go},
+	}
+	completed := map[string]bool{"go/pass": true, "go/fail": true, "go/xfail": true}
+	prev := &EvalSummary{Results: []EvalResult{
+		{Task: "go/pass", Passed: true},
+		{Task: "go/fail"},
+		{Task: "go/xfail", ExpectedFail: true},
+	}}
+
+	if requeued := requeueFailedTasks(completed, prev); len(requeued) != 1 || requeued[0] != "go/fail" {
+		t.Fatalf("requeueFailedTasks() = %v, want [go/fail]", requeued)
+	}
+	// The failed task's artifacts back its recorded result until it reruns.
+	failLog := filepath.Join(dir, "go-fail", "validation.log")
+	if err := os.WriteFile(failLog, []byte("FAIL\nHARNESS: validation exit_code=1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := cleanIncompleteTaskDirs(dir, completed, suite); err != nil {
+		t.Fatalf("cleanIncompleteTaskDirs() error = %v", err)
+	}
+	if _, err := os.Stat(failLog); err != nil {
+		t.Fatalf("go-fail artifacts should be kept until the retry runs: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "go-crashed")); !os.IsNotExist(err) {
+		t.Fatalf("incomplete go-crashed dir should be removed, stat error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "go-pass")); err != nil {
+		t.Fatalf("go-pass dir should be kept: %v", err)
+	}
+
+	// The retry passes; the old failure must not be merged alongside it.
+	ran := map[string]bool{"go/fail": true}
+	merged, _, passed, failed := mergePreviousResults(prev.Results, []EvalResult{{Task: "go/fail", Passed: true}}, nil, ran)
+	if len(merged) != 3 || passed != 1 || failed != 1 {
+		t.Fatalf("merged = %+v, carried over passed = %d failed = %d, want 3 results, 1 and 1", merged, passed, failed)
+	}
+	for _, r := range merged {
+		if r.Task == "go/fail" && !r.Passed {
+			t.Fatalf("stale failure merged: %+v", merged)
+		}
+	}
+
+	// A retry that only hits an external failure keeps the scored failure,
+	// so the pass rate does not go up.
+	merged, external, _, failed := mergePreviousResults(prev.Results, nil, []ExternalFailure{{Task: "go/fail"}}, ran)
+	if len(merged) != 3 || failed != 2 || len(external) != 0 {
+		t.Fatalf("merged = %+v, failed = %d, external = %+v, want the failure still counted and no skip", merged, failed, external)
+	}
+
+	// An external failure carried over for a task that did not run again
+	// replaces a stale scored result.
+	merged, external, _, failed = mergePreviousResults(prev.Results, nil, []ExternalFailure{{Task: "go/fail"}}, nil)
+	if len(merged) != 2 || failed != 1 || len(external) != 1 {
+		t.Fatalf("merged = %+v, failed = %d, external = %+v, want go/fail as a skip only", merged, failed, external)
+	}
+}
+
+func TestWriteOutcomeChart(t *testing.T) {
+	t.Parallel()
+
+	summary := EvalSummary{
+		Results: []EvalResult{
+			{Task: "go/a", Passed: true},
+			{Task: "go/b", Passed: true},
+			{Task: "go/c", FailureClass: FailureClassValidationError},
+			{Task: "go/d", AgentTimedOut: true, FailureClass: FailureClassValidationError},
+			{Task: "go/e", FailureClass: FailureClassIntegrity},
+		},
+		ExternalFailures: []ExternalFailure{{Task: "go/f", FailureClass: FailureClassInfra}},
+	}
+	var sb strings.Builder
+	writeOutcomeChart(&sb, outcomeDistribution(summary))
+	chart := sb.String()
+
+	for _, want := range []string{
+		"Passed              " + strings.Repeat("█", 13) + strings.Repeat("░", 27) + "    2  33.3%",
+		"Validation failed   " + strings.Repeat("█", 7) + strings.Repeat("░", 33) + "    1  16.7%",
+		"Timed out ",
+		"Integrity violation ",
+		"External skip ",
+	} {
+		if !strings.Contains(chart, want) {
+			t.Fatalf("chart missing %q:\n%s", want, chart)
+		}
+	}
+
+	sb.Reset()
+	writeOutcomeChart(&sb, outcomeDistribution(EvalSummary{}))
+	if sb.Len() != 0 {
+		t.Fatalf("chart for an empty run = %q, want nothing", sb.String())
+	}
+}
+
+func TestEventLog(t *testing.T) {
+	t.Parallel()
+
+	var buf strings.Builder
+	events := newEventLog(&buf)
+	done := make(chan struct{})
+	for i := range 8 {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			r := EvalResult{Task: fmt.Sprintf("go/task-%d", i), Attempts: 1, Passed: i%2 == 0, FailureClass: FailureClassValidationError}
+			events.emit(evalEvent{Event: eventTaskStarted, Task: r.Task})
+			events.emitValidationFinished(r)
+			events.emitTaskResult(r, "gemini")
+		}()
+	}
+	for range 8 {
+		<-done
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 24 {
+		t.Fatalf("got %d lines, want 24", len(lines))
+	}
+	for _, line := range lines {
+		var e evalEvent
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", line, err)
+		}
+		if e.Task == "" || e.Time == "" {
+			t.Fatalf("event missing task or time: %q", line)
+		}
+		if e.Event == eventTaskResult && (e.Agent != "gemini" || e.Passed == nil || e.FailureClass == "") {
+			t.Fatalf("incomplete task_result: %q", line)
+		}
+	}
+
+	var nilLog *eventLog
+	nilLog.emit(evalEvent{Event: eventTaskStarted}) // must not panic
+}
+
+func TestAttestationTasksHash(t *testing.T) {
+	t.Parallel()
+
+	results := []EvalResult{{Task: "go/a"}, {Task: "go/b"}, {Task: "go/gone"}}
+	attestation := EvalAttestation{Tasks: map[string]AttestationTask{
+		"go/a": {TaskHash: "blake3:aa"},
+		"go/b": {TaskHash: "blake3:bb"},
+	}}
+	attestation.Integrity.TasksHash = hashBytes([]byte("blake3:aablake3:bb"))
+
+	if got := attestationTasksHash(attestation, results); got != attestation.Integrity.TasksHash {
+		t.Fatalf("attestationTasksHash() = %s, want %s", got, attestation.Integrity.TasksHash)
+	}
+	attestation.Tasks["go/b"] = AttestationTask{TaskHash: "blake3:edited"}
+	if got := attestationTasksHash(attestation, results); got == attestation.Integrity.TasksHash {
+		t.Fatal("attestationTasksHash() did not change after a task hash was edited")
+	}
+}
+
+func TestWriteJUnitReport(t *testing.T) {
+	t.Parallel()
+
+	summary := EvalSummary{
+		Agent: "codex", Model: "gpt-5", Duration: 42,
+		Results: []EvalResult{
+			{Task: "go/bank-account", Language: "go", Passed: true, Status: task.StatusPass, Duration: 10},
+			{Task: "rust/react", Language: "rust", Status: task.StatusFail, FailureClass: FailureClassValidationError, Error: "tests failed", Duration: 20},
+			{Task: "go/grep", Language: "go", Status: task.StatusIntegrityViolation, FailureClass: FailureClassIntegrity,
+				Error: "modified task files (disallowed): grep_test.go", Duration: 5},
+			{Task: "zig/parser", Language: "zig", Status: task.StatusFail, ExpectedFail: true, Duration: 7},
+		},
+		Passed: 1, Failed: 3, Total: 4, ExpectedFailures: 1,
+		ExternalFailures: []ExternalFailure{{Task: "dart/queue", FailureClass: FailureClassQuotaExhausted}},
+	}
+
+	path, err := writeJUnitReport(t.TempDir(), summary)
+	if err != nil {
+		t.Fatalf("writeJUnitReport error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report junitTestSuites
+	if err := xml.Unmarshal(data, &report); err != nil {
+		t.Fatalf("junit.xml does not parse: %v\n%s", err, data)
+	}
+
+	wantTests := summary.Total + len(summary.ExternalFailures)
+	wantFailures := summary.Failed - summary.ExpectedFailures
+	wantSkipped := summary.ExpectedFailures + len(summary.ExternalFailures)
+	if report.Tests != wantTests || report.Failures != wantFailures || report.Skipped != wantSkipped {
+		t.Fatalf("counts = %d tests, %d failures, %d skipped; want %d, %d, %d",
+			report.Tests, report.Failures, report.Skipped, wantTests, wantFailures, wantSkipped)
+	}
+	if len(report.Suites) != 1 || len(report.Suites[0].Cases) != wantTests {
+		t.Fatalf("suites = %+v", report.Suites)
+	}
+	cases := report.Suites[0].Cases
+	if cases[0].Name != "go/bank-account" || cases[0].Classname != "go" || cases[0].Time != "10.000" || cases[0].Failure != nil {
+		t.Fatalf("passing case = %+v", cases[0])
+	}
+	if f := cases[2].Failure; f == nil || f.Type != "integrity" || !strings.Contains(f.Message, "grep_test.go") {
+		t.Fatalf("integrity case failure = %+v", f)
+	}
+	if cases[3].Skipped == nil || cases[4].Skipped == nil || cases[4].Classname != "dart" {
+		t.Fatalf("skipped cases = %+v, %+v", cases[3], cases[4])
+	}
+}
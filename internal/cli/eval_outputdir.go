@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// defaultOutputTemplate names run directories when [harness] output_template
+// is unset.
+const defaultOutputTemplate = "{timestamp}-{agent}"
+
+// outputTemplateVarPattern matches one {name} variable in an output template.
+var outputTemplateVarPattern = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// resolveOutputTemplate expands an output template for spec into a path
+// under eval-results. Variables are {agent}, {model} and {reasoning}
+// ("default" when unset), {date} (YYYY-MM-DD), {timestamp} and {uuid}, a
+// random ID unique to the run. timestamp is the run's start time in the
+// eval timestamp format.
+func resolveOutputTemplate(template string, spec RunSpec, timestamp string) (string, error) {
+	if template == "" {
+		template = defaultOutputTemplate
+	}
+
+	date := timestamp
+	if t, err := time.Parse("2006-01-02T150405", timestamp); err == nil {
+		date = t.Format("2006-01-02")
+	}
+	values := map[string]string{
+		"agent":     spec.Agent,
+		"model":     orDefault(sanitizeModel(spec.Model)),
+		"reasoning": orDefault(sanitizeModel(spec.Reasoning)),
+		"date":      date,
+		"timestamp": timestamp,
+	}
+
+	var unknown []string
+	resolved := outputTemplateVarPattern.ReplaceAllStringFunc(template, func(v string) string {
+		name := v[1 : len(v)-1]
+		if name == "uuid" {
+			return newRunUUID()
+		}
+		value, ok := values[name]
+		if !ok {
+			unknown = append(unknown, v)
+		}
+		return value
+	})
+	if len(unknown) > 0 {
+		return "", fmt.Errorf("output_template %q: unknown variable %s (valid: {agent}, {model}, {reasoning}, {date}, {timestamp}, {uuid})",
+			template, strings.Join(unknown, ", "))
+	}
+	if filepath.IsAbs(resolved) || !filepath.IsLocal(resolved) {
+		return "", fmt.Errorf("output_template %q must expand to a relative path inside eval-results", template)
+	}
+	return filepath.Join("eval-results", resolved), nil
+}
+
+// defaultOutputDir returns the run directory named by [harness]
+// output_template. Templates without {timestamp} or {uuid} can name an
+// existing run, which is refused rather than mixed into.
+func defaultOutputDir(spec RunSpec, timestamp string) (string, error) {
+	template := ""
+	if cfg != nil {
+		template = cfg.Harness.OutputTemplate
+	}
+	dir, err := resolveOutputTemplate(template, spec, timestamp)
+	if err != nil {
+		return "", err
+	}
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return "", fmt.Errorf("output directory %s already exists; add {timestamp} or {uuid} to output_template, or pass --output", dir)
+	}
+	return dir, nil
+}
+
+func orDefault(value string) string {
+	if value == "" {
+		return "default"
+	}
+	return value
+}
+
+// newRunUUID returns a random (version 4) UUID.
+func newRunUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// progressInterval is how often the progress line is repeated when stdout
+// is not a terminal, so CI logs get periodic lines instead of redraws.
+const progressInterval = 30 * time.Second
+
+// progressLine renders --progress: one status line such as
+// "[12/26] passed=8 failed=3 skipped=1 elapsed=4m12s" that a terminal
+// redraws in place as tasks finish. Other output goes through printf, which
+// clears the line, prints above it and redraws it.
+type progressLine struct {
+	mu        sync.Mutex
+	out       io.Writer
+	tty       bool
+	total     int
+	start     time.Time
+	lastPrint time.Time
+	drawn     bool
+
+	done, passed, failed, skipped int
+	running                       string
+}
+
+func newProgressLine(out *os.File, total int) *progressLine {
+	return &progressLine{
+		out:   out,
+		tty:   isTerminal(out),
+		total: total,
+		start: time.Now(),
+	}
+}
+
+// startTask shows taskID as running. Only a terminal shows it.
+func (p *progressLine) startTask(taskID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.running = taskID
+	if p.tty {
+		p.redraw()
+	}
+}
+
+// record counts a finished task and updates the line: redrawn on a
+// terminal, otherwise printed at most every progressInterval and once when
+// the last task finishes.
+func (p *progressLine) record(passed, skipped bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	p.running = ""
+	switch {
+	case skipped:
+		p.skipped++
+	case passed:
+		p.passed++
+	default:
+		p.failed++
+	}
+	if p.tty {
+		p.redraw()
+		return
+	}
+	if p.done == p.total || time.Since(p.lastPrint) >= progressInterval {
+		fmt.Fprintln(p.out, " "+p.status())
+		p.lastPrint = time.Now()
+	}
+}
+
+// printf prints a line above the progress line.
+func (p *progressLine) printf(format string, args ...any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+	fmt.Fprintf(p.out, format, args...)
+	if p.tty {
+		p.redraw()
+	}
+}
+
+// finish leaves the final status on its own line.
+func (p *progressLine) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.drawn {
+		p.running = ""
+		p.redraw()
+		fmt.Fprintln(p.out)
+		p.drawn = false
+	}
+}
+
+func (p *progressLine) status() string {
+	s := fmt.Sprintf("[%d/%d] passed=%d failed=%d skipped=%d elapsed=%s",
+		p.done, p.total, p.passed, p.failed, p.skipped, time.Since(p.start).Round(time.Second))
+	if p.running != "" {
+		s += " running=" + p.running
+	}
+	return s
+}
+
+func (p *progressLine) redraw() {
+	fmt.Fprintf(p.out, "\r\033[K %s", p.status())
+	p.drawn = true
+}
+
+func (p *progressLine) clear() {
+	if p.drawn {
+		fmt.Fprint(p.out, "\r\033[K")
+		p.drawn = false
+	}
+}
+
+// isTerminal reports whether f is a character device such as a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
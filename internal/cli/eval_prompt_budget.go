@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// promptTrimStages are applied in order by trimPromptToBudget until the
+// prompt fits. The opening line, TASK INFO and FILES TO READ are never
+// removed.
+var promptTrimStages = []func(sections []string) []string{
+	dropPromptSection("ENVIRONMENT:"),
+	dropPromptSection("IMPORTANT:"),
+	keepFirstPromptRule,
+	dropPromptSection("YOUR TASK:"),
+}
+
+// estimatePromptTokens approximates the token count of a prompt at four
+// characters per token.
+func estimatePromptTokens(prompt string) int {
+	return (utf8.RuneCountInString(prompt) + 3) / 4
+}
+
+// trimPromptToBudget shortens prompt when its estimated token count exceeds
+// budget, returning the trimmed prompt and whether anything was removed. A
+// budget of zero disables trimming. When every stage has been applied the
+// result is returned even if it is still over budget.
+func trimPromptToBudget(prompt string, budget int) (string, bool) {
+	if budget <= 0 || estimatePromptTokens(prompt) <= budget {
+		return prompt, false
+	}
+
+	sections := strings.Split(prompt, "\n\n")
+	for _, stage := range promptTrimStages {
+		sections = stage(sections)
+		if estimatePromptTokens(strings.Join(sections, "\n\n")) <= budget {
+			break
+		}
+	}
+	trimmed := strings.Join(sections, "\n\n")
+	return trimmed, trimmed != prompt
+}
+
+// taskPromptBudget returns the part of budget left for the task prompt once
+// systemPrompt, which the agent receives with it, is counted, so the budget
+// covers the combined prompt. It never drops below one token, which still
+// lets trimPromptToBudget apply every stage.
+func taskPromptBudget(budget int, systemPrompt string) int {
+	if budget <= 0 || systemPrompt == "" {
+		return budget
+	}
+	return max(budget-estimatePromptTokens(systemPrompt+"\n\n"), 1)
+}
+
+// dropPromptSection returns a trim stage removing the section whose first
+// line is header.
+func dropPromptSection(header string) func([]string) []string {
+	return func(sections []string) []string {
+		kept := sections[:0:0]
+		for _, s := range sections {
+			if !strings.HasPrefix(s, header+"\n") {
+				kept = append(kept, s)
+			}
+		}
+		return kept
+	}
+}
+
+// keepFirstPromptRule reduces the RULES section to its first bullet, which
+// names the files the agent may edit.
+func keepFirstPromptRule(sections []string) []string {
+	out := make([]string, len(sections))
+	for i, s := range sections {
+		lines := strings.Split(s, "\n")
+		if lines[0] == "RULES:" && len(lines) > 2 {
+			s = strings.Join(lines[:2], "\n")
+		}
+		out[i] = s
+	}
+	return out
+}
@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/lemon07r/sanityharness/internal/task"
+)
+
+// promptTemplateRequiredFields are the fields a --prompt-template must
+// reference; without them the agent cannot know what to implement.
+var promptTemplateRequiredFields = []string{"Description", "StubFiles"}
+
+// promptTemplateFuncs are available to templates besides the builtins, e.g.
+// {{join .StubFiles ", "}}.
+var promptTemplateFuncs = template.FuncMap{"join": strings.Join}
+
+// promptTemplateData is what a --prompt-template is rendered with, one per
+// task.
+type promptTemplateData struct {
+	Name          string
+	Language      string
+	Tier          string
+	Difficulty    string
+	Description   string
+	Hint          string
+	StubFiles     []string
+	TestFiles     []string
+	ContextFiles  []string
+	EditableFiles []string
+	NoNewFiles    bool
+	Toolchain     string
+	UseMCPTools   bool
+	UseSkills     bool
+}
+
+// promptTemplate is a parsed --prompt-template file.
+type promptTemplate struct {
+	tmpl *template.Template
+	hash string // BLAKE3 of the file, recorded in the attestation
+}
+
+// loadPromptTemplate parses the text/template file at path and checks it
+// against promptTemplateData: every field it references, in any branch, must
+// exist, the required fields must appear, and rendering a sample task must
+// succeed. An empty path returns nil, meaning the built-in prompt.
+func loadPromptTemplate(path string) (*promptTemplate, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading prompt template: %w", err)
+	}
+	tmpl, err := template.New("prompt").Funcs(promptTemplateFuncs).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing prompt template %s: %w", path, err)
+	}
+
+	w := templateFieldWalker{known: promptTemplateFields(), fields: make(map[string]bool)}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			w.walk(t.Root, true)
+		}
+	}
+	if len(w.unknown) > 0 {
+		return nil, fmt.Errorf("prompt template %s references unknown field {{.%s}}", path, w.unknown[0])
+	}
+	for _, name := range promptTemplateRequiredFields {
+		if !w.fields[name] {
+			return nil, fmt.Errorf("prompt template %s must reference {{.%s}}", path, name)
+		}
+	}
+
+	sample := promptTemplateData{
+		Name: "sample", Language: "go", Tier: "core", Difficulty: "easy", Description: "sample",
+		StubFiles: []string{"sample.go"}, TestFiles: []string{"sample_test.go"},
+	}
+	if err := tmpl.Execute(&bytes.Buffer{}, sample); err != nil {
+		return nil, fmt.Errorf("prompt template %s: %w", path, err)
+	}
+	return &promptTemplate{tmpl: tmpl, hash: hashBytes(data)}, nil
+}
+
+// promptTemplateFields returns the names of promptTemplateData's fields.
+func promptTemplateFields() map[string]bool {
+	typ := reflect.TypeOf(promptTemplateData{})
+	fields := make(map[string]bool, typ.NumField())
+	for i := range typ.NumField() {
+		fields[typ.Field(i).Name] = true
+	}
+	return fields
+}
+
+// templateFieldWalker collects the promptTemplateData fields a template
+// references, in every branch, so a field used only under an {{if}} that the
+// sample render skips is still checked.
+type templateFieldWalker struct {
+	known   map[string]bool
+	fields  map[string]bool
+	unknown []string
+}
+
+// walk visits node. dotIsData reports whether "." is still the
+// promptTemplateData there; inside a {{range}} or {{with}} body it is rebound
+// to something else, so only $.Field references are checked.
+func (w *templateFieldWalker) walk(node parse.Node, dotIsData bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			w.walk(child, dotIsData)
+		}
+	case *parse.ActionNode:
+		w.walk(n.Pipe, dotIsData)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			w.walk(cmd, dotIsData)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			w.walk(arg, dotIsData)
+		}
+	case *parse.FieldNode:
+		if dotIsData {
+			w.record(n.Ident[0])
+		}
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			w.record(n.Ident[1])
+		}
+	case *parse.ChainNode:
+		w.walk(n.Node, dotIsData)
+	case *parse.IfNode:
+		w.walk(n.Pipe, dotIsData)
+		w.walk(n.List, dotIsData)
+		w.walk(n.ElseList, dotIsData)
+	case *parse.RangeNode:
+		w.walk(n.Pipe, dotIsData)
+		w.walk(n.List, false)
+		w.walk(n.ElseList, dotIsData)
+	case *parse.WithNode:
+		w.walk(n.Pipe, dotIsData)
+		w.walk(n.List, false)
+		w.walk(n.ElseList, dotIsData)
+	case *parse.TemplateNode:
+		w.walk(n.Pipe, dotIsData)
+	}
+}
+
+func (w *templateFieldWalker) record(name string) {
+	if !w.known[name] {
+		w.unknown = append(w.unknown, name)
+		return
+	}
+	w.fields[name] = true
+}
+
+// newPromptTemplateData returns the template fields for t.
+func newPromptTemplateData(t *task.Task, useMCPTools, useSkills bool) promptTemplateData {
+	strip := func(files []string) []string {
+		out := make([]string, 0, len(files))
+		for _, f := range files {
+			out = append(out, task.StripTxtExtension(f))
+		}
+		return out
+	}
+	return promptTemplateData{
+		Name:          t.Name,
+		Language:      string(t.Language),
+		Tier:          t.Tier,
+		Difficulty:    t.Difficulty,
+		Description:   t.Description,
+		Hint:          t.PromptHint,
+		StubFiles:     strip(t.Files.Stub),
+		TestFiles:     strip(t.Files.Test),
+		ContextFiles:  strip(t.ContextFiles),
+		EditableFiles: t.EditableFiles,
+		NoNewFiles:    t.NoNewFiles,
+		Toolchain:     toolchainInfo(t.Language),
+		UseMCPTools:   useMCPTools,
+		UseSkills:     useSkills,
+	}
+}
+
+// render executes the template for t.
+func (p *promptTemplate) render(t *task.Task, useMCPTools, useSkills bool) (string, error) {
+	var buf bytes.Buffer
+	if err := p.tmpl.Execute(&buf, newPromptTemplateData(t, useMCPTools, useSkills)); err != nil {
+		return "", fmt.Errorf("rendering prompt template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// agentPrompt returns the prompt for t: tmpl's rendering when the run has a
+// --prompt-template, otherwise the built-in prompt.
+func agentPrompt(tmpl *promptTemplate, t *task.Task, useMCPTools, useSkills bool, mcpPrompt string) (string, error) {
+	if tmpl != nil {
+		return tmpl.render(t, useMCPTools, useSkills)
+	}
+	return buildAgentPrompt(t, useMCPTools, useSkills, mcpPrompt), nil
+}
@@ -4,14 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"reflect"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/lemon07r/sanityharness/internal/config"
+	"github.com/lemon07r/sanityharness/internal/runner"
 	"github.com/lemon07r/sanityharness/internal/task"
 )
 
@@ -57,6 +63,22 @@ func TestBuildAgentPromptIncludesKeyInfo(t *testing.T) {
 	if strings.Contains(prompt, ".txt") {
 		t.Fatalf("prompt should not include .txt filenames\n\nPrompt:\n%s", prompt)
 	}
+	if strings.Contains(prompt, "Hint:") {
+		t.Fatalf("prompt has a hint line for a task without prompt_hint\n\nPrompt:\n%s", prompt)
+	}
+
+	tt.ContextFiles = []string{"SPEC.md.txt"}
+	prompt = buildAgentPrompt(tt, false, false, "")
+	if want := "Test files:          demo_test.go\n- Context files:       SPEC.md (read-only reference material)\n"; !strings.Contains(prompt, want) {
+		t.Fatalf("prompt missing context files %q\n\nPrompt:\n%s", want, prompt)
+	}
+	tt.ContextFiles = nil
+
+	tt.PromptHint = "Amounts are whole cents."
+	prompt = buildAgentPrompt(tt, false, false, "")
+	if want := "Description: Implement the thing.\n- Hint:        Amounts are whole cents.\n"; !strings.Contains(prompt, want) {
+		t.Fatalf("prompt missing hint %q\n\nPrompt:\n%s", want, prompt)
+	}
 }
 
 func TestBuildAgentPromptWithMCPTools(t *testing.T) {
@@ -278,6 +300,7 @@ func TestBuildAgentCommandDisableMCP(t *testing.T) {
 				"test prompt",
 				"",
 				"",
+				"",
 				tc.disableMCP,
 				tc.useMCPTools,
 				tc.agentName,
@@ -528,6 +551,7 @@ type agentCommandTestCase struct {
 	prompt       string
 	model        string
 	reasoning    string
+	systemPrompt string
 	disableMCP   bool
 	useMCPTools  bool
 	agentName    string
@@ -547,6 +571,7 @@ func runAgentCommandTestCases(t *testing.T, tests []agentCommandTestCase) {
 				tc.prompt,
 				tc.model,
 				tc.reasoning,
+				tc.systemPrompt,
 				tc.disableMCP,
 				tc.useMCPTools,
 				tc.agentName,
@@ -712,6 +737,48 @@ func TestBuildAgentCommand_ModelAndReasoningFlags(t *testing.T) {
 	})
 }
 
+func TestBuildAgentCommand_SystemPrompt(t *testing.T) {
+	t.Parallel()
+
+	runAgentCommandTestCases(t, []agentCommandTestCase{
+		{
+			name: "system_prompt_flag_standard",
+			agentCfg: &config.AgentConfig{
+				Command:          "agent",
+				Args:             []string{"-p", "{prompt}"},
+				ModelFlag:        "--model",
+				SystemPromptFlag: "--append-system-prompt",
+			},
+			prompt:       "do the thing",
+			model:        "opus",
+			systemPrompt: "be careful",
+			expectedArgs: []string{"--model", "opus", "--append-system-prompt", "be careful", "-p", "do the thing"},
+		},
+		{
+			name: "system_prompt_flag_placeholder",
+			agentCfg: &config.AgentConfig{
+				Command:          "agent",
+				Args:             []string{"exec", "{prompt}"},
+				SystemPromptFlag: "--system={value}",
+			},
+			prompt:       "do the thing",
+			systemPrompt: "be careful",
+			expectedArgs: []string{"--system=be careful", "exec", "do the thing"},
+		},
+		{
+			name: "system_prompt_without_flag_is_prepended",
+			agentCfg: &config.AgentConfig{
+				Command:      "agent",
+				Args:         []string{"run", "{prompt}"},
+				PromptPrefix: "ulw",
+			},
+			prompt:       "do the thing",
+			systemPrompt: "be careful",
+			expectedArgs: []string{"run", "ulw be careful\n\ndo the thing"},
+		},
+	})
+}
+
 func TestBuildAgentCommand_RealWorldPatterns(t *testing.T) {
 	t.Parallel()
 
@@ -838,6 +905,75 @@ func TestBuildAgentCommand_EdgeCases(t *testing.T) {
 	})
 }
 
+func TestLoadPromptTemplate(t *testing.T) {
+	t.Parallel()
+
+	write := func(t *testing.T, content string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "prompt.tmpl")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("renders task fields", func(t *testing.T) {
+		t.Parallel()
+		path := write(t, "Solve {{.Name}} in {{.Language}} ({{.Toolchain}}).\n{{.Description}}\nEdit: {{join .StubFiles \", \"}}{{if .UseMCPTools}} with MCP{{end}}")
+		tmpl, err := loadPromptTemplate(path)
+		if err != nil {
+			t.Fatalf("loadPromptTemplate() error = %v", err)
+		}
+		if !strings.HasPrefix(tmpl.hash, "blake3:") {
+			t.Fatalf("hash = %q, want a blake3 hash", tmpl.hash)
+		}
+		tt := &task.Task{
+			Name: "Demo Task", Language: task.Go, Description: "Implement the thing.",
+			Files: task.TaskFiles{Stub: []string{"demo.go.txt", "util.go.txt"}},
+		}
+		prompt, err := tmpl.render(tt, true, false)
+		if err != nil {
+			t.Fatalf("render() error = %v", err)
+		}
+		want := "Solve Demo Task in go (" + toolchainInfo(task.Go) + ").\nImplement the thing.\nEdit: demo.go, util.go with MCP"
+		if prompt != want {
+			t.Fatalf("render() = %q, want %q", prompt, want)
+		}
+	})
+
+	for _, tc := range []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "missing required field", content: "{{.Description}}", wantErr: "must reference {{.StubFiles}}"},
+		{name: "unknown field", content: "{{.Description}} {{.StubFiles}} {{.Author}}", wantErr: "unknown field {{.Author}}"},
+		{name: "unknown field in untaken branch", content: "{{.Description}} {{.StubFiles}}{{if .UseSkills}}{{.Skils}}{{end}}", wantErr: "unknown field {{.Skils}}"},
+		{name: "unknown field in else branch", content: "{{.Description}} {{.StubFiles}}{{if .Hint}}{{.Hint}}{{else}}{{.Hnt}}{{end}}", wantErr: "unknown field {{.Hnt}}"},
+		{name: "unknown root field in range", content: "{{.Description}}{{range .StubFiles}}{{.}} {{$.Authr}}{{end}}", wantErr: "unknown field {{.Authr}}"},
+		{name: "syntax error", content: "{{.Description", wantErr: "parsing prompt template"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := loadPromptTemplate(write(t, tc.content))
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("loadPromptTemplate() error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+
+	t.Run("range body fields are not task fields", func(t *testing.T) {
+		t.Parallel()
+		if _, err := loadPromptTemplate(write(t, "{{.Description}}{{range .StubFiles}}{{.}}{{end}}{{with .Hint}}{{.}}{{end}}")); err != nil {
+			t.Fatalf("loadPromptTemplate() error = %v", err)
+		}
+	})
+
+	if tmpl, err := loadPromptTemplate(""); tmpl != nil || err != nil {
+		t.Fatalf("loadPromptTemplate(\"\") = %v, %v; want the built-in prompt", tmpl, err)
+	}
+}
+
 func TestDetectQuotaError(t *testing.T) {
 	t.Parallel()
 
@@ -996,17 +1132,88 @@ func TestDetectQuotaError(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			hasError, isRecoverable := detectQuotaError(tmpFile)
+			hasError, isRecoverable, pattern := detectQuotaError(tmpFile)
 			if hasError != tc.wantHasError {
 				t.Errorf("hasError = %v, want %v", hasError, tc.wantHasError)
 			}
 			if isRecoverable != tc.wantRecoverable {
 				t.Errorf("isRecoverable = %v, want %v", isRecoverable, tc.wantRecoverable)
 			}
+			if hasError != (pattern != "") {
+				t.Errorf("pattern = %q, want non-empty only when hasError", pattern)
+			}
+			if pattern != "" && !strings.Contains(strings.ToLower(tc.content), pattern) {
+				t.Errorf("pattern %q not found in log content", pattern)
+			}
 		})
 	}
 }
 
+func TestTrimPromptToBudget(t *testing.T) {
+	t.Parallel()
+
+	tt := &task.Task{
+		Slug:        "demo",
+		Name:        "Demo Task",
+		Language:    task.Go,
+		Tier:        "core",
+		Difficulty:  "hard",
+		Description: "Implement the thing.",
+		Files: task.TaskFiles{
+			Stub: []string{"demo.go.txt"},
+			Test: []string{"demo_test.go.txt"},
+		},
+	}
+	prompt := buildAgentPrompt(tt, false, false, "")
+	full := estimatePromptTokens(prompt)
+
+	if got, trimmed := trimPromptToBudget(prompt, 0); trimmed || got != prompt {
+		t.Fatal("budget 0 should disable trimming")
+	}
+	if got, trimmed := trimPromptToBudget(prompt, full); trimmed || got != prompt {
+		t.Fatal("prompt within budget should not be trimmed")
+	}
+
+	// A system prompt sent with the task prompt counts against the budget.
+	if _, trimmed := trimPromptToBudget(prompt, taskPromptBudget(full, "Be terse.")); !trimmed {
+		t.Fatal("system prompt should count toward the budget")
+	}
+	if got := taskPromptBudget(full, ""); got != full {
+		t.Fatalf("taskPromptBudget without a system prompt = %d, want %d", got, full)
+	}
+	if got := taskPromptBudget(2, strings.Repeat("x", 400)); got != 1 {
+		t.Fatalf("taskPromptBudget for an oversized system prompt = %d, want 1", got)
+	}
+
+	// Dropping ENVIRONMENT alone is enough for a budget just below the full size.
+	got, trimmed := trimPromptToBudget(prompt, full-1)
+	if !trimmed {
+		t.Fatal("expected prompt to be trimmed")
+	}
+	if strings.Contains(got, "ENVIRONMENT:") {
+		t.Errorf("ENVIRONMENT section should be dropped first, got:\n%s", got)
+	}
+	if !strings.Contains(got, "IMPORTANT:") || !strings.Contains(got, "YOUR TASK:") {
+		t.Errorf("later sections should survive the first stage, got:\n%s", got)
+	}
+
+	// An unreachable budget applies every stage but keeps the essentials.
+	got, trimmed = trimPromptToBudget(prompt, 1)
+	if !trimmed {
+		t.Fatal("expected prompt to be trimmed")
+	}
+	for _, gone := range []string{"ENVIRONMENT:", "IMPORTANT:", "YOUR TASK:", "Do NOT modify test files"} {
+		if strings.Contains(got, gone) {
+			t.Errorf("trimmed prompt still contains %q:\n%s", gone, got)
+		}
+	}
+	for _, kept := range []string{"Description: " + tt.Description, "Stub/solution files: demo.go", "RULES:\n- ONLY edit"} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("trimmed prompt missing %q:\n%s", kept, got)
+		}
+	}
+}
+
 func TestDetectAuthError(t *testing.T) {
 	t.Parallel()
 
@@ -1059,6 +1266,72 @@ func TestDetectAuthError(t *testing.T) {
 	}
 }
 
+func TestDetectRefusal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		content     string
+		wantRefused bool
+		wantPattern string
+	}{
+		{
+			name:        "plain refusal",
+			content:     "I cannot help with that request.",
+			wantRefused: true,
+			wantPattern: "i cannot help with that",
+		},
+		{
+			name:        "apologetic refusal with curly apostrophe",
+			content:     "I’m sorry, but I can’t assist with building this.",
+			wantRefused: true,
+			wantPattern: "i can't assist with",
+		},
+		{
+			name:        "decline",
+			content:     "Reading the task...\nI must decline to write this code.",
+			wantRefused: true,
+			wantPattern: "i must decline",
+		},
+		{
+			name:        "code comment is not a refusal",
+			content:     "// I cannot help with that unless the lock is held\nfunc (b *Bank) Withdraw() {}",
+			wantRefused: false,
+		},
+		{
+			name:        "hash comment is not a refusal",
+			content:     "# i can't help with that case, the parser rejects it",
+			wantRefused: false,
+		},
+		{
+			name:        "bare cannot is not a refusal",
+			content:     "The test cannot pass until Withdraw checks the balance. Fixing it now.",
+			wantRefused: false,
+		},
+		{
+			name:        "earlier attempt refusal is ignored",
+			content:     "I cannot help with that.\n=== RETRY 1 (quota) ===\nImplemented the solution.",
+			wantRefused: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpFile := filepath.Join(t.TempDir(), "agent.log")
+			if err := os.WriteFile(tmpFile, []byte(tc.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			refused, pattern := detectRefusal(tmpFile)
+			if refused != tc.wantRefused || pattern != tc.wantPattern {
+				t.Fatalf("detectRefusal() = %v, %q; want %v, %q", refused, pattern, tc.wantRefused, tc.wantPattern)
+			}
+		})
+	}
+}
+
 func TestIsValidationInfraError(t *testing.T) {
 	t.Parallel()
 
@@ -1082,6 +1355,16 @@ func TestIsValidationInfraError(t *testing.T) {
 			err:  errors.New("execution failed for task ':test'"),
 			want: false,
 		},
+		{
+			name: "container start phase",
+			err:  &runner.PhaseError{Phase: runner.PhaseStartContainer, Err: errors.New("OCI runtime create failed: exec format error")},
+			want: true,
+		},
+		{
+			name: "exec phase timeout is not infra",
+			err:  &runner.PhaseError{Phase: runner.PhaseExec, Err: errors.New("executing validation: exec timed out after 10m0s")},
+			want: false,
+		},
 		{
 			name: "nil error",
 			err:  nil,
@@ -1100,6 +1383,104 @@ func TestIsValidationInfraError(t *testing.T) {
 	}
 }
 
+func TestHandleValidationRunErrorRecordsPhase(t *testing.T) {
+	t.Parallel()
+
+	logPath := filepath.Join(t.TempDir(), "validation.log")
+	runErr := fmt.Errorf("run: %w", &runner.PhaseError{
+		Phase: runner.PhaseStartContainer,
+		Err:   errors.New("starting container: OCI runtime create failed"),
+	})
+
+	var result EvalResult
+	handleValidationRunError(&result, nil, runErr, logPath, []string{"go", "test"})
+
+	if result.FailurePhase != runner.PhaseStartContainer {
+		t.Fatalf("FailurePhase = %q, want %q", result.FailurePhase, runner.PhaseStartContainer)
+	}
+	if result.FailureClass != FailureClassInfra || !result.InfraFailure {
+		t.Fatalf("FailureClass = %q, InfraFailure = %v; want infra", result.FailureClass, result.InfraFailure)
+	}
+	if got := failurePhaseLabel(result.FailurePhase); got != "container-start" {
+		t.Fatalf("failurePhaseLabel() = %q, want container-start", got)
+	}
+}
+
+func TestClassifyAttemptTrustExitCode(t *testing.T) {
+	t.Parallel()
+
+	logPath := filepath.Join(t.TempDir(), "agent.log")
+	if err := os.WriteFile(logPath, []byte("Editing bank_account.go\nFailed to finish the task\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	attempt := agentAttemptResult{duration: 12, exitCode: 2}
+
+	classify := func(trust bool) (attemptDecision, agentExecutionResult) {
+		var quota, infra, timeouts int
+		var result agentExecutionResult
+		decision := classifyAttempt(attempt, trust, logPath, "", time.Time{}, &quota, &infra, &timeouts, &result)
+		return decision, result
+	}
+
+	decision, result := classify(false)
+	if !decision.done || result.failureClass != FailureClassNone {
+		t.Fatalf("untrusted exit code: decision=%+v class=%q, want done with no failure", decision, result.failureClass)
+	}
+
+	decision, result = classify(true)
+	if decision.done || decision.retryType != "infra" {
+		t.Fatalf("trusted exit code: decision=%+v, want infra retry", decision)
+	}
+	if result.retryReasons["infra: agent exit code 2"] != 1 {
+		t.Fatalf("retry reasons = %v, want agent exit code reason", result.retryReasons)
+	}
+
+	// Once retries run out the failure is scored, not an infra skip.
+	var quota, infra, timeouts int
+	result = agentExecutionResult{}
+	for {
+		decision = classifyAttempt(attempt, true, logPath, "", time.Time{}, &quota, &infra, &timeouts, &result)
+		if decision.done {
+			break
+		}
+	}
+	if result.failureClass != FailureClassAgentExit || result.infraFailure || result.exitCode != 2 {
+		t.Fatalf("exhausted retries: class=%q infraFailure=%v exitCode=%d, want a scored agent_exit failure", result.failureClass, result.infraFailure, result.exitCode)
+	}
+	if got, want := result.retryReasons["infra: agent exit code 2"], defaultInfraMaxRetries-1; got != want {
+		t.Fatalf("retry reasons = %v, want %d retries", result.retryReasons, want)
+	}
+	if isResumableExternalFailure(EvalResult{FailureClass: result.failureClass}) {
+		t.Fatal("agent_exit must be scored, not resumable")
+	}
+}
+
+func TestClassifyAttemptRetryReasonsOnlyForRetries(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name   string
+		log    string
+		reason string
+		want   int
+	}{
+		{name: "infra", log: "", reason: "infra: empty agent output", want: defaultInfraMaxRetries - 1},
+		{name: "quota", log: "Error: rate limit exceeded\n", reason: "quota: rate limit", want: defaultQuotaMaxRetries - 1},
+	} {
+		logPath := filepath.Join(t.TempDir(), "agent.log")
+		if err := os.WriteFile(logPath, []byte(tc.log), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		var quota, infra, timeouts int
+		var result agentExecutionResult
+		for !classifyAttempt(agentAttemptResult{}, false, logPath, "", time.Time{}, &quota, &infra, &timeouts, &result).done {
+		}
+		if got := result.retryReasons[tc.reason]; got != tc.want {
+			t.Errorf("%s: retry reasons = %v, want %d for the retries only", tc.name, result.retryReasons, tc.want)
+		}
+	}
+}
+
 func TestIsInfraFailure(t *testing.T) {
 	t.Parallel()
 
@@ -1219,7 +1600,7 @@ func TestBuildSandboxArgs(t *testing.T) {
 	t.Parallel()
 
 	workspaceDir := t.TempDir()
-	args := buildSandboxArgs(workspaceDir, "", nil, nil, nil, nil)
+	args := buildSandboxArgs(workspaceDir, "", true, nil, nil, nil, nil, nil)
 
 	// Verify required arguments are present.
 	assertContainsArg := func(flag, value string) {
@@ -1269,6 +1650,34 @@ func TestBuildSandboxArgs(t *testing.T) {
 	}
 }
 
+func TestBuildSandboxArgsNetwork(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		allowNetwork bool
+		want         string
+		notWant      string
+	}{
+		{name: "allowed", allowNetwork: true, want: "--share-net", notWant: "--unshare-net"},
+		{name: "denied", allowNetwork: false, want: "--unshare-net", notWant: "--share-net"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			args := buildSandboxArgs(t.TempDir(), "", tc.allowNetwork, nil, nil, nil, nil, nil)
+			if !slices.Contains(args, tc.want) {
+				t.Errorf("sandbox args missing %s: %v", tc.want, args)
+			}
+			if slices.Contains(args, tc.notWant) {
+				t.Errorf("sandbox args should not contain %s: %v", tc.notWant, args)
+			}
+		})
+	}
+}
+
 func TestWrapCommandWithSandbox(t *testing.T) {
 	t.Parallel()
 
@@ -1280,7 +1689,7 @@ func TestWrapCommandWithSandbox(t *testing.T) {
 		Args:    []string{"{prompt}"},
 	}
 
-	cmd := buildAgentCommand(ctx, agentCfg, "test prompt", "", "", false, false, "test")
+	cmd := buildAgentCommand(ctx, agentCfg, "test prompt", "", "", "", false, false, "test")
 	cmd.Dir = workspaceDir
 
 	wrapped := wrapCommandWithSandbox(ctx, cmd, nil, nil, nil, nil)
@@ -1322,6 +1731,42 @@ func TestWrapCommandWithSandbox(t *testing.T) {
 	}
 }
 
+func TestSandboxBinary(t *testing.T) {
+	t.Parallel()
+
+	if got := sandboxBinary(nil); got != "bwrap" {
+		t.Errorf("sandboxBinary(nil) = %q, want bwrap", got)
+	}
+	c := &config.Config{Sandbox: config.SandboxConfig{BwrapPath: "/opt/bwrap/bin/bwrap"}}
+	if got := sandboxBinary(c); got != "/opt/bwrap/bin/bwrap" {
+		t.Errorf("sandboxBinary() = %q, want /opt/bwrap/bin/bwrap", got)
+	}
+}
+
+func TestBuildSandboxArgsExtraArgsBeforeSeparator(t *testing.T) {
+	t.Parallel()
+
+	c := &config.Config{Sandbox: config.SandboxConfig{ExtraArgs: []string{"--bind", "/srv/models", "/srv/models"}}}
+	args := buildSandboxArgs(t.TempDir(), "", true, nil, nil, nil, nil, sandboxExtraArgs(c))
+	args = append(args, "--", "/bin/agent")
+
+	sep := slices.Index(args, "--")
+	extra := slices.Index(args, "/srv/models")
+	if extra < 0 {
+		t.Fatalf("sandbox args missing extra_args: %v", args)
+	}
+	if extra > sep {
+		t.Fatalf("extra_args at %d, want before -- at %d: %v", extra, sep, args)
+	}
+	if got := args[sep-3 : sep]; !slices.Equal(got, c.Sandbox.ExtraArgs) {
+		t.Fatalf("args before -- = %v, want %v", got, c.Sandbox.ExtraArgs)
+	}
+
+	if got := sandboxExtraArgs(nil); got != nil {
+		t.Fatalf("sandboxExtraArgs(nil) = %v, want nil", got)
+	}
+}
+
 func TestBuildSandboxArgsMasksDenylistedDirs(t *testing.T) {
 	t.Parallel()
 
@@ -1331,7 +1776,7 @@ func TestBuildSandboxArgsMasksDenylistedDirs(t *testing.T) {
 		t.Fatalf("mkdir deny dir: %v", err)
 	}
 
-	args := buildSandboxArgs(workspaceDir, "", nil, nil, nil, []string{denyDir, filepath.Join(t.TempDir(), "missing")})
+	args := buildSandboxArgs(workspaceDir, "", true, nil, nil, nil, []string{denyDir, filepath.Join(t.TempDir(), "missing")}, nil)
 
 	foundMask := false
 	for i, arg := range args {
@@ -1345,6 +1790,43 @@ func TestBuildSandboxArgsMasksDenylistedDirs(t *testing.T) {
 	}
 }
 
+func TestSandboxAudit(t *testing.T) {
+	t.Parallel()
+
+	workspaceDir := t.TempDir()
+	denyDir := filepath.Join(t.TempDir(), "tasks")
+	if err := os.MkdirAll(denyDir, 0o755); err != nil {
+		t.Fatalf("mkdir deny dir: %v", err)
+	}
+	missing := filepath.Join(t.TempDir(), "missing")
+	missingRO := filepath.Join(t.TempDir(), "missing-ro")
+	denylist := []string{denyDir, missing}
+
+	args := buildSandboxArgs(workspaceDir, "", true, nil, nil, []string{missingRO}, denylist, nil)
+	args = append(args, "--", "/bin/agent", "--tmpfs", "ignored")
+	path := filepath.Join(t.TempDir(), "sandbox.json")
+	writeSandboxAudit(path, args, nil, nil, []string{missingRO}, denylist)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read sandbox.json: %v", err)
+	}
+	var audit SandboxAudit
+	if err := json.Unmarshal(data, &audit); err != nil {
+		t.Fatalf("parse sandbox.json: %v", err)
+	}
+	if !slices.Contains(audit.Writable, workspaceDir) {
+		t.Fatalf("writable mounts %v missing workspace %s", audit.Writable, workspaceDir)
+	}
+	if !slices.Contains(audit.Masked, denyDir) || slices.Contains(audit.Masked, "ignored") || slices.Contains(audit.Masked, "/tmp") {
+		t.Fatalf("unexpected masked paths %v", audit.Masked)
+	}
+	wantSkipped := []SandboxSkippedPath{{Path: missingRO, Kind: "readonly"}, {Path: missing, Kind: "denylist"}}
+	if !slices.Equal(audit.Skipped, wantSkipped) {
+		t.Fatalf("skipped = %v, want %v", audit.Skipped, wantSkipped)
+	}
+}
+
 func TestBuildSandboxArgsMasksNonAllowlistedHomeDirs(t *testing.T) {
 	homeDir := t.TempDir()
 	t.Setenv("HOME", homeDir)
@@ -1365,10 +1847,12 @@ func TestBuildSandboxArgsMasksNonAllowlistedHomeDirs(t *testing.T) {
 	args := buildSandboxArgs(
 		workspaceDir,
 		"",
+		true,
 		nil,
 		[]string{".config", ".factory"},
 		nil,
 		nil,
+		nil,
 	)
 
 	assertHasMount := func(flag, value string) bool {
@@ -1412,7 +1896,7 @@ func TestBuildSandboxArgsAllowsAgentCommandDir(t *testing.T) {
 	}
 
 	workspaceDir := t.TempDir()
-	args := buildSandboxArgs(workspaceDir, commandPath, nil, nil, nil, nil)
+	args := buildSandboxArgs(workspaceDir, commandPath, true, nil, nil, nil, nil, nil)
 
 	hasReadOnlyBind := false
 	hasBinMask := false
@@ -1432,6 +1916,28 @@ func TestBuildSandboxArgsAllowsAgentCommandDir(t *testing.T) {
 	}
 }
 
+func TestResolveSandboxMountPathsExpandsEnv(t *testing.T) {
+	homeDir := t.TempDir()
+	cargoHome := t.TempDir()
+	t.Setenv("SANITY_TEST_CARGO_HOME", cargoHome)
+	t.Setenv("SANITY_TEST_UNSET", "")
+
+	got := resolveSandboxMountPaths(homeDir, []string{
+		"${SANITY_TEST_CARGO_HOME}/bin",
+		"$SANITY_TEST_CARGO_HOME",
+		"${SANITY_TEST_UNSET}/bin",
+		".cache",
+	})
+	want := []string{
+		filepath.Join(canonicalizeExistingPath(cargoHome), "bin"),
+		canonicalizeExistingPath(cargoHome),
+		filepath.Join(canonicalizeExistingPath(homeDir), ".cache"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("resolveSandboxMountPaths() = %v, want %v", got, want)
+	}
+}
+
 func TestResolveSandboxDenylistPaths(t *testing.T) {
 	origDir, _ := os.Getwd()
 	repoRoot := t.TempDir()
@@ -1547,7 +2053,7 @@ func TestParseAgentBehaviorMetrics(t *testing.T) {
 		t.Fatalf("write log: %v", err)
 	}
 
-	metrics := parseAgentBehaviorMetrics(logPath, workspaceDir)
+	metrics := parseAgentBehaviorMetrics(logPath, workspaceDir, "", nil, nil)
 	if metrics.SelfTestCommands != 2 {
 		t.Fatalf("self test commands = %d, want 2", metrics.SelfTestCommands)
 	}
@@ -1571,6 +2077,197 @@ func TestParseAgentBehaviorMetrics(t *testing.T) {
 	}
 }
 
+func TestConfirmDangerousAgents(t *testing.T) {
+	t.Parallel()
+
+	agents := []string{"claude (--dangerously-skip-permissions)"}
+	if err := confirmDangerousAgents(nil, strings.NewReader(""), io.Discard, false); err != nil {
+		t.Fatalf("no agents: error = %v, want nil", err)
+	}
+
+	var out strings.Builder
+	if err := confirmDangerousAgents(agents, strings.NewReader("yes\n"), &out, true); err != nil {
+		t.Fatalf("answer yes: error = %v, want nil", err)
+	}
+	if !strings.Contains(out.String(), agents[0]) {
+		t.Fatalf("prompt %q does not name the agent", out.String())
+	}
+	if err := confirmDangerousAgents(agents, strings.NewReader("\n"), io.Discard, true); !errors.Is(err, errDangerousAgentsDeclined) {
+		t.Fatalf("empty answer: error = %v, want errDangerousAgentsDeclined", err)
+	}
+	out.Reset()
+	if err := confirmDangerousAgents(agents, strings.NewReader(""), &out, false); err != nil {
+		t.Fatalf("non-interactive: error = %v, want nil", err)
+	}
+	if !strings.Contains(out.String(), "Warning") || !strings.Contains(out.String(), agents[0]) {
+		t.Fatalf("non-interactive output %q, want a warning naming the agent", out.String())
+	}
+}
+
+func TestDangerousFlag(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"claude", "-p", "--dangerously-skip-permissions", "x"}, "--dangerously-skip-permissions"},
+		{[]string{"sh", "-c", "my-agent --yolo --model 'm' {prompt}"}, "--yolo"},
+		{[]string{"agent", "--auto=true"}, "--auto"},
+		{[]string{"agent", "--automatic", "--model", "m"}, ""},
+	} {
+		if got := dangerousFlag(tc.args); got != tc.want {
+			t.Errorf("dangerousFlag(%q) = %q, want %q", tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestAgentVersion(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	got, err := agentVersion(context.Background(), &config.AgentConfig{
+		VersionCommand: []string{"sh", "-c", "printf '  agent 1.2.3\\nbuild abc\\n'"},
+	}, "my-agent")
+	if err != nil || got != "agent 1.2.3" {
+		t.Fatalf("agentVersion() = %q, %v; want %q", got, err, "agent 1.2.3")
+	}
+
+	if _, err := agentVersion(context.Background(), &config.AgentConfig{
+		VersionCommand: []string{"sh", "-c", "exit 3"},
+	}, "my-agent"); err == nil {
+		t.Fatal("expected error from failing version command")
+	}
+
+	cmd := agentVersionCommand(&config.AgentConfig{Command: "codex"})
+	if strings.Join(cmd, " ") != "codex --version" {
+		t.Fatalf("default version command = %v, want codex --version", cmd)
+	}
+}
+
+func TestParseAgentBehaviorMetricsEscapeAttempts(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	runDir := filepath.Join(tmpDir, "out", "run-1")
+	tests := []struct {
+		name  string
+		lines []string
+		want  int
+	}{
+		{
+			name: "writes into harness dirs",
+			lines: []string{
+				"$ echo '{\"passed\":true}' > ../eval-results/run-1/summary.json",
+				"$ cp solution.go /home/user/sessions/old/",
+				"$ cat /home/user/eval-results/run-1/report.md",
+				"$ cd ../src && go test ./...",
+			},
+			want: 2,
+		},
+		{
+			name: "copies out of harness dirs",
+			lines: []string{
+				"$ cp eval-results/x .",
+				"$ rsync -a ../eval-results/run-0/ ./prev/",
+			},
+			want: 2,
+		},
+		{
+			name: "writes into run dir",
+			lines: []string{
+				"$ echo pass > " + filepath.ToSlash(runDir) + "/summary.json",
+				"$ ls " + filepath.ToSlash(runDir),
+			},
+			want: 1,
+		},
+		{
+			name: "parent traversal is not an escape",
+			lines: []string{
+				"$ cd ../.. && ls",
+				"panic: boom",
+				"    at main (../../src/main.ts:12:3)",
+				"#include \"../../include/util.h\"",
+				"$ cp ../../fixtures/input.txt .",
+				"$ mkdir build-sessions/",
+			},
+			want: 0,
+		},
+	}
+	for i, tt := range tests {
+		logPath := filepath.Join(tmpDir, fmt.Sprintf("agent-%d.log", i))
+		if err := os.WriteFile(logPath, []byte(strings.Join(tt.lines, "\n")), 0o644); err != nil {
+			t.Fatalf("write log: %v", err)
+		}
+		metrics := parseAgentBehaviorMetrics(logPath, filepath.Join(tmpDir, "workspace"), runDir, nil, nil)
+		if metrics.EscapeAttempts != tt.want {
+			t.Errorf("%s: escape attempts = %d, want %d", tt.name, metrics.EscapeAttempts, tt.want)
+		}
+	}
+}
+
+func TestParseAgentBehaviorMetricsTimeline(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "agent.log")
+	content := strings.Join([]string{
+		"starting up",
+		"[2026-01-07T12:00:00Z] reading the task",
+		"[2026-01-07T12:00:30Z] running",
+		"$ go test ./...",
+		"ok  example 0.2s",
+		"[2026-01-07T12:00:40Z] tests pass, editing",
+		"[2026-01-07T12:00:45Z] done",
+	}, "\n")
+	if err := os.WriteFile(logPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+
+	format := newAgentTimestampFormat(&config.AgentConfig{TimestampPattern: `^\[([^\]]+)\]`})
+	metrics := parseAgentBehaviorMetrics(logPath, filepath.Join(tmpDir, "workspace"), "", format, nil)
+	if metrics.ThinkingTime != 35 || metrics.ActingTime != 10 {
+		t.Fatalf("thinking/acting = %v/%v, want 35/10", metrics.ThinkingTime, metrics.ActingTime)
+	}
+
+	unix := newAgentTimestampFormat(&config.AgentConfig{TimestampPattern: `"ts":([0-9.]+)`, TimestampLayout: "unix"})
+	thinking, acting := segmentAgentTimeline([]string{`{"ts":100.5}`, `{"ts":102}`}, unix)
+	if thinking != 1500*time.Millisecond || acting != 0 {
+		t.Fatalf("unix thinking/acting = %v/%v, want 1.5s/0", thinking, acting)
+	}
+
+	if metrics := parseAgentBehaviorMetrics(logPath, filepath.Join(tmpDir, "workspace"), "", nil, nil); metrics.ThinkingTime != 0 || metrics.ActingTime != 0 {
+		t.Fatalf("without a format thinking/acting = %v/%v, want 0/0", metrics.ThinkingTime, metrics.ActingTime)
+	}
+}
+
+func TestParseAgentBehaviorMetricsTokens(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "agent.log")
+	content := strings.Join([]string{
+		`{"usage":{"input_tokens": 800,"output_tokens": 200}}`,
+		"editing main.go",
+		"tokens used: 1,500",
+	}, "\n")
+	if err := os.WriteFile(logPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+
+	metrics := parseAgentBehaviorMetrics(logPath, filepath.Join(tmpDir, "workspace"), "", nil, agentTokenPatterns(nil))
+	if metrics.TotalTokens != 2500 || !metrics.TotalTokensConfident {
+		t.Fatalf("tokens = %d (confident %v), want 2500 confident", metrics.TotalTokens, metrics.TotalTokensConfident)
+	}
+
+	custom := agentTokenPatterns(&config.AgentConfig{TokenPatterns: []string{`cost: \d+ credits, (\d+) tok`}})
+	if metrics := parseAgentBehaviorMetrics(logPath, filepath.Join(tmpDir, "workspace"), "", nil, custom); metrics.TotalTokens != 0 || metrics.TotalTokensConfident {
+		t.Fatalf("unmatched custom pattern tokens = %d (confident %v), want 0 not confident", metrics.TotalTokens, metrics.TotalTokensConfident)
+	}
+}
+
 func TestParseAgentBehaviorMetricsFallbackConfidence(t *testing.T) {
 	t.Parallel()
 
@@ -1584,7 +2281,7 @@ func TestParseAgentBehaviorMetricsFallbackConfidence(t *testing.T) {
 		t.Fatalf("write log: %v", err)
 	}
 
-	metrics := parseAgentBehaviorMetrics(logPath, filepath.Join(tmpDir, "workspace"))
+	metrics := parseAgentBehaviorMetrics(logPath, filepath.Join(tmpDir, "workspace"), "", nil, nil)
 	if metrics.OutOfWorkspaceReads == 0 {
 		t.Fatal("out-of-workspace reads = 0, want > 0 from fallback matcher")
 	}
@@ -1615,11 +2312,194 @@ func TestParseAgentBehaviorMetricsSkillSignalsFromStructuredLines(t *testing.T)
 		t.Fatalf("write log: %v", err)
 	}
 
-	metrics := parseAgentBehaviorMetrics(logPath, filepath.Join(tmpDir, "workspace"))
+	metrics := parseAgentBehaviorMetrics(logPath, filepath.Join(tmpDir, "workspace"), "", nil, nil)
 	if !metrics.SkillsUsed {
 		t.Fatal("skills_used = false, want true")
 	}
 	if metrics.SkillsUsageSignals < 4 {
 		t.Fatalf("skills_usage_signals = %d, want >= 4", metrics.SkillsUsageSignals)
 	}
+	if got := strings.Join(metrics.SkillNames, ","); got != "firecrawl,skill-installer" {
+		t.Fatalf("skill names = %q, want %q", got, "firecrawl,skill-installer")
+	}
+}
+
+func TestApplyAgentExecutionResultRelevantSkill(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "agent.log")
+	if err := os.WriteFile(logPath, []byte("→ Skill \"Firecrawl\"\n"), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+
+	var used EvalResult
+	applyAgentExecutionResult(&used, agentExecutionResult{}, logPath, tmpDir, "firecrawl", nil, nil)
+	if used.RelevantSkill != "firecrawl" || !used.RelevantSkillUsed {
+		t.Fatalf("relevant skill = %q used=%t, want firecrawl used", used.RelevantSkill, used.RelevantSkillUsed)
+	}
+
+	var other EvalResult
+	applyAgentExecutionResult(&other, agentExecutionResult{}, logPath, tmpDir, "context7", nil, nil)
+	if !other.SkillsUsed || other.RelevantSkillUsed {
+		t.Fatalf("skills used=%t relevant used=%t, want any skill but not the relevant one", other.SkillsUsed, other.RelevantSkillUsed)
+	}
+}
+
+func TestApplyAgentExecutionResultLogSize(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "agent.log")
+	if err := os.WriteFile(logPath, make([]byte, 1234), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+
+	var truncated EvalResult
+	applyAgentExecutionResult(&truncated, agentExecutionResult{logTruncated: true}, logPath, tmpDir, "", nil, nil)
+	if truncated.AgentLogBytes != 1234 || !truncated.AgentLogTruncated {
+		t.Fatalf("agent log bytes=%d truncated=%t, want 1234 and true", truncated.AgentLogBytes, truncated.AgentLogTruncated)
+	}
+
+	var missing EvalResult
+	applyAgentExecutionResult(&missing, agentExecutionResult{}, filepath.Join(tmpDir, "missing.log"), tmpDir, "", nil, nil)
+	if missing.AgentLogBytes != 0 || missing.AgentLogTruncated {
+		t.Fatalf("missing log bytes=%d truncated=%t, want 0 and false", missing.AgentLogBytes, missing.AgentLogTruncated)
+	}
+}
+
+func TestResolveAgentLogMax(t *testing.T) {
+	t.Parallel()
+
+	withMB := func(mb int) *config.Config {
+		cfg := config.Default
+		cfg.Harness.MaxAgentLogMB = mb
+		return &cfg
+	}
+	tests := []struct {
+		name      string
+		flagSet   bool
+		flagValue int64
+		cfg       *config.Config
+		want      int64
+	}{
+		{"no config", false, defaultAgentLogMaxBytes, nil, defaultAgentLogMaxBytes},
+		{"config unset", false, defaultAgentLogMaxBytes, withMB(0), defaultAgentLogMaxBytes},
+		{"config applies", false, defaultAgentLogMaxBytes, withMB(8), 8 << 20},
+		{"flag wins", true, 1000, withMB(8), 1000},
+		{"flag zero wins", true, 0, withMB(8), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := resolveAgentLogMax(tt.flagSet, tt.flagValue, tt.cfg); got != tt.want {
+				t.Fatalf("resolveAgentLogMax() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEnvFile(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`# provider credentials
+OPENAI_API_KEY=sk-test-1234567890
+export OPENAI_BASE_URL = https://llm.example.com/v1 # self-hosted
+QUOTED="two\nlines"
+SINGLE='$NOT_EXPANDED'
+
+EMPTY=
+`)
+	got, err := parseEnvFile(data)
+	if err != nil {
+		t.Fatalf("parseEnvFile() error = %v", err)
+	}
+	want := map[string]string{
+		"OPENAI_API_KEY":  "sk-test-1234567890",
+		"OPENAI_BASE_URL": "https://llm.example.com/v1",
+		"QUOTED":          "two\nlines",
+		"SINGLE":          "$NOT_EXPANDED",
+		"EMPTY":           "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseEnvFile() = %v, want %v", got, want)
+	}
+
+	if _, err := parseEnvFile([]byte("OK=1\nnot a pair\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("parseEnvFile() error = %v, want a line 2 error", err)
+	}
+}
+
+func TestBuildAgentEnvPrecedence(t *testing.T) {
+	t.Parallel()
+
+	cmd := exec.Command("true")
+	cmd.Env = buildAgentEnv(
+		map[string]string{"SANITY_TEST_KEY": "from-agent"},
+		map[string]string{"SANITY_TEST_KEY": "from-file", "SANITY_TEST_FILE_ONLY": "file"},
+		false, false, "codex",
+	)
+	// exec keeps the last value of a duplicated key.
+	env := cmd.Environ()
+	lookup := func(key string) string {
+		value := ""
+		for _, kv := range env {
+			if k, v, _ := strings.Cut(kv, "="); k == key {
+				value = v
+			}
+		}
+		return value
+	}
+	if got := lookup("SANITY_TEST_KEY"); got != "from-agent" {
+		t.Fatalf("SANITY_TEST_KEY = %q, want agent env to win", got)
+	}
+	if got := lookup("SANITY_TEST_FILE_ONLY"); got != "file" {
+		t.Fatalf("SANITY_TEST_FILE_ONLY = %q, want the env file value", got)
+	}
+}
+
+func TestRedactEnvValues(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "agent.log")
+	log := "using key sk-test-1234567890 with region=us\n"
+	if err := os.WriteFile(path, []byte(log), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	redactEnvValues(path, map[string]string{"OPENAI_API_KEY": "sk-test-1234567890", "REGION": "us"})
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "using key [REDACTED:OPENAI_API_KEY] with region=us\n"; got != want {
+		t.Fatalf("redacted log = %q, want %q", got, want)
+	}
+}
+
+func TestBuildAgentCommandShellCommand(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	agentCfg := &config.AgentConfig{
+		ShellCommand: `printf '%s|%s|%s' {prompt} {model} "$(echo {reasoning})"`,
+		PromptPrefix: "ulw",
+	}
+	prompt := `fix it; don't "rm -rf" $HOME`
+	cmd := buildAgentCommand(context.Background(), agentCfg, prompt, "vendor/model", "high", "", false, false, "custom")
+	if len(cmd.Args) != 3 || cmd.Args[0] != "sh" || cmd.Args[1] != "-c" {
+		t.Fatalf("cmd.Args = %q, want sh -c <command>", cmd.Args)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("running shell command: %v", err)
+	}
+	if got, want := string(out), "ulw "+prompt+"|vendor/model|high"; got != want {
+		t.Fatalf("output = %q, want %q", got, want)
+	}
+
+	if got, want := shellCommandProvenance(agentCfg, "vendor/model", "high"), `printf '%s|%s|%s' {prompt} 'vendor/model' "$(echo 'high')"`; got != want {
+		t.Fatalf("shellCommandProvenance() = %q, want %q", got, want)
+	}
 }
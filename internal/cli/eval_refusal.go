@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"os"
+	"strings"
+)
+
+// defaultRefusalPatterns are lowercase phrases a model uses when it declines
+// a task outright. [harness] refusal_patterns replaces them.
+var defaultRefusalPatterns = []string{
+	"i cannot help with that",
+	"i can't help with that",
+	"i cannot assist with",
+	"i can't assist with",
+	"i'm not able to help with",
+	"i am unable to help with",
+	"i won't be able to help with",
+	"i'm sorry, but i can't",
+	"i'm sorry, but i cannot",
+	"i must decline",
+}
+
+// commentPrefixes mark log lines that echo source code comments rather than
+// the model's own reply, so a "cannot" in a code comment is not a refusal.
+var commentPrefixes = []string{"//", "/*", "*", "#", "--", ";"}
+
+// refusalPatterns returns the configured refusal phrases, lowercased, or
+// the defaults.
+func refusalPatterns() []string {
+	if cfg == nil || len(cfg.Harness.RefusalPatterns) == 0 {
+		return defaultRefusalPatterns
+	}
+	patterns := make([]string, 0, len(cfg.Harness.RefusalPatterns))
+	for _, p := range cfg.Harness.RefusalPatterns {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// detectRefusal checks if the latest attempt in the agent log is the model
+// refusing the task, returning the matched phrase. Lines that look like code
+// comments are ignored.
+func detectRefusal(logPath string) (refused bool, pattern string) {
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		return false, ""
+	}
+	patterns := refusalPatterns()
+	lower := strings.ToLower(string(lastAttemptContent(content)))
+	lower = strings.ReplaceAll(lower, "’", "'")
+	for _, line := range strings.Split(lower, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if isCommentLine(trimmed) {
+			continue
+		}
+		for _, p := range patterns {
+			if strings.Contains(trimmed, p) {
+				return true, p
+			}
+		}
+	}
+	return false, ""
+}
+
+func isCommentLine(line string) bool {
+	for _, prefix := range commentPrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,14 +1,53 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	resultpkg "github.com/lemon07r/sanityharness/internal/result"
 	"github.com/lemon07r/sanityharness/internal/task"
 )
 
+func TestSampleTasks(t *testing.T) {
+	t.Parallel()
+
+	var tasks []*task.Task
+	for i := range 20 {
+		tasks = append(tasks, &task.Task{Slug: fmt.Sprintf("task-%02d", i), Language: task.Go})
+	}
+
+	for _, uniform := range []bool{false, true} {
+		first := sampleTasks(tasks, 5, uniform, 42)
+		second := sampleTasks(tasks, 5, uniform, 42)
+		if len(first) != 5 {
+			t.Fatalf("uniform=%t: sampled %d tasks, want 5", uniform, len(first))
+		}
+		for i := range first {
+			if first[i] != second[i] {
+				t.Fatalf("uniform=%t: same seed gave different samples", uniform)
+			}
+			if i > 0 && first[i-1].Slug >= first[i].Slug {
+				t.Fatalf("uniform=%t: sample not in input order: %s before %s", uniform, first[i-1].Slug, first[i].Slug)
+			}
+		}
+	}
+
+	if got := sampleTasks(tasks, 25, false, 1); len(got) != len(tasks) {
+		t.Fatalf("oversized sample returned %d tasks, want all %d", len(got), len(tasks))
+	}
+}
+
 func TestFinalizeEvalResult(t *testing.T) {
 	t.Parallel()
 
@@ -202,3 +241,881 @@ func TestResolveAgentTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestLanguageTimeoutPrecedence(t *testing.T) {
+	t.Parallel()
+
+	overrides := map[string]int{"rust": 900, "go": 0}
+	tests := []struct {
+		name           string
+		lang           task.Language
+		explicit       bool
+		agentTask      int
+		validationTask int
+		wantAgent      int
+		wantValidation int
+	}{
+		{name: "global_default", lang: task.TypeScript, wantAgent: 300, wantValidation: 300},
+		{name: "zero_override_keeps_global", lang: task.Go, wantAgent: 300, wantValidation: 300},
+		{name: "language_replaces_global", lang: task.Rust, wantAgent: 900, wantValidation: 900},
+		{name: "task_beats_language", lang: task.Rust, agentTask: 1200, validationTask: 600, wantAgent: 1200, wantValidation: 600},
+		{name: "shorter_task_does_not_reduce_language", lang: task.Rust, agentTask: 600, validationTask: 300, wantAgent: 900, wantValidation: 300},
+		{name: "language_beats_shorter_explicit_global", lang: task.Rust, explicit: true, wantAgent: 900, wantValidation: 900},
+		{name: "task_beats_global", lang: task.TypeScript, agentTask: 400, validationTask: 200, wantAgent: 400, wantValidation: 200},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			timeout := languageTimeout(300, tc.explicit, overrides, tc.lang)
+			if got := resolveAgentTimeout(timeout, 0, tc.agentTask); got != time.Duration(tc.wantAgent)*time.Second {
+				t.Fatalf("agent timeout = %v, want %ds", got, tc.wantAgent)
+			}
+			if got := resolveValidationTimeout(timeout, tc.validationTask); got != tc.wantValidation {
+				t.Fatalf("validation timeout = %d, want %d", got, tc.wantValidation)
+			}
+		})
+	}
+
+	if got := languageTimeout(1200, true, overrides, task.Rust); got != 1200 {
+		t.Fatalf("languageTimeout with explicit 1200s = %d, want the longer --timeout kept", got)
+	}
+	if got := languageTimeout(1200, false, overrides, task.Rust); got != 900 {
+		t.Fatalf("languageTimeout with default 1200s = %d, want the language entry", got)
+	}
+	if got := resolveValidationTimeout(60, 0); got != 120 {
+		t.Fatalf("resolveValidationTimeout(60, 0) = %d, want the 120s floor", got)
+	}
+}
+
+func TestBuildValidationCommandsExpandsVariants(t *testing.T) {
+	t.Parallel()
+
+	tk := &task.Task{
+		Slug:       "merge-sort",
+		Language:   task.Go,
+		Validation: task.Validation{Command: "go", Args: []string{"test", "./..."}},
+		Variants: []task.Variant{
+			{Name: "small", Params: map[string]string{"N": "1000", "MODE": "check"}},
+			{Name: "large"},
+		},
+	}
+
+	validationCmd, effective, variants := buildValidationCommands(tk)
+	if validationCmd != nil {
+		t.Fatalf("validationCmd = %v, want nil", validationCmd)
+	}
+	if got := strings.Join(effective, " "); got != "go test ./..." {
+		t.Fatalf("effective = %q", got)
+	}
+	if len(variants) != 2 {
+		t.Fatalf("len(variants) = %d, want 2", len(variants))
+	}
+	if got := strings.Join(variants[0].command, " "); got != "env MODE=check N=1000 go test ./..." {
+		t.Fatalf("variant small command = %q", got)
+	}
+	if got := strings.Join(variants[1].command, " "); got != "env go test ./..." {
+		t.Fatalf("variant large command = %q", got)
+	}
+
+	assertionOnly := &task.Task{
+		Slug:           "config-gen",
+		Language:       task.Go,
+		JSONAssertions: []task.JSONAssertion{{File: "out.json", Path: "ok", Equals: true}},
+		Variants:       []task.Variant{{Name: "small", Params: map[string]string{"N": "1"}}},
+	}
+	if _, effective, variants := buildValidationCommands(assertionOnly); len(effective) != 0 || len(variants) != 0 {
+		t.Fatalf("assertion-only task: effective = %v, variants = %v, want neither", effective, variants)
+	}
+}
+
+func TestIsEvalOutputFile(t *testing.T) {
+	t.Parallel()
+
+	for name, want := range map[string]bool{
+		"agent.log":            true,
+		"validation.log":       true,
+		"validation-small.log": true,
+		"integrity-diff":       true,
+		"main.go":              false,
+		"validation.go":        false,
+	} {
+		if got := isEvalOutputFile(name); got != want {
+			t.Errorf("isEvalOutputFile(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestCleanupWorkspaceFilesKeepsArtifacts(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"agent.log", "sandbox.json", "main.go"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cleanupWorkspaceFiles(dir)
+	for name, kept := range map[string]bool{"agent.log": true, "sandbox.json": true, "main.go": false} {
+		if _, err := os.Stat(filepath.Join(dir, name)); (err == nil) != kept {
+			t.Errorf("%s kept = %v, want %v", name, err == nil, kept)
+		}
+	}
+}
+
+func TestBuildDryRunPlanJSON(t *testing.T) {
+	t.Parallel()
+
+	suite := []*task.Task{
+		{Slug: "bank-account", Language: task.Go, Tier: "core", Difficulty: "hard"},
+		{Slug: "slow-task", Language: task.Rust, Tier: "extended", AgentTimeout: 900},
+	}
+	plan := buildDryRunPlan([]RunSpec{{Agent: "codex"}}, suite, 600, 1)
+
+	var sb strings.Builder
+	if err := writeDryRunPlanJSON(&sb, plan); err != nil {
+		t.Fatalf("writeDryRunPlanJSON() error = %v", err)
+	}
+	var got DryRunPlan
+	if err := json.Unmarshal([]byte(sb.String()), &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v\n%s", err, sb.String())
+	}
+	if len(got.Runs) != 1 || got.Runs[0].Agent != "codex" || len(got.Tasks) != 2 {
+		t.Fatalf("plan = %+v", got)
+	}
+	first, second := got.Tasks[0], got.Tasks[1]
+	if first.ID != "go/bank-account" || first.Language != "go" || first.Tier != "core" || first.Difficulty != "hard" {
+		t.Errorf("first task = %+v", first)
+	}
+	if first.TimeoutSeconds != 600 || second.TimeoutSeconds != 900 {
+		t.Errorf("timeouts = %d, %d, want 600, 900", first.TimeoutSeconds, second.TimeoutSeconds)
+	}
+	if want := task.ComputeWeight(suite[0]).Base; math.Abs(first.Weight-want) > 1e-9 {
+		t.Errorf("weight = %.4f, want %.4f", first.Weight, want)
+	}
+
+	optimistic, worst := estimateDryRunSeconds(plan, 1, 1)
+	if optimistic != 1500 {
+		t.Errorf("optimistic estimate = %d, want 1500 (sum of timeouts)", optimistic)
+	}
+	// Defaults: five attempts each, so the quota delays 30+60+120+240 and
+	// infra 15+30+60+120 before the four retries per task, plus the maximum
+	// 20% jitter.
+	if want := 1500 + 2*810; worst != want {
+		t.Errorf("worst-case estimate = %d, want %d", worst, want)
+	}
+	if optimistic, _ := estimateDryRunSeconds(plan, 4, 1); optimistic != 900 {
+		t.Errorf("optimistic estimate with --parallel 4 = %d, want the longest task's 900", optimistic)
+	}
+	plan.Runs = append(plan.Runs, RunSpec{Agent: "claude"})
+	plan.Repeat = 2
+	if optimistic, _ := estimateDryRunSeconds(plan, 1, 2); optimistic != 3000 {
+		t.Errorf("optimistic estimate for 4 runs, 2 at once = %d, want 3000", optimistic)
+	}
+
+	if err := validateOutputFormat("json"); err != nil {
+		t.Errorf("validateOutputFormat(json) error = %v", err)
+	}
+	if err := validateOutputFormat("yaml"); err == nil {
+		t.Error("validateOutputFormat(yaml) error = nil")
+	}
+}
+
+func TestFullSuiteMaxScore(t *testing.T) {
+	t.Parallel()
+
+	suite := []*task.Task{
+		{Slug: "bank-account", Language: task.Go},
+		{Slug: "not-a-known-task", Language: task.Rust},
+	}
+	want := task.ComputeWeight(suite[0]).Base + 1.0
+	if got := fullSuiteMaxScore(suite); math.Abs(got-want) > 1e-9 {
+		t.Fatalf("fullSuiteMaxScore() = %.4f, want %.4f", got, want)
+	}
+	if got := fullSuiteMaxScore(nil); got != 0 {
+		t.Fatalf("fullSuiteMaxScore(nil) = %.4f, want 0", got)
+	}
+}
+
+func TestFilterUnsubmitted(t *testing.T) {
+	t.Parallel()
+
+	tasks := []*task.Task{
+		{Slug: "bank-account", Language: task.Go},
+		{Slug: "bank-account", Language: task.Rust},
+		{Slug: "react", Language: task.TypeScript},
+	}
+	submitted := []SubmittedEntry{
+		{Agent: "codex", Model: "gpt-5", Task: "go/bank-account"},
+		{Agent: "codex", Model: "gpt-5", Task: "react"},
+		{Agent: "codex", Model: "gpt-5", Task: "bank-account"}, // ambiguous, ignored
+		{Agent: "codex", Model: "o3", Task: "rust/bank-account"},
+		{Agent: "claude", Model: "gpt-5", Task: "rust/bank-account"},
+	}
+
+	got := filterUnsubmitted(tasks, submitted, RunSpec{Agent: "codex", Model: "gpt-5"})
+	if len(got) != 1 || got[0].ID() != "rust/bank-account" {
+		ids := make([]string, len(got))
+		for i, tk := range got {
+			ids[i] = tk.ID()
+		}
+		t.Fatalf("filterUnsubmitted() = %v, want [rust/bank-account]", ids)
+	}
+}
+
+func TestParseAgentFallback(t *testing.T) {
+	t.Parallel()
+
+	got := parseAgentFallback(" opencode:openrouter/kimi-k2:free, claude ,,")
+	want := []RunSpec{
+		{Agent: "opencode", Model: "openrouter/kimi-k2:free"},
+		{Agent: "claude"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseAgentFallback() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("parseAgentFallback()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if labels := fallbackChainLabels(""); labels != nil {
+		t.Fatalf("fallbackChainLabels(\"\") = %v, want nil", labels)
+	}
+}
+
+func TestIsAgentInfraFailure(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		result EvalResult
+		want   bool
+	}{
+		{name: "agent infra", result: EvalResult{FailureClass: FailureClassInfra}, want: true},
+		{name: "validation infra", result: EvalResult{FailureClass: FailureClassInfra, ValidateTime: 1.5}, want: false},
+		{name: "quota", result: EvalResult{FailureClass: FailureClassQuotaExhausted}, want: false},
+		{name: "passed", result: EvalResult{FailureClass: FailureClassNone, Passed: true, Attempts: 1}, want: false},
+	}
+	for _, tt := range tests {
+		if got := isAgentInfraFailure(tt.result); got != tt.want {
+			t.Errorf("%s: isAgentInfraFailure() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCountLintWarnings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		lang   task.Language
+		output string
+		want   int
+	}{
+		{
+			name:   "go vet",
+			lang:   task.Go,
+			output: "# example.com/bank\n./bank.go:12:2: unreachable code\n./bank.go:30:9: printf: fmt.Sprintf format %d has arg of wrong type\n",
+			want:   2,
+		},
+		{
+			name:   "clippy short format ignores summary lines",
+			lang:   task.Rust,
+			output: "src/lib.rs:10:5: warning: redundant clone\nwarning: `buffer` (lib) generated 1 warning\n",
+			want:   1,
+		},
+		{
+			name:   "eslint unix format",
+			lang:   task.TypeScript,
+			output: "/workspace/csv.ts:3:7: 'x' is assigned a value but never used. [Warning/no-unused-vars]\n\n1 problem\n",
+			want:   1,
+		},
+		{
+			name:   "dart analyze machine format",
+			lang:   task.Dart,
+			output: "INFO|LINT|prefer_const|/workspace/lib/pool.dart|4|3|5|Prefer const\nWARNING|STATIC_WARNING|unused|/workspace/lib/pool.dart|9|1|2|Unused\n",
+			want:   2,
+		},
+		{
+			name:   "language without linter",
+			lang:   task.Zig,
+			output: "src/main.zig:1:1: error: whatever\n",
+			want:   0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := countLintWarnings(tc.lang, tc.output); got != tc.want {
+				t.Fatalf("countLintWarnings() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWriteReportLint(t *testing.T) {
+	t.Parallel()
+
+	summary := EvalSummary{
+		Lint:              true,
+		LintedTasks:       2,
+		TotalLintWarnings: 3,
+		Results: []EvalResult{
+			{Task: "go/bank-account", Linted: true, LintWarnings: 3},
+			{Task: "go/clean", Linted: true},
+			{Task: "typescript/csv-lite", LintError: "linter exited with code 127"},
+		},
+	}
+
+	var sb strings.Builder
+	writeReportLint(&sb, summary)
+	got := sb.String()
+	for _, want := range []string{
+		"## Code Quality (Lint)",
+		"- **Total warnings**: 3 (1.5 per linted task)",
+		"| go/bank-account | 3 | |",
+		"| typescript/csv-lite | - | linter exited with code 127 |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("lint report missing %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "go/clean") {
+		t.Errorf("lint report should omit clean tasks, got:\n%s", got)
+	}
+
+	sb.Reset()
+	writeReportLint(&sb, EvalSummary{})
+	if sb.Len() != 0 {
+		t.Errorf("lint report should be empty without --lint, got:\n%s", sb.String())
+	}
+}
+
+func TestRobustnessValidationCommand(t *testing.T) {
+	t.Parallel()
+
+	goTask := &task.Task{
+		Language:   task.Go,
+		Files:      task.TaskFiles{RobustnessTests: []string{"robust_test.go.txt"}},
+		Validation: task.Validation{Command: "go", Args: []string{"test", "./..."}},
+	}
+	if got := strings.Join(robustnessValidationCommand(goTask), " "); got != "go test ./..." {
+		t.Errorf("go command = %q, want %q", got, "go test ./...")
+	}
+
+	tsTask := &task.Task{
+		Language: task.TypeScript,
+		Files: task.TaskFiles{
+			HiddenTest:      []string{"hidden.test.ts.txt"},
+			RobustnessTests: []string{"robust.test.ts.txt"},
+		},
+		Validation: task.Validation{Command: "npx", Args: []string{"vitest", "run"}},
+		Variants:   []task.Variant{{Name: "small", Params: map[string]string{"N": "10"}}},
+	}
+	want := "env N=10 npx vitest run hidden.test.ts robust.test.ts"
+	if got := strings.Join(robustnessValidationCommand(tsTask), " "); got != want {
+		t.Errorf("typescript command = %q, want %q", got, want)
+	}
+}
+
+func TestMergeVariantResult(t *testing.T) {
+	t.Parallel()
+
+	result := EvalResult{Passed: true}
+	if mergeVariantResult(&result, VariantResult{Name: "small", Passed: true, Duration: 1}, &EvalResult{Attempts: 1}) {
+		t.Fatal("passing variant reported as the first failure")
+	}
+	// An OOM-killed variant fails with a class but no error.
+	oom := VariantResult{Name: "large", Duration: 2, FailureClass: FailureClassValidationOOM}
+	if !mergeVariantResult(&result, oom, &EvalResult{Attempts: 1}) {
+		t.Fatal("first failing variant not reported")
+	}
+	if result.Passed || result.FailureClass != FailureClassValidationOOM || result.Error != "" {
+		t.Fatalf("result = passed %v class %q error %q, want the OOM class without an error", result.Passed, result.FailureClass, result.Error)
+	}
+	if mergeVariantResult(&result, VariantResult{Name: "huge", Error: "timed out", FailureClass: FailureClassValidationTimeout}, &EvalResult{}) {
+		t.Fatal("second failing variant reported as the first failure")
+	}
+	if result.FailureClass != FailureClassValidationOOM || len(result.Variants) != 3 || result.ValidateTime != 3 || result.Attempts != 2 {
+		t.Fatalf("result = %+v, want the first failure kept and all variants recorded", result)
+	}
+}
+
+func TestRevalidationCommands(t *testing.T) {
+	t.Parallel()
+
+	plain := &task.Task{Language: task.Go, Validation: task.Validation{Command: "go", Args: []string{"test"}}}
+	if cmds := revalidationCommands(plain); len(cmds) != 1 || cmds[0] != nil {
+		t.Fatalf("plain task commands = %q, want one default (nil) command", cmds)
+	}
+
+	parameterized := &task.Task{
+		Language:   task.Go,
+		Validation: task.Validation{Command: "go", Args: []string{"test"}},
+		Variants: []task.Variant{
+			{Name: "small", Params: map[string]string{"N": "1"}},
+			{Name: "large", Params: map[string]string{"N": "1000"}},
+		},
+	}
+	cmds := revalidationCommands(parameterized)
+	if len(cmds) != 2 || strings.Join(cmds[1], " ") != "env N=1000 go test" {
+		t.Fatalf("parameterized task commands = %q, want one per variant", cmds)
+	}
+}
+
+func TestScoreBaseline(t *testing.T) {
+	t.Parallel()
+
+	results := []EvalResult{
+		{Task: "go/a", Weight: 1, Passed: true},
+		{Task: "go/b", Weight: 3},
+		{Task: "go/c", Weight: 1},
+		{Task: "go/d", Weight: 1},
+	}
+	baseline := []EvalResult{
+		{Task: "go/a", Passed: true},
+		{Task: "go/b", Passed: true},
+		{Task: "go/c"},
+		{Task: "rust/other", Passed: true},
+	}
+
+	got := scoreBaseline("reference", "./ref", baseline, results)
+	if got.Passed != 2 || got.Covered != 3 || got.Total != 4 {
+		t.Fatalf("baseline = %+v, want 2 passed, 3 covered of 4", got)
+	}
+	if math.Abs(got.PassRate-50) > 1e-9 || math.Abs(got.WeightedPassRate-(4.0/6*100)) > 1e-9 {
+		t.Fatalf("baseline rates = %.2f / %.2f, want 50.00 / 66.67", got.PassRate, got.WeightedPassRate)
+	}
+
+	if _, _, err := parseBaselineFlag("no-separator"); err == nil {
+		t.Error("expected error for --baseline without name=dir")
+	}
+	if name, dir, err := parseBaselineFlag("stub = ./runs/stub"); err != nil || name != "stub" || dir != "./runs/stub" {
+		t.Errorf("parseBaselineFlag() = %q, %q, %v; want stub, ./runs/stub", name, dir, err)
+	}
+
+	var sb strings.Builder
+	writeReportBaselines(&sb, EvalSummary{Agent: "codex", Passed: 1, Total: 4, PassRate: 25, Baselines: []BaselineScore{got}})
+	if !strings.Contains(sb.String(), "| reference | 2/4 | 50.0% | 66.7% | 3/4 |") {
+		t.Errorf("baseline report missing reference row, got:\n%s", sb.String())
+	}
+}
+
+func TestWriteReportRobustness(t *testing.T) {
+	t.Parallel()
+
+	summary := EvalSummary{
+		Robustness:        true,
+		RobustnessChecked: 2,
+		RobustnessPassed:  1,
+		Results: []EvalResult{
+			{Task: "go/bank-account", Passed: true, RobustnessChecked: true, RobustnessPassed: true},
+			{Task: "go/react", Passed: true, RobustnessChecked: true},
+			{Task: "rust/regex-lite", Passed: true, RobustnessError: "writing robustness tests: missing"},
+		},
+	}
+
+	var sb strings.Builder
+	writeReportRobustness(&sb, summary)
+	got := sb.String()
+	for _, want := range []string{
+		"## Robustness",
+		"- **Robustness pass**: 1 (50.0%)",
+		"| go/react | fail | |",
+		"| rust/regex-lite | error | writing robustness tests: missing |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("robustness report missing %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "go/bank-account") {
+		t.Errorf("robustness report should omit robust tasks, got:\n%s", got)
+	}
+
+	sb.Reset()
+	writeReportRobustness(&sb, EvalSummary{})
+	if sb.Len() != 0 {
+		t.Errorf("robustness report should be empty without --robustness, got:\n%s", sb.String())
+	}
+}
+
+func TestWriteReportRetryReasons(t *testing.T) {
+	t.Parallel()
+
+	summary := EvalSummary{
+		RetryReasons: map[string]int{
+			"quota: dial tcp":           1,
+			"quota: http 429":           4,
+			"infra: empty agent output": 1,
+		},
+	}
+
+	var sb strings.Builder
+	writeReportRetryReasons(&sb, summary)
+	got := sb.String()
+
+	order := []string{
+		"| quota: http 429 | 4 |",
+		"| infra: empty agent output | 1 |",
+		"| quota: dial tcp | 1 |",
+	}
+	last := -1
+	for _, want := range order {
+		idx := strings.Index(got, want)
+		if idx < 0 {
+			t.Fatalf("retry reasons report missing %q, got:\n%s", want, got)
+		}
+		if idx < last {
+			t.Errorf("retry reason %q out of order, got:\n%s", want, got)
+		}
+		last = idx
+	}
+
+	sb.Reset()
+	writeReportRetryReasons(&sb, EvalSummary{})
+	if sb.Len() != 0 {
+		t.Errorf("retry reasons report should be empty without retries, got:\n%s", sb.String())
+	}
+}
+
+func TestNotifyShellCommand(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell syntax")
+	}
+
+	cmd := notifyShellCommand(context.Background(), `printf '%s|%s' "$1" "$2"`, "eval-results/run", "87.5")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("running notify command: %v", err)
+	}
+	if got := string(out); got != "eval-results/run|87.5" {
+		t.Errorf("notify command args = %q, want %q", got, "eval-results/run|87.5")
+	}
+}
+
+func TestMeanPassRate(t *testing.T) {
+	t.Parallel()
+
+	results := []runResult{
+		{summary: &EvalSummary{PassRate: 50}},
+		{summary: &EvalSummary{PassRate: 70}},
+		{err: context.Canceled},
+	}
+	if got := meanPassRate(results); got != 60 {
+		t.Errorf("meanPassRate = %v, want 60", got)
+	}
+}
+
+func TestGetResultStatusDisplayExpectedFailure(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		r    EvalResult
+		want string
+	}{
+		{name: "pass", r: EvalResult{Passed: true}, want: "PASS"},
+		{name: "fail", r: EvalResult{}, want: "FAIL"},
+		{name: "xfail", r: EvalResult{ExpectedFail: true}, want: "XFAIL (expected)"},
+		{name: "xpass", r: EvalResult{ExpectedFail: true, Passed: true}, want: "**XPASS**"},
+		{name: "violation", r: EvalResult{ExpectedFail: true, Status: task.StatusIntegrityViolation}, want: "VIOLATION"},
+		{name: "oom", r: EvalResult{FailureClass: FailureClassValidationOOM}, want: "FAIL (out of memory)"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if _, got := getResultStatusDisplay(tc.r); got != tc.want {
+				t.Errorf("status = %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	var sb strings.Builder
+	writeReportUnexpectedPasses(&sb, EvalSummary{UnexpectedPasses: []string{"go/frontier"}})
+	if !strings.Contains(sb.String(), "- **go/frontier**") {
+		t.Errorf("unexpected passes section missing task, got:\n%s", sb.String())
+	}
+}
+
+func TestBuildTimingBreakdown(t *testing.T) {
+	t.Parallel()
+
+	results := []EvalResult{
+		{
+			Language:     "kotlin",
+			Duration:     100,
+			AgentTime:    40,
+			ValidateTime: 55,
+			PhaseTimes:   map[string]float64{"ensure-image": 1, "container-start": 4, "exec": 45},
+		},
+		{
+			Language:     "go",
+			Duration:     50,
+			AgentTime:    30,
+			ValidateTime: 20,
+			PhaseTimes:   map[string]float64{"container-start": 2, "exec": 15},
+		},
+	}
+
+	tb := buildTimingBreakdown(results)
+	if tb.TotalSeconds != 150 {
+		t.Fatalf("total = %v, want 150", tb.TotalSeconds)
+	}
+	want := map[string]float64{
+		"agent":               70,
+		"ensure-image":        1,
+		"container-start":     6,
+		"exec":                60,
+		"validation-overhead": 8,
+		"harness-overhead":    5,
+	}
+	for _, p := range tb.Phases {
+		if p.Seconds != want[p.Name] {
+			t.Errorf("%s = %v, want %v", p.Name, p.Seconds, want[p.Name])
+		}
+	}
+	if tb.Phases[0].Name != "agent" || math.Abs(tb.Phases[0].Percent-70.0/150*100) > 1e-9 {
+		t.Errorf("first phase = %+v, want agent at 46.7%%", tb.Phases[0])
+	}
+	if tb.ExecByLanguage["kotlin"] != 45 || tb.ExecByLanguage["go"] != 15 {
+		t.Errorf("exec by language = %v", tb.ExecByLanguage)
+	}
+
+	var sb strings.Builder
+	writeReportTiming(&sb, EvalSummary{Results: results})
+	for _, s := range []string{"## Timing Breakdown", "exec ", "| kotlin | 45.0s |"} {
+		if !strings.Contains(sb.String(), s) {
+			t.Errorf("timing report missing %q, got:\n%s", s, sb.String())
+		}
+	}
+}
+
+func TestBuildValidationCommandsCoverage(t *testing.T) {
+	t.Parallel()
+
+	tk := &task.Task{
+		Slug:       "bank-account",
+		Language:   task.Go,
+		Coverage:   true,
+		Validation: task.Validation{Command: "go", Args: []string{"test", "-race", "./..."}},
+	}
+	validationCmd, effective, _ := buildValidationCommands(tk)
+	if got := strings.Join(effective, " "); got != "go test -cover -race ./..." {
+		t.Fatalf("effective = %q", got)
+	}
+	if strings.Join(validationCmd, " ") != strings.Join(effective, " ") {
+		t.Fatalf("validationCmd = %v, want the coverage command", validationCmd)
+	}
+
+	tk.Language = task.Zig
+	tk.Validation = task.Validation{Command: "zig", Args: []string{"build", "test"}}
+	if validationCmd, _, _ := buildValidationCommands(tk); validationCmd != nil {
+		t.Fatalf("validationCmd = %v for a language without coverage, want nil", validationCmd)
+	}
+}
+
+func TestParseCoverage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		lang   task.Language
+		output string
+		want   float64
+		found  bool
+	}{
+		{
+			name:   "go single package",
+			lang:   task.Go,
+			output: "--- PASS: TestDeposit (0.00s)\nPASS\ncoverage: 87.5% of statements\nok  \tbank\t0.012s\tcoverage: 87.5% of statements\n",
+			want:   87.5,
+			found:  true,
+		},
+		{
+			name:   "node test runner",
+			lang:   task.TypeScript,
+			output: "# start of coverage report\n# file      | line % | branch % | funcs % |\n# csv.ts    |  92.31 |    80.00 |  100.00 |\n# all files |  92.31 |    80.00 |  100.00 |\n",
+			want:   92.31,
+			found:  true,
+		},
+		{
+			name:   "no coverage reported",
+			lang:   task.Go,
+			output: "ok  \tbank\t0.012s\n",
+		},
+		{
+			name:   "language without coverage",
+			lang:   task.Rust,
+			output: "coverage: 50.0% of statements\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, found := parseCoverage(tc.lang, tc.output)
+			if found != tc.found || math.Abs(got-tc.want) > 1e-9 {
+				t.Fatalf("parseCoverage() = %v, %v, want %v, %v", got, found, tc.want, tc.found)
+			}
+		})
+	}
+}
+
+func TestValidationAttempts(t *testing.T) {
+	t.Parallel()
+
+	session := resultpkg.NewSession("go/bank-account", "go", resultpkg.SessionConfig{})
+	session.Attempts = []resultpkg.Attempt{{Number: 1, ExitCode: 1, Duration: 3200 * time.Millisecond}}
+	if got := validationAttempts(session); got != nil {
+		t.Fatalf("validationAttempts() = %+v for a single attempt, want nil", got)
+	}
+
+	session.Attempts = append(session.Attempts, resultpkg.Attempt{Number: 2, ExitCode: 0, Passed: true, Duration: 2900 * time.Millisecond})
+	var result EvalResult
+	applyValidationSessionResult(&result, session)
+	want := []ValidationAttempt{
+		{Number: 1, ExitCode: 1, Duration: 3.2},
+		{Number: 2, ExitCode: 0, Passed: true, Duration: 2.9},
+	}
+	if len(result.ValidationAttempts) != 2 || result.ValidationAttempts[0] != want[0] || result.ValidationAttempts[1] != want[1] {
+		t.Fatalf("ValidationAttempts = %+v, want %+v", result.ValidationAttempts, want)
+	}
+
+	log := withAttemptLines("ok\n", result.ValidationAttempts)
+	if !strings.HasPrefix(log, "HARNESS: attempt 1/2 exit_code=1 passed=false duration_seconds=3.200\nHARNESS: attempt 2/2 exit_code=0 passed=true") ||
+		!strings.HasSuffix(log, "\nok\n") {
+		t.Fatalf("withAttemptLines() = %q", log)
+	}
+
+	var sb strings.Builder
+	writeReportValidationAttempts(&sb, EvalSummary{Results: []EvalResult{
+		{Task: "go/bank-account", ValidationAttempts: result.ValidationAttempts},
+		{Task: "go/steady", ValidationAttempts: []ValidationAttempt{{Number: 1}, {Number: 2}}},
+	}})
+	report := sb.String()
+	if !strings.Contains(report, "| go/bank-account | 1: ❌ exit 1 (3.2s) → 2: ✅ exit 0 (2.9s) |") {
+		t.Errorf("report missing flaky task:\n%s", report)
+	}
+	if strings.Contains(report, "go/steady") {
+		t.Errorf("report lists a task whose attempts agreed:\n%s", report)
+	}
+}
+
+func TestRetryDelays(t *testing.T) {
+	t.Parallel()
+
+	defaults := []time.Duration{time.Second, 2 * time.Second}
+	if got := retryDelays(nil, defaults); len(got) != 2 {
+		t.Fatalf("retryDelays(nil) = %v, want defaults", got)
+	}
+	if got := retryDelays([]int{5, -1}, defaults); got[0] != time.Second {
+		t.Fatalf("retryDelays(negative) = %v, want defaults", got)
+	}
+	delays := retryDelays([]int{0, 90}, defaults)
+	for attempt, want := range map[int]time.Duration{0: 0, 1: 0, 2: 90 * time.Second, 7: 90 * time.Second} {
+		if got := retryDelayAt(delays, attempt); got != want {
+			t.Fatalf("retryDelayAt(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestJitterDelay(t *testing.T) {
+	t.Parallel()
+
+	base := 60 * time.Second
+	low, high := 48*time.Second, 72*time.Second
+	for _, u := range []float64{0, 0.25, 0.5, 0.999999} {
+		if got := jitterDelay(base, 0.2, u); got < low || got > high {
+			t.Errorf("jitterDelay(%v, 0.2, %v) = %v, want within [%v, %v]", base, u, got, low, high)
+		}
+	}
+	for range 1000 {
+		if got := jitterDelay(base, 0.2, rand.Float64()); got < low || got > high {
+			t.Fatalf("jitterDelay(%v, 0.2) = %v, want within [%v, %v]", base, got, low, high)
+		}
+	}
+	if got := jitterDelay(base, 0.2, 0); got != low {
+		t.Errorf("jitterDelay(u=0) = %v, want %v", got, low)
+	}
+	if got := jitterDelay(base, 0, 0.9); got != base {
+		t.Errorf("jitterDelay(fraction=0) = %v, want %v", got, base)
+	}
+}
+
+func TestIsFailFastTrigger(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		r    EvalResult
+		want bool
+	}{
+		{"passed", EvalResult{Passed: true}, false},
+		{"failed", EvalResult{}, true},
+		{"expected failure", EvalResult{ExpectedFail: true}, false},
+		{"unexpected pass", EvalResult{Passed: true, ExpectedFail: true}, false},
+	}
+	for _, tt := range tests {
+		if got := isFailFastTrigger(tt.r); got != tt.want {
+			t.Fatalf("%s: isFailFastTrigger = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFailFastError(t *testing.T) {
+	t.Parallel()
+
+	if err := failFastError(nil, &EvalSummary{}); err != nil {
+		t.Fatalf("failFastError() without a fail-fast run = %v", err)
+	}
+	err := failFastError(&EvalSummary{}, nil, &EvalSummary{FailFastTask: "go/react"})
+	var exitErr *exitError
+	if !errors.As(err, &exitErr) || exitErr.code != failFastExitCode {
+		t.Fatalf("failFastError() = %v, want exit code %d", err, failFastExitCode)
+	}
+}
+
+func TestTaskListEntries(t *testing.T) {
+	t.Parallel()
+
+	suite := []*task.Task{
+		{Slug: "bank-account", Language: task.Go, Tier: "core", Difficulty: "hard"},
+		{Slug: "slow-task", Language: task.Rust, Tier: "extended", AgentTimeout: 900},
+	}
+	entries := taskListEntries(filterByTier(suite, "core"), 300)
+	if len(entries) != 1 || entries[0].ID != "go/bank-account" || entries[0].AgentTimeoutSeconds != 300 {
+		t.Fatalf("core entries = %+v", entries)
+	}
+	if want := task.ComputeWeight(suite[0]).Base; math.Abs(entries[0].Weight-want) > 1e-9 {
+		t.Errorf("weight = %.4f, want %.4f", entries[0].Weight, want)
+	}
+
+	var sb strings.Builder
+	if err := outputTable(&sb, taskListEntries(suite, 300)); err != nil {
+		t.Fatalf("outputTable() error = %v", err)
+	}
+	if !strings.Contains(sb.String(), "rust/slow-task") || !strings.Contains(sb.String(), "900s") {
+		t.Errorf("table missing slow task timeout:\n%s", sb.String())
+	}
+
+	sb.Reset()
+	if err := outputTaskListJSON(&sb, entries); err != nil {
+		t.Fatalf("outputTaskListJSON() error = %v", err)
+	}
+	var decoded []map[string]any
+	if err := json.Unmarshal([]byte(sb.String()), &decoded); err != nil {
+		t.Fatalf("decode JSON: %v", err)
+	}
+	var keys []string
+	for k := range decoded[0] {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	want := []string{"agent_timeout_seconds", "description", "difficulty", "id", "language", "slug", "tier", "weight"}
+	if !slices.Equal(keys, want) {
+		t.Errorf("JSON keys = %v, want %v", keys, want)
+	}
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// validationLogComplete reports whether the validation.log at path was
+// written in full: it must end with the HARNESS footer that
+// writeValidationLog appends last. Empty and truncated logs from a crash
+// mid-write fail the check.
+func validationLogComplete(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	return strings.HasPrefix(lines[len(lines)-1], "HARNESS: validation ")
+}
+
+// loadResumeSummary loads the summary of the run being resumed. A summary
+// truncated or corrupted by a crash is reported and dropped instead of
+// failing the resume; dropUnrecordedTasks then re-runs the tasks it held.
+func loadResumeSummary(outputDir string) (*EvalSummary, error) {
+	out := evalOutput()
+	summary, err := loadPreviousSummary(outputDir)
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		fmt.Fprintf(out, " Warning: summary.json is corrupt (%v); re-running the tasks it recorded\n", err)
+		return nil, nil
+	}
+	return summary, err
+}
+
+// dropUnrecordedTasks removes from completed the tasks whose results are not
+// in the previous summary, e.g. because the run crashed before writing it,
+// so resume re-runs them instead of leaving them out of the results. It
+// returns the dropped task IDs in sorted order.
+func dropUnrecordedTasks(completed map[string]bool, prev *EvalSummary) []string {
+	recorded := make(map[string]bool)
+	if prev != nil {
+		for _, r := range prev.Results {
+			recorded[r.Task] = true
+		}
+	}
+
+	var dropped []string
+	for id := range completed {
+		if !recorded[id] {
+			delete(completed, id)
+			dropped = append(dropped, id)
+		}
+	}
+	sort.Strings(dropped)
+	return dropped
+}
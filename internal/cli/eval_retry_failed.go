@@ -0,0 +1,65 @@
+package cli
+
+import "sort"
+
+// requeueFailedTasks removes from completed the tasks that failed validation
+// in the previous summary, so a --retry-failed resume runs them again. Their
+// output directories are replaced only when each task actually reruns.
+// Passes and expected failures stay completed. It returns the requeued task
+// IDs in sorted order.
+func requeueFailedTasks(completed map[string]bool, prev *EvalSummary) []string {
+	if prev == nil {
+		return nil
+	}
+	var requeued []string
+	for _, r := range prev.Results {
+		if r.Passed || r.ExpectedFail || !completed[r.Task] {
+			continue
+		}
+		delete(completed, r.Task)
+		requeued = append(requeued, r.Task)
+	}
+	sort.Strings(requeued)
+	return requeued
+}
+
+// mergePreviousResults prepends the previous results of a resumed run to the
+// results of this session and counts the carried-over passes and failures.
+// A previous result is dropped when the task ran again this session, e.g. a
+// --retry-failed retry, or is now tracked as an external failure carried over
+// from a prior resume. Without the latter, a task stored in results under an
+// earlier bug and now classified as infra/auth/quota would be counted twice:
+// as a scored failure and a skip.
+//
+// A task in ran that this session only recorded as an external failure keeps
+// its previous result instead, and the external failure is removed: a
+// retried failure that hits a quota limit is still a failure, not a skip.
+func mergePreviousResults(previous, results []EvalResult, externalFailures []ExternalFailure, ran map[string]bool) (merged []EvalResult, external []ExternalFailure, passed, failed int) {
+	rerun := make(map[string]bool, len(results))
+	for _, r := range results {
+		rerun[r.Task] = true
+	}
+	skipped := make(map[string]bool, len(externalFailures))
+	for _, f := range externalFailures {
+		skipped[f.Task] = true
+	}
+	kept := make(map[string]bool)
+	for _, r := range previous {
+		if rerun[r.Task] || (skipped[r.Task] && !ran[r.Task]) {
+			continue
+		}
+		kept[r.Task] = true
+		merged = append(merged, r)
+		if r.Passed {
+			passed++
+		} else {
+			failed++
+		}
+	}
+	for _, f := range externalFailures {
+		if !kept[f.Task] {
+			external = append(external, f)
+		}
+	}
+	return append(merged, results...), external, passed, failed
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lemon07r/sanityharness/internal/runner"
+	"github.com/lemon07r/sanityharness/internal/task"
+)
+
+// runRobustnessCheck writes the task's robustness tests next to a passing
+// solution and re-runs validation. Output goes to robustness.log; the
+// outcome is reported separately and never affects pass/fail or scoring.
+func runRobustnessCheck(
+	ctx context.Context,
+	r *runner.Runner,
+	loader *task.Loader,
+	t *task.Task,
+	workspaceDir, taskOutputDir string,
+	timeout int,
+	result *EvalResult,
+) {
+	files := t.RobustnessTestFiles()
+	if len(files) == 0 {
+		return
+	}
+	// Remove the robustness tests afterwards so kept workspaces validate
+	// exactly as the eval did.
+	defer func() {
+		for _, f := range files {
+			_ = os.Remove(filepath.Join(workspaceDir, task.StripTxtExtension(f)))
+		}
+	}()
+	if err := writeTaskFilesToWorkspace(loader, t, workspaceDir, files); err != nil {
+		result.RobustnessError = fmt.Sprintf("writing robustness tests: %v", err)
+		return
+	}
+
+	cmd := robustnessValidationCommand(t)
+	session, err := r.Run(ctx, runner.RunOptions{
+		Task:              t,
+		WorkspaceDir:      workspaceDir,
+		Timeout:           timeout,
+		MaxAttempts:       1,
+		ValidationCommand: cmd,
+		Quiet:             true,
+	})
+	writeValidationSessionLog(filepath.Join(taskOutputDir, "robustness.log"), cmd, session)
+	if err != nil {
+		result.RobustnessError = err.Error()
+		return
+	}
+	if _, _, _, ok := lastSessionAttempt(session); !ok {
+		result.RobustnessError = "robustness validation produced no result"
+		return
+	}
+	result.RobustnessChecked = true
+	result.RobustnessPassed = session.Passed()
+}
+
+// robustnessValidationCommand returns the validation command for the
+// robustness run. TypeScript only runs test files named on the command line,
+// so hidden and robustness test files are appended there. Parameterized
+// tasks run once with their first variant's params.
+func robustnessValidationCommand(t *task.Task) []string {
+	cmd := append([]string{}, t.ValidationCommand()...)
+	if t.Language == task.TypeScript {
+		for _, filename := range t.HiddenTestFiles() {
+			cmd = append(cmd, task.StripTxtExtension(filename))
+		}
+		for _, filename := range t.RobustnessTestFiles() {
+			cmd = append(cmd, task.StripTxtExtension(filename))
+		}
+	}
+	if len(t.Variants) == 0 {
+		return cmd
+	}
+	params := t.Variants[0].Params
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	env := []string{"env"}
+	for _, k := range keys {
+		env = append(env, k+"="+params[k])
+	}
+	return append(env, cmd...)
+}
+
+func writeReportRobustness(sb *strings.Builder, summary EvalSummary) {
+	if !summary.Robustness {
+		return
+	}
+
+	sb.WriteString("## Robustness\n\n")
+	sb.WriteString("Passing solutions re-validated against each task's robustness tests. ")
+	sb.WriteString("This does not affect the pass rate or score.\n\n")
+	rate := 0.0
+	if summary.RobustnessChecked > 0 {
+		rate = float64(summary.RobustnessPassed) / float64(summary.RobustnessChecked) * 100
+	}
+	fmt.Fprintf(sb, "- **Checked tasks**: %d\n", summary.RobustnessChecked)
+	fmt.Fprintf(sb, "- **Robustness pass**: %d (%.1f%%)\n\n", summary.RobustnessPassed, rate)
+
+	var rows []string
+	for _, r := range summary.Results {
+		switch {
+		case r.RobustnessError != "":
+			rows = append(rows, fmt.Sprintf("| %s | error | %s |", r.Task, r.RobustnessError))
+		case r.RobustnessChecked && !r.RobustnessPassed:
+			rows = append(rows, fmt.Sprintf("| %s | fail | |", r.Task))
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+	sb.WriteString("| Task | Robustness | Note |\n")
+	sb.WriteString("|------|------------|------|\n")
+	sb.WriteString(strings.Join(rows, "\n"))
+	sb.WriteString("\n\n")
+}
@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/lemon07r/sanityharness/internal/runner"
+)
+
+// runSettings are the values of the run a task belongs to that differ
+// between the runs of a multi-run session. They travel in the task's
+// context rather than the eval globals, so runs executing side by side
+// under --run-parallel do not overwrite each other's.
+type runSettings struct {
+	agent           string
+	command         string // RunSpec.Command override (--agent-versions)
+	reasoning       string
+	useMCPTools     bool
+	promptTmpl      *promptTemplate // nil for the built-in prompt
+	workDirRoot     string          // private parent of --debug-workspaces dirs; "" for random temp dirs
+	timeoutExplicit bool            // SharedConfig.TimeoutExplicit
+}
+
+type runSettingsKey struct{}
+
+// withRunSettings returns ctx carrying s for the run's tasks.
+func withRunSettings(ctx context.Context, s runSettings) context.Context {
+	return context.WithValue(ctx, runSettingsKey{}, s)
+}
+
+// runSettingsFrom returns the run settings carried by ctx, falling back to
+// the eval flag globals outside evalRunSingle.
+func runSettingsFrom(ctx context.Context) runSettings {
+	if s, ok := ctx.Value(runSettingsKey{}).(runSettings); ok {
+		return s
+	}
+	return runSettings{
+		agent:           evalAgent,
+		command:         evalAgentCommand,
+		reasoning:       evalReasoning,
+		useMCPTools:     evalUseMCPTools,
+		timeoutExplicit: evalTimeoutExplicit,
+	}
+}
+
+// setRunGlobals sets the eval globals that task execution reads from
+// shared. It is called once before a session's runs start, since shared is
+// the same for all of them and runs under --run-parallel read the globals
+// concurrently.
+func setRunGlobals(shared SharedConfig) {
+	evalAgentFallback = shared.AgentFallback
+	evalUseSkills = shared.UseSkills
+	evalLint = shared.Lint
+	evalPromptBudget = shared.PromptBudget
+	evalReuseContainer = shared.ReuseContainer
+	evalRobustness = shared.Robustness
+	evalDisableMCP = shared.DisableMCP
+	evalLegacy = shared.Legacy
+	evalKeepWorkspaces = shared.KeepWorkspaces
+	evalTimeoutGrace = shared.TimeoutGrace
+	evalSystemPrompt = shared.SystemPrompt
+	evalPromptTemplate = shared.PromptTemplate
+}
+
+// multiRunJob is one spec/repeat combination of a multi-run session.
+type multiRunJob struct {
+	specIdx int
+	spec    RunSpec
+	repeat  int
+	dir     string
+}
+
+// multiRunTracker collects finished runs of a multi-run session and rewrites
+// multi-run-state.json after each one. It is safe for concurrent use by the
+// runs of --run-parallel.
+type multiRunTracker struct {
+	umbrellaDir string
+	specs       []RunSpec
+	repeat      int
+
+	mu       sync.Mutex
+	results  []runResult
+	failFast string // first task that stopped a run under --fail-fast
+}
+
+func newMultiRunTracker(umbrellaDir string, specs []RunSpec, repeat int, prior []runResult) *multiRunTracker {
+	return &multiRunTracker{umbrellaDir: umbrellaDir, specs: specs, repeat: repeat, results: prior}
+}
+
+// record adds a finished run and persists the state. A run --fail-fast
+// stopped is recorded as interrupted, so resuming the session finishes its
+// remaining tasks.
+func (t *multiRunTracker) record(rr runResult) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if rr.summary != nil && rr.summary.FailFastTask != "" {
+		rr.interrupted = true
+		if t.failFast == "" {
+			t.failFast = rr.summary.FailFastTask
+		}
+	}
+	t.results = append(t.results, rr)
+	updateMultiRunState(t.umbrellaDir, t.results, t.specs, t.repeat, false)
+}
+
+// interrupt persists the state with the session marked as interrupted.
+func (t *multiRunTracker) interrupt() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	updateMultiRunState(t.umbrellaDir, t.results, t.specs, t.repeat, true)
+}
+
+// failFastTask returns the task that stopped a recorded run under
+// --fail-fast, or "" if none has.
+func (t *multiRunTracker) failFastTask() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.failFast
+}
+
+// summaries returns the recorded runs in spec then repeat order, the order a
+// sequential session records them in.
+func (t *multiRunTracker) summaries() []runResult {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := append([]runResult(nil), t.results...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].specIdx != out[j].specIdx {
+			return out[i].specIdx < out[j].specIdx
+		}
+		return out[i].repeat < out[j].repeat
+	})
+	return out
+}
+
+// runMultiRunJobs runs jobs with up to parallel of them at once, recording
+// each in tracker as it finishes. No new job starts once ctx is interrupted
+// or a run has stopped under --fail-fast; running ones finish first. It
+// reports whether the session was interrupted.
+func runMultiRunJobs(ctx context.Context, jobs []multiRunJob, parallel int, tracker *multiRunTracker, run func(multiRunJob) runResult) bool {
+	if parallel < 1 {
+		parallel = 1
+	}
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	interrupted := false
+	for _, job := range jobs {
+		sem <- struct{}{}
+		if checkInterrupted(ctx) {
+			<-sem
+			interrupted = true
+			break
+		}
+		if tracker.failFastTask() != "" {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			tracker.record(run(job))
+		}()
+	}
+	wg.Wait()
+	if interrupted {
+		tracker.interrupt()
+	}
+	return interrupted
+}
+
+// newSharedRunner creates a runner with shared's legacy and container reuse
+// settings, one per run under --run-parallel.
+func newSharedRunner(shared SharedConfig) (*runner.Runner, error) {
+	r, err := newRunnerFromConfig()
+	if err != nil {
+		return nil, err
+	}
+	r.LegacyHiddenTests = shared.Legacy
+	r.ReuseContainers = shared.ReuseContainer
+	return r, nil
+}
+
+// printRunParallelNotice tells the user how many tasks may run at once when
+// --run-parallel combines with --parallel, since each running task has its
+// own agent process and validation container.
+func printRunParallelNotice(w io.Writer, runs, taskParallel int) {
+	if evalRunParallel <= 1 || runs <= 1 {
+		return
+	}
+	concurrent := min(evalRunParallel, runs) * max(taskParallel, 1)
+	fmt.Fprintf(w, " Running up to %d of %d runs at once (up to %d tasks concurrently)\n", min(evalRunParallel, runs), runs, concurrent)
+}
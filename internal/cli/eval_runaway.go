@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// runawayWriter watches agent output for runaway loops: an agent that writes
+// limit bytes of output without editing a workspace file in between is
+// stuck repeating itself, so kill is called and the attempt is marked
+// tripped. Agents that keep editing files may write any amount.
+type runawayWriter struct {
+	out          io.Writer
+	limit        int64
+	workspaceDir string
+	kill         func()
+
+	written    int64     // bytes since checkpoint
+	checkpoint time.Time // last time the agent was seen editing
+	tripped    atomic.Bool
+}
+
+// newRunawayWriter wraps out; limit <= 0 disables the check.
+func newRunawayWriter(out io.Writer, limit int64, workspaceDir string, kill func()) *runawayWriter {
+	return &runawayWriter{out: out, limit: limit, workspaceDir: workspaceDir, kill: kill, checkpoint: time.Now()}
+}
+
+func (w *runawayWriter) Write(p []byte) (int, error) {
+	n, err := w.out.Write(p)
+	if w.limit <= 0 || w.tripped.Load() {
+		return n, err
+	}
+	w.written += int64(len(p))
+	if w.written >= w.limit {
+		if !hasModifiedFiles(w.workspaceDir, w.checkpoint) {
+			w.tripped.Store(true)
+			w.kill()
+			return n, err
+		}
+		w.written = 0
+		w.checkpoint = time.Now()
+	}
+	return n, err
+}
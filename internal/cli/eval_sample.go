@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+
+	"github.com/lemon07r/sanityharness/internal/task"
+)
+
+// sampleTasks draws n of tasks without replacement using a PRNG seeded with
+// seed, so the same seed over the same task list gives the same sample.
+// Weighted sampling favors tasks with a higher difficulty weight; uniform
+// sampling gives every task the same chance. The sample keeps the input
+// order. All tasks are returned when n >= len(tasks).
+func sampleTasks(tasks []*task.Task, n int, uniform bool, seed int64) []*task.Task {
+	if n >= len(tasks) {
+		return tasks
+	}
+
+	// Weighted sampling without replacement (Efraimidis-Spirakis): each task
+	// gets key u^(1/w) and the n largest keys win.
+	rng := rand.New(rand.NewPCG(uint64(seed), 0))
+	keys := make([]float64, len(tasks))
+	for i, t := range tasks {
+		w := 1.0
+		if !uniform {
+			w = task.ComputeWeight(t).Base
+		}
+		keys[i] = math.Pow(rng.Float64(), 1/w)
+	}
+
+	idx := make([]int, len(tasks))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return keys[idx[a]] > keys[idx[b]] })
+	chosen := idx[:n]
+	sort.Ints(chosen)
+
+	sampled := make([]*task.Task, 0, n)
+	for _, i := range chosen {
+		sampled = append(sampled, tasks[i])
+	}
+	return sampled
+}
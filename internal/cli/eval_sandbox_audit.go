@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SandboxAudit is the effective bubblewrap mount set of one agent attempt,
+// written to sandbox.json in the task output directory so reviewers can see
+// exactly what the agent could read and write.
+type SandboxAudit struct {
+	Writable []string `json:"writable"`
+	ReadOnly []string `json:"readonly"`
+	Masked   []string `json:"masked"`
+	// Skipped lists configured paths that were not mounted or masked
+	// because they do not exist.
+	Skipped []SandboxSkippedPath `json:"skipped,omitempty"`
+}
+
+// SandboxSkippedPath is a configured sandbox path that was skipped.
+type SandboxSkippedPath struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"` // "writable", "readonly" or "denylist"
+}
+
+// sandboxAuditFromArgs collects the mounts from bubblewrap args, stopping at
+// the "--" that separates them from the command. Only tmpfs mounts over a
+// denied path count as masked; others, such as /tmp, are scratch space.
+func sandboxAuditFromArgs(args []string, denied map[string]bool) SandboxAudit {
+	audit := SandboxAudit{Writable: []string{}, ReadOnly: []string{}, Masked: []string{}}
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--":
+			return audit
+		case "--bind":
+			if i+2 < len(args) {
+				audit.Writable = append(audit.Writable, args[i+2])
+				i += 2
+			}
+		case "--ro-bind":
+			if i+2 < len(args) {
+				audit.ReadOnly = append(audit.ReadOnly, args[i+2])
+				i += 2
+			}
+		case "--tmpfs":
+			if i+1 < len(args) {
+				if denied[args[i+1]] {
+					audit.Masked = append(audit.Masked, args[i+1])
+				}
+				i++
+			}
+		}
+	}
+	return audit
+}
+
+// sandboxSkippedPaths returns the configured sandbox paths that do not
+// exist, which buildSandboxArgs leaves out without a word.
+func sandboxSkippedPaths(extraWritableDirs, sharedReadWriteDirs, sharedReadOnlyDirs, readableDenylist []string) []SandboxSkippedPath {
+	homeDir, _ := os.UserHomeDir()
+	var skipped []SandboxSkippedPath
+	addMissing := func(paths []string, kind string) {
+		for _, p := range paths {
+			if _, err := os.Stat(p); err != nil {
+				skipped = append(skipped, SandboxSkippedPath{Path: p, Kind: kind})
+			}
+		}
+	}
+	addMissing(resolveSandboxMountPaths(homeDir, append(append([]string{}, sharedReadWriteDirs...), extraWritableDirs...)), "writable")
+	addMissing(resolveSandboxMountPaths(homeDir, sharedReadOnlyDirs), "readonly")
+
+	var denied []string
+	for _, raw := range readableDenylist {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		if p, err := normalizeDenylistPath(raw); err == nil {
+			denied = append(denied, p)
+		}
+	}
+	addMissing(denied, "denylist")
+	return skipped
+}
+
+// writeSandboxAudit writes sandbox.json for a sandboxed agent command.
+func writeSandboxAudit(path string, bwrapArgs []string, extraWritableDirs, sharedReadWriteDirs, sharedReadOnlyDirs, readableDenylist []string) {
+	denied := make(map[string]bool)
+	for _, raw := range readableDenylist {
+		if p, err := normalizeDenylistPath(raw); raw != "" && err == nil {
+			denied[p] = true
+		}
+	}
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		for _, p := range defaultSandboxSensitiveHomeMasks(homeDir) {
+			denied[p] = true
+		}
+	}
+	audit := sandboxAuditFromArgs(bwrapArgs, denied)
+	audit.Skipped = sandboxSkippedPaths(extraWritableDirs, sharedReadWriteDirs, sharedReadOnlyDirs, readableDenylist)
+	data, err := json.MarshalIndent(audit, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := writeFileAtomic(path, data, 0o644); err != nil {
+		logger.Warn("failed to write sandbox audit", "path", path, "error", err)
+	}
+}
+
+// sandboxAuditPath returns the sandbox.json path next to agentLogPath.
+func sandboxAuditPath(agentLogPath string) string {
+	return filepath.Join(filepath.Dir(agentLogPath), "sandbox.json")
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lemon07r/sanityharness/internal/task"
+)
+
+// parseShard parses a --shard value "i/n" into its 1-based index and count.
+func parseShard(value string) (shard, shards int, err error) {
+	i, n, ok := strings.Cut(value, "/")
+	if ok {
+		shard, err = strconv.Atoi(strings.TrimSpace(i))
+		if err == nil {
+			shards, err = strconv.Atoi(strings.TrimSpace(n))
+		}
+	}
+	if !ok || err != nil || shards < 1 || shard < 1 || shard > shards {
+		return 0, 0, fmt.Errorf("invalid --shard %q (want i/n with 1 <= i <= n, e.g. 1/4)", value)
+	}
+	return shard, shards, nil
+}
+
+// shardTasks returns shard i of n: the tasks at positions i-1, i-1+n,
+// i-1+2n, ... of the list sorted by task ID. Every machine that selects the
+// same tasks gets the same partition whatever order they were listed in.
+// The shard keeps the input order.
+func shardTasks(tasks []*task.Task, shard, shards int) []*task.Task {
+	ids := make([]string, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID()
+	}
+	sort.Strings(ids)
+	inShard := make(map[string]bool)
+	for pos, id := range ids {
+		if pos%shards == shard-1 {
+			inShard[id] = true
+		}
+	}
+
+	var selected []*task.Task
+	for _, t := range tasks {
+		if inShard[t.ID()] {
+			selected = append(selected, t)
+		}
+	}
+	return selected
+}
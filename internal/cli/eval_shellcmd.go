@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/lemon07r/sanityharness/internal/config"
+)
+
+// resolveShellCommand fills an agent's shell_command template. {prompt},
+// {model} and {reasoning} become single-quoted shell words, so values are
+// passed verbatim however they are placed in the template.
+func resolveShellCommand(template, prompt, model, reasoning string) string {
+	return strings.NewReplacer(
+		"{prompt}", shellQuote(prompt),
+		"{model}", shellQuote(model),
+		"{reasoning}", shellQuote(reasoning),
+	).Replace(template)
+}
+
+// shellCommandProvenance returns the shell command an agent runs with, for
+// the result record: resolved except for the {prompt} placeholder. It is
+// empty for agents without a shell_command.
+func shellCommandProvenance(agentCfg *config.AgentConfig, model, reasoning string) string {
+	if agentCfg == nil || agentCfg.ShellCommand == "" {
+		return ""
+	}
+	return strings.NewReplacer(
+		"{model}", shellQuote(model),
+		"{reasoning}", shellQuote(reasoning),
+	).Replace(agentCfg.ShellCommand)
+}
+
+// shellQuote quotes s as a single POSIX shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// statusMatrixCell returns the status-matrix symbol for a run: ✅ completed,
+// ❌ completed without a summary (the run errored), ⏸ interrupted and —
+// not yet started.
+func statusMatrixCell(umbrellaDir string, item MultiRunItem) string {
+	switch item.Status {
+	case "completed":
+		if _, err := os.Stat(filepath.Join(umbrellaDir, item.Dir, "summary.json")); err != nil {
+			return "❌"
+		}
+		return "✅"
+	case "interrupted":
+		return "⏸"
+	default:
+		return "—"
+	}
+}
+
+// buildStatusMatrix renders a multi-run's state as a grid of specs × repeats,
+// followed by how many runs a resume would pick up.
+func buildStatusMatrix(umbrellaDir string, state MultiRunState) string {
+	cells := make(map[[2]int]string, len(state.Runs))
+	resumable := 0
+	for _, item := range state.Runs {
+		cells[[2]int{item.SpecIndex, item.Repeat}] = statusMatrixCell(umbrellaDir, item)
+		if item.Status != "completed" {
+			resumable++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Multi-Run Status: %s\n\n", state.ID)
+	sb.WriteString("| Run |")
+	for rep := 1; rep <= state.Repeat; rep++ {
+		fmt.Fprintf(&sb, " %d |", rep)
+	}
+	sb.WriteString("\n|-----|")
+	for rep := 1; rep <= state.Repeat; rep++ {
+		sb.WriteString("---|")
+	}
+	sb.WriteString("\n")
+	for specIdx, spec := range state.Specs {
+		label := runSpecLabel(spec)
+		if spec.Label != "" {
+			label += " [" + spec.Label + "]"
+		}
+		fmt.Fprintf(&sb, "| %s |", label)
+		for rep := 1; rep <= state.Repeat; rep++ {
+			cell, ok := cells[[2]int{specIdx, rep}]
+			if !ok {
+				cell = "—"
+			}
+			fmt.Fprintf(&sb, " %s |", cell)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n✅ completed, ❌ errored, ⏸ interrupted, — not started\n\n")
+	fmt.Fprintf(&sb, "%d of %d runs resumable", resumable, len(state.Runs))
+	if resumable > 0 {
+		fmt.Fprintf(&sb, " with `sanity eval --resume %s`", umbrellaDir)
+	}
+	sb.WriteString(".\n")
+	return sb.String()
+}
+
+// writeStatusMatrix writes status-matrix.md to the umbrella directory.
+func writeStatusMatrix(umbrellaDir string, state MultiRunState) {
+	report := buildStatusMatrix(umbrellaDir, state)
+	_ = writeFileAtomic(filepath.Join(umbrellaDir, "status-matrix.md"), []byte(report), 0o644)
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/lemon07r/sanityharness/internal/config"
+)
+
+// agentTimestampFormat locates and parses timestamps in an agent's log lines,
+// from the agent's timestamp_pattern and timestamp_layout.
+type agentTimestampFormat struct {
+	pattern *regexp.Regexp
+	layout  string
+}
+
+// newAgentTimestampFormat returns the agent's timestamp format, or nil when
+// the agent has no timestamp_pattern.
+func newAgentTimestampFormat(agentCfg *config.AgentConfig) *agentTimestampFormat {
+	if agentCfg == nil || agentCfg.TimestampPattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(agentCfg.TimestampPattern)
+	if err != nil {
+		return nil
+	}
+	layout := agentCfg.TimestampLayout
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	return &agentTimestampFormat{pattern: re, layout: layout}
+}
+
+// parse returns the timestamp on line, if any.
+func (f *agentTimestampFormat) parse(line string) (time.Time, bool) {
+	m := f.pattern.FindStringSubmatch(line)
+	if m == nil {
+		return time.Time{}, false
+	}
+	value := m[0]
+	if len(m) > 1 {
+		value = m[1]
+	}
+	if f.layout == "unix" {
+		secs, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return time.Unix(0, int64(secs*float64(time.Second))), true
+	}
+	ts, err := time.Parse(f.layout, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return ts, true
+}
+
+// segmentAgentTimeline splits the time between timestamped log lines into
+// thinking and acting. Each timestamped line starts an event that runs until
+// the next one; an event whose lines include a shell command (as found by
+// extractCommandLines) is acting, since the gap is spent running the tool,
+// and any other event is thinking. Both are zero without a format or when
+// fewer than two lines carry a timestamp.
+func segmentAgentTimeline(lines []string, format *agentTimestampFormat) (thinking, acting time.Duration) {
+	if format == nil {
+		return 0, 0
+	}
+
+	var prev time.Time
+	var started bool
+	var block []string
+	for _, line := range lines {
+		ts, found := format.parse(line)
+		if !found {
+			block = append(block, line)
+			continue
+		}
+		if started {
+			if gap := ts.Sub(prev); gap > 0 {
+				if len(extractCommandLines(block)) > 0 {
+					acting += gap
+				} else {
+					thinking += gap
+				}
+			}
+		}
+		started = true
+		prev = ts
+		block = append(block[:0], line)
+	}
+	return thinking, acting
+}
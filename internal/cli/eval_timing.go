@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	resultpkg "github.com/lemon07r/sanityharness/internal/result"
+	"github.com/lemon07r/sanityharness/internal/runner"
+)
+
+// Timing breakdown phases, in report order. The runner phases come from
+// validation sessions; the rest are derived from per-task durations.
+const (
+	timingAgent              = "agent"
+	timingValidationOverhead = "validation-overhead" // workspace setup and session capture around the runner phases
+	timingHarnessOverhead    = "harness-overhead"    // workspace init, integrity checks and copying outside agent/validation
+)
+
+var timingPhaseOrder = []string{
+	timingAgent,
+	string(runner.PhaseEnsureImage),
+	string(runner.PhaseStartContainer),
+	string(runner.PhaseExec),
+	timingValidationOverhead,
+	timingHarnessOverhead,
+}
+
+// TimingBreakdown aggregates where task time went across a run. Times are
+// summed over tasks, so with --parallel the total exceeds wall time.
+type TimingBreakdown struct {
+	TotalSeconds   float64            `json:"total_task_seconds"`
+	Phases         []TimingPhase      `json:"phases"`
+	ExecByLanguage map[string]float64 `json:"exec_seconds_by_language,omitempty"`
+}
+
+// TimingPhase is one row of a TimingBreakdown.
+type TimingPhase struct {
+	Name    string  `json:"name"`
+	Seconds float64 `json:"seconds"`
+	Percent float64 `json:"percent"`
+}
+
+// addSessionPhaseTimes adds a validation session's runner phase times to
+// the result.
+func addSessionPhaseTimes(result *EvalResult, session *resultpkg.Session) {
+	for phase, d := range session.PhaseTimes {
+		addPhaseSeconds(result, phase, d.Seconds())
+	}
+}
+
+func addPhaseSeconds(result *EvalResult, phase string, seconds float64) {
+	if result.PhaseTimes == nil {
+		result.PhaseTimes = make(map[string]float64)
+	}
+	result.PhaseTimes[phase] += seconds
+}
+
+// buildTimingBreakdown sums per-task timings into run-level phases.
+func buildTimingBreakdown(results []EvalResult) TimingBreakdown {
+	totals := make(map[string]float64)
+	execByLang := make(map[string]float64)
+	for _, r := range results {
+		totals[timingAgent] += r.AgentTime
+		var runnerTime float64
+		for phase, seconds := range r.PhaseTimes {
+			totals[phase] += seconds
+			runnerTime += seconds
+		}
+		if exec := r.PhaseTimes[string(runner.PhaseExec)]; exec > 0 {
+			execByLang[r.Language] += exec
+		}
+		totals[timingValidationOverhead] += max(r.ValidateTime-runnerTime, 0)
+		totals[timingHarnessOverhead] += max(r.Duration-r.AgentTime-r.ValidateTime, 0)
+	}
+
+	var tb TimingBreakdown
+	for _, name := range timingPhaseOrder {
+		tb.TotalSeconds += totals[name]
+	}
+	for _, name := range timingPhaseOrder {
+		phase := TimingPhase{Name: name, Seconds: totals[name]}
+		if tb.TotalSeconds > 0 {
+			phase.Percent = totals[name] / tb.TotalSeconds * 100
+		}
+		tb.Phases = append(tb.Phases, phase)
+	}
+	if len(execByLang) > 0 {
+		tb.ExecByLanguage = execByLang
+	}
+	return tb
+}
+
+// writeTimingBreakdown writes timing-breakdown.json to the run directory.
+func writeTimingBreakdown(outputDir string, tb TimingBreakdown) error {
+	data, err := json.MarshalIndent(tb, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(outputDir, "timing-breakdown.json"), data, 0o644)
+}
+
+// writeReportTiming renders the timing breakdown as a text bar chart.
+func writeReportTiming(sb *strings.Builder, summary EvalSummary) {
+	tb := buildTimingBreakdown(summary.Results)
+	if tb.TotalSeconds <= 0 {
+		return
+	}
+
+	sb.WriteString("## Timing Breakdown\n\n")
+	fmt.Fprintf(sb, "Task time summed over all tasks: %.1fs (exceeds wall time with --parallel).\n\n", tb.TotalSeconds)
+	sb.WriteString("```text\n")
+	for _, p := range tb.Phases {
+		fmt.Fprintf(sb, "%-19s %s %5.1f%% %9.1fs\n", p.Name, timingBar(p.Percent), p.Percent, p.Seconds)
+	}
+	sb.WriteString("```\n\n")
+
+	if len(tb.ExecByLanguage) > 1 {
+		langs := make([]string, 0, len(tb.ExecByLanguage))
+		for lang := range tb.ExecByLanguage {
+			langs = append(langs, lang)
+		}
+		sort.Slice(langs, func(i, j int) bool {
+			return tb.ExecByLanguage[langs[i]] > tb.ExecByLanguage[langs[j]]
+		})
+		sb.WriteString("| Language | Validation Exec |\n")
+		sb.WriteString("|----------|-----------------|\n")
+		for _, lang := range langs {
+			fmt.Fprintf(sb, "| %s | %.1fs |\n", lang, tb.ExecByLanguage[lang])
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// timingBar draws a 30-character bar for percent.
+func timingBar(percent float64) string {
+	const width = 30
+	filled := int(percent/100*width + 0.5)
+	filled = min(max(filled, 0), width)
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lemon07r/sanityharness/internal/config"
+)
+
+// defaultTokenUsagePatterns match the token counts agent CLIs commonly print,
+// e.g. "tokens used: 12,345" or "input_tokens=812 output_tokens=97".
+var defaultTokenUsagePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\btokens used:?\s*([\d,]+)`),
+	regexp.MustCompile(`(?i)\b(?:input|output)_tokens"?\s*[=:]\s*(\d+)`),
+}
+
+// agentTokenPatterns returns the agent's token_patterns, or the defaults
+// when it has none. Patterns that don't compile are skipped; config
+// validation reports them at startup.
+func agentTokenPatterns(agentCfg *config.AgentConfig) []*regexp.Regexp {
+	if agentCfg == nil || len(agentCfg.TokenPatterns) == 0 {
+		return defaultTokenUsagePatterns
+	}
+	var patterns []*regexp.Regexp
+	for _, p := range agentCfg.TokenPatterns {
+		if re, err := regexp.Compile(p); err == nil {
+			patterns = append(patterns, re)
+		}
+	}
+	return patterns
+}
+
+// countTokenUsage sums the token counts patterns find in an agent log, each
+// taken from the first capture group, else the whole match. ok is false when
+// no count was found, so a zero total means "unknown" rather than "none".
+func countTokenUsage(content string, patterns []*regexp.Regexp) (total int, ok bool) {
+	for _, re := range patterns {
+		for _, m := range re.FindAllStringSubmatch(content, -1) {
+			raw := m[0]
+			if len(m) > 1 {
+				raw = m[1]
+			}
+			n, err := strconv.Atoi(strings.ReplaceAll(strings.TrimSpace(raw), ",", ""))
+			if err != nil {
+				continue
+			}
+			total += n
+			ok = true
+		}
+	}
+	return total, ok
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/lemon07r/sanityharness/internal/runner"
+	"github.com/lemon07r/sanityharness/internal/task"
+)
+
+// runVerifyOnly re-runs validation against the solutions preserved in a
+// completed run directory and checks that each recorded pass/fail
+// reproduces. No agent is invoked, and each workspace is validated in a
+// temp copy, so build outputs never land in the run directory. The run must
+// have been made with --keep-workspaces.
+func runVerifyOnly(runDir string) error {
+	if isMultiRunDir(runDir) {
+		return fmt.Errorf("%s is a multi-run directory; pass one of its run-N directories to --verify-only", runDir)
+	}
+	runCfg, err := loadRunConfig(runDir)
+	if err != nil {
+		return fmt.Errorf("loading run config: %w", err)
+	}
+	if !runCfg.KeepWorkspaces {
+		return errors.New("--verify-only needs the run's workspaces; re-run the eval with --keep-workspaces")
+	}
+	summary, err := loadSummaryFromDir(runDir)
+	if err != nil {
+		return err
+	}
+
+	r, err := newRunnerFromConfig()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = r.Close() }()
+	allTasks, err := r.ListTasks()
+	if err != nil {
+		return fmt.Errorf("listing tasks: %w", err)
+	}
+
+	ctx, cancel := setupInterruptHandler()
+	defer cancel()
+
+	fmt.Println()
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Println(" SANITY HARNESS - Reproduce Validation")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Println()
+	fmt.Printf(" Run:     %s\n", runDir)
+	fmt.Printf(" Agent:   %s\n", summary.Agent)
+	fmt.Printf(" Tasks:   %d\n\n", len(summary.Results))
+
+	var reproduced, skipped int
+	var mismatched []string
+	for _, res := range summary.Results {
+		if checkInterrupted(ctx) {
+			return errors.New("interrupted")
+		}
+		t, err := task.ResolveRef(allTasks, res.Task)
+		if err != nil {
+			fmt.Printf(" %-40s \033[33mSKIP\033[0m (%v)\n", res.Task, err)
+			skipped++
+			continue
+		}
+		if res.Status == task.StatusIntegrityViolation {
+			fmt.Printf(" %-40s \033[33mSKIP\033[0m (integrity violation, never validated)\n", res.Task)
+			skipped++
+			continue
+		}
+		_, workspaceDir := evalWorkspacePaths(runDir, t)
+		if _, err := os.Stat(workspaceDir); err != nil {
+			fmt.Printf(" %-40s \033[33mSKIP\033[0m (workspace not preserved)\n", res.Task)
+			skipped++
+			continue
+		}
+
+		timeout := resolveValidationTimeout(taskTimeoutSeconds(runCfg.Timeout, runCfg.TimeoutExplicit, t), t.Timeout)
+		passed, err := revalidateWorkspace(ctx, r, t, workspaceDir, timeout)
+		switch {
+		case err != nil:
+			fmt.Printf(" %-40s \033[31mERROR\033[0m %v\n", res.Task, err)
+			mismatched = append(mismatched, res.Task)
+		case passed != res.Passed:
+			fmt.Printf(" %-40s \033[31mMISMATCH\033[0m recorded %s, now %s\n", res.Task, passFailLabel(res.Passed), passFailLabel(passed))
+			mismatched = append(mismatched, res.Task)
+		default:
+			fmt.Printf(" %-40s \033[32mOK\033[0m %s\n", res.Task, passFailLabel(passed))
+			reproduced++
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf(" Reproduced: %d  Mismatched: %d  Skipped: %d\n", reproduced, len(mismatched), skipped)
+	if len(mismatched) > 0 {
+		return fmt.Errorf("%d task(s) did not reproduce their recorded result", len(mismatched))
+	}
+	return nil
+}
+
+// revalidateWorkspace runs the task's validation against a temp copy of a
+// preserved workspace, leaving the original untouched. Parameterized tasks
+// pass only if every variant passes.
+func revalidateWorkspace(ctx context.Context, r *runner.Runner, t *task.Task, workspaceDir string, timeout int) (bool, error) {
+	copyDir, err := os.MkdirTemp("", fmt.Sprintf("sanity-verify-%s-%s-*", t.Language, t.Slug))
+	if err != nil {
+		return false, fmt.Errorf("creating temp workspace: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(copyDir) }()
+	if err := copyDirContentsSkipping(workspaceDir, copyDir, isEvalOutputFile); err != nil {
+		return false, fmt.Errorf("copying workspace: %w", err)
+	}
+
+	for _, cmd := range revalidationCommands(t) {
+		session, err := r.Run(ctx, runner.RunOptions{
+			Task:              t,
+			WorkspaceDir:      copyDir,
+			Timeout:           timeout,
+			MaxAttempts:       1,
+			ValidationCommand: cmd,
+			Quiet:             true,
+		})
+		if err != nil {
+			return false, err
+		}
+		if !session.Passed() {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// revalidationCommands returns the validation commands an eval ran for t:
+// one per variant, or the single default (nil meaning the task's own).
+func revalidationCommands(t *task.Task) [][]string {
+	validationCmd, _, variants := buildValidationCommands(t)
+	if len(variants) == 0 {
+		return [][]string{validationCmd}
+	}
+	cmds := make([][]string, 0, len(variants))
+	for _, v := range variants {
+		cmds = append(cmds, v.command)
+	}
+	return cmds
+}
+
+func passFailLabel(passed bool) string {
+	if passed {
+		return "PASS"
+	}
+	return "FAIL"
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lemon07r/sanityharness/internal/runner"
+	"github.com/lemon07r/sanityharness/internal/task"
+)
+
+// warmupPullConcurrency bounds how many images warmup pulls at once.
+const warmupPullConcurrency = 3
+
+// warmupImages makes sure every image the run needs is available before the
+// first task starts, pulling missing ones concurrently. Failures are only
+// warnings: affected tasks hit the same error in their ensure-image phase and
+// are classified as infra failures there.
+func warmupImages(ctx context.Context, r *runner.Runner, tasks []*task.Task) {
+	out := evalOutput()
+	if len(tasks) == 0 {
+		return
+	}
+	start := time.Now()
+	fmt.Fprintln(out, " Preparing container images...")
+	err := r.EnsureImages(ctx, tasks, warmupPullConcurrency, func(p runner.ImageProgress) {
+		status := "\033[32mready\033[0m"
+		if p.Err != nil {
+			status = "\033[31mfailed\033[0m"
+		}
+		fmt.Fprintf(out, "   [%d/%d] %s %s\n", p.Done, p.Total, p.Image, status)
+	})
+	if err != nil {
+		logger.Warn("image warmup failed; affected tasks will report infra failures", "error", err)
+		fmt.Fprintf(out, " Image warmup finished with errors in %.1fs\n\n", time.Since(start).Seconds())
+		return
+	}
+	fmt.Fprintf(out, " Images ready in %.1fs\n\n", time.Since(start).Seconds())
+}
@@ -7,17 +7,29 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/lemon07r/sanityharness/internal/config"
 	"github.com/lemon07r/sanityharness/internal/task"
 	"github.com/lemon07r/sanityharness/tasks"
 )
 
-var initOutput string
+var (
+	initOutput string
+	initForce  bool
+)
+
+// configScaffoldFile is where `sanity init` without a task writes the
+// starter config.
+const configScaffoldFile = "sanity.toml"
 
 var initCmd = &cobra.Command{
-	Use:   "init <task>",
-	Short: "Initialize a workspace for a task",
+	Use:   "init [task]",
+	Short: "Initialize a workspace for a task, or a starter sanity.toml",
 	Long: `Creates a new directory with the task's stub files for manual development.
 
+Without a task, writes a commented starter sanity.toml with the default
+harness, docker and sandbox settings to the current directory instead. An
+existing sanity.toml is only replaced with --force.
+
 Use this when you want to work on a task interactively and run tests repeatedly.
 The workspace is created in the current directory by default.
 
@@ -26,9 +38,13 @@ To run tests against your workspace:
 
 Example:
   sanity init bank-account
-  sanity init go/bank-account -o ./my-workspace`,
-	Args: cobra.ExactArgs(1),
+  sanity init go/bank-account -o ./my-workspace
+  sanity init`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return writeConfigScaffold(configScaffoldFile, initForce)
+		}
 		taskRef := args[0]
 
 		// Load task without creating a Docker client
@@ -100,6 +116,23 @@ Example:
 	},
 }
 
+// writeConfigScaffold writes config.Scaffold to path, refusing to replace an
+// existing file unless force is set.
+func writeConfigScaffold(path string, force bool) error {
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+	if err := os.WriteFile(path, []byte(config.Scaffold()), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	fmt.Printf("Wrote %s with the default settings\n", path)
+	fmt.Println("\nNext steps:")
+	fmt.Println("  1. Uncomment and adapt the [agents.custom] example, or override a built-in agent")
+	fmt.Println("  2. Run: sanity eval --agent <name> --dry-run")
+	return nil
+}
+
 func init() {
 	initCmd.Flags().StringVarP(&initOutput, "output", "o", "", "output directory (default: ./<task-slug>)")
+	initCmd.Flags().BoolVar(&initForce, "force", false, "overwrite an existing sanity.toml (without a task)")
 }
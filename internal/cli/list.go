@@ -3,8 +3,10 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -22,91 +24,137 @@ var (
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List available tasks",
-	Long:  `Lists all available evaluation tasks, optionally filtered by language.`,
+	Long: `Lists all available evaluation tasks with each task's scoring weight and
+agent timeout, optionally filtered by language, tier and difficulty.
+
+Examples:
+  sanity list --tier core --difficulty hard,expert
+  sanity list --json | jq -r '.[].slug'
+
+sanity tasks list is the same listing with eval's --lang flag and a --json
+format that includes each task's id, weight and agent timeout.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Load tasks directly without creating Docker client
-		loader := task.NewLoader(tasks.FS, tasksDir)
-
-		var taskList []*task.Task
-		var err error
-		if listLanguage != "" {
-			lang, parseErr := task.ParseLanguage(listLanguage)
-			if parseErr != nil {
-				return parseErr
-			}
-			taskList, err = loader.LoadByLanguage(lang)
-			if err != nil {
-				return err
-			}
-		} else {
-			taskList, err = loader.LoadAll()
-			if err != nil {
-				return err
-			}
+		taskList, err := selectTaskList(listLanguage, listTier, listDifficulty)
+		if err != nil {
+			return err
 		}
-
-		// Validate tier filter
-		if listTier != "" && listTier != "core" && listTier != "extended" {
-			return fmt.Errorf("invalid tier %q: must be 'core' or 'extended'", listTier)
+		if listJSON {
+			return outputJSON(taskList)
 		}
+		return outputTable(os.Stdout, taskListEntries(taskList, listTimeoutSeconds()))
+	},
+}
 
-		if listTier != "" {
-			var filtered []*task.Task
-			for _, t := range taskList {
-				if t.Tier == listTier {
-					filtered = append(filtered, t)
-				}
-			}
-			taskList = filtered
+// selectTaskList returns the tasks matching eval's language, tier and
+// difficulty filters. It backs both sanity list and sanity tasks list.
+func selectTaskList(lang, tier, difficulty string) ([]*task.Task, error) {
+	if lang != "" {
+		if _, err := task.ParseLanguage(lang); err != nil {
+			return nil, err
 		}
+	}
+	switch tier {
+	case "", "all", "core", "extended":
+	default:
+		return nil, fmt.Errorf("invalid tier %q: must be 'core', 'extended' or 'all'", tier)
+	}
 
-		if listDifficulty != "" {
-			var filtered []*task.Task
-			for _, t := range taskList {
-				if t.Difficulty == listDifficulty {
-					filtered = append(filtered, t)
-				}
-			}
-			taskList = filtered
-		}
+	// Load tasks directly without creating Docker client
+	loader := task.NewLoader(tasks.FS, tasksDir)
+	taskList, err := loader.LoadAll()
+	if err != nil {
+		return nil, err
+	}
 
-		if listJSON {
-			return outputJSON(taskList)
-		}
+	// Same filters as eval
+	if lang != "" {
+		taskList = filterByLanguage(taskList, lang)
+	}
+	if tier != "" && tier != "all" {
+		taskList = filterByTier(taskList, tier)
+	}
+	if difficulty != "" {
+		taskList = filterByDifficulty(taskList, difficulty)
+	}
+	return taskList, nil
+}
 
-		return outputTable(taskList)
-	},
+// listTimeoutSeconds returns the global timeout eval uses without --timeout.
+func listTimeoutSeconds() int {
+	if cfg != nil && cfg.Harness.DefaultTimeout > 0 {
+		return cfg.Harness.DefaultTimeout
+	}
+	return 600
 }
 
 func init() {
 	listCmd.Flags().StringVarP(&listLanguage, "language", "l", "", "filter by language (go, rust, ts, kotlin, dart, zig)")
-	listCmd.Flags().StringVar(&listTier, "tier", "", "filter by tier (core, extended)")
-	listCmd.Flags().StringVar(&listDifficulty, "difficulty", "", "filter by difficulty (e.g., hard, expert)")
+	listCmd.Flags().StringVar(&listTier, "tier", "", "filter by tier (core, extended, all)")
+	listCmd.Flags().StringVar(&listDifficulty, "difficulty", "", "filter by difficulty (comma-separated, e.g., hard,expert)")
 	listCmd.Flags().BoolVar(&listJSON, "json", false, "output as JSON")
 }
 
+// TaskListEntry is one row of sanity list and sanity tasks list, and the
+// stable JSON format of sanity tasks list --json. AgentTimeoutSeconds is the agent
+// timeout eval gives the task before any per-agent minimum.
+type TaskListEntry struct {
+	ID                  string  `json:"id"`
+	Slug                string  `json:"slug"`
+	Language            string  `json:"language"`
+	Tier                string  `json:"tier"`
+	Difficulty          string  `json:"difficulty"`
+	Description         string  `json:"description"`
+	Weight              float64 `json:"weight"`
+	AgentTimeoutSeconds int     `json:"agent_timeout_seconds"`
+}
+
+// taskListEntries describes taskList for an eval with the given --timeout.
+func taskListEntries(taskList []*task.Task, timeoutSeconds int) []TaskListEntry {
+	entries := make([]TaskListEntry, 0, len(taskList))
+	for _, t := range taskList {
+		entries = append(entries, TaskListEntry{
+			ID:                  t.ID(),
+			Slug:                t.Slug,
+			Language:            string(t.Language),
+			Tier:                t.Tier,
+			Difficulty:          t.Difficulty,
+			Description:         t.Description,
+			Weight:              task.ComputeWeight(t).Base,
+			AgentTimeoutSeconds: int(resolveAgentTimeout(taskTimeoutSeconds(timeoutSeconds, false, t), 0, t.AgentTimeout) / time.Second),
+		})
+	}
+	return entries
+}
+
 func outputJSON(tasks []*task.Task) error {
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
 	return enc.Encode(tasks)
 }
 
-func outputTable(taskList []*task.Task) error {
-	if len(taskList) == 0 {
-		fmt.Println("No tasks found.")
+// outputTaskListJSON writes entries as the JSON output of sanity tasks list.
+func outputTaskListJSON(out io.Writer, entries []TaskListEntry) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+func outputTable(out io.Writer, entries []TaskListEntry) error {
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(out, "No tasks found.")
 		return nil
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	_, _ = fmt.Fprintln(w, "ID\tLANGUAGE\tTIER\tDIFFICULTY\tDESCRIPTION")
-	_, _ = fmt.Fprintln(w, "--\t--------\t----\t----------\t-----------")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tLANGUAGE\tTIER\tDIFFICULTY\tWEIGHT\tTIMEOUT\tDESCRIPTION")
+	_, _ = fmt.Fprintln(w, "--\t--------\t----\t----------\t------\t-------\t-----------")
 
-	for _, t := range taskList {
-		desc := t.Description
+	for _, e := range entries {
+		desc := e.Description
 		if len(desc) > 50 {
 			desc = desc[:47] + "..."
 		}
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.ID(), t.Language, t.Tier, t.Difficulty, desc)
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.2f\t%ds\t%s\n", e.ID, e.Language, e.Tier, e.Difficulty, e.Weight, e.AgentTimeoutSeconds, desc)
 	}
 
 	return w.Flush()
@@ -0,0 +1,291 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/lemon07r/sanityharness/internal/task"
+	"github.com/lemon07r/sanityharness/tasks"
+)
+
+var mergeShardsOutput string
+
+var mergeShardsCmd = &cobra.Command{
+	Use:   "merge-shards <dir> <dir> [dir...]",
+	Short: "Combine the results of eval --shard runs",
+	Long: `Combine the run directories of an eval split with --shard i/n into one run
+directory with a single summary, report, attestation and submission.
+
+The task directories of every shard are copied into the output directory and
+the combined results are scored as if one eval had run them all. Tasks that no
+shard finished can be completed afterwards with 'sanity eval --resume <output>'.`,
+	Example: `  sanity merge-shards eval-results/shard-1 eval-results/shard-2 eval-results/shard-3
+  sanity merge-shards -o eval-results/full-run ./shard-*`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		shards := make([]shardRun, 0, len(args))
+		for _, dir := range args {
+			s, err := loadShardRun(dir)
+			if err != nil {
+				return err
+			}
+			shards = append(shards, s)
+		}
+		merged, err := mergeShardRuns(shards)
+		if err != nil {
+			return err
+		}
+
+		r, err := newRunnerFromConfig()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = r.Close() }()
+		allTasks, err := r.ListTasks()
+		if err != nil {
+			return fmt.Errorf("listing tasks: %w", err)
+		}
+		if err := checkShardTaskHashes(shards, task.NewLoader(tasks.FS, tasksDir), allTasks); err != nil {
+			return err
+		}
+
+		outputDir := mergeShardsOutput
+		if outputDir == "" {
+			outputDir = filepath.Join("eval-results", fmt.Sprintf("%s-%s-merged", merged.timestamp, merged.runCfg.Agent))
+		}
+		if entries, err := os.ReadDir(outputDir); err == nil && len(entries) > 0 {
+			return fmt.Errorf("output directory %s already exists and is not empty", outputDir)
+		}
+		if err := os.MkdirAll(outputDir, 0o755); err != nil {
+			return fmt.Errorf("creating output directory: %w", err)
+		}
+		for _, s := range shards {
+			if err := copyShardTaskDirs(s.dir, outputDir); err != nil {
+				// The directory was empty, so nothing but the partial copy is lost.
+				_ = os.RemoveAll(outputDir)
+				return err
+			}
+		}
+		data, err := json.MarshalIndent(merged.runCfg, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling run config: %w", err)
+		}
+		if err := writeFileAtomic(filepath.Join(outputDir, "run-config.json"), data, 0o644); err != nil {
+			return fmt.Errorf("writing run config: %w", err)
+		}
+
+		completedTasks, err := findCompletedTasks(outputDir)
+		if err != nil {
+			return fmt.Errorf("finding completed tasks: %w", err)
+		}
+
+		ctx, cancel := setupInterruptHandler()
+		defer cancel()
+
+		// Score the combined results through the resume path, which merges
+		// previous results and regenerates every run artifact.
+		applyRunConfig(&merged.runCfg)
+		evalMergingShards = true
+		defer func() { evalMergingShards = false }()
+		spec := shardRunSpec(&merged.runCfg)
+		summary, _, err := evalRunSingle(
+			ctx, spec, sharedConfigFromGlobals(), allTasks, allTasks,
+			outputDir, merged.timestamp, r, true,
+			merged.results, merged.externalFailures, completedTasks, merged.attestation, &merged.runCfg,
+		)
+		if err != nil {
+			return err
+		}
+		if summary != nil {
+			if missing := len(merged.runCfg.TaskList) - len(summary.Results) - len(summary.ExternalFailures); missing > 0 {
+				fmt.Printf(" %d task(s) were not finished by any shard. To run them:\n", missing)
+				fmt.Printf("   ./sanity eval --resume %s\n\n", outputDir)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	mergeShardsCmd.Flags().StringVarP(&mergeShardsOutput, "output", "o", "", "merged run directory (default: eval-results/<timestamp>-<agent>-merged)")
+}
+
+// shardRun is one eval --shard run directory loaded for merging.
+type shardRun struct {
+	dir         string
+	runCfg      *RunConfig
+	summary     *EvalSummary
+	attestation *EvalAttestation
+}
+
+// mergedShards is the combination of several shardRuns.
+type mergedShards struct {
+	runCfg           RunConfig
+	timestamp        string
+	results          []EvalResult
+	externalFailures []ExternalFailure
+	attestation      *EvalAttestation
+}
+
+func loadShardRun(dir string) (shardRun, error) {
+	runCfg, err := loadRunConfig(dir)
+	if err != nil {
+		return shardRun{}, fmt.Errorf("loading run config from %s: %w", dir, err)
+	}
+	summary, err := loadSummaryFromDir(dir)
+	if err != nil {
+		return shardRun{}, fmt.Errorf("loading summary from %s: %w", dir, err)
+	}
+	attestation, err := loadPreviousAttestation(dir)
+	if err != nil {
+		return shardRun{}, fmt.Errorf("loading attestation from %s: %w", dir, err)
+	}
+	return shardRun{dir: dir, runCfg: runCfg, summary: summary, attestation: attestation}, nil
+}
+
+// mergeShardRuns checks that shards are distinct shards of the same eval
+// and combines their task lists, results and attestation task hashes. The
+// merged run config is the first shard's without the shard.
+func mergeShardRuns(shards []shardRun) (mergedShards, error) {
+	first := shards[0]
+	_, total, err := parseShard(first.runCfg.Shard)
+	if err != nil {
+		return mergedShards{}, fmt.Errorf("%s is not a --shard run: %w", first.dir, err)
+	}
+
+	merged := mergedShards{runCfg: *first.runCfg, timestamp: first.summary.Timestamp}
+	merged.runCfg.Shard = ""
+	merged.runCfg.TaskList = nil
+	merged.runCfg.CreatedAt = time.Now().Format(time.RFC3339)
+	seenShards := make(map[int]string)
+	seenTasks := make(map[string]bool)
+	for _, s := range shards {
+		shard, shards, err := parseShard(s.runCfg.Shard)
+		if err != nil {
+			return mergedShards{}, fmt.Errorf("%s is not a --shard run: %w", s.dir, err)
+		}
+		if shards != total {
+			return mergedShards{}, fmt.Errorf("%s is shard %s but %s is shard %s", s.dir, s.runCfg.Shard, first.dir, first.runCfg.Shard)
+		}
+		if prev, dup := seenShards[shard]; dup {
+			return mergedShards{}, fmt.Errorf("%s and %s are both shard %s", prev, s.dir, s.runCfg.Shard)
+		}
+		seenShards[shard] = s.dir
+		if spec, firstSpec := shardRunSpec(s.runCfg), shardRunSpec(first.runCfg); spec != firstSpec {
+			return mergedShards{}, fmt.Errorf("%s ran %s but %s ran %s", s.dir, runSpecLabel(spec), first.dir, runSpecLabel(firstSpec))
+		}
+		if diff := shardConfigDiff(first.runCfg, s.runCfg); len(diff) > 0 {
+			return mergedShards{}, fmt.Errorf("%s and %s were run with different settings: %s", first.dir, s.dir, strings.Join(diff, ", "))
+		}
+
+		for _, id := range s.runCfg.TaskList {
+			if !seenTasks[id] {
+				seenTasks[id] = true
+				merged.runCfg.TaskList = append(merged.runCfg.TaskList, id)
+			}
+		}
+		merged.results = append(merged.results, s.summary.Results...)
+		merged.externalFailures = append(merged.externalFailures, s.summary.ExternalFailures...)
+		if s.attestation != nil {
+			if merged.attestation == nil {
+				merged.attestation = &EvalAttestation{Eval: s.attestation.Eval, Tasks: make(map[string]AttestationTask)}
+			}
+			for id, at := range s.attestation.Tasks {
+				if prev, ok := merged.attestation.Tasks[id]; ok && prev.TaskHash != at.TaskHash {
+					return mergedShards{}, fmt.Errorf("shards ran different versions of task %s (task_hash %s and %s)", id, prev.TaskHash, at.TaskHash)
+				}
+				merged.attestation.Tasks[id] = at
+			}
+		}
+	}
+	if len(seenShards) < total {
+		fmt.Printf(" Warning: merging %d of %d shards\n", len(seenShards), total)
+	}
+	return merged, nil
+}
+
+// shardRunSpec returns the agent, model and reasoning a shard ran.
+func shardRunSpec(runCfg *RunConfig) RunSpec {
+	return RunSpec{Agent: runCfg.Agent, Model: runCfg.Model, Reasoning: runCfg.Reasoning}
+}
+
+// shardIndependentConfigKeys are the run-config.json keys that may differ
+// between shards of one eval.
+var shardIndependentConfigKeys = []string{"shard", "task_list", "created_at", "parallel"}
+
+// shardConfigDiff returns the run-config.json keys whose values differ
+// between a and b, ignoring shardIndependentConfigKeys.
+func shardConfigDiff(a, b *RunConfig) []string {
+	am, bm := runConfigFields(a), runConfigFields(b)
+	var diff []string
+	for key := range am {
+		if _, ok := bm[key]; !ok {
+			bm[key] = nil
+		}
+	}
+	for key, bv := range bm {
+		if slices.Contains(shardIndependentConfigKeys, key) {
+			continue
+		}
+		if !bytes.Equal(am[key], bv) {
+			diff = append(diff, key)
+		}
+	}
+	slices.Sort(diff)
+	return diff
+}
+
+func runConfigFields(runCfg *RunConfig) map[string]json.RawMessage {
+	fields := make(map[string]json.RawMessage)
+	if data, err := json.Marshal(runCfg); err == nil {
+		_ = json.Unmarshal(data, &fields)
+	}
+	return fields
+}
+
+// checkShardTaskHashes fails when a shard attests a task whose task_hash
+// differs from the current task files, since the merged results are scored
+// against the current tasks.
+func checkShardTaskHashes(shards []shardRun, loader *task.Loader, allTasks []*task.Task) error {
+	current := make(map[string]string, len(allTasks))
+	for _, t := range allTasks {
+		current[t.ID()] = hashBytes(loader.HashContent(t))
+	}
+	for _, s := range shards {
+		if s.attestation == nil {
+			continue
+		}
+		for id, at := range s.attestation.Tasks {
+			if hash, ok := current[id]; ok && at.TaskHash != hash {
+				return fmt.Errorf("%s ran a different version of task %s (task_hash %s, current %s)", s.dir, id, at.TaskHash, hash)
+			}
+		}
+	}
+	return nil
+}
+
+// copyShardTaskDirs copies the task directories of a shard run into dst.
+// Run-level files are regenerated by the merge and are not copied.
+func copyShardTaskDirs(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", src, err)
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if err := copyDirContents(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name())); err != nil {
+			return fmt.Errorf("copying %s: %w", filepath.Join(src, e.Name()), err)
+		}
+	}
+	return nil
+}
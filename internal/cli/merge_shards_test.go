@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeShardRunsChecksShardsAgree(t *testing.T) {
+	t.Parallel()
+
+	shard := func(dir, shard string, timeout int, hashes map[string]string) shardRun {
+		attestation := &EvalAttestation{Tasks: make(map[string]AttestationTask)}
+		for id, hash := range hashes {
+			attestation.Tasks[id] = AttestationTask{TaskHash: hash}
+		}
+		return shardRun{
+			dir:         dir,
+			runCfg:      &RunConfig{Agent: "codex", Timeout: timeout, Parallel: 1, Shard: shard, TaskList: []string{dir}},
+			summary:     &EvalSummary{Timestamp: "2026-01-01T000000"},
+			attestation: attestation,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		shards  []shardRun
+		wantErr string
+	}{
+		{
+			name: "agreeing shards",
+			shards: []shardRun{
+				shard("a", "1/2", 600, map[string]string{"go/a": "blake3:aa"}),
+				shard("b", "2/2", 600, map[string]string{"go/b": "blake3:bb"}),
+			},
+		},
+		{
+			name: "different settings",
+			shards: []shardRun{
+				shard("a", "1/2", 600, nil),
+				shard("b", "2/2", 300, nil),
+			},
+			wantErr: "different settings: timeout",
+		},
+		{
+			name: "different task hash",
+			shards: []shardRun{
+				shard("a", "1/2", 600, map[string]string{"go/a": "blake3:aa"}),
+				shard("b", "2/2", 600, map[string]string{"go/a": "blake3:edited"}),
+			},
+			wantErr: "different versions of task go/a",
+		},
+	}
+	for _, tt := range tests {
+		_, err := mergeShardRuns(tt.shards)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestShardConfigDiffIgnoresShardFields(t *testing.T) {
+	t.Parallel()
+
+	a := &RunConfig{Agent: "codex", Shard: "1/2", Parallel: 4, TaskList: []string{"go/a"}, CreatedAt: "x"}
+	b := &RunConfig{Agent: "codex", Shard: "2/2", Parallel: 1, TaskList: []string{"go/b"}, CreatedAt: "y", Lint: true}
+	if diff := shardConfigDiff(a, b); len(diff) != 1 || diff[0] != "lint" {
+		t.Fatalf("diff = %v, want [lint]", diff)
+	}
+}
@@ -20,3 +20,16 @@ func setupProcessGroup(cmd *exec.Cmd) {
 		return nil
 	}
 }
+
+// terminateProcessGroup sends SIGTERM to the command's process group, giving
+// cooperative agents a chance to flush work before the hard kill. It reports
+// whether the signal was delivered.
+func terminateProcessGroup(cmd *exec.Cmd) (bool, error) {
+	if cmd.Process == nil {
+		return false, nil
+	}
+	if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM); err != nil {
+		return false, err
+	}
+	return true, nil
+}
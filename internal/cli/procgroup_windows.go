@@ -8,3 +8,8 @@ import "os/exec"
 // supported in the same way; the context cancellation will still kill the
 // direct child process.
 func setupProcessGroup(_ *exec.Cmd) {}
+
+// terminateProcessGroup is a no-op on Windows, which has no SIGTERM
+// equivalent for process trees; the hard kill at the deadline still applies.
+// It reports that no signal was sent.
+func terminateProcessGroup(_ *exec.Cmd) (bool, error) { return false, nil }
@@ -6,18 +6,23 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 
 	"github.com/spf13/cobra"
 
 	"github.com/lemon07r/sanityharness/internal/config"
+	"github.com/lemon07r/sanityharness/internal/task"
 )
 
 var (
-	cfgFile  string
-	tasksDir string
-	verbose  bool
-	cfg      *config.Config
-	logger   *slog.Logger
+	cfgFile      string
+	profile      string
+	tasksDir     string
+	dockerHost   string
+	verbose      bool
+	strictConfig bool
+	cfg          *config.Config
+	logger       *slog.Logger
 )
 
 // rootCmd represents the base command.
@@ -50,12 +55,53 @@ Features:
 			Level: level,
 		}))
 
+		// config validate loads the config itself to report every problem
+		// instead of stopping at the first.
+		if cmd == configValidateCmd {
+			return nil
+		}
+
 		// Load config
-		var err error
-		cfg, err = config.Load(cfgFile)
+		loaded, report, err := config.LoadProfileReport(cfgFile, profile)
 		if err != nil {
 			return fmt.Errorf("loading config: %w", err)
 		}
+		if err := checkUnknownConfigKeys(logger, report, strictConfig); err != nil {
+			return err
+		}
+		cfg = loaded
+		if dockerHost != "" {
+			cfg.Docker.Host = dockerHost
+		}
+		for _, w := range cfg.Harness.Retry.Warnings() {
+			logger.Warn("config [harness.retry]: " + w)
+		}
+		for name, ac := range cfg.Agents {
+			if err := ac.Validate(); err != nil {
+				return fmt.Errorf("config [agents.%s]: %w", name, err)
+			}
+			for _, w := range ac.Warnings() {
+				logger.Warn(fmt.Sprintf("config [agents.%s]: %s", name, w))
+			}
+		}
+		// Make config-defined languages known to task loading and parsing.
+		for name, lc := range cfg.Languages {
+			if err := lc.Parse.Validate(); err != nil {
+				return fmt.Errorf("config [languages.%s.parse]: %w", name, err)
+			}
+			// Built-in languages may only add a parse table.
+			if slices.Contains(task.AllLanguages, task.Language(name)) &&
+				lc.Image == "" && lc.ToolchainInfo == "" && lc.Extension == "" && len(lc.ValidationCommand) == 0 {
+				continue
+			}
+			if err := task.RegisterLanguage(task.CustomLanguage{
+				Name:              task.Language(name),
+				Extension:         lc.Extension,
+				ValidationCommand: lc.ValidationCommand,
+			}); err != nil {
+				return fmt.Errorf("config [languages.%s]: %w", name, err)
+			}
+		}
 
 		return nil
 	},
@@ -75,8 +121,11 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default: ./sanity.toml)")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "config profile to apply from [profiles.<name>] (default: $SANITY_PROFILE)")
 	rootCmd.PersistentFlags().StringVar(&tasksDir, "tasks-dir", "", "external tasks directory (for development)")
+	rootCmd.PersistentFlags().StringVar(&dockerHost, "docker-host", "", "Docker endpoint to run validation on, e.g. tcp://host:2375 (overrides [docker] host)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().BoolVar(&strictConfig, "strict-config", false, "fail instead of warning when the config file has unknown keys")
 
 	// Add subcommands
 	rootCmd.AddCommand(listCmd)
@@ -88,6 +137,9 @@ func init() {
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(compareCmd)
 	rootCmd.AddCommand(batchCmd)
+	rootCmd.AddCommand(statsDiffCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(mergeShardsCmd)
 }
 
 // Version information (set by build flags).
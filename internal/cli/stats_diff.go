@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var statsDiffJSON bool
+
+var statsDiffCmd = &cobra.Command{
+	Use:   "stats-diff <dirA> <dirB>",
+	Short: "Compare the repeat statistics of two multi-run directories",
+	Long: `Compare the repeat-stats.json files of two multi-run directories produced
+with --repeat, e.g. the same agent before and after a provider change.
+
+For each configuration present in both directories, reports the difference in
+mean pass rate and mean weighted score (B - A) along with 95% confidence
+intervals for each mean. Non-overlapping intervals indicate a difference that
+is unlikely to be run-to-run noise. When each directory holds exactly one
+configuration they are compared even if agent/model differ.`,
+	Example: `  sanity stats-diff eval-results/multi-2026-02-01T100000 eval-results/multi-2026-02-08T100000
+  sanity stats-diff ./before ./after --json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		statsA, err := loadRepeatStats(args[0])
+		if err != nil {
+			return fmt.Errorf("loading repeat stats from %s: %w", args[0], err)
+		}
+		statsB, err := loadRepeatStats(args[1])
+		if err != nil {
+			return fmt.Errorf("loading repeat stats from %s: %w", args[1], err)
+		}
+
+		diffs := diffRepeatStats(statsA, statsB)
+		if len(diffs) == 0 {
+			return fmt.Errorf("no matching configurations between %s and %s", args[0], args[1])
+		}
+
+		if statsDiffJSON {
+			data, err := json.MarshalIndent(diffs, "", "  ")
+			if err != nil {
+				return fmt.Errorf("marshaling stats diff: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+		fmt.Print(buildStatsDiffReport(diffs))
+		return nil
+	},
+}
+
+func init() {
+	statsDiffCmd.Flags().BoolVar(&statsDiffJSON, "json", false, "output as JSON")
+}
+
+// StatsDiff compares the repeat statistics of one configuration across two
+// multi-run directories.
+type StatsDiff struct {
+	ConfigA       RunSpec    `json:"config_a"`
+	ConfigB       RunSpec    `json:"config_b"`
+	RunsA         int        `json:"runs_a"`
+	RunsB         int        `json:"runs_b"`
+	PassRate      MetricDiff `json:"pass_rate"`
+	WeightedScore MetricDiff `json:"weighted_score"`
+}
+
+// MetricDiff is a two-sample comparison of one metric. Confidence intervals
+// are 95% intervals for each mean; they are omitted when a side has fewer
+// than two runs.
+type MetricDiff struct {
+	MeanA      float64    `json:"mean_a"`
+	MeanB      float64    `json:"mean_b"`
+	Difference float64    `json:"difference"`
+	CIA        [2]float64 `json:"ci_a"`
+	CIB        [2]float64 `json:"ci_b"`
+	Overlap    bool       `json:"ci_overlap"`
+	Sufficient bool       `json:"sufficient_runs"`
+}
+
+// loadRepeatStats loads repeat-stats.json from a multi-run directory.
+func loadRepeatStats(dir string) ([]RepeatStats, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "repeat-stats.json"))
+	if err != nil {
+		return nil, fmt.Errorf("reading repeat-stats.json: %w", err)
+	}
+	var stats []RepeatStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, fmt.Errorf("parsing repeat-stats.json: %w", err)
+	}
+	return stats, nil
+}
+
+// diffRepeatStats pairs configurations by agent/model/reasoning and compares
+// them. A single configuration on each side is always paired.
+func diffRepeatStats(a, b []RepeatStats) []StatsDiff {
+	if len(a) == 1 && len(b) == 1 {
+		return []StatsDiff{compareRepeatStats(a[0], b[0])}
+	}
+
+	var diffs []StatsDiff
+	for _, sa := range a {
+		for _, sb := range b {
+			if sa.Config == sb.Config {
+				diffs = append(diffs, compareRepeatStats(sa, sb))
+				break
+			}
+		}
+	}
+	return diffs
+}
+
+func compareRepeatStats(a, b RepeatStats) StatsDiff {
+	return StatsDiff{
+		ConfigA:       a.Config,
+		ConfigB:       b.Config,
+		RunsA:         a.Runs,
+		RunsB:         b.Runs,
+		PassRate:      compareMetric(a.MeanPassRate, a.StdDevPassRate, a.Runs, b.MeanPassRate, b.StdDevPassRate, b.Runs),
+		WeightedScore: compareMetric(a.MeanWeightedScore, a.StdDevWeightedScore, a.Runs, b.MeanWeightedScore, b.StdDevWeightedScore, b.Runs),
+	}
+}
+
+func compareMetric(meanA, stddevA float64, runsA int, meanB, stddevB float64, runsB int) MetricDiff {
+	d := MetricDiff{
+		MeanA:      meanA,
+		MeanB:      meanB,
+		Difference: meanB - meanA,
+		Sufficient: runsA >= 2 && runsB >= 2,
+	}
+	if !d.Sufficient {
+		return d
+	}
+	d.CIA = meanConfidenceInterval(meanA, stddevA, runsA)
+	d.CIB = meanConfidenceInterval(meanB, stddevB, runsB)
+	d.Overlap = d.CIA[0] <= d.CIB[1] && d.CIB[0] <= d.CIA[1]
+	return d
+}
+
+// meanConfidenceInterval returns the 95% confidence interval of a mean from
+// the population standard deviation stored in repeat-stats.json.
+func meanConfidenceInterval(m, popStddev float64, n int) [2]float64 {
+	sampleStddev := popStddev * math.Sqrt(float64(n)/float64(n-1))
+	margin := tCritical95(n-1) * sampleStddev / math.Sqrt(float64(n))
+	return [2]float64{m - margin, m + margin}
+}
+
+// tCritical95Table holds two-sided 95% Student's t critical values for 1-30
+// degrees of freedom.
+var tCritical95Table = []float64{
+	12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+func tCritical95(df int) float64 {
+	if df < 1 {
+		return math.Inf(1)
+	}
+	if df <= len(tCritical95Table) {
+		return tCritical95Table[df-1]
+	}
+	return 1.96
+}
+
+// buildStatsDiffReport builds a human-readable stats diff report as a string.
+func buildStatsDiffReport(diffs []StatsDiff) string {
+	var sb strings.Builder
+
+	for _, d := range diffs {
+		labelA := runSpecLabel(d.ConfigA)
+		labelB := runSpecLabel(d.ConfigB)
+		if labelA == labelB {
+			fmt.Fprintf(&sb, "### Stats Diff — %s (A: %d runs, B: %d runs)\n\n", labelA, d.RunsA, d.RunsB)
+		} else {
+			fmt.Fprintf(&sb, "### Stats Diff — A: %s (%d runs) vs B: %s (%d runs)\n\n", labelA, d.RunsA, labelB, d.RunsB)
+		}
+		fmt.Fprintf(&sb, "| Metric | Mean A | Mean B | Diff (B-A) | 95%% CI A | 95%% CI B | Verdict |\n")
+		fmt.Fprintf(&sb, "|--------|--------|--------|------------|----------|----------|---------|\n")
+		writeMetricDiffRow(&sb, "Pass Rate", "%.1f%%", d.PassRate)
+		writeMetricDiffRow(&sb, "Weighted Score", "%.2f", d.WeightedScore)
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+func writeMetricDiffRow(sb *strings.Builder, name, format string, d MetricDiff) {
+	f := func(v float64) string { return fmt.Sprintf(format, v) }
+	ci := func(c [2]float64) string {
+		if !d.Sufficient {
+			return "-"
+		}
+		return f(c[0]) + " – " + f(c[1])
+	}
+	fmt.Fprintf(sb, "| %s | %s | %s | %s%s | %s | %s | %s |\n",
+		name, f(d.MeanA), f(d.MeanB), signPrefix(d.Difference), f(d.Difference), ci(d.CIA), ci(d.CIB), metricDiffVerdict(d))
+}
+
+func signPrefix(v float64) string {
+	if v > 0 {
+		return "+"
+	}
+	return ""
+}
+
+func metricDiffVerdict(d MetricDiff) string {
+	switch {
+	case !d.Sufficient:
+		return "insufficient runs (need ≥2 each)"
+	case d.Overlap:
+		return "not significant (CIs overlap)"
+	default:
+		return "significant (CIs do not overlap)"
+	}
+}
+
+func runSpecLabel(spec RunSpec) string {
+	label := spec.Agent
+	if spec.Model != "" {
+		label += " / " + spec.Model
+	}
+	if spec.Reasoning != "" {
+		label += " (" + spec.Reasoning + ")"
+	}
+	return label
+}
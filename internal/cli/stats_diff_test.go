@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDiffRepeatStats(t *testing.T) {
+	t.Parallel()
+
+	a := []RepeatStats{
+		{Config: RunSpec{Agent: "codex", Model: "gpt-5"}, Runs: 5, MeanPassRate: 50, StdDevPassRate: 2, MeanWeightedScore: 10, StdDevWeightedScore: 3},
+		{Config: RunSpec{Agent: "claude"}, Runs: 3, MeanPassRate: 70, StdDevPassRate: 1},
+	}
+	b := []RepeatStats{
+		{Config: RunSpec{Agent: "codex", Model: "gpt-5"}, Runs: 5, MeanPassRate: 80, StdDevPassRate: 2, MeanWeightedScore: 11, StdDevWeightedScore: 3},
+	}
+
+	diffs := diffRepeatStats(a, b)
+	if len(diffs) != 1 {
+		t.Fatalf("len(diffs) = %d, want 1", len(diffs))
+	}
+	d := diffs[0]
+	if d.PassRate.Difference != 30 {
+		t.Errorf("pass rate difference = %v, want 30", d.PassRate.Difference)
+	}
+	if d.PassRate.Overlap {
+		t.Errorf("pass rate CIs should not overlap: A=%v B=%v", d.PassRate.CIA, d.PassRate.CIB)
+	}
+	if !d.WeightedScore.Overlap {
+		t.Errorf("weighted score CIs should overlap: A=%v B=%v", d.WeightedScore.CIA, d.WeightedScore.CIB)
+	}
+
+	report := buildStatsDiffReport(diffs)
+	if !strings.Contains(report, "+30.0%") || !strings.Contains(report, "significant (CIs do not overlap)") {
+		t.Errorf("unexpected report:\n%s", report)
+	}
+}
+
+func TestDiffRepeatStatsSingleConfigsArePaired(t *testing.T) {
+	t.Parallel()
+
+	a := []RepeatStats{{Config: RunSpec{Agent: "opencode", Model: "provider-a/kimi"}, Runs: 1, MeanPassRate: 40}}
+	b := []RepeatStats{{Config: RunSpec{Agent: "opencode", Model: "provider-b/kimi"}, Runs: 1, MeanPassRate: 45}}
+
+	diffs := diffRepeatStats(a, b)
+	if len(diffs) != 1 {
+		t.Fatalf("len(diffs) = %d, want 1", len(diffs))
+	}
+	if diffs[0].PassRate.Sufficient {
+		t.Error("single-run stats should be marked insufficient")
+	}
+	if got := metricDiffVerdict(diffs[0].PassRate); !strings.HasPrefix(got, "insufficient") {
+		t.Errorf("verdict = %q, want insufficient", got)
+	}
+}
+
+func TestMeanConfidenceInterval(t *testing.T) {
+	t.Parallel()
+
+	// Two runs at 40 and 60: mean 50, population stddev 10, sample stddev ~14.14.
+	ci := meanConfidenceInterval(50, 10, 2)
+	wantMargin := 12.706 * (10 * math.Sqrt(2)) / math.Sqrt(2)
+	if math.Abs(ci[1]-50-wantMargin) > 1e-9 || math.Abs(50-ci[0]-wantMargin) > 1e-9 {
+		t.Errorf("ci = %v, want 50 ± %.3f", ci, wantMargin)
+	}
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	tasksListLang       string
+	tasksListTier       string
+	tasksListDifficulty string
+	tasksListJSON       bool
+)
+
+var tasksCmd = &cobra.Command{
+	Use:   "tasks",
+	Short: "Inspect the task suite",
+}
+
+var tasksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tasks with their weight and agent timeout",
+	Long: `Lists the tasks eval would select with the same --lang, --tier and
+--difficulty filters, with each task's weight and agent timeout.
+
+Examples:
+  sanity tasks list --tier all --lang go
+  sanity tasks list --difficulty hard,expert --json | jq -r '.[].id'`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskList, err := selectTaskList(tasksListLang, tasksListTier, tasksListDifficulty)
+		if err != nil {
+			return err
+		}
+		entries := taskListEntries(taskList, listTimeoutSeconds())
+		if tasksListJSON {
+			return outputTaskListJSON(os.Stdout, entries)
+		}
+		return outputTable(os.Stdout, entries)
+	},
+}
+
+func init() {
+	tasksListCmd.Flags().StringVar(&tasksListLang, "lang", "", "filter by language (go, rust, typescript, kotlin, dart, zig)")
+	tasksListCmd.Flags().StringVar(&tasksListTier, "tier", "all", "filter by tier (core, extended, all)")
+	tasksListCmd.Flags().StringVar(&tasksListDifficulty, "difficulty", "", "filter by difficulty (comma-separated)")
+	tasksListCmd.Flags().BoolVar(&tasksListJSON, "json", false, "output as JSON")
+	tasksCmd.AddCommand(tasksListCmd)
+	rootCmd.AddCommand(tasksCmd)
+}
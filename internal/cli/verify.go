@@ -14,6 +14,8 @@ import (
 	"github.com/lemon07r/sanityharness/tasks"
 )
 
+var verifyAllowVersionMismatch bool
+
 var verifyCmd = &cobra.Command{
 	Use:   "verify <eval-dir>",
 	Short: "Verify integrity of an eval submission",
@@ -21,13 +23,18 @@ var verifyCmd = &cobra.Command{
 
 This command checks:
   1. Results hash - ensures summary.json wasn't modified after generation
-  2. Task hashes - ensures tasks match your embedded version (same harness)
+  2. Tasks hash - ensures the per-task hashes in attestation.json weren't edited
+  3. Task hashes - ensures tasks match your embedded version (same harness)
+  4. Harness and weight versions - flags submissions from another version
 
-No tests are re-run; this only validates hash integrity.
+No tests are re-run; this only validates hash integrity. Exits nonzero when
+any hash check fails or the harness or weight version differs, so it can gate
+submissions in CI. --allow-version-mismatch turns version differences into
+warnings.
 
 Examples:
   sanity verify ./eval-results/2026-01-07T120000-gemini
-  sanity verify /path/to/submission`,
+  sanity verify /path/to/submission --allow-version-mismatch`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		evalDir := args[0]
@@ -94,6 +101,17 @@ Examples:
 			fmt.Printf("   Got:      %s\n", computedResultsHash)
 			failed++
 		}
+
+		computedTasksHash := attestationTasksHash(attestation, summary.Results)
+		if computedTasksHash == attestation.Integrity.TasksHash {
+			fmt.Println(" ✓ Tasks hash matches - per-task hashes are unmodified")
+			passed++
+		} else {
+			fmt.Println(" ✗ Tasks hash MISMATCH - attestation.json task hashes may have been tampered with")
+			fmt.Printf("   Expected: %s\n", attestation.Integrity.TasksHash)
+			fmt.Printf("   Got:      %s\n", computedTasksHash)
+			failed++
+		}
 		fmt.Println()
 
 		// 2. Verify task hashes against our embedded tasks
@@ -125,14 +143,8 @@ Examples:
 				continue
 			}
 
-			// Compute hash of our embedded task files
-			var taskFileContents []byte
-			for _, f := range append(append(t.Files.Stub, t.Files.Test...), t.Files.Support...) {
-				if content, err := loader.ReadTaskFile(t, f); err == nil {
-					taskFileContents = append(taskFileContents, content...)
-				}
-			}
-			ourTaskHash := verifyHashBytes(taskFileContents)
+			// Compute hash of our embedded task files and prompt hint
+			ourTaskHash := verifyHashBytes(loader.HashContent(t))
 
 			if ourTaskHash == taskAttest.TaskHash {
 				taskMatches++
@@ -167,14 +179,36 @@ Examples:
 		fmt.Println(" Version Compatibility")
 		fmt.Println("─────────────────────────────────────────────────────────────")
 
+		// A version mismatch fails unless --allow-version-mismatch makes it
+		// a warning.
+		mark := "✗"
+		if verifyAllowVersionMismatch {
+			mark = "!"
+		}
+		versionMismatch := func() {
+			if verifyAllowVersionMismatch {
+				warnings++
+			} else {
+				failed++
+			}
+		}
 		if attestation.Harness.Version == Version {
 			fmt.Printf(" ✓ Harness version matches (%s)\n", Version)
 			passed++
 		} else {
-			fmt.Printf(" ! Harness version differs (theirs: %s, yours: %s)\n",
-				attestation.Harness.Version, Version)
+			fmt.Printf(" %s Harness version differs (theirs: %s, yours: %s)\n",
+				mark, attestation.Harness.Version, Version)
 			fmt.Println("   Task hashes may differ due to version mismatch")
-			warnings++
+			versionMismatch()
+		}
+		if attestation.Harness.WeightVersion == task.WeightVersion {
+			fmt.Printf(" ✓ Weight version matches (%s)\n", task.WeightVersion)
+			passed++
+		} else {
+			fmt.Printf(" %s Weight version differs (theirs: %s, yours: %s)\n",
+				mark, attestation.Harness.WeightVersion, task.WeightVersion)
+			fmt.Println("   Weighted scores are not comparable across weight versions")
+			versionMismatch()
 		}
 		fmt.Println()
 
@@ -210,10 +244,26 @@ Examples:
 		fmt.Printf(" Pass Rate: %.1f%% (%d/%d)\n", summary.PassRate, summary.Passed, summary.Total)
 		fmt.Println()
 
+		if failed > 0 {
+			return &exitError{code: 1}
+		}
 		return nil
 	},
 }
 
+// attestationTasksHash recomputes the attestation's tasks hash: the hash of
+// the per-task hashes concatenated in result order, as generateAttestation
+// builds it.
+func attestationTasksHash(attestation EvalAttestation, results []EvalResult) string {
+	var all []byte
+	for _, r := range results {
+		if at, ok := attestation.Tasks[r.Task]; ok {
+			all = append(all, at.TaskHash...)
+		}
+	}
+	return verifyHashBytes(all)
+}
+
 // verifyHashBytes returns the BLAKE3 hash of data as a prefixed hex string.
 func verifyHashBytes(data []byte) string {
 	h := blake3.Sum256(data)
@@ -221,5 +271,6 @@ func verifyHashBytes(data []byte) string {
 }
 
 func init() {
+	verifyCmd.Flags().BoolVar(&verifyAllowVersionMismatch, "allow-version-mismatch", false, "warn instead of failing when the harness or weight version differs")
 	rootCmd.AddCommand(verifyCmd)
 }
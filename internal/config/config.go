@@ -2,10 +2,15 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
 	"sort"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 )
@@ -14,14 +19,74 @@ import (
 type AgentConfig struct {
 	Command               string            `toml:"command"`                 // Binary name or path
 	Args                  []string          `toml:"args"`                    // Args with {prompt} placeholder
+	ShellCommand          string            `toml:"shell_command"`           // sh -c template with {prompt}, {model}, {reasoning}; replaces command and args
 	ModelFlag             string            `toml:"model_flag"`              // e.g., "--model", "-m"
 	ModelFlagPosition     string            `toml:"model_flag_position"`     // "before" or "after" {prompt} in args (default: "before")
 	ReasoningFlag         string            `toml:"reasoning_flag"`          // e.g., "-r", "--reasoning-effort"
 	ReasoningFlagPosition string            `toml:"reasoning_flag_position"` // "before" or "after" {prompt} in args (default: "before")
 	Env                   map[string]string `toml:"env"`                     // Environment variables
+	EnvFile               string            `toml:"env_file"`                // Dotenv file of KEY=VALUE pairs; env entries take precedence
 	DefaultTimeout        int               `toml:"default_timeout"`         // Per-agent minimum timeout in seconds (overrides harness default if larger)
+	ReasoningTimeouts     map[string]int    `toml:"reasoning_timeouts"`      // Per-reasoning-level minimum timeout in seconds, replacing default_timeout for that level
 	MCPPrompt             string            `toml:"mcp_prompt,omitempty"`    // Agent-specific MCP tool guidance (appended when --use-mcp-tools is set)
 	PromptPrefix          string            `toml:"prompt_prefix,omitempty"` // Prefix prepended to the prompt (e.g., "ulw" for ultrawork mode)
+	SystemPromptFlag      string            `toml:"system_prompt_flag"`      // e.g., "--system-prompt"; supports {value}
+	VersionCommand        []string          `toml:"version_command"`         // Full command printing the agent version (default: <command> --version)
+	TrustExitCode         bool              `toml:"trust_exit_code"`         // Treat a non-zero exit (not a timeout) as an agent failure: retried as infra, then scored as agent_exit
+	TimestampPattern      string            `toml:"timestamp_pattern"`       // Regex locating a timestamp in agent log lines (first capture group, else the match)
+	TimestampLayout       string            `toml:"timestamp_layout"`        // Go time layout for timestamp_pattern, or "unix" (default: RFC 3339)
+	TokenPatterns         []string          `toml:"token_patterns"`          // Regexes for token counts in agent logs (first capture group, else the match); replaces the built-in set
+}
+
+// MinTimeout returns the agent's minimum timeout in seconds for the given
+// reasoning level: its reasoning_timeouts entry when set, else
+// default_timeout.
+func (a AgentConfig) MinTimeout(reasoning string) int {
+	if seconds := a.ReasoningTimeouts[reasoning]; seconds > 0 {
+		return seconds
+	}
+	return a.DefaultTimeout
+}
+
+// Executable returns the program that runs the agent: sh for a
+// shell_command agent, else command.
+func (a AgentConfig) Executable() string {
+	if a.ShellCommand != "" {
+		return "sh"
+	}
+	return a.Command
+}
+
+// Validate reports whether the agent's timestamp_pattern and token_patterns
+// compile.
+func (a AgentConfig) Validate() error {
+	if _, err := regexp.Compile(a.TimestampPattern); err != nil {
+		return fmt.Errorf("timestamp_pattern: %w", err)
+	}
+	for _, p := range a.TokenPatterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("token_patterns: %w", err)
+		}
+	}
+	return nil
+}
+
+// Warnings reports agent settings eval cannot follow: nothing to run, or a
+// flag position other than "before" or "after".
+func (a AgentConfig) Warnings() []string {
+	var warnings []string
+	if a.Command == "" && a.ShellCommand == "" {
+		warnings = append(warnings, "command (or shell_command) is required")
+	}
+	for _, f := range []struct{ key, value string }{
+		{"model_flag_position", a.ModelFlagPosition},
+		{"reasoning_flag_position", a.ReasoningFlagPosition},
+	} {
+		if f.value != "" && f.value != "before" && f.value != "after" {
+			warnings = append(warnings, fmt.Sprintf("%s must be \"before\" or \"after\", got %q", f.key, f.value))
+		}
+	}
+	return warnings
 }
 
 // DefaultAgents provides built-in configurations for popular coding agents.
@@ -64,6 +129,7 @@ var DefaultAgents = map[string]AgentConfig{
 		Args:              []string{"-p", "--dangerously-skip-permissions", "{prompt}"},
 		ModelFlag:         "--model",
 		ModelFlagPosition: "before",
+		SystemPromptFlag:  "--append-system-prompt",
 	},
 	"codex": {
 		Command:               "codex",
@@ -180,14 +246,88 @@ type Config struct {
 	Docker  DockerConfig           `toml:"docker"`
 	Sandbox SandboxConfig          `toml:"sandbox"`
 	Agents  map[string]AgentConfig `toml:"agents"`
+
+	// Languages defines languages beyond the built-in ones, keyed by the
+	// name tasks use in their language field and directory.
+	Languages map[string]LanguageConfig `toml:"languages"`
+
+	// Profile is the name of the [profiles.<name>] section merged over the
+	// base config, if any.
+	Profile string `toml:"-"`
 }
 
+// ProfileEnvVar selects a config profile when --profile is not given.
+const ProfileEnvVar = "SANITY_PROFILE"
+
 // HarnessConfig contains harness-specific settings.
 type HarnessConfig struct {
-	SessionDir     string `toml:"session_dir"`
-	DefaultTimeout int    `toml:"default_timeout"`
-	MaxAttempts    int    `toml:"max_attempts"`
-	OutputFormat   string `toml:"output_format"`
+	SessionDir     string   `toml:"session_dir"`
+	DefaultTimeout int      `toml:"default_timeout"`
+	MaxAttempts    int      `toml:"max_attempts"`
+	OutputFormat   string   `toml:"output_format"`
+	MinFreeDiskMB  int      `toml:"min_free_disk_mb"` // Abort eval when the output dir has less free space (0 = disabled)
+	MaxAgentLogMB  int      `toml:"max_agent_log_mb"` // Truncate agent.log at this size (0 = built-in 256 MiB; see --agent-log-max-bytes)
+	SystemPrompt   string   `toml:"system_prompt"`    // Default system message for eval (see --system-prompt)
+	SkipLangs      []string `toml:"skip_langs"`       // Languages eval skips by default (see --skip-langs)
+	CopyIgnoreDirs []string `toml:"copy_ignore_dirs"` // Directory names (e.g. VCS metadata) not copied back from agent workspaces
+	OutputTemplate string   `toml:"output_template"`  // Eval run directory under eval-results, e.g. "{agent}/{model}/{date}"
+	DefaultAgent   string   `toml:"default_agent"`    // Agent eval uses when --agent is omitted
+
+	// RefusalPatterns replaces the phrases eval matches in agent logs to
+	// classify a model declining the task as a refusal.
+	RefusalPatterns []string `toml:"refusal_patterns"`
+
+	// ConfirmDangerousAgents makes eval ask before running agents whose args
+	// skip permission prompts (see --i-understand-costs).
+	ConfirmDangerousAgents bool `toml:"confirm_dangerous_agents"`
+
+	// ConsecutiveInfraStopThreshold stops eval (resumably) after this many
+	// tasks in a row infra-fail, e.g. because Docker died (0 = disabled).
+	ConsecutiveInfraStopThreshold int `toml:"consecutive_infra_stop_threshold"`
+
+	// Retry overrides eval's quota and infra retry schedules.
+	Retry RetryConfig `toml:"retry"`
+
+	// LanguageTimeouts replaces default_timeout (or --timeout) for tasks in
+	// the named languages, in seconds. Per-task timeouts still take
+	// precedence.
+	LanguageTimeouts map[string]int `toml:"language_timeouts"`
+}
+
+// RetryConfig is the [harness.retry] section. Delays are in seconds, one per
+// retry; retries past the end of a list reuse its last delay. Empty lists and
+// zero max_retries keep eval's built-in schedules.
+type RetryConfig struct {
+	QuotaDelays     []int `toml:"quota_delays"`      // Delays before quota (rate-limit) retries
+	InfraDelays     []int `toml:"infra_delays"`      // Delays before infra (no response) retries
+	QuotaMaxRetries int   `toml:"quota_max_retries"` // Quota-failed attempts before a task gives up
+	InfraMaxRetries int   `toml:"infra_max_retries"` // Infra-failed attempts before a task gives up
+
+	// Jitter randomizes each quota and infra delay by up to this fraction
+	// either way, so parallel tasks do not retry in lockstep (0 = off).
+	Jitter float64 `toml:"jitter"`
+}
+
+// Warnings reports retry settings eval ignores or cannot follow as written.
+func (r RetryConfig) Warnings() []string {
+	var warnings []string
+	check := func(kind string, delays []int, maxRetries int) {
+		if slices.ContainsFunc(delays, func(d int) bool { return d < 0 }) {
+			warnings = append(warnings, fmt.Sprintf("%s_delays contains a negative delay; using the default schedule", kind))
+		}
+		if maxRetries < 0 {
+			warnings = append(warnings, fmt.Sprintf("%s_max_retries is negative; using the default", kind))
+		}
+		if len(delays) > 0 && maxRetries > len(delays) {
+			warnings = append(warnings, fmt.Sprintf("%s_max_retries (%d) exceeds the %d %s_delays; later retries reuse the last delay", kind, maxRetries, len(delays), kind))
+		}
+	}
+	check("quota", r.QuotaDelays, r.QuotaMaxRetries)
+	check("infra", r.InfraDelays, r.InfraMaxRetries)
+	if r.Jitter < 0 || r.Jitter > 1 {
+		warnings = append(warnings, fmt.Sprintf("jitter (%g) is outside 0-1; clamping it", r.Jitter))
+	}
+	return warnings
 }
 
 // SandboxConfig contains bubblewrap sandbox settings.
@@ -196,6 +336,37 @@ type SandboxConfig struct {
 	ReadableDenylist    []string `toml:"readable_denylist"`     // Repo-relative or absolute paths to hide from agents
 	SharedReadWriteDirs []string `toml:"shared_readwrite_dirs"` // Broad shared allowlist mounted read/write (home-relative or absolute)
 	SharedReadOnlyDirs  []string `toml:"shared_readonly_dirs"`  // Broad shared allowlist mounted read-only (home-relative or absolute)
+	BwrapPath           string   `toml:"bwrap_path"`            // bubblewrap binary name or path (default: "bwrap" on PATH)
+	ExtraArgs           []string `toml:"extra_args"`            // Extra bwrap args inserted before the "--" separator
+	AllowNetwork        bool     `toml:"allow_network"`         // Share the host network with agents (default: true)
+}
+
+// LanguageConfig defines a custom language and its toolchain.
+type LanguageConfig struct {
+	Image             string      `toml:"image"`              // Validation container image
+	ToolchainInfo     string      `toml:"toolchain_info"`     // Toolchain description shown to agents, e.g. "GCC 13"
+	Extension         string      `toml:"extension"`          // Primary source file extension, e.g. ".cpp"
+	ValidationCommand []string    `toml:"validation_command"` // Default validation command for tasks without [validation]
+	Parse             ParseConfig `toml:"parse"`              // Test count patterns for validation output
+}
+
+// ParseConfig holds regexes that extract test counts from validation output.
+// A line matching a pattern counts as one test unless the pattern's first
+// capture group holds a number, which is added instead.
+type ParseConfig struct {
+	TestPassPattern string `toml:"test_pass_pattern"`
+	TestFailPattern string `toml:"test_fail_pattern"`
+}
+
+// Validate reports whether the configured patterns compile.
+func (p ParseConfig) Validate() error {
+	if _, err := regexp.Compile(p.TestPassPattern); err != nil {
+		return fmt.Errorf("test_pass_pattern: %w", err)
+	}
+	if _, err := regexp.Compile(p.TestFailPattern); err != nil {
+		return fmt.Errorf("test_fail_pattern: %w", err)
+	}
+	return nil
 }
 
 // DockerConfig contains Docker-related settings.
@@ -207,6 +378,16 @@ type DockerConfig struct {
 	DartImage       string `toml:"dart_image"`
 	ZigImage        string `toml:"zig_image"`
 	AutoPull        bool   `toml:"auto_pull"`
+
+	// Resource limits for validation containers (0 = unlimited).
+	MemoryLimitMB int     `toml:"memory_limit_mb"`
+	CPUQuota      float64 `toml:"cpu_quota"` // CPUs, e.g. 1.5
+
+	// Host is the Docker endpoint validation runs on, e.g.
+	// "tcp://build-box:2375". Empty uses the environment (DOCKER_HOST or the
+	// local socket). A remote host gets workspaces copied in instead of
+	// bind-mounted.
+	Host string `toml:"host"`
 }
 
 // Default configuration values.
@@ -216,6 +397,11 @@ var Default = Config{
 		DefaultTimeout: 600,
 		MaxAttempts:    5,
 		OutputFormat:   "all",
+		CopyIgnoreDirs: []string{".git", ".hg"},
+
+		ConfirmDangerousAgents:        true,
+		ConsecutiveInfraStopThreshold: 3,
+		Retry:                         RetryConfig{Jitter: 0.2},
 	},
 	Docker: DockerConfig{
 		GoImage:         "ghcr.io/lemon07r/sanity-go:latest",
@@ -227,6 +413,7 @@ var Default = Config{
 		AutoPull:        true,
 	},
 	Sandbox: SandboxConfig{
+		AllowNetwork: true,
 		// Compatibility-focused shared allowlist: keep common auth/config/cache/toolchain
 		// paths writable while masking high-risk read locations in the sandbox layer.
 		SharedReadWriteDirs: []string{
@@ -293,13 +480,52 @@ func configPaths() []string {
 // If configFile is empty, it searches standard locations.
 // Returns default config if no file is found.
 func Load(configFile string) (*Config, error) {
+	return LoadProfile(configFile, "")
+}
+
+// LoadProfile is like Load but deep-merges the [profiles.<profile>] section
+// over the base config. An empty profile falls back to $SANITY_PROFILE; when
+// neither is set, profiles are ignored.
+func LoadProfile(configFile, profile string) (*Config, error) {
+	cfg, _, err := LoadProfileReport(configFile, profile)
+	return cfg, err
+}
+
+// LoadReport describes how LoadProfileReport resolved a config.
+type LoadReport struct {
+	Path      string   // File loaded; empty when only defaults apply
+	Defaulted []string // [harness], [docker] and [sandbox] keys the file does not set, e.g. "harness.default_timeout"
+	Unknown   []UnknownKey
+}
+
+// UnknownKey is a key in the config file that matches no setting, e.g. a
+// misspelled "harness.default_timout". The decoder ignores such keys.
+type UnknownKey struct {
+	Key  string // Dotted key path
+	Line int    // Line in the config file; 0 if it could not be located
+}
+
+func (k UnknownKey) String() string {
+	if k.Line == 0 {
+		return k.Key
+	}
+	return fmt.Sprintf("%s (line %d)", k.Key, k.Line)
+}
+
+// LoadProfileReport is like LoadProfile and also reports which file was
+// loaded, which settings kept their defaults and which keys were ignored.
+func LoadProfileReport(configFile, profile string) (*Config, *LoadReport, error) {
 	cfg := Default // Start with defaults
+	report := &LoadReport{}
+	if profile == "" {
+		profile = os.Getenv(ProfileEnvVar)
+	}
 
 	var path string
 	if configFile != "" {
 		path = configFile
 		if _, err := os.Stat(path); err != nil {
-			return nil, fmt.Errorf("config file not found: %s", path)
+			return nil, nil, fmt.Errorf("config file not found: %s", path)
 		}
 	} else {
 		for _, p := range configPaths() {
@@ -311,11 +537,26 @@ func Load(configFile string) (*Config, error) {
 	}
 
 	if path == "" {
-		return &cfg, nil
+		if profile != "" {
+			return nil, nil, fmt.Errorf("config profile %q requested but no config file found", profile)
+		}
+		report.Defaulted = defaultedKeys(toml.MetaData{})
+		return &cfg, report, nil
 	}
 
-	if _, err := toml.DecodeFile(path, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading config %s: %w", path, err)
+	}
+	md, err := decodeConfigFile(path, data, profile, &cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	report.Path = path
+	report.Defaulted = defaultedKeys(md)
+	report.Unknown = unknownKeys(md, data, profile)
+	if cfg.Languages, err = normalizeLanguageKeys(cfg.Languages); err != nil {
+		return nil, nil, fmt.Errorf("config %s: %w", path, err)
 	}
 
 	// Ensure critical fields aren't zeroed out by partial config
@@ -347,12 +588,227 @@ func Load(configFile string) (*Config, error) {
 		cfg.Docker.ZigImage = Default.Docker.ZigImage
 	}
 
-	return &cfg, nil
+	return &cfg, report, nil
+}
+
+// defaultedKeys returns the keys of the [harness], [docker] and [sandbox]
+// tables that md does not define.
+func defaultedKeys(md toml.MetaData) []string {
+	var keys []string
+	for _, section := range []struct {
+		name string
+		typ  reflect.Type
+	}{
+		{"harness", reflect.TypeOf(HarnessConfig{})},
+		{"docker", reflect.TypeOf(DockerConfig{})},
+		{"sandbox", reflect.TypeOf(SandboxConfig{})},
+	} {
+		for i := range section.typ.NumField() {
+			name, _, _ := strings.Cut(section.typ.Field(i).Tag.Get("toml"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			if !md.IsDefined(section.name, name) {
+				keys = append(keys, section.name+"."+name)
+			}
+		}
+	}
+	return keys
+}
+
+// unknownKeys returns the keys md did not decode, located in data, followed
+// by those of the profiles other than profile. Keys nested under an unknown
+// table are folded into it, so a misspelled section is reported once.
+func unknownKeys(md toml.MetaData, data []byte, profile string) []UnknownKey {
+	var unknown []UnknownKey
+	for _, key := range foldUndecoded(md.Undecoded()) {
+		// Profiles are merged before decoding; the others are checked below.
+		if key[0] == "profiles" {
+			continue
+		}
+		line := keyLine(data, key)
+		if line == 0 && profile != "" {
+			line = keyLine(data, append(toml.Key{"profiles", profile}, key...))
+		}
+		unknown = append(unknown, UnknownKey{Key: key.String(), Line: line})
+	}
+	unknown = append(unknown, profileUnknownKeys(data, profile)...)
+	// A merged profile is re-encoded, so list keys in file order instead.
+	sort.SliceStable(unknown, func(i, j int) bool {
+		li, lj := unknown[i].Line, unknown[j].Line
+		return li != 0 && (lj == 0 || li < lj)
+	})
+	return unknown
+}
+
+// profileUnknownKeys returns the unknown keys of every [profiles.<name>]
+// section but active, whose keys were decoded with the config. Each overlay is
+// decoded on its own over the default config, so a typo in a profile that is
+// not selected is reported too.
+func profileUnknownKeys(data []byte, active string) []UnknownKey {
+	var raw map[string]any
+	if _, err := toml.Decode(string(data), &raw); err != nil {
+		return nil
+	}
+	profiles, _ := raw["profiles"].(map[string]any)
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var unknown []UnknownKey
+	for _, name := range names {
+		overlay, ok := profiles[name].(map[string]any)
+		if !ok || name == active {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(overlay); err != nil {
+			continue
+		}
+		cfg := Default
+		md, err := toml.Decode(buf.String(), &cfg)
+		if err != nil {
+			continue
+		}
+		for _, key := range foldUndecoded(md.Undecoded()) {
+			full := append(toml.Key{"profiles", name}, key...)
+			unknown = append(unknown, UnknownKey{Key: full.String(), Line: keyLine(data, full)})
+		}
+	}
+	return unknown
+}
+
+// foldUndecoded drops keys nested under another key in keys, which lists
+// parents before their children.
+func foldUndecoded(keys []toml.Key) []toml.Key {
+	var parents []toml.Key
+	for _, key := range keys {
+		if slices.ContainsFunc(parents, func(p toml.Key) bool {
+			return len(p) < len(key) && slices.Equal(p, key[:len(p)])
+		}) {
+			continue
+		}
+		parents = append(parents, key)
+	}
+	return parents
+}
+
+// keyLine returns the 1-based line of data that defines key, either as a
+// table header or as a key/value pair, or 0 if none does.
+func keyLine(data []byte, key toml.Key) int {
+	var table []string
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "["):
+			header, _, _ := strings.Cut(strings.TrimLeft(line, "["), "]")
+			table = splitKey(header)
+			if slices.Equal(table, key) {
+				return i + 1
+			}
+		case strings.HasPrefix(line, "#"):
+		default:
+			name, _, ok := strings.Cut(line, "=")
+			if ok && slices.Equal(append(slices.Clip(table), splitKey(name)...), key) {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+// splitKey splits a dotted TOML key into its parts, dropping quotes.
+func splitKey(s string) []string {
+	parts := strings.Split(s, ".")
+	for i, p := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(p), `"'`)
+	}
+	return parts
+}
+
+// decodeConfigFile decodes data, the contents of path, into cfg. When profile
+// is set, the file is decoded generically so the profile section can be
+// deep-merged over the base tables before decoding into the typed config.
+func decodeConfigFile(path string, data []byte, profile string, cfg *Config) (toml.MetaData, error) {
+	if profile == "" {
+		md, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return md, fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+		return md, nil
+	}
+
+	var raw map[string]any
+	if _, err := toml.Decode(string(data), &raw); err != nil {
+		return toml.MetaData{}, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	profiles, _ := raw["profiles"].(map[string]any)
+	overlay, ok := profiles[profile].(map[string]any)
+	if !ok {
+		return toml.MetaData{}, fmt.Errorf("config profile %q not found in %s", profile, path)
+	}
+	delete(raw, "profiles")
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(mergeTables(raw, overlay)); err != nil {
+		return toml.MetaData{}, fmt.Errorf("merging config profile %q: %w", profile, err)
+	}
+	md, err := toml.Decode(buf.String(), cfg)
+	if err != nil {
+		return md, fmt.Errorf("failed to parse config %s with profile %q: %w", path, profile, err)
+	}
+	cfg.Profile = profile
+	return md, nil
+}
+
+// mergeTables deep-merges overlay into base: nested tables are merged key by
+// key, while scalars and arrays in overlay replace the base value.
+func mergeTables(base, overlay map[string]any) map[string]any {
+	merged := make(map[string]any, len(base)+len(overlay))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overlay {
+		overlayTable, overlayIsTable := v.(map[string]any)
+		baseTable, baseIsTable := merged[k].(map[string]any)
+		if overlayIsTable && baseIsTable {
+			merged[k] = mergeTables(baseTable, overlayTable)
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// normalizeLanguageKeys lowercases the [languages.<name>] keys, matching the
+// names task.RegisterLanguage registers, so [languages.CPP] configures the
+// cpp language. Two keys differing only in case are an error.
+func normalizeLanguageKeys(langs map[string]LanguageConfig) (map[string]LanguageConfig, error) {
+	if len(langs) == 0 {
+		return langs, nil
+	}
+	normalized := make(map[string]LanguageConfig, len(langs))
+	for name, lc := range langs {
+		key := strings.ToLower(name)
+		if _, dup := normalized[key]; dup {
+			return nil, fmt.Errorf("[languages] defines %q more than once (names are case-insensitive)", key)
+		}
+		normalized[key] = lc
+	}
+	return normalized, nil
+}
+
+// LanguageConfig returns the [languages.<name>] entry for lang, matching the
+// name case-insensitively.
+func (c *Config) LanguageConfig(lang string) LanguageConfig {
+	return c.Languages[strings.ToLower(lang)]
 }
 
 // ImageForLanguage returns the Docker image for a given language.
 func (c *Config) ImageForLanguage(lang string) string {
-	switch lang {
+	switch strings.ToLower(lang) {
 	case "go":
 		return c.Docker.GoImage
 	case "rust":
@@ -366,7 +822,7 @@ func (c *Config) ImageForLanguage(lang string) string {
 	case "zig":
 		return c.Docker.ZigImage
 	default:
-		return ""
+		return c.LanguageConfig(lang).Image
 	}
 }
 
@@ -3,7 +3,12 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+
+	"github.com/BurntSushi/toml"
 )
 
 func TestDefault(t *testing.T) {
@@ -19,6 +24,12 @@ func TestDefault(t *testing.T) {
 	if Default.Harness.MaxAttempts <= 0 {
 		t.Errorf("default max attempts = %d, want > 0", Default.Harness.MaxAttempts)
 	}
+	if len(Default.Harness.CopyIgnoreDirs) == 0 {
+		t.Error("default copy_ignore_dirs should not be empty")
+	}
+	if Default.Harness.ConsecutiveInfraStopThreshold <= 0 {
+		t.Errorf("default consecutive_infra_stop_threshold = %d, want > 0", Default.Harness.ConsecutiveInfraStopThreshold)
+	}
 	if Default.Docker.AutoPull != true {
 		t.Error("default auto pull should be true")
 	}
@@ -31,6 +42,36 @@ func TestDefault(t *testing.T) {
 	if len(Default.Sandbox.SharedReadOnlyDirs) == 0 {
 		t.Error("default shared_readonly_dirs should not be empty")
 	}
+	if !Default.Sandbox.AllowNetwork {
+		t.Error("default allow_network should be true")
+	}
+}
+
+func TestScaffoldMatchesDefault(t *testing.T) {
+	t.Parallel()
+
+	// Decode into a zero Config rather than through Load, which starts from
+	// Default and would hide keys missing from the scaffold.
+	var cfg Config
+	md, err := toml.Decode(Scaffold(), &cfg)
+	if err != nil {
+		t.Fatalf("decode scaffold: %v", err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		t.Errorf("scaffold has unknown keys: %v", undecoded)
+	}
+	if !reflect.DeepEqual(cfg.Harness, Default.Harness) {
+		t.Errorf("scaffold harness = %+v, want %+v", cfg.Harness, Default.Harness)
+	}
+	if !reflect.DeepEqual(cfg.Docker, Default.Docker) {
+		t.Errorf("scaffold docker = %+v, want %+v", cfg.Docker, Default.Docker)
+	}
+	if !reflect.DeepEqual(cfg.Sandbox, Default.Sandbox) {
+		t.Errorf("scaffold sandbox = %+v, want %+v", cfg.Sandbox, Default.Sandbox)
+	}
+	if len(cfg.Agents) != 0 {
+		t.Errorf("scaffold agents = %v, want only a commented-out custom agent", cfg.Agents)
+	}
 }
 
 func TestLoadNoFile(t *testing.T) {
@@ -74,6 +115,7 @@ writable_dirs = ["go"]
 readable_denylist = ["tasks", "/tmp/secret"]
 shared_readwrite_dirs = [".config", ".cache", ".factory"]
 shared_readonly_dirs = [".local/bin", "bin"]
+allow_network = false
 		`
 	if err := os.WriteFile(cfgPath, []byte(content), 0644); err != nil {
 		t.Fatalf("writing config: %v", err)
@@ -99,6 +141,9 @@ shared_readonly_dirs = [".local/bin", "bin"]
 	if cfg.Docker.AutoPull != false {
 		t.Error("auto pull should be false")
 	}
+	if cfg.Sandbox.AllowNetwork {
+		t.Error("allow_network should be false")
+	}
 	if len(cfg.Sandbox.WritableDirs) != 1 || cfg.Sandbox.WritableDirs[0] != "go" {
 		t.Errorf("sandbox writable dirs = %v, want [go]", cfg.Sandbox.WritableDirs)
 	}
@@ -129,6 +174,205 @@ func TestLoadMissingExplicitFile(t *testing.T) {
 	}
 }
 
+func TestLoadProfileDeepMerges(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "test.toml")
+
+	content := `
+[harness]
+default_timeout = 600
+max_attempts = 3
+
+[sandbox]
+writable_dirs = ["go"]
+
+[agents.local-agent]
+command = "local-agent"
+args = ["{prompt}"]
+env = { API_BASE = "https://api.example.com" }
+
+[profiles.local.harness]
+default_timeout = 1800
+
+[profiles.local.sandbox]
+writable_dirs = [".ollama"]
+
+[profiles.local.agents.local-agent]
+env = { API_BASE = "http://localhost:11434" }
+`
+	if err := os.WriteFile(cfgPath, []byte(content), 0644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+
+	cfg, err := LoadProfile(cfgPath, "local")
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+	if cfg.Profile != "local" {
+		t.Errorf("profile = %q, want local", cfg.Profile)
+	}
+	if cfg.Harness.DefaultTimeout != 1800 {
+		t.Errorf("default timeout = %d, want 1800", cfg.Harness.DefaultTimeout)
+	}
+	if cfg.Harness.MaxAttempts != 3 {
+		t.Errorf("max attempts = %d, want base value 3", cfg.Harness.MaxAttempts)
+	}
+	if len(cfg.Sandbox.WritableDirs) != 1 || cfg.Sandbox.WritableDirs[0] != ".ollama" {
+		t.Errorf("writable dirs = %v, want [.ollama]", cfg.Sandbox.WritableDirs)
+	}
+	agent := cfg.Agents["local-agent"]
+	if agent.Command != "local-agent" {
+		t.Errorf("agent command = %q, want base value local-agent", agent.Command)
+	}
+	if agent.Env["API_BASE"] != "http://localhost:11434" {
+		t.Errorf("agent env API_BASE = %q, want profile value", agent.Env["API_BASE"])
+	}
+
+	base, err := LoadProfile(cfgPath, "")
+	if err != nil {
+		t.Fatalf("LoadProfile() without profile error = %v", err)
+	}
+	if base.Harness.DefaultTimeout != 600 || base.Profile != "" {
+		t.Errorf("base config = timeout %d profile %q, want 600 and no profile", base.Harness.DefaultTimeout, base.Profile)
+	}
+
+	if _, err := LoadProfile(cfgPath, "missing"); err == nil {
+		t.Error("LoadProfile() should error for an unknown profile")
+	}
+}
+
+func TestLoadProfileReport(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "test.toml")
+	content := `
+[harness]
+default_timeout = 90
+default_timout = 60
+
+[docker]
+auto_pull = false
+
+[agents.mine]
+command = "mine"
+comand = "typo"
+
+[profiles.local.harness]
+max_attempts = 2
+
+[profiles.ci.harness]
+max_atempts = 3
+`
+	if err := os.WriteFile(cfgPath, []byte(content), 0644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+
+	_, report, err := LoadProfileReport(cfgPath, "")
+	if err != nil {
+		t.Fatalf("LoadProfileReport() error = %v", err)
+	}
+	if report.Path != cfgPath {
+		t.Errorf("path = %q, want %q", report.Path, cfgPath)
+	}
+	want := []UnknownKey{
+		{Key: "harness.default_timout", Line: 4},
+		{Key: "agents.mine.comand", Line: 11},
+		{Key: "profiles.ci.harness.max_atempts", Line: 17},
+	}
+	if !reflect.DeepEqual(report.Unknown, want) {
+		t.Errorf("unknown = %v, want %v", report.Unknown, want)
+	}
+	if slices.Contains(report.Defaulted, "harness.default_timeout") || slices.Contains(report.Defaulted, "docker.auto_pull") {
+		t.Errorf("defaulted = %v, should not list keys the file sets", report.Defaulted)
+	}
+	if !slices.Contains(report.Defaulted, "harness.max_attempts") || !slices.Contains(report.Defaulted, "sandbox.allow_network") {
+		t.Errorf("defaulted = %v, want harness.max_attempts and sandbox.allow_network", report.Defaulted)
+	}
+
+	_, report, err = LoadProfileReport(cfgPath, "local")
+	if err != nil {
+		t.Fatalf("LoadProfileReport() with profile error = %v", err)
+	}
+	if slices.Contains(report.Defaulted, "harness.max_attempts") {
+		t.Errorf("defaulted = %v, should not list keys the profile sets", report.Defaulted)
+	}
+}
+
+func TestLoadProfileReportUnknownSection(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "test.toml")
+	content := `[harnes]
+default_timeout = 90
+max_attempts = 2
+
+[profiles.local.docker]
+auto_pul = false
+
+[profiles.ci.dockr]
+auto_pull = false
+`
+	if err := os.WriteFile(cfgPath, []byte(content), 0644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+
+	_, report, err := LoadProfileReport(cfgPath, "local")
+	if err != nil {
+		t.Fatalf("LoadProfileReport() error = %v", err)
+	}
+	want := []UnknownKey{{Key: "harnes", Line: 1}, {Key: "docker.auto_pul", Line: 6}, {Key: "profiles.ci.dockr", Line: 8}}
+	if !reflect.DeepEqual(report.Unknown, want) {
+		t.Errorf("unknown = %v, want %v", report.Unknown, want)
+	}
+	if got := report.Unknown[0].String(); got != "harnes (line 1)" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestAgentConfigWarnings(t *testing.T) {
+	t.Parallel()
+
+	for name, ac := range DefaultAgents {
+		if w := ac.Warnings(); len(w) != 0 {
+			t.Errorf("built-in agent %s warnings = %v", name, w)
+		}
+	}
+	if w := (AgentConfig{ShellCommand: "run {prompt}"}).Warnings(); len(w) != 0 {
+		t.Errorf("shell_command agent warnings = %v", w)
+	}
+	w := AgentConfig{Args: []string{"{prompt}"}, ModelFlagPosition: "middle", ReasoningFlagPosition: "after"}.Warnings()
+	if len(w) != 2 || !strings.Contains(w[0], "command") || !strings.Contains(w[1], "model_flag_position") {
+		t.Errorf("warnings = %v, want missing command and bad model_flag_position", w)
+	}
+}
+
+func TestAgentMinTimeout(t *testing.T) {
+	t.Parallel()
+
+	agent := AgentConfig{
+		DefaultTimeout:    300,
+		ReasoningTimeouts: map[string]int{"low": 120, "high": 1200},
+	}
+	tests := []struct {
+		reasoning string
+		want      int
+	}{
+		{"high", 1200},
+		{"low", 120},
+		{"medium", 300},
+		{"", 300},
+	}
+	for _, tt := range tests {
+		if got := agent.MinTimeout(tt.reasoning); got != tt.want {
+			t.Errorf("MinTimeout(%q) = %d, want %d", tt.reasoning, got, tt.want)
+		}
+	}
+}
+
 func TestImageForLanguage(t *testing.T) {
 	t.Parallel()
 
@@ -141,6 +385,9 @@ func TestImageForLanguage(t *testing.T) {
 			DartImage:       "dart-img",
 			ZigImage:        "zig-img",
 		},
+		Languages: map[string]LanguageConfig{
+			"cpp": {Image: "cpp-img"},
+		},
 	}
 
 	tests := []struct {
@@ -153,6 +400,8 @@ func TestImageForLanguage(t *testing.T) {
 		{"kotlin", "kotlin-img"},
 		{"dart", "dart-img"},
 		{"zig", "zig-img"},
+		{"cpp", "cpp-img"},
+		{"CPP", "cpp-img"},
 		{"unknown", ""},
 		{"", ""},
 	}
@@ -167,3 +416,53 @@ func TestImageForLanguage(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadNormalizesLanguageKeys(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "test.toml")
+	content := `
+[languages.CPP]
+image = "cpp-img"
+toolchain_info = "GCC 14"
+`
+	if err := os.WriteFile(cfgPath, []byte(content), 0644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+	cfg, err := Load(cfgPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := cfg.ImageForLanguage("cpp"); got != "cpp-img" {
+		t.Errorf("ImageForLanguage(cpp) = %q, want cpp-img", got)
+	}
+	if got := cfg.Languages["cpp"].ToolchainInfo; got != "GCC 14" {
+		t.Errorf("Languages[cpp].ToolchainInfo = %q, want GCC 14", got)
+	}
+
+	dupPath := filepath.Join(dir, "dup.toml")
+	if err := os.WriteFile(dupPath, []byte("[languages.cpp]\nimage = \"a\"\n[languages.CPP]\nimage = \"b\"\n"), 0644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+	if _, err := Load(dupPath); err == nil || !strings.Contains(err.Error(), "more than once") {
+		t.Fatalf("Load() with case-duplicate languages error = %v, want a duplicate error", err)
+	}
+}
+
+func TestRetryConfigWarnings(t *testing.T) {
+	t.Parallel()
+
+	if w := (RetryConfig{}).Warnings(); len(w) != 0 {
+		t.Fatalf("empty retry config warnings = %v", w)
+	}
+	ok := RetryConfig{QuotaDelays: []int{60, 300}, QuotaMaxRetries: 2, InfraMaxRetries: 8, Jitter: 0.2}
+	if w := ok.Warnings(); len(w) != 0 {
+		t.Fatalf("valid retry config warnings = %v", w)
+	}
+	bad := RetryConfig{QuotaDelays: []int{60, -1}, InfraDelays: []int{10}, InfraMaxRetries: 3, Jitter: 1.5}
+	w := bad.Warnings()
+	if len(w) != 3 || !strings.Contains(w[0], "quota_delays") || !strings.Contains(w[1], "infra_max_retries (3)") || !strings.Contains(w[2], "jitter (1.5)") {
+		t.Fatalf("warnings = %v", w)
+	}
+}
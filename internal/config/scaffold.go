@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Scaffold returns a commented starter sanity.toml holding the values of
+// Default, so the generated file always matches the built-in configuration.
+func Scaffold() string {
+	d := Default
+	var sb strings.Builder
+
+	sb.WriteString("# SanityHarness configuration, generated by `sanity init`.\n")
+	sb.WriteString("# Values shown are the built-in defaults; delete any key to keep the default.\n")
+	sb.WriteString("# See docs/CONFIGURATION.md for every option.\n\n")
+
+	sb.WriteString("[harness]\n")
+	fmt.Fprintf(&sb, "session_dir = %s # Directory for session output\n", strconv.Quote(d.Harness.SessionDir))
+	fmt.Fprintf(&sb, "default_timeout = %d # Default timeout in seconds\n", d.Harness.DefaultTimeout)
+	fmt.Fprintf(&sb, "max_attempts = %d # Maximum validation attempts per run\n", d.Harness.MaxAttempts)
+	fmt.Fprintf(&sb, "output_format = %s # json, human, or all\n", strconv.Quote(d.Harness.OutputFormat))
+	fmt.Fprintf(&sb, "min_free_disk_mb = %d # Stop eval below this much free disk (0 = disabled)\n", d.Harness.MinFreeDiskMB)
+	fmt.Fprintf(&sb, "copy_ignore_dirs = %s # Not copied back from agent workspaces\n", tomlStringArray(d.Harness.CopyIgnoreDirs))
+	fmt.Fprintf(&sb, "confirm_dangerous_agents = %t # Ask before running agents with full-auto flags\n", d.Harness.ConfirmDangerousAgents)
+	fmt.Fprintf(&sb, "consecutive_infra_stop_threshold = %d # Stop eval after this many infra failures in a row (0 = disabled)\n", d.Harness.ConsecutiveInfraStopThreshold)
+	sb.WriteString("# max_agent_log_mb = 256 # Truncate agent.log at this size\n")
+	sb.WriteString("# default_agent = \"claude\" # Agent eval uses when --agent is omitted\n")
+	sb.WriteString("# system_prompt = \"You are a careful engineer.\" # Default --system-prompt for eval\n")
+	sb.WriteString("# skip_langs = [\"kotlin\", \"dart\"] # Languages eval skips by default\n")
+	sb.WriteString("# output_template = \"{agent}/{model}/{date}-{uuid}\" # Eval run directory under eval-results\n\n")
+
+	sb.WriteString("# Agent retry schedules in seconds (defaults shown).\n")
+	sb.WriteString("[harness.retry]\n")
+	sb.WriteString("# quota_delays = [30, 60, 120, 240, 480]\n")
+	sb.WriteString("# quota_max_retries = 5\n")
+	sb.WriteString("# infra_delays = [15, 30, 60, 120, 240]\n")
+	sb.WriteString("# infra_max_retries = 5\n")
+	fmt.Fprintf(&sb, "jitter = %s # Randomize each delay by up to this fraction either way (0 = off)\n\n", strconv.FormatFloat(d.Harness.Retry.Jitter, 'g', -1, 64))
+
+	sb.WriteString("# Per-language timeouts in seconds, replacing default_timeout and --timeout.\n")
+	sb.WriteString("# [harness.language_timeouts]\n")
+	sb.WriteString("# rust = 900\n")
+	sb.WriteString("# kotlin = 900\n\n")
+
+	sb.WriteString("[docker]\n")
+	fmt.Fprintf(&sb, "go_image = %s\n", strconv.Quote(d.Docker.GoImage))
+	fmt.Fprintf(&sb, "rust_image = %s\n", strconv.Quote(d.Docker.RustImage))
+	fmt.Fprintf(&sb, "typescript_image = %s\n", strconv.Quote(d.Docker.TypeScriptImage))
+	fmt.Fprintf(&sb, "kotlin_image = %s\n", strconv.Quote(d.Docker.KotlinImage))
+	fmt.Fprintf(&sb, "dart_image = %s\n", strconv.Quote(d.Docker.DartImage))
+	fmt.Fprintf(&sb, "zig_image = %s\n", strconv.Quote(d.Docker.ZigImage))
+	fmt.Fprintf(&sb, "auto_pull = %t # Pull missing images automatically\n", d.Docker.AutoPull)
+	sb.WriteString("# memory_limit_mb = 4096 # Memory limit per validation container (0 = unlimited)\n")
+	sb.WriteString("# cpu_quota = 2.0 # CPUs per validation container (0 = unlimited)\n")
+	sb.WriteString("# host = \"tcp://build-box:2375\" # Remote Docker endpoint (default: DOCKER_HOST or local socket)\n\n")
+
+	sb.WriteString("[sandbox]\n")
+	sb.WriteString("# Home-relative or absolute directories shared into the agent sandbox.\n")
+	fmt.Fprintf(&sb, "shared_readwrite_dirs = %s\n", tomlStringArrayMultiline(d.Sandbox.SharedReadWriteDirs))
+	fmt.Fprintf(&sb, "shared_readonly_dirs = %s\n", tomlStringArrayMultiline(d.Sandbox.SharedReadOnlyDirs))
+	sb.WriteString("# writable_dirs = [\".my-agent\"] # Extra $HOME-relative dirs mounted writable\n")
+	sb.WriteString("# readable_denylist = [\"secrets/\"] # Paths hidden from agents\n")
+	sb.WriteString("# bwrap_path = \"bwrap\" # bubblewrap binary\n")
+	sb.WriteString("# extra_args = [\"--unshare-ipc\"] # Extra bwrap args\n")
+	fmt.Fprintf(&sb, "allow_network = %t # false blocks agent network access (local models only)\n\n", d.Sandbox.AllowNetwork)
+
+	sb.WriteString("# Custom agent example. Built-in agents can be overridden the same way.\n")
+	sb.WriteString("# [agents.custom]\n")
+	sb.WriteString("# command = \"/path/to/my-agent\" # Binary name or path\n")
+	sb.WriteString("# args = [\"--auto-approve\", \"{prompt}\"] # {prompt} is replaced with the task prompt\n")
+	sb.WriteString("# model_flag = \"-m\" # Passed as -m <model> with --model\n")
+	sb.WriteString("# model_flag_position = \"before\" # before or after args\n")
+	sb.WriteString("# reasoning_flag = \"-r\" # Passed with --reasoning\n")
+	sb.WriteString("# system_prompt_flag = \"--system\" # Passed with --system-prompt\n")
+	sb.WriteString("# default_timeout = 600 # Minimum agent timeout in seconds\n")
+	sb.WriteString("# env = { API_KEY = \"xxx\" }\n")
+
+	return sb.String()
+}
+
+func tomlStringArray(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+func tomlStringArrayMultiline(values []string) string {
+	if len(values) == 0 {
+		return "[]"
+	}
+	var sb strings.Builder
+	sb.WriteString("[\n")
+	for _, v := range values {
+		fmt.Fprintf(&sb, "  %s,\n", strconv.Quote(v))
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
@@ -2,6 +2,7 @@
 package errors
 
 import (
+	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
@@ -16,6 +17,11 @@ type Pattern struct {
 // Summarizer extracts human-readable error summaries from compiler/test output.
 type Summarizer struct {
 	patterns []Pattern
+
+	// testPass and testFail count passing and failing tests; set from a
+	// language's config [languages.<lang>.parse] table.
+	testPass *regexp.Regexp
+	testFail *regexp.Regexp
 }
 
 // NewSummarizer creates a summarizer for the given language.
@@ -49,11 +55,38 @@ func (s *Summarizer) Summarize(output string) []string {
 		return s.fallbackSummary(output)
 	}
 
-	var summaries []string
+	summaries := s.matchPatterns(output, nil, make(map[string]bool))
+	if len(summaries) == 0 {
+		return s.fallbackSummary(output)
+	}
+
+	return summaries
+}
+
+// SummarizeStreams is like Summarize for output captured as separate
+// streams. Test frameworks tend to write failures to stderr and progress to
+// stdout, so stderr's matches come first and stderr is preferred for the
+// fallback summary when nothing matches.
+func (s *Summarizer) SummarizeStreams(stdout, stderr string) []string {
 	seen := make(map[string]bool)
+	summaries := s.matchPatterns(stderr, nil, seen)
+	summaries = s.matchPatterns(stdout, summaries, seen)
+	if len(summaries) > 0 {
+		return summaries
+	}
+	if strings.TrimSpace(stderr) != "" {
+		return s.fallbackSummary(stderr)
+	}
+	return s.fallbackSummary(stdout)
+}
 
-	lines := strings.Split(output, "\n")
-	for _, line := range lines {
+// matchPatterns appends the summaries of pattern matches in output not
+// already in seen.
+func (s *Summarizer) matchPatterns(output string, summaries []string, seen map[string]bool) []string {
+	if len(s.patterns) == 0 {
+		return summaries
+	}
+	for _, line := range strings.Split(output, "\n") {
 		for _, p := range s.patterns {
 			if matches := p.Regex.FindStringSubmatch(line); matches != nil {
 				summary := p.Summary
@@ -69,12 +102,60 @@ func (s *Summarizer) Summarize(output string) []string {
 			}
 		}
 	}
+	return summaries
+}
 
-	if len(summaries) == 0 {
-		return s.fallbackSummary(output)
+// SetTestPatterns configures the regexes CountTests uses. Each line
+// matching a pattern counts as one test, unless the pattern has a capture
+// group holding a number (e.g. `(\d+) passed`), which is added instead.
+// Empty patterns are ignored.
+func (s *Summarizer) SetTestPatterns(pass, fail string) error {
+	var err error
+	if s.testPass, err = compileOptional(pass); err != nil {
+		return fmt.Errorf("test_pass_pattern: %w", err)
+	}
+	if s.testFail, err = compileOptional(fail); err != nil {
+		return fmt.Errorf("test_fail_pattern: %w", err)
 	}
+	return nil
+}
 
-	return summaries
+// CountTests returns the passing and failing test counts in output. ok is
+// false when no test patterns are configured.
+func (s *Summarizer) CountTests(output string) (passed, failed int, ok bool) {
+	if s.testPass == nil && s.testFail == nil {
+		return 0, 0, false
+	}
+	for _, line := range strings.Split(output, "\n") {
+		passed += countMatch(s.testPass, line)
+		failed += countMatch(s.testFail, line)
+	}
+	return passed, failed, true
+}
+
+func compileOptional(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile(pattern)
+}
+
+// countMatch returns the number captured by re's first group, or 1 for a
+// match without a numeric group, or 0 when line does not match.
+func countMatch(re *regexp.Regexp, line string) int {
+	if re == nil {
+		return 0
+	}
+	matches := re.FindStringSubmatch(line)
+	if matches == nil {
+		return 0
+	}
+	if len(matches) > 1 {
+		if n, err := strconv.Atoi(matches[1]); err == nil {
+			return n
+		}
+	}
+	return 1
 }
 
 // fallbackSummary returns the first few lines of error output when no patterns match.
@@ -195,6 +195,51 @@ func TestSummarizeFallback(t *testing.T) {
 	}
 }
 
+func TestSummarizeStreams(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		lang   string
+		stdout string
+		stderr string
+		want   []string
+	}{
+		{
+			name:   "stderr matches first",
+			lang:   "go",
+			stdout: "=== RUN   TestFoo\nundefined: Bar\n",
+			stderr: "fatal error: all goroutines are asleep - deadlock!\nundefined: Bar\n",
+			want:   []string{"Deadlock detected", "Undefined: Bar"},
+		},
+		{
+			name:   "fallback prefers stderr",
+			lang:   "unknown",
+			stdout: "progress 1/3\nprogress 2/3\n",
+			stderr: "assertion failed: expected 3\n",
+			want:   []string{"assertion failed: expected 3"},
+		},
+		{
+			name:   "fallback to stdout when stderr is empty",
+			lang:   "unknown",
+			stdout: "assertion failed: expected 3\n",
+			stderr: "  \n",
+			want:   []string{"assertion failed: expected 3"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := NewSummarizer(tc.lang).SummarizeStreams(tc.stdout, tc.stderr)
+			if strings.Join(got, "|") != strings.Join(tc.want, "|") {
+				t.Errorf("SummarizeStreams() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestSummarizeDeduplication(t *testing.T) {
 	t.Parallel()
 
@@ -213,3 +258,33 @@ func TestSummarizeDeduplication(t *testing.T) {
 		t.Errorf("expected deduplicated errors, got %d occurrences", count)
 	}
 }
+
+func TestCountTests(t *testing.T) {
+	t.Parallel()
+
+	s := NewSummarizer("cpp")
+	if _, _, ok := s.CountTests("anything"); ok {
+		t.Fatal("CountTests without patterns should report ok=false")
+	}
+
+	// Per-line matches.
+	if err := s.SetTestPatterns(`^\[  PASSED  \]`, `^\[  FAILED  \]`); err != nil {
+		t.Fatal(err)
+	}
+	output := "[  PASSED  ] a\n[  PASSED  ] b\n[  FAILED  ] c\nother line"
+	if passed, failed, ok := s.CountTests(output); !ok || passed != 2 || failed != 1 {
+		t.Errorf("CountTests() = %d, %d, %t; want 2, 1, true", passed, failed, ok)
+	}
+
+	// Numeric capture groups are summed.
+	if err := s.SetTestPatterns(`(\d+) passed`, `(\d+) failed`); err != nil {
+		t.Fatal(err)
+	}
+	if passed, failed, _ := s.CountTests("suite a: 7 passed, 2 failed\nsuite b: 3 passed"); passed != 10 || failed != 2 {
+		t.Errorf("CountTests() = %d, %d; want 10, 2", passed, failed)
+	}
+
+	if err := s.SetTestPatterns(`(`, ""); err == nil || !strings.Contains(err.Error(), "test_pass_pattern") {
+		t.Errorf("SetTestPatterns with invalid regex error = %v, want test_pass_pattern error", err)
+	}
+}
@@ -20,6 +20,7 @@ const (
 	StatusFail    Status = "fail"
 	StatusTimeout Status = "timeout"
 	StatusError   Status = "error"
+	StatusOOM     Status = "oom" // Validation killed for exceeding the memory limit
 )
 
 // StatusEmoji maps status values to their emoji representations.
@@ -28,6 +29,7 @@ var StatusEmoji = map[Status]string{
 	StatusFail:    "❌",
 	StatusTimeout: "⏱️",
 	StatusError:   "⚠️",
+	StatusOOM:     "💥",
 }
 
 // Session represents a complete evaluation session.
@@ -42,6 +44,10 @@ type Session struct {
 	CompletedAt time.Time         `json:"completed_at"`
 	FinalCode   map[string]string `json:"final_code,omitempty"`
 	Config      SessionConfig     `json:"config"`
+
+	// PhaseTimes holds time spent per runner phase (ensure-image,
+	// container-start, exec).
+	PhaseTimes map[string]time.Duration `json:"phase_times_ns,omitempty"`
 }
 
 // SessionConfig captures the configuration used for a session.
@@ -59,8 +65,49 @@ type Attempt struct {
 	Passed       bool          `json:"passed"`
 	Duration     time.Duration `json:"duration_ns"`
 	ErrorSummary []string      `json:"error_summary,omitempty"`
-	RawOutput    string        `json:"raw_output"`
+	Stdout       string        `json:"stdout,omitempty"`
+	Stderr       string        `json:"stderr,omitempty"`
 	Timestamp    time.Time     `json:"timestamp"`
+	Tests        *TestCounts   `json:"tests,omitempty"` // Set when the language configures test patterns
+}
+
+// RawOutput returns the attempt's stdout followed by its stderr.
+func (a Attempt) RawOutput() string {
+	return a.Stdout + a.Stderr
+}
+
+// MarshalJSON writes an attempt with its combined raw_output next to stdout
+// and stderr, so consumers of the older format keep working.
+func (a Attempt) MarshalJSON() ([]byte, error) {
+	type plain Attempt
+	return json.Marshal(struct {
+		plain
+		RawOutput string `json:"raw_output"`
+	}{plain: plain(a), RawOutput: a.RawOutput()})
+}
+
+// UnmarshalJSON reads an attempt, taking the combined raw_output of results
+// saved before stdout and stderr were kept apart as its stdout.
+func (a *Attempt) UnmarshalJSON(data []byte) error {
+	type plain Attempt
+	aux := struct {
+		*plain
+		RawOutput string `json:"raw_output"`
+	}{plain: (*plain)(a)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if a.Stdout == "" && a.Stderr == "" {
+		a.Stdout = aux.RawOutput
+	}
+	return nil
+}
+
+// TestCounts holds the passing and failing test counts parsed from an
+// attempt's output.
+type TestCounts struct {
+	Passed int `json:"passed"`
+	Failed int `json:"failed"`
 }
 
 // NewSession creates a new session with the given parameters.
@@ -84,15 +131,17 @@ func NewSession(taskSlug, language string, cfg SessionConfig) *Session {
 	}
 }
 
-// AddAttempt adds a new attempt to the session.
-func (s *Session) AddAttempt(exitCode int, duration time.Duration, output string, errorSummary []string) {
+// AddAttempt adds a new attempt to the session from the validation
+// command's separately captured stdout and stderr.
+func (s *Session) AddAttempt(exitCode int, duration time.Duration, stdout, stderr string, errorSummary []string) {
 	attempt := Attempt{
 		Number:       len(s.Attempts) + 1,
 		ExitCode:     exitCode,
 		Passed:       exitCode == 0,
 		Duration:     duration,
 		ErrorSummary: errorSummary,
-		RawOutput:    output,
+		Stdout:       stdout,
+		Stderr:       stderr,
 		Timestamp:    time.Now(),
 	}
 
@@ -103,6 +152,14 @@ func (s *Session) AddAttempt(exitCode int, duration time.Duration, output string
 	}
 }
 
+// AddPhaseTime adds d to the time recorded for phase.
+func (s *Session) AddPhaseTime(phase string, d time.Duration) {
+	if s.PhaseTimes == nil {
+		s.PhaseTimes = make(map[string]time.Duration)
+	}
+	s.PhaseTimes[phase] += d
+}
+
 // Complete finalizes the session.
 func (s *Session) Complete() {
 	s.CompletedAt = time.Now()
@@ -156,7 +213,7 @@ func (s *Session) Save(baseDir string) error {
 	// Write attempt logs
 	for _, attempt := range s.Attempts {
 		logFile := filepath.Join(dir, "logs", fmt.Sprintf("attempt-%d.log", attempt.Number))
-		if err := os.WriteFile(logFile, []byte(attempt.RawOutput), 0644); err != nil {
+		if err := os.WriteFile(logFile, []byte(attempt.RawOutput()), 0644); err != nil {
 			return fmt.Errorf("writing attempt log: %w", err)
 		}
 	}
@@ -204,7 +261,7 @@ func (s *Session) GenerateMarkdown() string {
 		}
 
 		sb.WriteString("<details>\n<summary>Raw Output</summary>\n\n```\n")
-		sb.WriteString(attempt.RawOutput)
+		sb.WriteString(attempt.RawOutput())
 		sb.WriteString("\n```\n</details>\n\n")
 	}
 
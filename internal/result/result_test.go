@@ -52,7 +52,7 @@ func TestAddAttempt(t *testing.T) {
 	session := NewSession("test", "go", SessionConfig{MaxAttempts: 5})
 
 	// Add failing attempt
-	session.AddAttempt(1, 100*time.Millisecond, "error output", []string{"Error 1"})
+	session.AddAttempt(1, 100*time.Millisecond, "error output", "", []string{"Error 1"})
 
 	if len(session.Attempts) != 1 {
 		t.Fatalf("Attempts = %d, want 1", len(session.Attempts))
@@ -68,7 +68,7 @@ func TestAddAttempt(t *testing.T) {
 	}
 
 	// Add passing attempt
-	session.AddAttempt(0, 50*time.Millisecond, "success output", nil)
+	session.AddAttempt(0, 50*time.Millisecond, "success output", "", nil)
 
 	if len(session.Attempts) != 2 {
 		t.Fatalf("Attempts = %d, want 2", len(session.Attempts))
@@ -84,6 +84,38 @@ func TestAddAttempt(t *testing.T) {
 	}
 }
 
+func TestAttemptJSON(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(Attempt{Number: 1, Stdout: "--- FAIL\n", Stderr: "panic: boom\n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["stdout"] != "--- FAIL\n" || fields["stderr"] != "panic: boom\n" || fields["raw_output"] != "--- FAIL\npanic: boom\n" {
+		t.Fatalf("attempt JSON = %s, want stdout, stderr and the combined raw_output", data)
+	}
+	var got Attempt
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.RawOutput() != "--- FAIL\npanic: boom\n" {
+		t.Fatalf("RawOutput() = %q after a round trip", got.RawOutput())
+	}
+
+	// Results saved before the streams were split only have raw_output.
+	var old Attempt
+	if err := json.Unmarshal([]byte(`{"number":1,"exit_code":1,"raw_output":"FAIL\n"}`), &old); err != nil {
+		t.Fatal(err)
+	}
+	if old.RawOutput() != "FAIL\n" || old.ExitCode != 1 {
+		t.Fatalf("old attempt = %+v, want its raw output kept", old)
+	}
+}
+
 func TestComplete(t *testing.T) {
 	t.Parallel()
 
@@ -109,8 +141,8 @@ func TestLastAttempt(t *testing.T) {
 		t.Error("LastAttempt should be nil when no attempts")
 	}
 
-	session.AddAttempt(1, time.Second, "output", nil)
-	session.AddAttempt(0, time.Second, "output", nil)
+	session.AddAttempt(1, time.Second, "output", "", nil)
+	session.AddAttempt(0, time.Second, "output", "", nil)
 
 	last := session.LastAttempt()
 	if last == nil {
@@ -165,8 +197,8 @@ func TestSave(t *testing.T) {
 		MaxAttempts: 5,
 		Image:       "test:latest",
 	})
-	session.AddAttempt(1, time.Second, "error output", []string{"Error 1"})
-	session.AddAttempt(0, time.Second, "success output", nil)
+	session.AddAttempt(1, time.Second, "error output", "", []string{"Error 1"})
+	session.AddAttempt(0, time.Second, "success output", "", nil)
 	session.Complete()
 	session.FinalCode["test.ts"] = "console.log('hello');"
 
@@ -227,7 +259,7 @@ func TestGenerateMarkdown(t *testing.T) {
 		MaxAttempts: 5,
 		Image:       "test:latest",
 	})
-	session.AddAttempt(1, time.Second, "error output", []string{"Error 1", "Error 2"})
+	session.AddAttempt(1, time.Second, "error output", "", []string{"Error 1", "Error 2"})
 	session.Complete()
 
 	md := session.GenerateMarkdown()
@@ -251,7 +283,7 @@ func TestFormatTerminal(t *testing.T) {
 	t.Parallel()
 
 	session := NewSession("test", "go", SessionConfig{MaxAttempts: 5})
-	session.AddAttempt(1, time.Second, "error output", []string{"Error 1"})
+	session.AddAttempt(1, time.Second, "error output", "", []string{"Error 1"})
 
 	output := FormatTerminal(session, session.LastAttempt(), false)
 
@@ -271,7 +303,7 @@ func TestFormatFinalResult(t *testing.T) {
 
 	session := NewSession("test", "go", SessionConfig{})
 	session.Status = StatusPass
-	session.AddAttempt(0, time.Second, "output", nil)
+	session.AddAttempt(0, time.Second, "output", "", nil)
 	session.Complete()
 
 	output := FormatFinalResult(session)
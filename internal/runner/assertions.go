@@ -0,0 +1,43 @@
+package runner
+
+import (
+	"fmt"
+	"time"
+
+	errsummary "github.com/lemon07r/sanityharness/internal/errors"
+	"github.com/lemon07r/sanityharness/internal/result"
+	"github.com/lemon07r/sanityharness/internal/task"
+)
+
+// applyJSONAssertions checks t's JSON assertions against workspaceDir and
+// appends their PASS/FAIL lines to execResult's output. A failed assertion
+// fails an otherwise passing exec.
+func applyJSONAssertions(t *task.Task, workspaceDir string, execResult *ExecResult) {
+	if len(t.JSONAssertions) == 0 {
+		return
+	}
+	report, err := t.CheckJSONAssertions(workspaceDir)
+	output := "HARNESS: json assertions\n" + report
+	if execResult.Stdout != "" {
+		output = "\n" + output
+	}
+	execResult.Stdout += output
+	if err != nil && execResult.ExitCode == 0 {
+		execResult.ExitCode = 1
+	}
+}
+
+// runAssertions validates an assertion-only task by checking its JSON
+// assertions directly against the workspace, without a container.
+func (r *Runner) runAssertions(t *task.Task, session *result.Session, workspaceDir string, opts RunOptions) {
+	start := time.Now()
+	execResult := &ExecResult{}
+	applyJSONAssertions(t, workspaceDir, execResult)
+	execResult.Duration = time.Since(start)
+	session.AddPhaseTime(string(PhaseExec), execResult.Duration)
+
+	addSummarizedAttempt(session, errsummary.NewSummarizer(string(t.Language)), execResult)
+	if !opts.Quiet {
+		fmt.Print(result.FormatTerminal(session, session.LastAttempt(), false))
+	}
+}
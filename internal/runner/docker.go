@@ -23,18 +23,25 @@ type ExecResult struct {
 	ExitCode int
 	Stdout   string
 	Stderr   string
-	Combined string
 	Duration time.Duration
 }
 
+// Combined returns stdout followed by stderr. The streams are captured
+// separately, so this is not the order the command interleaved them in.
+func (e *ExecResult) Combined() string {
+	return e.Stdout + e.Stderr
+}
+
 // DockerClient wraps the Docker SDK client with harness-specific operations.
 type DockerClient struct {
 	client *client.Client
 }
 
-// NewDockerClient creates a new Docker client and verifies the daemon is accessible.
-func NewDockerClient() (*DockerClient, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+// NewDockerClient creates a new Docker client for host and verifies the
+// daemon is accessible. An empty host uses the environment (DOCKER_HOST or
+// the local socket).
+func NewDockerClient(host string) (*DockerClient, error) {
+	cli, err := newAPIClient(host)
 	if err != nil {
 		return nil, fmt.Errorf("creating docker client: %w", err)
 	}
@@ -51,6 +58,22 @@ func NewDockerClient() (*DockerClient, error) {
 	return &DockerClient{client: cli}, nil
 }
 
+// Host returns the daemon endpoint the client talks to, whether it came from
+// the config or from DOCKER_HOST.
+func (d *DockerClient) Host() string {
+	return d.client.DaemonHost()
+}
+
+// newAPIClient creates a Docker SDK client for host without contacting the
+// daemon.
+func newAPIClient(host string) (*client.Client, error) {
+	opts := []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}
+	if host != "" {
+		opts = append(opts, client.WithHost(host))
+	}
+	return client.NewClientWithOpts(opts...)
+}
+
 // Close closes the Docker client.
 func (d *DockerClient) Close() error {
 	return d.client.Close()
@@ -161,6 +184,7 @@ type ContainerConfig struct {
 	User         string
 	Env          []string
 	Mounts       []mount.Mount
+	Resources    container.Resources // CPU and memory limits
 }
 
 // CreateContainer creates a new container with the specified configuration.
@@ -173,17 +197,7 @@ func (d *DockerClient) CreateContainer(ctx context.Context, cfg ContainerConfig)
 		Env:   cfg.Env,
 	}
 
-	hostCfg := &container.HostConfig{
-		Mounts: append([]mount.Mount{
-			{
-				Type:   mount.TypeBind,
-				Source: cfg.WorkspaceDir,
-				Target: "/workspace",
-			},
-		}, cfg.Mounts...),
-	}
-
-	resp, err := d.client.ContainerCreate(ctx, containerCfg, hostCfg, nil, hostPlatform(), cfg.Name)
+	resp, err := d.client.ContainerCreate(ctx, containerCfg, hostConfig(cfg), nil, hostPlatform(), cfg.Name)
 	if err != nil {
 		return "", fmt.Errorf("creating container: %w", err)
 	}
@@ -191,6 +205,52 @@ func (d *DockerClient) CreateContainer(ctx context.Context, cfg ContainerConfig)
 	return resp.ID, nil
 }
 
+// hostConfig returns the host configuration for cfg: the workspace bind
+// mount, unless WorkspaceDir is empty, followed by cfg's extra mounts, and
+// cfg's resource limits.
+func hostConfig(cfg ContainerConfig) *container.HostConfig {
+	var mounts []mount.Mount
+	if cfg.WorkspaceDir != "" {
+		mounts = append(mounts, mount.Mount{
+			Type:   mount.TypeBind,
+			Source: cfg.WorkspaceDir,
+			Target: "/workspace",
+		})
+	}
+	return &container.HostConfig{
+		Mounts:    append(mounts, cfg.Mounts...),
+		Resources: cfg.Resources,
+	}
+}
+
+// OOMKilled reports whether the kernel OOM killer has fired in a container.
+func (d *DockerClient) OOMKilled(ctx context.Context, containerID string) (bool, error) {
+	info, err := d.client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return false, fmt.Errorf("inspecting container: %w", err)
+	}
+	return info.State != nil && info.State.OOMKilled, nil
+}
+
+// CopyToContainer extracts the tar archive content into dstPath in a
+// container.
+func (d *DockerClient) CopyToContainer(ctx context.Context, containerID, dstPath string, content io.Reader) error {
+	if err := d.client.CopyToContainer(ctx, containerID, dstPath, content, container.CopyToContainerOptions{}); err != nil {
+		return fmt.Errorf("copying to container: %w", err)
+	}
+	return nil
+}
+
+// CopyFromContainer returns a tar archive of srcPath in a container. The
+// caller must close it.
+func (d *DockerClient) CopyFromContainer(ctx context.Context, containerID, srcPath string) (io.ReadCloser, error) {
+	rc, _, err := d.client.CopyFromContainer(ctx, containerID, srcPath)
+	if err != nil {
+		return nil, fmt.Errorf("copying from container: %w", err)
+	}
+	return rc, nil
+}
+
 // StartContainer starts a container.
 func (d *DockerClient) StartContainer(ctx context.Context, containerID string) error {
 	if err := d.client.ContainerStart(ctx, containerID, container.StartOptions{}); err != nil {
@@ -213,7 +273,7 @@ type copyResult struct {
 }
 
 // Exec executes a command in a running container and returns the result.
-func (d *DockerClient) Exec(ctx context.Context, containerID string, cmd []string, workdir string, timeout time.Duration) (*ExecResult, error) {
+func (d *DockerClient) Exec(ctx context.Context, containerID string, cmd []string, workdir, user string, timeout time.Duration) (*ExecResult, error) {
 	start := time.Now()
 
 	// Create exec context with timeout
@@ -226,6 +286,7 @@ func (d *DockerClient) Exec(ctx context.Context, containerID string, cmd []strin
 		AttachStdout: true,
 		AttachStderr: true,
 		WorkingDir:   workdir,
+		User:         user, // Empty inherits the container's user
 	}
 
 	// Create exec instance
@@ -285,7 +346,6 @@ func (d *DockerClient) Exec(ctx context.Context, containerID string, cmd []strin
 			ExitCode: -1,
 			Stdout:   stdoutStr,
 			Stderr:   stderrStr,
-			Combined: stdoutStr + stderrStr,
 			Duration: time.Since(start),
 		}, fmt.Errorf("exec timed out after %v", timeout)
 	}
@@ -316,7 +376,6 @@ func (d *DockerClient) Exec(ctx context.Context, containerID string, cmd []strin
 				ExitCode: -1,
 				Stdout:   stdout.String(),
 				Stderr:   stderr.String(),
-				Combined: stdout.String() + stderr.String(),
 				Duration: time.Since(start),
 			}, fmt.Errorf("timeout waiting for exec exit code")
 		case <-time.After(50 * time.Millisecond):
@@ -330,7 +389,6 @@ func (d *DockerClient) Exec(ctx context.Context, containerID string, cmd []strin
 		ExitCode: exitCode,
 		Stdout:   stdout.String(),
 		Stderr:   stderr.String(),
-		Combined: stdout.String() + stderr.String(),
 		Duration: duration,
 	}, nil
 }
@@ -1,8 +1,25 @@
 package runner
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"runtime"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/pkg/stdcopy"
+
+	"github.com/lemon07r/sanityharness/internal/config"
 )
 
 func TestPlatformString(t *testing.T) {
@@ -61,3 +78,190 @@ func TestHostPlatformString(t *testing.T) {
 		t.Fatalf("hostPlatformString() = %q, want %q", got, want)
 	}
 }
+
+func TestContainerResources(t *testing.T) {
+	t.Parallel()
+
+	if res := containerResources(config.DockerConfig{}); res.Memory != 0 || res.MemorySwap != 0 || res.NanoCPUs != 0 {
+		t.Fatalf("unlimited resources = %+v, want zero", res)
+	}
+	res := containerResources(config.DockerConfig{MemoryLimitMB: 512, CPUQuota: 1.5})
+	if res.Memory != 512<<20 || res.MemorySwap != 512<<20 {
+		t.Fatalf("memory = %d swap = %d, want %d", res.Memory, res.MemorySwap, 512<<20)
+	}
+	if res.NanoCPUs != 1_500_000_000 {
+		t.Fatalf("NanoCPUs = %d, want 1500000000", res.NanoCPUs)
+	}
+}
+
+func TestHostConfig(t *testing.T) {
+	t.Parallel()
+
+	cacheMount := mount.Mount{Type: mount.TypeVolume, Source: "cache", Target: "/tmp/cache"}
+	hc := hostConfig(ContainerConfig{
+		WorkspaceDir: "/ws",
+		Mounts:       []mount.Mount{cacheMount},
+		Resources:    containerResources(config.DockerConfig{MemoryLimitMB: 256, CPUQuota: 2}),
+	})
+	if len(hc.Mounts) != 2 || hc.Mounts[0].Source != "/ws" || hc.Mounts[0].Target != "/workspace" || hc.Mounts[1] != cacheMount {
+		t.Fatalf("mounts = %+v", hc.Mounts)
+	}
+	if hc.Memory != 256<<20 || hc.NanoCPUs != 2_000_000_000 {
+		t.Fatalf("resources = %+v, want 256 MB and 2 CPUs", hc.Resources)
+	}
+}
+
+func TestHostConfigRemote(t *testing.T) {
+	t.Parallel()
+
+	hc := hostConfig(ContainerConfig{})
+	if len(hc.Mounts) != 0 {
+		t.Fatalf("mounts = %+v, want none without a workspace dir", hc.Mounts)
+	}
+}
+
+func TestNewAPIClientHost(t *testing.T) {
+	t.Parallel()
+
+	const host = "tcp://build-box:2375"
+	cli, err := newAPIClient(host)
+	if err != nil {
+		t.Fatalf("newAPIClient(%q) error: %v", host, err)
+	}
+	defer func() { _ = cli.Close() }()
+	if got := cli.DaemonHost(); got != host {
+		t.Fatalf("DaemonHost() = %q, want %q", got, host)
+	}
+}
+
+func TestRemoteFromDockerHostEnv(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "tcp://build-box:2375")
+
+	cli, err := newAPIClient("")
+	if err != nil {
+		t.Fatalf("newAPIClient() error: %v", err)
+	}
+	defer func() { _ = cli.Close() }()
+	r := &Runner{cfg: &config.Config{}, docker: &DockerClient{client: cli}}
+	if !r.remote() {
+		t.Fatalf("remote() = false with DOCKER_HOST=%s and no configured host", cli.DaemonHost())
+	}
+}
+
+// fakeExecDaemon serves the Docker API calls Exec makes, running each exec's
+// command on the host and multiplexing its output the way dockerd does.
+func fakeExecDaemon(t *testing.T) *httptest.Server {
+	t.Helper()
+	var (
+		mu       sync.Mutex
+		cmd      []string
+		exitCode int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/_ping"):
+			w.Header().Set("API-Version", "1.44")
+			_, _ = w.Write([]byte("OK"))
+		case strings.HasSuffix(req.URL.Path, "/exec"):
+			var opts container.ExecOptions
+			_ = json.NewDecoder(req.Body).Decode(&opts)
+			mu.Lock()
+			cmd = opts.Cmd
+			mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"Id":"exec1"}`))
+		case strings.HasSuffix(req.URL.Path, "/exec/exec1/start"):
+			conn, buf, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("hijack: %v", err)
+				return
+			}
+			defer func() { _ = conn.Close() }()
+			_, _ = fmt.Fprint(buf, "HTTP/1.1 101 UPGRADED\r\nContent-Type: application/vnd.docker.multiplexed-stream\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+			_ = buf.Flush()
+			mu.Lock()
+			c := exec.Command(cmd[0], cmd[1:]...)
+			mu.Unlock()
+			c.Stdout = stdcopy.NewStdWriter(conn, stdcopy.Stdout)
+			c.Stderr = stdcopy.NewStdWriter(conn, stdcopy.Stderr)
+			_ = c.Run()
+			mu.Lock()
+			exitCode = c.ProcessState.ExitCode()
+			mu.Unlock()
+		case strings.HasSuffix(req.URL.Path, "/exec/exec1/json"):
+			mu.Lock()
+			defer mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"Running":false,"ExitCode":%d}`, exitCode)
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestExecSeparatesStreams(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	srv := fakeExecDaemon(t)
+	cli, err := newAPIClient("tcp://" + strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatalf("newAPIClient() error: %v", err)
+	}
+	defer func() { _ = cli.Close() }()
+	d := &DockerClient{client: cli}
+
+	res, err := d.Exec(context.Background(), "c1", []string{"sh", "-c", "echo '--- FAIL: TestX'; echo 'panic: boom' >&2; exit 3"}, "/workspace", "", 10*time.Second)
+	if err != nil {
+		t.Fatalf("Exec() error: %v", err)
+	}
+	if res.Stdout != "--- FAIL: TestX\n" || res.Stderr != "panic: boom\n" || res.ExitCode != 3 {
+		t.Fatalf("Exec() = stdout %q, stderr %q, exit %d; want each stream on its own and exit 3", res.Stdout, res.Stderr, res.ExitCode)
+	}
+}
+
+func TestIsRemoteDockerHost(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]bool{
+		"":                            false,
+		"unix:///var/run/docker.sock": false,
+		"npipe:////./pipe/docker":     false,
+		"tcp://build-box:2375":        true,
+		"ssh://me@build-box":          true,
+	}
+	for host, want := range tests {
+		if got := isRemoteDockerHost(host); got != want {
+			t.Fatalf("isRemoteDockerHost(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestTarWorkspaceRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "sub", "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := tarWorkspace(src, "workspace/task", 1000, 1000)
+	if err != nil {
+		t.Fatalf("tarWorkspace error: %v", err)
+	}
+	dst := t.TempDir()
+	if err := untarWorkspace(archive, "workspace", dst); err != nil {
+		t.Fatalf("untarWorkspace error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dst, "task", "sub", "main.go"))
+	if err != nil || string(data) != "package main\n" {
+		t.Fatalf("extracted file = %q, %v", data, err)
+	}
+}
@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/lemon07r/sanityharness/internal/task"
+)
+
+// languageCacheEnv returns the cache-path variables the harness sets for a
+// language, pointing toolchain caches at the mounted cache directories.
+func languageCacheEnv(lang task.Language) []string {
+	switch lang {
+	case task.Rust:
+		return []string{
+			"CARGO_TARGET_DIR=/tmp/sanity-cargo-target",
+			"CARGO_HOME=/tmp/sanity-cargo-home",
+		}
+	case task.Go:
+		return []string{
+			"GOCACHE=/tmp/sanity-go-build-cache",
+			"GOMODCACHE=/tmp/sanity-go-mod-cache",
+		}
+	case task.TypeScript:
+		return []string{"npm_config_cache=/tmp/sanity-npm-cache"}
+	case task.Kotlin:
+		return []string{"GRADLE_USER_HOME=/tmp/sanity-gradle-home"}
+	case task.Dart:
+		return []string{"PUB_CACHE=/tmp/sanity-pub-cache"}
+	}
+	return nil
+}
+
+// containerEnvForTask returns the validation container environment for t:
+// HOME=/tmp, then the task's env in key order, then the language's cache
+// paths. Task env overrides defaults such as HOME but never the cache paths.
+func containerEnvForTask(t *task.Task) []string {
+	cacheEnv := languageCacheEnv(t.Language)
+	protected := make(map[string]bool, len(cacheEnv))
+	for _, kv := range cacheEnv {
+		key, _, _ := strings.Cut(kv, "=")
+		protected[key] = true
+	}
+
+	env := []string{}
+	if _, ok := t.Env["HOME"]; !ok {
+		env = append(env, "HOME=/tmp")
+	}
+	for _, key := range slices.Sorted(maps.Keys(t.Env)) {
+		if protected[key] {
+			continue
+		}
+		env = append(env, key+"="+t.Env[key])
+	}
+	return append(env, cacheEnv...)
+}
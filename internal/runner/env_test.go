@@ -0,0 +1,35 @@
+package runner
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lemon07r/sanityharness/internal/task"
+)
+
+func TestContainerEnvForTask(t *testing.T) {
+	t.Parallel()
+
+	base := containerEnvForTask(&task.Task{Language: task.Go})
+	want := []string{"HOME=/tmp", "GOCACHE=/tmp/sanity-go-build-cache", "GOMODCACHE=/tmp/sanity-go-mod-cache"}
+	if !slices.Equal(base, want) {
+		t.Fatalf("containerEnvForTask() = %v, want %v", base, want)
+	}
+	if got := containerEnvForTask(&task.Task{Language: task.Go, Env: map[string]string{}}); !slices.Equal(got, base) {
+		t.Fatalf("empty env: containerEnvForTask() = %v, want %v", got, base)
+	}
+
+	got := containerEnvForTask(&task.Task{Language: task.Go, Env: map[string]string{
+		"RAND_SEED": "42",
+		"GOFLAGS":   "-mod=mod",
+		"HOME":      "/home/task",
+		"GOCACHE":   "/elsewhere",
+	}})
+	want = []string{
+		"GOFLAGS=-mod=mod", "HOME=/home/task", "RAND_SEED=42",
+		"GOCACHE=/tmp/sanity-go-build-cache", "GOMODCACHE=/tmp/sanity-go-mod-cache",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("containerEnvForTask() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// execWatchdogMargin is how long a validation exec may overrun its timeout
+// (or its context's cancellation) before the watchdog abandons it.
+const execWatchdogMargin = 30 * time.Second
+
+// containerRemoveTimeout bounds a forced container removal, so cleanup of a
+// wedged container cannot itself hang the run.
+const containerRemoveTimeout = 30 * time.Second
+
+// execWithWatchdog runs exec, which should honour timeout and ctx itself.
+// If it is still running margin after timeout expires or ctx is done, kill
+// is called and a timeout error is returned without waiting further. Exec
+// can otherwise block forever when the Docker daemon stops responding.
+func execWithWatchdog(ctx context.Context, timeout, margin time.Duration, exec func() (*ExecResult, error), kill func()) (*ExecResult, error) {
+	type execOutcome struct {
+		result *ExecResult
+		err    error
+	}
+	start := time.Now()
+	done := make(chan execOutcome, 1)
+	go func() {
+		res, err := exec()
+		done <- execOutcome{res, err}
+	}()
+
+	watchdog := time.NewTimer(timeout + margin)
+	defer watchdog.Stop()
+	ctxDone := ctx.Done()
+	for {
+		select {
+		case out := <-done:
+			return out.result, out.err
+		case <-ctxDone:
+			ctxDone = nil
+			watchdog.Reset(margin)
+		case <-watchdog.C:
+			kill()
+			return &ExecResult{ExitCode: -1, Duration: time.Since(start)},
+				fmt.Errorf("exec timed out after %v (watchdog: no response %v past the deadline, container removed)", timeout, margin)
+		}
+	}
+}
+
+// execValidation runs cmd in the validation container under the exec
+// watchdog. A hung exec gets its container force-removed.
+func (r *Runner) execValidation(ctx context.Context, containerID string, cmd []string, opts RunOptions) (*ExecResult, error) {
+	timeout := time.Duration(opts.Timeout) * time.Second
+	return execWithWatchdog(ctx, timeout, execWatchdogMargin,
+		func() (*ExecResult, error) {
+			return r.docker.Exec(ctx, containerID, cmd, opts.execDir, opts.ValidationUser, timeout)
+		},
+		func() {
+			r.logger.Warn("validation exec hung past its timeout, removing container", "id", containerID[:12])
+			r.removeContainer(containerID)
+		})
+}
+
+// removeContainer force-removes a container, giving up after
+// containerRemoveTimeout.
+func (r *Runner) removeContainer(containerID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), containerRemoveTimeout)
+	defer cancel()
+	_ = r.docker.RemoveContainer(ctx, containerID, true)
+}
@@ -0,0 +1,190 @@
+package runner
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/lemon07r/sanityharness/internal/task"
+)
+
+// isRemoteDockerHost reports whether host is a Docker endpoint that cannot
+// see the harness's filesystem. Unix sockets and named pipes are local; any
+// other endpoint (tcp://, ssh://) is treated as remote.
+func isRemoteDockerHost(host string) bool {
+	return host != "" && !strings.HasPrefix(host, "unix://") && !strings.HasPrefix(host, "npipe://")
+}
+
+// remote reports whether validation containers run on a remote Docker host,
+// where workspaces are copied in rather than bind-mounted. It asks the client
+// rather than the config, so a tcp:// DOCKER_HOST counts too.
+func (r *Runner) remote() bool {
+	return isRemoteDockerHost(r.docker.Host())
+}
+
+// validate runs one validation exec. On a remote Docker host the workspace
+// is copied into the container first and, for tasks with JSON assertions,
+// copied back afterwards so the assertions see the files validation wrote.
+func (r *Runner) validate(ctx context.Context, t *task.Task, containerID string, cmd []string, opts RunOptions) (*ExecResult, error) {
+	if !r.remote() {
+		return r.execValidation(ctx, containerID, cmd, opts)
+	}
+	if err := r.copyWorkspaceIn(ctx, containerID, opts); err != nil {
+		return nil, err
+	}
+	execResult, err := r.execValidation(ctx, containerID, cmd, opts)
+	if err == nil && len(t.JSONAssertions) > 0 {
+		if copyErr := r.copyWorkspaceOut(ctx, containerID, opts); copyErr != nil {
+			r.logger.Warn("failed to copy workspace back from container", "error", copyErr)
+		}
+	}
+	return execResult, err
+}
+
+// copyWorkspaceIn copies the host workspace to the container's exec
+// directory.
+func (r *Runner) copyWorkspaceIn(ctx context.Context, containerID string, opts RunOptions) error {
+	archive, err := tarWorkspace(opts.workspaceDir, strings.TrimPrefix(opts.execDir, "/"), os.Getuid(), os.Getgid())
+	if err != nil {
+		return fmt.Errorf("archiving workspace: %w", err)
+	}
+	return r.docker.CopyToContainer(ctx, containerID, "/", archive)
+}
+
+// copyWorkspaceOut copies the container's exec directory back over the host
+// workspace.
+func (r *Runner) copyWorkspaceOut(ctx context.Context, containerID string, opts RunOptions) error {
+	rc, err := r.docker.CopyFromContainer(ctx, containerID, opts.execDir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+	return untarWorkspace(rc, path.Base(opts.execDir), opts.workspaceDir)
+}
+
+// tarWorkspace archives dir under the slash-separated path name, owned by
+// uid:gid so the container user can write to it. Each directory of name
+// gets its own entry, so extracting at "/" creates the full path.
+func tarWorkspace(dir, name string, uid, gid int) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	var parents []string
+	for p := name; p != "." && p != "/" && p != ""; p = path.Dir(p) {
+		parents = append([]string{p}, parents...)
+	}
+	for _, p := range parents[:max(len(parents)-1, 0)] {
+		if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: p + "/", Mode: 0o755, Uid: uid, Gid: gid}); err != nil {
+			return nil, err
+		}
+	}
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		var link string
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		} else if !info.Mode().IsRegular() && !info.IsDir() {
+			return nil
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = path.Join(name, filepath.ToSlash(rel))
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		hdr.Uid, hdr.Gid = uid, gid
+		hdr.Uname, hdr.Gname = "", ""
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
+
+// untarWorkspace extracts the directories and regular files under prefix in
+// the tar stream r into dir. Entries that would land outside dir are
+// rejected; links and other special files are skipped.
+func untarWorkspace(r io.Reader, prefix, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading archive: %w", err)
+		}
+		rel, ok := strings.CutPrefix(strings.TrimSuffix(hdr.Name, "/"), prefix+"/")
+		if !ok {
+			continue
+		}
+		if !filepath.IsLocal(filepath.FromSlash(rel)) {
+			return fmt.Errorf("archive entry %q escapes the workspace", hdr.Name)
+		}
+		target := filepath.Join(dir, filepath.FromSlash(rel))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := writeArchiveFile(target, tr, hdr.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeArchiveFile(target string, r io.Reader, perm fs.FileMode) error {
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,51 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/lemon07r/sanityharness/internal/config"
+)
+
+// oomExitCode is the exit code of a process killed with SIGKILL, which is
+// how the OOM killer ends a validation command.
+const oomExitCode = 137
+
+// containerResources converts the [docker] resource limits into container
+// resources. Swap is capped at the memory limit so a task cannot page its
+// way past it.
+func containerResources(dc config.DockerConfig) container.Resources {
+	var res container.Resources
+	if dc.MemoryLimitMB > 0 {
+		res.Memory = int64(dc.MemoryLimitMB) * 1024 * 1024
+		res.MemorySwap = res.Memory
+	}
+	if dc.CPUQuota > 0 {
+		res.NanoCPUs = int64(dc.CPUQuota * 1e9)
+	}
+	return res
+}
+
+// checkOOMKill reports whether a validation exec was killed for exceeding
+// memory_limit_mb, noting it in the exec output when it was.
+func (r *Runner) checkOOMKill(ctx context.Context, containerID string, execResult *ExecResult) bool {
+	if r.cfg.Docker.MemoryLimitMB <= 0 || execResult.ExitCode != oomExitCode {
+		return false
+	}
+	oomKilled, err := r.docker.OOMKilled(ctx, containerID)
+	if err != nil {
+		r.logger.Warn("failed to check for OOM kill", "container", containerID, "error", err)
+		return false
+	}
+	if !oomKilled {
+		return false
+	}
+	note := fmt.Sprintf("HARNESS: validation killed for exceeding memory_limit_mb = %d\n", r.cfg.Docker.MemoryLimitMB)
+	if execResult.Stderr != "" {
+		note = "\n" + note
+	}
+	execResult.Stderr += note
+	return true
+}
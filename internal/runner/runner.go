@@ -6,10 +6,13 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/docker/api/types/mount"
@@ -27,11 +30,49 @@ type Runner struct {
 	docker            *DockerClient
 	logger            *slog.Logger
 	LegacyHiddenTests bool // When true, include hidden tests in workspace init (pre-v1.6.0 behavior)
+	ReuseContainers   bool // When true, keep one container per language alive across runs
+
+	poolMu   sync.Mutex
+	pool     map[string]*pooledContainer // keyed by poolKey
+	poolRoot string                      // host dir mounted in pooled containers; created on first use
+}
+
+// pooledContainer is a validation container shared by runs when
+// ReuseContainers is set.
+type pooledContainer struct {
+	id      string
+	users   int  // runs currently using the container
+	retired bool // removed from the pool; deleted once users drops to zero
 }
 
+// Phase identifies the container lifecycle step a run was in when it failed.
+type Phase string
+
+// Run phases reported by PhaseError.
+const (
+	PhaseEnsureImage     Phase = "ensure-image"
+	PhaseCreateContainer Phase = "container-create"
+	PhaseStartContainer  Phase = "container-start"
+	PhaseExec            Phase = "exec"
+)
+
+// PhaseError wraps a run error with the phase it occurred in, so callers can
+// tell container setup failures apart from failures while running tests.
+type PhaseError struct {
+	Phase Phase
+	Err   error
+}
+
+func (e *PhaseError) Error() string { return e.Err.Error() }
+
+func (e *PhaseError) Unwrap() error { return e.Err }
+
+// IsSetup reports whether the error occurred before validation started.
+func (e *PhaseError) IsSetup() bool { return e.Phase != PhaseExec }
+
 // NewRunner creates a new runner.
 func NewRunner(cfg *config.Config, tasksFS embed.FS, tasksDir string, logger *slog.Logger) (*Runner, error) {
-	docker, err := NewDockerClient()
+	docker, err := NewDockerClient(cfg.Docker.Host)
 	if err != nil {
 		return nil, fmt.Errorf("creating docker client: %w", err)
 	}
@@ -60,11 +101,99 @@ func (r *Runner) ResolveTaskRef(ref string) (*task.Task, error) {
 	return t, nil
 }
 
-// Close cleans up runner resources.
+// Close cleans up runner resources, including pooled containers.
 func (r *Runner) Close() error {
+	r.poolMu.Lock()
+	for key, pc := range r.pool {
+		r.removeContainer(pc.id)
+		delete(r.pool, key)
+	}
+	if r.poolRoot != "" {
+		_ = os.RemoveAll(r.poolRoot)
+		r.poolRoot = ""
+	}
+	r.poolMu.Unlock()
 	return r.docker.Close()
 }
 
+// ImageProgress reports an image finished by EnsureImages.
+type ImageProgress struct {
+	Image string
+	Err   error
+	Done  int // images finished so far, including this one
+	Total int
+}
+
+// EnsureImages makes sure the images for the given tasks' languages are
+// available, pulling up to concurrency images at once. progress, when
+// non-nil, is called serially as each image finishes. Every image is
+// attempted; failures are joined into the returned error.
+func (r *Runner) EnsureImages(ctx context.Context, tasks []*task.Task, concurrency int, progress func(ImageProgress)) error {
+	ensure := func(ctx context.Context, image string) error {
+		return r.docker.EnsureImage(ctx, image, r.cfg.Docker.AutoPull)
+	}
+	return ensureImagesWith(ctx, r.imagesForTasks(tasks), concurrency, ensure, progress)
+}
+
+// imagesForTasks returns the distinct configured images for tasks, in first
+// use order. Assertion-only tasks run without a container and need none.
+func (r *Runner) imagesForTasks(tasks []*task.Task) []string {
+	seen := make(map[string]bool)
+	var images []string
+	for _, t := range tasks {
+		if t.AssertionOnly() {
+			continue
+		}
+		image := r.cfg.ImageForLanguage(string(t.Language))
+		if image == "" || seen[image] {
+			continue
+		}
+		seen[image] = true
+		images = append(images, image)
+	}
+	return images
+}
+
+func ensureImagesWith(
+	ctx context.Context,
+	images []string,
+	concurrency int,
+	ensure func(ctx context.Context, image string) error,
+	progress func(ImageProgress),
+) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		done int
+		errs []error
+	)
+	sem := make(chan struct{}, concurrency)
+	for _, image := range images {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			err := ensure(ctx, image)
+			<-sem
+
+			mu.Lock()
+			defer mu.Unlock()
+			done++
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", image, err))
+			}
+			if progress != nil {
+				progress(ImageProgress{Image: image, Err: err, Done: done, Total: len(images)})
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
 func (r *Runner) cacheMountsForLanguage(lang task.Language) ([]mount.Mount, error) {
 	// Cache directory lives alongside the workspace/session directories.
 	// It is safe to delete at any time; it only improves performance.
@@ -140,6 +269,26 @@ type RunOptions struct {
 	// ValidationCommand overrides the task's default validation command when set.
 	// The first element is the command, followed by args.
 	ValidationCommand []string
+
+	// ValidationUser runs the validation exec as this user ("uid" or
+	// "uid:gid") instead of the container's run-level user. Defaults to the
+	// task's validation_user when empty.
+	ValidationUser string
+
+	// execDir is the workspace path inside the container, set by Run.
+	execDir string
+
+	// workspaceDir is the absolute host workspace path, set by Run.
+	workspaceDir string
+
+	// Quiet suppresses the terminal result output, for auxiliary runs such
+	// as lint passes.
+	Quiet bool
+
+	// ContainerLogPath, when set, receives the raw stdout and stderr of every
+	// validation exec, appended one section per exec. Execs that fail before
+	// producing a result are recorded too, so infra errors stay debuggable.
+	ContainerLogPath string
 }
 
 // Run executes a task and returns the session result.
@@ -170,17 +319,29 @@ func (r *Runner) Run(ctx context.Context, opts RunOptions) (*result.Session, err
 	if opts.OutputDir == "" {
 		opts.OutputDir = r.cfg.Harness.SessionDir
 	}
+	if opts.ValidationUser == "" {
+		opts.ValidationUser = t.ValidationUser
+	}
+
+	// Assertion-only tasks are checked on the host and need no container
+	assertionOnly := t.AssertionOnly() && len(opts.ValidationCommand) == 0
 
 	// Get image for language
-	imageName := r.cfg.ImageForLanguage(string(t.Language))
-	if imageName == "" {
-		return nil, fmt.Errorf("no image configured for language: %s", t.Language)
-	}
+	var imageName string
+	var imageTime time.Duration
+	if !assertionOnly {
+		imageName = r.cfg.ImageForLanguage(string(t.Language))
+		if imageName == "" {
+			return nil, fmt.Errorf("no image configured for language: %s", t.Language)
+		}
 
-	// Ensure image is available
-	r.logger.Info("ensuring container image", "image", imageName)
-	if err := r.docker.EnsureImage(ctx, imageName, r.cfg.Docker.AutoPull); err != nil {
-		return nil, fmt.Errorf("ensuring image: %w", err)
+		// Ensure image is available
+		r.logger.Info("ensuring container image", "image", imageName)
+		imageStart := time.Now()
+		if err := r.docker.EnsureImage(ctx, imageName, r.cfg.Docker.AutoPull); err != nil {
+			return nil, &PhaseError{Phase: PhaseEnsureImage, Err: fmt.Errorf("ensuring image: %w", err)}
+		}
+		imageTime = time.Since(imageStart)
 	}
 
 	// Create session first so we can put workspace inside session directory
@@ -190,6 +351,7 @@ func (r *Runner) Run(ctx context.Context, opts RunOptions) (*result.Session, err
 		WatchMode:   opts.WatchMode,
 		Image:       imageName,
 	})
+	session.AddPhaseTime(string(PhaseEnsureImage), imageTime)
 
 	// Determine workspace directory - now inside the session folder
 	var workspaceDir string
@@ -209,68 +371,60 @@ func (r *Runner) Run(ctx context.Context, opts RunOptions) (*result.Session, err
 	if err := r.ensureWorkspace(t, workspaceDir); err != nil {
 		return nil, fmt.Errorf("setting up workspace: %w", err)
 	}
+	opts.workspaceDir = workspaceDir
 
-	// Create container
-	r.logger.Info("creating container", "workspace", workspaceDir)
-	containerUser := fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid())
-	containerEnv := []string{"HOME=/tmp"}
-
-	cacheMounts, err := r.cacheMountsForLanguage(t.Language)
-	if err != nil {
-		return nil, err
-	}
-	switch t.Language {
-	case task.Rust:
-		containerEnv = append(containerEnv,
-			"CARGO_TARGET_DIR=/tmp/sanity-cargo-target",
-			"CARGO_HOME=/tmp/sanity-cargo-home",
-		)
-	case task.Go:
-		containerEnv = append(containerEnv,
-			"GOCACHE=/tmp/sanity-go-build-cache",
-			"GOMODCACHE=/tmp/sanity-go-mod-cache",
-		)
-	case task.TypeScript:
-		containerEnv = append(containerEnv,
-			"npm_config_cache=/tmp/sanity-npm-cache",
-		)
-	case task.Kotlin:
-		containerEnv = append(containerEnv,
-			"GRADLE_USER_HOME=/tmp/sanity-gradle-home",
-		)
-	case task.Dart:
-		containerEnv = append(containerEnv,
-			"PUB_CACHE=/tmp/sanity-pub-cache",
-		)
-	}
-	containerID, err := r.docker.CreateContainer(ctx, ContainerConfig{
-		Image:        imageName,
-		WorkspaceDir: workspaceDir,
-		Name:         fmt.Sprintf("sanity-%s-%s-%d", t.Language, t.Slug, time.Now().UnixNano()),
-		User:         containerUser,
-		Env:          containerEnv,
-		Mounts:       cacheMounts,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("creating container: %w", err)
+	if assertionOnly {
+		r.runAssertions(t, session, workspaceDir, opts)
+		r.finishSession(t, session, workspaceDir, opts)
+		return session, nil
 	}
-	defer func() {
-		r.logger.Debug("cleaning up container", "id", containerID[:12])
-		_ = r.docker.RemoveContainer(context.Background(), containerID, true)
-	}()
 
-	// Start container
-	if err := r.docker.StartContainer(ctx, containerID); err != nil {
-		return nil, fmt.Errorf("starting container: %w", err)
+	// Create container, or reuse a pooled one
+	containerStart := time.Now()
+	var containerID string
+	var pooled *pooledContainer
+	if r.ReuseContainers && !opts.WatchMode {
+		pooled, err = r.acquireContainer(ctx, t, imageName)
+		if err != nil {
+			return nil, err
+		}
+		containerID = pooled.id
+		stageDir, err := r.stageWorkspace(workspaceDir)
+		if err != nil {
+			r.releaseContainer(pooled, false)
+			return nil, fmt.Errorf("staging workspace: %w", err)
+		}
+		opts.execDir = path.Join("/workspace", filepath.Base(stageDir))
+		if stageDir != workspaceDir {
+			opts.workspaceDir = stageDir
+			defer func() { _ = os.RemoveAll(stageDir) }()
+		}
+	} else {
+		containerID, err = r.startContainer(ctx, t, imageName, workspaceDir,
+			fmt.Sprintf("sanity-%s-%s-%d", t.Language, t.Slug, time.Now().UnixNano()))
+		if err != nil {
+			return nil, err
+		}
+		opts.execDir = "/workspace"
+		defer func() {
+			r.logger.Debug("cleaning up container", "id", containerID[:12])
+			r.removeContainer(containerID)
+		}()
 	}
+	session.AddPhaseTime(string(PhaseStartContainer), time.Since(containerStart))
 
 	// Create error summarizer
 	summarizer := errsummary.NewSummarizer(string(t.Language))
+	if lc, ok := r.cfg.Languages[string(t.Language)]; ok {
+		if err := summarizer.SetTestPatterns(lc.Parse.TestPassPattern, lc.Parse.TestFailPattern); err != nil {
+			r.logger.Warn("ignoring invalid test patterns", "language", t.Language, "error", err)
+		}
+	}
 
 	// Touch stub files to invalidate build cache (prevents false positives from stale cached binaries).
 	// This is necessary because Cargo uses mtime-based fingerprinting - if an agent doesn't modify
 	// the stub file, Cargo may reuse a cached binary from a previous successful run.
-	if err := r.touchStubFiles(workspaceDir, t); err != nil {
+	if err := r.touchStubFiles(opts.workspaceDir, t); err != nil {
 		r.logger.Warn("failed to touch stub files", "error", err)
 	}
 
@@ -280,7 +434,22 @@ func (r *Runner) Run(ctx context.Context, opts RunOptions) (*result.Session, err
 	} else {
 		err = r.runSingle(ctx, t, containerID, session, summarizer, opts)
 	}
+	if pooled != nil {
+		r.releaseContainer(pooled, shouldRetireContainer(session, err))
+		if opts.workspaceDir != workspaceDir {
+			if copyErr := copyTree(opts.workspaceDir, workspaceDir); copyErr != nil {
+				r.logger.Warn("failed to copy staged workspace back", "error", copyErr)
+			}
+		}
+	}
 
+	r.finishSession(t, session, workspaceDir, opts)
+	return session, err
+}
+
+// finishSession completes session, captures the final workspace code and
+// saves the session.
+func (r *Runner) finishSession(t *task.Task, session *result.Session, workspaceDir string, opts RunOptions) {
 	// Complete session
 	session.Complete()
 
@@ -293,8 +462,204 @@ func (r *Runner) Run(ctx context.Context, opts RunOptions) (*result.Session, err
 	if saveErr := session.Save(opts.OutputDir); saveErr != nil {
 		r.logger.Error("failed to save session", "error", saveErr)
 	}
+}
 
-	return session, err
+// startContainer creates and starts a validation container for t with
+// mountDir bind-mounted at /workspace. On a remote Docker host nothing is
+// mounted: host paths do not exist there, so validate copies the workspace
+// in instead and the build caches start empty.
+func (r *Runner) startContainer(ctx context.Context, t *task.Task, imageName, mountDir, name string) (string, error) {
+	r.logger.Info("creating container", "workspace", mountDir)
+	containerUser := fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid())
+	containerEnv := containerEnvForTask(t)
+
+	var cacheMounts []mount.Mount
+	if r.remote() {
+		mountDir = ""
+	} else {
+		var err error
+		if cacheMounts, err = r.cacheMountsForLanguage(t.Language); err != nil {
+			return "", err
+		}
+	}
+	containerID, err := r.docker.CreateContainer(ctx, ContainerConfig{
+		Image:        imageName,
+		WorkspaceDir: mountDir,
+		Name:         name,
+		User:         containerUser,
+		Env:          containerEnv,
+		Mounts:       cacheMounts,
+		Resources:    containerResources(r.cfg.Docker),
+	})
+	if err != nil {
+		return "", &PhaseError{Phase: PhaseCreateContainer, Err: fmt.Errorf("creating container: %w", err)}
+	}
+
+	// Start container
+	if err := r.docker.StartContainer(ctx, containerID); err != nil {
+		r.removeContainer(containerID)
+		return "", &PhaseError{Phase: PhaseStartContainer, Err: fmt.Errorf("starting container: %w", err)}
+	}
+	return containerID, nil
+}
+
+// poolKey identifies a pooled container: tasks share one when they have the
+// same language and image. Tasks with their own env additionally key on it
+// in acquireContainer.
+func poolKey(lang task.Language, imageName string) string {
+	return string(lang) + "|" + imageName
+}
+
+// poolDir returns the host directory pooled containers mount at /workspace,
+// creating it on first use. It is a fresh temp dir holding only the staged
+// workspaces of runs in flight, so no other host files are visible to the
+// code validation runs. Callers must hold poolMu.
+func (r *Runner) poolDir() (string, error) {
+	if r.poolRoot == "" {
+		dir, err := os.MkdirTemp("", "sanity-pool-*")
+		if err != nil {
+			return "", err
+		}
+		r.poolRoot = dir
+	}
+	return r.poolRoot, nil
+}
+
+// stageWorkspace copies workspaceDir into a new directory under the pool
+// root and returns it. On a remote Docker host nothing is mounted, so the
+// workspace is returned unchanged and validate copies it in.
+func (r *Runner) stageWorkspace(workspaceDir string) (string, error) {
+	if r.remote() {
+		return workspaceDir, nil
+	}
+	r.poolMu.Lock()
+	root, err := r.poolDir()
+	r.poolMu.Unlock()
+	if err != nil {
+		return "", err
+	}
+	stageDir, err := os.MkdirTemp(root, filepath.Base(workspaceDir)+"-*")
+	if err != nil {
+		return "", err
+	}
+	if err := copyTree(workspaceDir, stageDir); err != nil {
+		_ = os.RemoveAll(stageDir)
+		return "", err
+	}
+	return stageDir, nil
+}
+
+// copyTree copies the directories and regular files under src into dst,
+// replacing files that already exist there. Symlinks and other special
+// files are skipped.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		// Remove first: read-only files cannot be opened for writing.
+		if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return writeArchiveFile(target, f, info.Mode().Perm())
+	})
+}
+
+// acquireContainer returns a pooled container for t, starting one on first
+// use. The pooled container mounts the pool root, so each task execs in its
+// own staged subdirectory while toolchain state in the container persists.
+// Callers must pass the container to releaseContainer when done.
+func (r *Runner) acquireContainer(ctx context.Context, t *task.Task, imageName string) (*pooledContainer, error) {
+	key := poolKey(t.Language, imageName)
+	if len(t.Env) > 0 {
+		key += "|" + strings.Join(containerEnvForTask(t), "|")
+	}
+
+	r.poolMu.Lock()
+	if pc, ok := r.pool[key]; ok {
+		pc.users++
+		r.poolMu.Unlock()
+		return pc, nil
+	}
+	root, err := r.poolDir()
+	r.poolMu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("creating container pool dir: %w", err)
+	}
+
+	// Start the container without holding poolMu, so other runs are not
+	// blocked behind container startup.
+	id, err := r.startContainer(ctx, t, imageName, root,
+		fmt.Sprintf("sanity-%s-pool-%d", t.Language, time.Now().UnixNano()))
+	if err != nil {
+		return nil, err
+	}
+
+	r.poolMu.Lock()
+	if pc, ok := r.pool[key]; ok {
+		// Another run started one meanwhile; use it and drop ours.
+		pc.users++
+		r.poolMu.Unlock()
+		r.removeContainer(id)
+		return pc, nil
+	}
+	if r.pool == nil {
+		r.pool = make(map[string]*pooledContainer)
+	}
+	pc := &pooledContainer{id: id, users: 1}
+	r.pool[key] = pc
+	r.poolMu.Unlock()
+	return pc, nil
+}
+
+// releaseContainer ends one run's use of a pooled container. With retire
+// set the container leaves the pool, so later runs start a fresh one, and is
+// removed once no run is using it.
+func (r *Runner) releaseContainer(pc *pooledContainer, retire bool) {
+	r.poolMu.Lock()
+	pc.users--
+	if retire && !pc.retired {
+		pc.retired = true
+		for key, pooled := range r.pool {
+			if pooled == pc {
+				delete(r.pool, key)
+			}
+		}
+	}
+	remove := pc.retired && pc.users == 0
+	r.poolMu.Unlock()
+
+	if remove {
+		r.removeContainer(pc.id)
+	}
+}
+
+// shouldRetireContainer reports whether a pooled container is unfit for
+// later tasks after a run. A failed or timed-out exec may leave processes
+// running, and after an OOM kill the cgroup the tasks share may still be
+// near its limit.
+func shouldRetireContainer(session *result.Session, runErr error) bool {
+	return runErr != nil || session.Status == result.StatusOOM
 }
 
 // runSingle runs a single validation attempt.
@@ -304,18 +669,27 @@ func (r *Runner) runSingle(ctx context.Context, t *task.Task, containerID string
 		cmd = opts.ValidationCommand
 	}
 
-	execResult, err := r.docker.Exec(ctx, containerID, cmd, "/workspace", time.Duration(opts.Timeout)*time.Second)
+	execStart := time.Now()
+	execResult, err := r.validate(ctx, t, containerID, cmd, opts)
+	session.AddPhaseTime(string(PhaseExec), time.Since(execStart))
+	r.appendContainerLog(opts.ContainerLogPath, cmd, execResult, err)
 	if err != nil {
 		recordExecErrorAttempt(session, summarizer, execResult)
 		setSessionStatusFromExecError(session, err)
-		return fmt.Errorf("executing validation: %w", err)
+		return &PhaseError{Phase: PhaseExec, Err: fmt.Errorf("executing validation: %w", err)}
 	}
 
-	errorSummary := summarizer.Summarize(execResult.Combined)
-	session.AddAttempt(execResult.ExitCode, execResult.Duration, execResult.Combined, errorSummary)
+	oomKilled := r.checkOOMKill(ctx, containerID, execResult)
+	applyJSONAssertions(t, opts.workspaceDir, execResult)
+	addSummarizedAttempt(session, summarizer, execResult)
+	if oomKilled {
+		session.Status = result.StatusOOM
+	}
 
 	// Print result
-	fmt.Print(result.FormatTerminal(session, session.LastAttempt(), false))
+	if !opts.Quiet {
+		fmt.Print(result.FormatTerminal(session, session.LastAttempt(), false))
+	}
 
 	return nil
 }
@@ -383,18 +757,27 @@ func (r *Runner) runAttempt(ctx context.Context, t *task.Task, containerID strin
 		cmd = opts.ValidationCommand
 	}
 
-	execResult, err := r.docker.Exec(ctx, containerID, cmd, "/workspace", time.Duration(opts.Timeout)*time.Second)
+	execStart := time.Now()
+	execResult, err := r.validate(ctx, t, containerID, cmd, opts)
+	session.AddPhaseTime(string(PhaseExec), time.Since(execStart))
+	r.appendContainerLog(opts.ContainerLogPath, cmd, execResult, err)
 	if err != nil {
 		recordExecErrorAttempt(session, summarizer, execResult)
 		setSessionStatusFromExecError(session, err)
-		return fmt.Errorf("executing validation: %w", err)
+		return &PhaseError{Phase: PhaseExec, Err: fmt.Errorf("executing validation: %w", err)}
 	}
 
-	errorSummary := summarizer.Summarize(execResult.Combined)
-	session.AddAttempt(execResult.ExitCode, execResult.Duration, execResult.Combined, errorSummary)
+	oomKilled := r.checkOOMKill(ctx, containerID, execResult)
+	applyJSONAssertions(t, opts.workspaceDir, execResult)
+	addSummarizedAttempt(session, summarizer, execResult)
+	if oomKilled {
+		session.Status = result.StatusOOM
+	}
 
 	// Print result
-	fmt.Print(result.FormatTerminal(session, session.LastAttempt(), true))
+	if !opts.Quiet {
+		fmt.Print(result.FormatTerminal(session, session.LastAttempt(), true))
+	}
 
 	return nil
 }
@@ -414,8 +797,53 @@ func recordExecErrorAttempt(session *result.Session, summarizer *errsummary.Summ
 	if session == nil || summarizer == nil || execResult == nil {
 		return
 	}
-	errorSummary := summarizer.Summarize(execResult.Combined)
-	session.AddAttempt(execResult.ExitCode, execResult.Duration, execResult.Combined, errorSummary)
+	addSummarizedAttempt(session, summarizer, execResult)
+}
+
+// appendContainerLog appends one exec's raw output to path. A nil
+// execResult (the exec never ran) still records the command and error.
+func (r *Runner) appendContainerLog(path string, cmd []string, execResult *ExecResult, execErr error) {
+	if path == "" {
+		return
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "=== %s %s\n", time.Now().Format(time.RFC3339), strings.Join(cmd, " "))
+	if execErr != nil {
+		fmt.Fprintf(&sb, "error: %v\n", execErr)
+	}
+	if execResult != nil {
+		fmt.Fprintf(&sb, "exit code: %d, duration: %s\n", execResult.ExitCode, execResult.Duration.Round(time.Millisecond))
+		fmt.Fprintf(&sb, "--- stdout ---\n%s", execResult.Stdout)
+		if execResult.Stdout != "" && !strings.HasSuffix(execResult.Stdout, "\n") {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "--- stderr ---\n%s", execResult.Stderr)
+		if execResult.Stderr != "" && !strings.HasSuffix(execResult.Stderr, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString("\n")
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		r.logger.Warn("failed to open container log", "path", path, "error", err)
+		return
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.WriteString(sb.String()); err != nil {
+		r.logger.Warn("failed to write container log", "path", path, "error", err)
+	}
+}
+
+// addSummarizedAttempt records execResult as a session attempt with its
+// error summary and, when the language configures test patterns, its test
+// counts.
+func addSummarizedAttempt(session *result.Session, summarizer *errsummary.Summarizer, execResult *ExecResult) {
+	errorSummary := summarizer.SummarizeStreams(execResult.Stdout, execResult.Stderr)
+	session.AddAttempt(execResult.ExitCode, execResult.Duration, execResult.Stdout, execResult.Stderr, errorSummary)
+	if passed, failed, ok := summarizer.CountTests(execResult.Combined()); ok {
+		session.LastAttempt().Tests = &result.TestCounts{Passed: passed, Failed: failed}
+	}
 }
 
 // ensureWorkspace creates the workspace directory and copies task files.
@@ -479,7 +907,12 @@ func (r *Runner) copyTaskFiles(t *task.Task, destDir string, files []string) err
 			return fmt.Errorf("creating directory for %s: %w", destFilename, err)
 		}
 
-		if err := os.WriteFile(destPath, content, 0644); err != nil {
+		// Context files are reference material for the agent, not input.
+		mode := os.FileMode(0o644)
+		if t.IsContextFile(filename) {
+			mode = 0o444
+		}
+		if err := os.WriteFile(destPath, content, mode); err != nil {
 			return fmt.Errorf("writing file %s: %w", destFilename, err)
 		}
 	}
@@ -1,12 +1,20 @@
 package runner
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/lemon07r/sanityharness/internal/config"
 	errsummary "github.com/lemon07r/sanityharness/internal/errors"
 	"github.com/lemon07r/sanityharness/internal/result"
+	"github.com/lemon07r/sanityharness/internal/task"
 )
 
 func TestSetSessionStatusFromExecError(t *testing.T) {
@@ -32,7 +40,7 @@ func TestRecordExecErrorAttempt(t *testing.T) {
 	summarizer := errsummary.NewSummarizer("go")
 	execResult := &ExecResult{
 		ExitCode: -1,
-		Combined: "panic: timed out",
+		Stdout:   "panic: timed out",
 		Duration: 2 * time.Second,
 	}
 
@@ -45,10 +53,253 @@ func TestRecordExecErrorAttempt(t *testing.T) {
 	if got.ExitCode != -1 {
 		t.Fatalf("exit code = %d, want -1", got.ExitCode)
 	}
-	if got.RawOutput != execResult.Combined {
-		t.Fatalf("raw output = %q, want %q", got.RawOutput, execResult.Combined)
+	if got.RawOutput() != execResult.Combined() {
+		t.Fatalf("raw output = %q, want %q", got.RawOutput(), execResult.Combined())
 	}
 	if got.Duration != execResult.Duration {
 		t.Fatalf("duration = %s, want %s", got.Duration, execResult.Duration)
 	}
 }
+
+func TestAddSummarizedAttemptSeparatesStreams(t *testing.T) {
+	t.Parallel()
+
+	// A test run that writes progress to stdout and its failure to stderr.
+	session := result.NewSession("task", "go", result.SessionConfig{})
+	execResult := &ExecResult{
+		ExitCode: 1,
+		Stdout:   "=== RUN   TestAccount\n--- FAIL: TestAccount (0.00s)\n",
+		Stderr:   "panic: runtime error: index out of range [3] with length 3\n",
+	}
+
+	addSummarizedAttempt(session, errsummary.NewSummarizer("go"), execResult)
+
+	got := session.LastAttempt()
+	if got.Stdout != execResult.Stdout || got.Stderr != execResult.Stderr {
+		t.Fatalf("attempt streams = %q / %q, want %q / %q", got.Stdout, got.Stderr, execResult.Stdout, execResult.Stderr)
+	}
+	if got.RawOutput() != execResult.Combined() {
+		t.Fatalf("raw output = %q, want %q", got.RawOutput(), execResult.Combined())
+	}
+	if len(got.ErrorSummary) == 0 || !strings.Contains(got.ErrorSummary[0], "index out of range") {
+		t.Fatalf("error summary = %q, want the stderr panic first", got.ErrorSummary)
+	}
+}
+
+func TestAppendContainerLog(t *testing.T) {
+	t.Parallel()
+
+	r := &Runner{}
+	path := filepath.Join(t.TempDir(), "container.log")
+	r.appendContainerLog(path, []string{"go", "test"}, &ExecResult{
+		ExitCode: 2,
+		Stdout:   "FAIL example",
+		Stderr:   "runtime: out of memory\n",
+		Duration: time.Second,
+	}, nil)
+	r.appendContainerLog(path, []string{"go", "test"}, nil, errors.New("attaching to exec: EOF"))
+	r.appendContainerLog("", []string{"ignored"}, nil, nil)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	got := string(data)
+	for _, want := range []string{
+		"exit code: 2",
+		"--- stdout ---\nFAIL example\n",
+		"--- stderr ---\nruntime: out of memory\n",
+		"error: attaching to exec: EOF",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("container log missing %q:\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, "=== "); n != 2 {
+		t.Fatalf("got %d exec sections, want 2:\n%s", n, got)
+	}
+}
+
+func TestPhaseError(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("Error response from daemon: OCI runtime create failed")
+	var err error = &PhaseError{Phase: PhaseStartContainer, Err: fmt.Errorf("starting container: %w", cause)}
+	wrapped := fmt.Errorf("run: %w", err)
+
+	var phaseErr *PhaseError
+	if !errors.As(wrapped, &phaseErr) {
+		t.Fatal("errors.As() did not find PhaseError")
+	}
+	if phaseErr.Phase != PhaseStartContainer || !phaseErr.IsSetup() {
+		t.Fatalf("phase = %s, setup = %v; want %s, true", phaseErr.Phase, phaseErr.IsSetup(), PhaseStartContainer)
+	}
+	if !errors.Is(wrapped, cause) {
+		t.Fatal("errors.Is() did not find the underlying cause")
+	}
+	if got, want := err.Error(), "starting container: "+cause.Error(); got != want {
+		t.Fatalf("Error() = %q, want %q", got, want)
+	}
+	if (&PhaseError{Phase: PhaseExec, Err: cause}).IsSetup() {
+		t.Fatal("exec phase should not be a setup failure")
+	}
+}
+
+func TestContainerPool(t *testing.T) {
+	t.Parallel()
+
+	tk := &task.Task{Slug: "demo", Language: task.Go}
+	key := poolKey(task.Go, "img")
+	r := &Runner{pool: map[string]*pooledContainer{key: {id: "abc"}}}
+
+	pc, err := r.acquireContainer(context.Background(), tk, "img")
+	if err != nil {
+		t.Fatalf("acquireContainer() error = %v", err)
+	}
+	if pc.id != "abc" || pc.users != 1 {
+		t.Fatalf("pooled container = %+v, want id abc with 1 user", pc)
+	}
+	if _, err := r.acquireContainer(context.Background(), tk, "img"); err != nil {
+		t.Fatalf("acquireContainer() error = %v", err)
+	}
+
+	// Retiring with a second user still attached only removes it from the pool.
+	r.releaseContainer(pc, true)
+	if _, ok := r.pool[key]; ok {
+		t.Fatal("retired container should leave the pool")
+	}
+	if !pc.retired || pc.users != 1 {
+		t.Fatalf("pooled container = %+v, want retired with 1 user", pc)
+	}
+}
+
+func TestCopyTreeReplacesReadOnlyFiles(t *testing.T) {
+	t.Parallel()
+
+	src, dst := t.TempDir(), t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "sub", "ctx.md"), []byte("new"), 0o444); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dst, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dst, "sub", "ctx.md"), []byte("old"), 0o444); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := copyTree(src, dst); err != nil {
+		t.Fatalf("copyTree() error = %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dst, "sub", "ctx.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "new" {
+		t.Fatalf("copied file = %q, want %q", got, "new")
+	}
+}
+
+func TestShouldRetireContainer(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name   string
+		status result.Status
+		err    error
+		want   bool
+	}{
+		{name: "passed", status: result.StatusPass},
+		{name: "failed", status: result.StatusFail},
+		{name: "oom", status: result.StatusOOM, want: true},
+		{name: "exec error", status: result.StatusError, err: errors.New("boom"), want: true},
+	} {
+		session := result.NewSession("task", "go", result.SessionConfig{})
+		session.Status = tc.status
+		if got := shouldRetireContainer(session, tc.err); got != tc.want {
+			t.Errorf("%s: shouldRetireContainer() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestEnsureImagesWith(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Default
+	r := &Runner{cfg: &cfg}
+	images := r.imagesForTasks([]*task.Task{
+		{Language: task.Go}, {Language: task.Rust}, {Language: task.Go},
+		{Language: task.Dart, JSONAssertions: []task.JSONAssertion{{File: "out.json", Path: "ok"}}},
+	})
+	if len(images) != 2 || images[0] != cfg.Docker.GoImage || images[1] != cfg.Docker.RustImage {
+		t.Fatalf("imagesForTasks() = %v, want go and rust images once each", images)
+	}
+
+	var active, peak atomic.Int32
+	ensure := func(_ context.Context, image string) error {
+		if n := active.Add(1); n > peak.Load() {
+			peak.Store(n)
+		}
+		time.Sleep(10 * time.Millisecond)
+		active.Add(-1)
+		if image == "bad" {
+			return errors.New("pull denied")
+		}
+		return nil
+	}
+	var reports []ImageProgress
+	err := ensureImagesWith(context.Background(), []string{"a", "b", "bad", "c"}, 2, ensure, func(p ImageProgress) {
+		reports = append(reports, p)
+	})
+	if err == nil || !strings.Contains(err.Error(), "bad: pull denied") {
+		t.Fatalf("ensureImagesWith() error = %v, want bad image failure", err)
+	}
+	if peak.Load() > 2 {
+		t.Fatalf("peak concurrency = %d, want <= 2", peak.Load())
+	}
+	if len(reports) != 4 || reports[3].Done != 4 || reports[3].Total != 4 {
+		t.Fatalf("progress reports = %+v, want 4 ending at 4/4", reports)
+	}
+}
+
+func TestExecWithWatchdog(t *testing.T) {
+	t.Parallel()
+
+	want := &ExecResult{ExitCode: 0, Stdout: "ok"}
+	got, err := execWithWatchdog(context.Background(), time.Second, time.Second,
+		func() (*ExecResult, error) { return want, nil },
+		func() { t.Error("kill called for an exec that finished") })
+	if err != nil || got != want {
+		t.Fatalf("execWithWatchdog() = %+v, %v; want the exec result", got, err)
+	}
+
+	unblock := make(chan struct{})
+	var kills atomic.Int32
+	got, err = execWithWatchdog(context.Background(), 10*time.Millisecond, 10*time.Millisecond,
+		func() (*ExecResult, error) {
+			<-unblock
+			return nil, errors.New("connection closed")
+		},
+		func() {
+			kills.Add(1)
+			close(unblock)
+		})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("execWithWatchdog() error = %v, want a timeout", err)
+	}
+	if got == nil || got.ExitCode != -1 || kills.Load() != 1 {
+		t.Fatalf("hung exec: result=%+v kills=%d, want exit -1 and one kill", got, kills.Load())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	hung := make(chan struct{})
+	defer close(hung)
+	if _, err := execWithWatchdog(ctx, time.Hour, 10*time.Millisecond,
+		func() (*ExecResult, error) { <-hung; return nil, nil },
+		func() {}); err == nil {
+		t.Fatal("execWithWatchdog() with a cancelled context: want the watchdog to give up")
+	}
+}
@@ -0,0 +1,173 @@
+package task
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSONAssertion checks one value in a JSON file the agent produces, e.g.
+//
+//	[[json_assertions]]
+//	file = "output.json"
+//	path = ".server.port"
+//	equals = 8080
+//
+// Path is a jq-style path of .field and [index] steps; "." is the whole
+// document. Equals is compared after decoding both sides as JSON, so 8080
+// and 8080.0 are equal and tables compare as objects.
+type JSONAssertion struct {
+	File   string `json:"file"   toml:"file"`
+	Path   string `json:"path"   toml:"path"`
+	Equals any    `json:"equals" toml:"equals"`
+}
+
+// String renders the assertion as `file path == value`.
+func (a JSONAssertion) String() string {
+	want, _ := json.Marshal(a.Equals)
+	return fmt.Sprintf("%s %s == %s", a.File, a.Path, want)
+}
+
+// validate checks that the assertion is complete and its path parses.
+func (a JSONAssertion) validate() error {
+	if a.File == "" || a.Equals == nil {
+		return fmt.Errorf("json assertion %q needs file, path and equals", a.Path)
+	}
+	if _, err := parseJSONPath(a.Path); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Check evaluates the assertion against the file in workspaceDir. It returns
+// nil when the value at Path equals Equals.
+func (a JSONAssertion) Check(workspaceDir string) error {
+	data, err := os.ReadFile(filepath.Join(workspaceDir, a.File))
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", a.File, err)
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing %s: %w", a.File, err)
+	}
+	got, err := lookupJSONPath(doc, a.Path)
+	if err != nil {
+		return err
+	}
+
+	// Round-trip the expected value so TOML types (int64, nested tables)
+	// compare like the decoded JSON (float64, map[string]any).
+	wantJSON, err := json.Marshal(a.Equals)
+	if err != nil {
+		return fmt.Errorf("encoding expected value: %w", err)
+	}
+	var want any
+	if err := json.Unmarshal(wantJSON, &want); err != nil {
+		return fmt.Errorf("decoding expected value: %w", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.Marshal(got)
+		return fmt.Errorf("%s is %s, want %s", a.Path, gotJSON, wantJSON)
+	}
+	return nil
+}
+
+// jsonPathStep is one step of a parsed JSON path: an object key, or an
+// array index when isIndex is set.
+type jsonPathStep struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseJSONPath parses paths like ".a.b[0].c" and ".".
+func parseJSONPath(p string) ([]jsonPathStep, error) {
+	if !strings.HasPrefix(p, ".") {
+		return nil, fmt.Errorf("json path %q must start with \".\"", p)
+	}
+	var steps []jsonPathStep
+	rest := p[1:]
+	for rest != "" {
+		switch {
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("json path %q: unclosed [", p)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("json path %q: invalid index %q", p, rest[1:end])
+			}
+			steps = append(steps, jsonPathStep{index: index, isIndex: true})
+			rest = rest[end+1:]
+		case rest[0] == '.':
+			rest = rest[1:]
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			steps = append(steps, jsonPathStep{key: rest[:end]})
+			rest = rest[end:]
+		}
+		if strings.HasPrefix(rest, "..") {
+			return nil, fmt.Errorf("json path %q: empty key", p)
+		}
+	}
+	return steps, nil
+}
+
+// lookupJSONPath returns the value at path p in doc.
+func lookupJSONPath(doc any, p string) (any, error) {
+	steps, err := parseJSONPath(p)
+	if err != nil {
+		return nil, err
+	}
+	cur := doc
+	for _, s := range steps {
+		if s.isIndex {
+			arr, ok := cur.([]any)
+			if !ok || s.index >= len(arr) {
+				return nil, fmt.Errorf("%s: no element [%d]", p, s.index)
+			}
+			cur = arr[s.index]
+			continue
+		}
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s: no field %q", p, s.key)
+		}
+		if cur, ok = obj[s.key]; !ok {
+			return nil, fmt.Errorf("%s: no field %q", p, s.key)
+		}
+	}
+	return cur, nil
+}
+
+// AssertionOnly reports whether the task is validated by its JSON assertions
+// alone, without a validation command or container.
+func (t *Task) AssertionOnly() bool {
+	return t.Validation.Command == "" && len(t.JSONAssertions) > 0
+}
+
+// CheckJSONAssertions evaluates the task's JSON assertions against
+// workspaceDir. It returns one report line per assertion and an error
+// joining the failures.
+func (t *Task) CheckJSONAssertions(workspaceDir string) (report string, err error) {
+	var sb strings.Builder
+	var failures []error
+	for _, a := range t.JSONAssertions {
+		if checkErr := a.Check(workspaceDir); checkErr != nil {
+			fmt.Fprintf(&sb, "FAIL %s: %v\n", a, checkErr)
+			failures = append(failures, checkErr)
+			continue
+		}
+		fmt.Fprintf(&sb, "PASS %s\n", a)
+	}
+	return sb.String(), errors.Join(failures...)
+}
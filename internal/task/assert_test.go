@@ -0,0 +1,57 @@
+package task
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJSONAssertionCheck(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	doc := `{"field": "value", "server": {"port": 8080, "hosts": ["a", "b"]}, "tags": {"env": "prod"}}`
+	if err := os.WriteFile(filepath.Join(dir, "output.json"), []byte(doc), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	tests := []struct {
+		path    string
+		equals  any
+		wantErr bool
+	}{
+		{".field", "value", false},
+		{".server.port", int64(8080), false},
+		{".server.hosts[1]", "b", false},
+		{".tags", map[string]any{"env": "prod"}, false},
+		{".field", "other", true},
+		{".server.port", "8080", true},
+		{".server.hosts[2]", "c", true},
+		{".missing", "value", true},
+	}
+	for _, tc := range tests {
+		a := JSONAssertion{File: "output.json", Path: tc.path, Equals: tc.equals}
+		err := a.Check(dir)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("Check(%s) error = %v, wantErr %t", a, err, tc.wantErr)
+		}
+	}
+
+	if err := (JSONAssertion{File: "absent.json", Path: ".", Equals: 1}).Check(dir); err == nil {
+		t.Fatalf("Check on missing file: expected error")
+	}
+
+	tk := Task{JSONAssertions: []JSONAssertion{
+		{File: "output.json", Path: ".field", Equals: "value"},
+		{File: "output.json", Path: ".server.port", Equals: int64(80)},
+	}}
+	report, err := tk.CheckJSONAssertions(dir)
+	if err == nil {
+		t.Fatalf("CheckJSONAssertions: expected error")
+	}
+	if !strings.Contains(report, `PASS output.json .field == "value"`) ||
+		!strings.Contains(report, "FAIL output.json .server.port == 80: .server.port is 8080, want 80") {
+		t.Fatalf("unexpected report:\n%s", report)
+	}
+}
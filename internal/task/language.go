@@ -0,0 +1,84 @@
+package task
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CustomLanguage is a language defined in config ([languages.<name>]) rather
+// than built into the harness.
+type CustomLanguage struct {
+	Name              Language
+	Extension         string   // Primary source file extension, e.g. ".cpp"
+	ValidationCommand []string // Default [validation] for tasks that omit it
+}
+
+var (
+	customLanguagesMu sync.RWMutex
+	customLanguages   = map[Language]CustomLanguage{}
+)
+
+// languageNamePattern restricts custom language names to characters that are
+// safe in task IDs and directory names.
+var languageNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_+-]*$`)
+
+// RegisterLanguage makes a config-defined language known to ParseLanguage,
+// the task loader and Extension. Built-in languages cannot be redefined.
+// Registering the same name again replaces the earlier definition.
+func RegisterLanguage(cl CustomLanguage) error {
+	name := Language(strings.ToLower(string(cl.Name)))
+	if !languageNamePattern.MatchString(string(name)) {
+		return fmt.Errorf("invalid language name %q", cl.Name)
+	}
+	for _, builtin := range AllLanguages {
+		if name == builtin {
+			return fmt.Errorf("language %q is built in and cannot be redefined", name)
+		}
+	}
+	cl.Name = name
+	customLanguagesMu.Lock()
+	customLanguages[name] = cl
+	customLanguagesMu.Unlock()
+	return nil
+}
+
+// Languages returns the built-in languages followed by any registered custom
+// languages in name order.
+func Languages() []Language {
+	customLanguagesMu.RLock()
+	defer customLanguagesMu.RUnlock()
+
+	langs := make([]Language, 0, len(AllLanguages)+len(customLanguages))
+	langs = append(langs, AllLanguages...)
+	custom := make([]Language, 0, len(customLanguages))
+	for name := range customLanguages {
+		custom = append(custom, name)
+	}
+	sort.Slice(custom, func(i, j int) bool { return custom[i] < custom[j] })
+	return append(langs, custom...)
+}
+
+func lookupCustomLanguage(l Language) (CustomLanguage, bool) {
+	customLanguagesMu.RLock()
+	defer customLanguagesMu.RUnlock()
+	cl, ok := customLanguages[l]
+	return cl, ok
+}
+
+// applyLanguageDefaults fills in a custom language's default validation
+// command for tasks that do not declare one. Assertion-only tasks keep
+// their empty command.
+func applyLanguageDefaults(t *Task) {
+	if t.Validation.Command != "" || len(t.JSONAssertions) > 0 {
+		return
+	}
+	cl, ok := lookupCustomLanguage(t.Language)
+	if !ok || len(cl.ValidationCommand) == 0 {
+		return
+	}
+	t.Validation.Command = cl.ValidationCommand[0]
+	t.Validation.Args = append([]string(nil), cl.ValidationCommand[1:]...)
+}
@@ -0,0 +1,73 @@
+package task
+
+import (
+	"embed"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegisterLanguage(t *testing.T) {
+	t.Parallel()
+
+	if err := RegisterLanguage(CustomLanguage{
+		Name:              "cpp",
+		Extension:         ".cpp",
+		ValidationCommand: []string{"make", "test"},
+	}); err != nil {
+		t.Fatalf("RegisterLanguage() error = %v", err)
+	}
+	if err := RegisterLanguage(CustomLanguage{Name: "go"}); err == nil {
+		t.Error("expected error redefining a built-in language")
+	}
+	if err := RegisterLanguage(CustomLanguage{Name: "c/c++"}); err == nil {
+		t.Error("expected error for a name that is unsafe in task IDs")
+	}
+
+	lang, err := ParseLanguage("CPP")
+	if err != nil || lang != "cpp" {
+		t.Fatalf("ParseLanguage(CPP) = %q, %v; want cpp", lang, err)
+	}
+	if got := lang.Extension(); got != ".cpp" {
+		t.Errorf("Extension() = %q, want .cpp", got)
+	}
+	langs := Languages()
+	if langs[len(langs)-1] != "cpp" || langs[0] != Go {
+		t.Errorf("Languages() = %v, want built-ins first and cpp after them", langs)
+	}
+
+	dir := t.TempDir()
+	taskDir := filepath.Join(dir, "cpp", "hello")
+	if err := os.MkdirAll(taskDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	toml := strings.Join([]string{
+		`slug = "hello"`,
+		`name = "Hello"`,
+		`language = "cpp"`,
+		`difficulty = "hard"`,
+		`description = "Say hello"`,
+		`[files]`,
+		`stub = ["hello.cpp.txt"]`,
+		`test = ["hello_test.cpp.txt"]`,
+	}, "\n")
+	if err := os.WriteFile(filepath.Join(taskDir, "task.toml"), []byte(toml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"hello.cpp.txt", "hello_test.cpp.txt"} {
+		if err := os.WriteFile(filepath.Join(taskDir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tasks, err := NewLoader(embed.FS{}, dir).LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll() error = %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID() != "cpp/hello" {
+		t.Fatalf("LoadAll() = %v, want cpp/hello", tasks)
+	}
+	if got := strings.Join(tasks[0].ValidationCommand(), " "); got != "make test" {
+		t.Errorf("ValidationCommand() = %q, want default %q", got, "make test")
+	}
+}
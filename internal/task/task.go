@@ -9,6 +9,8 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
@@ -27,7 +29,8 @@ const (
 	Zig        Language = "zig"
 )
 
-// AllLanguages lists all supported languages.
+// AllLanguages lists the built-in languages. Languages also includes those
+// defined in config.
 var AllLanguages = []Language{Go, Rust, TypeScript, Kotlin, Dart, Zig}
 
 // ValidTiers lists valid tier values.
@@ -36,18 +39,39 @@ var ValidTiers = []string{"core", "extended"}
 // ValidDifficulties lists valid difficulty values.
 var ValidDifficulties = []string{"hard", "expert"}
 
+// ValidExpectedStatuses lists valid expected_status values.
+var ValidExpectedStatuses = []string{"pass", "fail"}
+
 // Task represents a single evaluation task.
 type Task struct {
-	Slug         string     `json:"slug"                    toml:"slug"`
-	Name         string     `json:"name"                    toml:"name"`
-	Language     Language   `json:"language"                toml:"language"`
-	Tier         string     `json:"tier,omitempty"          toml:"tier,omitempty"`
-	Difficulty   string     `json:"difficulty"              toml:"difficulty"`
-	Description  string     `json:"description"             toml:"description"`
-	Timeout      int        `json:"timeout,omitempty"       toml:"timeout,omitempty"`
-	AgentTimeout int        `json:"agent_timeout,omitempty" toml:"agent_timeout,omitempty"`
-	Files        TaskFiles  `json:"files"                   toml:"files"`
-	Validation   Validation `json:"validation"              toml:"validation"`
+	Slug           string     `json:"slug"                      toml:"slug"`
+	Name           string     `json:"name"                      toml:"name"`
+	Language       Language   `json:"language"                  toml:"language"`
+	Tier           string     `json:"tier,omitempty"            toml:"tier,omitempty"`
+	Difficulty     string     `json:"difficulty"                toml:"difficulty"`
+	Description    string     `json:"description"               toml:"description"`
+	PromptHint     string     `json:"prompt_hint,omitempty"     toml:"prompt_hint,omitempty"` // Clarification appended to the description in agent prompts; part of the task hash
+	Timeout        int        `json:"timeout,omitempty"         toml:"timeout,omitempty"`
+	AgentTimeout   int        `json:"agent_timeout,omitempty"   toml:"agent_timeout,omitempty"`
+	ValidationUser string     `json:"validation_user,omitempty" toml:"validation_user,omitempty"` // Container user for validation ("uid" or "uid:gid"); empty = run-level user
+	EditableFiles  []string   `json:"editable_files,omitempty"  toml:"editable_files,omitempty"`  // Extra workspace paths/globs the agent may edit or create
+	ContextFiles   []string   `json:"context_files,omitempty"   toml:"context_files,omitempty"`   // Reference material copied read-only into the workspace; never validated
+	NoNewFiles     bool       `json:"no_new_files,omitempty"    toml:"no_new_files,omitempty"`    // Forbid creating files other than stubs and editable_files
+	ExpectedStatus string     `json:"expected_status,omitempty" toml:"expected_status,omitempty"` // "pass" (default) or "fail" for known-unsolvable tasks
+	RelevantSkill  string     `json:"relevant_skill,omitempty"  toml:"relevant_skill,omitempty"`  // Skill the task is designed to exercise, for skills-usage analysis
+	Coverage       bool       `json:"coverage,omitempty"        toml:"coverage,omitempty"`        // Run validation with the language's coverage flags and record the percentage
+	Files          TaskFiles  `json:"files"                     toml:"files"`
+	Validation     Validation `json:"validation"                toml:"validation"`
+	Variants       []Variant  `json:"variants,omitempty"        toml:"variants,omitempty"`
+
+	// JSONAssertions are checked by the harness against workspace files after
+	// validation; with no validation command they are the whole check and
+	// the task runs without a container.
+	JSONAssertions []JSONAssertion `json:"json_assertions,omitempty" toml:"json_assertions,omitempty"`
+
+	// Env sets extra environment variables in the validation container,
+	// e.g. GOFLAGS or a fixed RAND_SEED.
+	Env map[string]string `json:"env,omitempty" toml:"env,omitempty"`
 }
 
 // ID returns the canonical task identifier in the form "<language>/<slug>".
@@ -61,6 +85,14 @@ type TaskFiles struct {
 	Test       []string `json:"test"                  toml:"test"`
 	HiddenTest []string `json:"hidden_test,omitempty" toml:"hidden_test,omitempty"`
 	Support    []string `json:"support,omitempty"     toml:"support,omitempty"`
+	// RobustnessTests are extra (e.g. mutation-derived) tests run only with
+	// `sanity eval --robustness` against solutions that already passed. They
+	// are not part of AllFiles, so legacy mode never exposes them.
+	RobustnessTests []string `json:"robustness_tests,omitempty" toml:"robustness_tests,omitempty"`
+	// Regenerable are support files the toolchain may legitimately rewrite
+	// while the agent self-tests (lockfiles, go.sum). Each must also be
+	// listed under Support; eval skips them in the integrity check.
+	Regenerable []string `json:"regenerable,omitempty" toml:"regenerable,omitempty"`
 }
 
 // Validation specifies how to validate a task solution.
@@ -69,32 +101,95 @@ type Validation struct {
 	Args    []string `json:"args"    toml:"args"`
 }
 
+// Variant is a named parameter set. Each variant re-runs validation against
+// the same agent solution with its params exported as environment variables,
+// e.g. to check correctness at small N and performance at large N.
+type Variant struct {
+	Name   string            `json:"name"             toml:"name"`
+	Params map[string]string `json:"params,omitempty" toml:"params,omitempty"`
+}
+
+// variantNamePattern restricts variant names to characters that are safe in
+// artifact file names.
+var variantNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
 // VisibleFiles returns the files that should be visible to the agent initially.
 func (t *Task) VisibleFiles() []string {
-	files := make([]string, 0, len(t.Files.Stub)+len(t.Files.Test)+len(t.Files.Support))
+	files := make([]string, 0, len(t.Files.Stub)+len(t.Files.Test)+len(t.Files.Support)+len(t.ContextFiles))
 	files = append(files, t.Files.Stub...)
 	files = append(files, t.Files.Test...)
 	files = append(files, t.Files.Support...)
+	files = append(files, t.ContextFiles...)
 	return files
 }
 
 // AllFiles returns all files associated with this task, including hidden tests.
 func (t *Task) AllFiles() []string {
-	files := make([]string, 0, len(t.Files.Stub)+len(t.Files.Test)+len(t.Files.HiddenTest)+len(t.Files.Support))
+	files := make([]string, 0, len(t.Files.Stub)+len(t.Files.Test)+len(t.Files.HiddenTest)+len(t.Files.Support)+len(t.ContextFiles))
 	files = append(files, t.Files.Stub...)
 	files = append(files, t.Files.Test...)
 	files = append(files, t.Files.HiddenTest...)
 	files = append(files, t.Files.Support...)
+	files = append(files, t.ContextFiles...)
 	return files
 }
 
+// IsContextFile reports whether filename, as listed in the manifest, is one
+// of the task's context files.
+func (t *Task) IsContextFile(filename string) bool {
+	return slices.Contains(t.ContextFiles, filename)
+}
+
+// ExpectsFailure reports whether the task is marked expected_status = "fail".
+func (t *Task) ExpectsFailure() bool {
+	return t.ExpectedStatus == "fail"
+}
+
 // HiddenTestFiles returns the hidden test files for this task.
 func (t *Task) HiddenTestFiles() []string {
 	return t.Files.HiddenTest
 }
 
-// ValidationCommand returns the full command to run for validation.
+// RobustnessTestFiles returns the robustness test files for this task.
+func (t *Task) RobustnessTestFiles() []string {
+	return t.Files.RobustnessTests
+}
+
+// IsEditable reports whether the agent may modify or create the given
+// workspace-relative path: stub files plus anything matching EditableFiles.
+// Test files are never editable.
+func (t *Task) IsEditable(workspacePath string) bool {
+	workspacePath = filepath.ToSlash(workspacePath)
+	for _, f := range t.Files.Test {
+		if StripTxtExtension(f) == workspacePath {
+			return false
+		}
+	}
+	for _, f := range t.Files.Stub {
+		if StripTxtExtension(f) == workspacePath {
+			return true
+		}
+	}
+	for _, pattern := range t.EditableFiles {
+		if ok, err := path.Match(pattern, workspacePath); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRegenerable reports whether filename is a support file listed in
+// Files.Regenerable, whose changes are not integrity violations.
+func (t *Task) IsRegenerable(filename string) bool {
+	return slices.Contains(t.Files.Regenerable, filename)
+}
+
+// ValidationCommand returns the full command to run for validation, or nil
+// for assertion-only tasks.
 func (t *Task) ValidationCommand() []string {
+	if t.Validation.Command == "" {
+		return nil
+	}
 	cmd := make([]string, 0, 1+len(t.Validation.Args))
 	cmd = append(cmd, t.Validation.Command)
 	cmd = append(cmd, t.Validation.Args...)
@@ -139,15 +234,61 @@ func (t *Task) Validate() error {
 			return fmt.Errorf("invalid difficulty %q: must be one of %v", t.Difficulty, ValidDifficulties)
 		}
 	}
-	if t.Validation.Command == "" {
+	// Validate expected status if specified
+	if t.ExpectedStatus != "" {
+		validStatus := false
+		for _, status := range ValidExpectedStatuses {
+			if t.ExpectedStatus == status {
+				validStatus = true
+				break
+			}
+		}
+		if !validStatus {
+			return fmt.Errorf("invalid expected_status %q: must be one of %v", t.ExpectedStatus, ValidExpectedStatuses)
+		}
+	}
+	if t.Validation.Command == "" && len(t.JSONAssertions) == 0 {
 		return errors.New("task validation command is required")
 	}
 	if len(t.Files.Stub) == 0 {
 		return fmt.Errorf("task %s has no stub files", t.Slug)
 	}
-	if len(t.Files.Test) == 0 {
+	if len(t.Files.Test) == 0 && !t.AssertionOnly() {
 		return fmt.Errorf("task %s has no test files", t.Slug)
 	}
+	for _, a := range t.JSONAssertions {
+		if err := a.validate(); err != nil {
+			return fmt.Errorf("task %s: %w", t.Slug, err)
+		}
+	}
+	for _, pattern := range t.EditableFiles {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("task %s has invalid editable_files pattern %q: %w", t.Slug, pattern, err)
+		}
+	}
+	for _, f := range t.Files.Regenerable {
+		if !slices.Contains(t.Files.Support, f) {
+			return fmt.Errorf("task %s lists regenerable file %q that is not a support file", t.Slug, f)
+		}
+		if slices.Contains(t.Files.Test, f) || slices.Contains(t.Files.HiddenTest, f) {
+			return fmt.Errorf("task %s lists test file %q as regenerable", t.Slug, f)
+		}
+	}
+	for _, f := range t.ContextFiles {
+		if t.IsEditable(StripTxtExtension(f)) || slices.Contains(t.Files.Test, f) || slices.Contains(t.Files.Support, f) {
+			return fmt.Errorf("task %s lists context file %q as a stub, test, support or editable file too", t.Slug, f)
+		}
+	}
+	seen := make(map[string]bool, len(t.Variants))
+	for _, v := range t.Variants {
+		if !variantNamePattern.MatchString(v.Name) {
+			return fmt.Errorf("task %s has invalid variant name %q", t.Slug, v.Name)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("task %s has duplicate variant %q", t.Slug, v.Name)
+		}
+		seen[v.Name] = true
+	}
 	return nil
 }
 
@@ -224,7 +365,7 @@ func (l *Loader) LoadByLanguage(lang Language) ([]*Task, error) {
 func (l *Loader) loadFromEmbed() ([]*Task, error) {
 	var tasks []*Task
 
-	for _, lang := range AllLanguages {
+	for _, lang := range Languages() {
 		langDir := string(lang) // The embed is from tasks/, so paths are relative to that
 		entries, err := fs.ReadDir(l.embeddedFS, langDir)
 		if err != nil {
@@ -252,9 +393,13 @@ func (l *Loader) loadFromEmbed() ([]*Task, error) {
 			if task.Tier == "" {
 				task.Tier = "core"
 			}
+			applyLanguageDefaults(&task)
 			if err := task.Validate(); err != nil {
 				return nil, fmt.Errorf("invalid task %s: %w", taskPath, err)
 			}
+			if err := l.checkFiles(&task); err != nil {
+				return nil, err
+			}
 
 			tasks = append(tasks, &task)
 		}
@@ -274,7 +419,7 @@ func (l *Loader) loadFromEmbed() ([]*Task, error) {
 func (l *Loader) loadFromDir(dir string) ([]*Task, error) {
 	var tasks []*Task
 
-	for _, lang := range AllLanguages {
+	for _, lang := range Languages() {
 		langDir := filepath.Join(dir, string(lang))
 		entries, err := os.ReadDir(langDir)
 		if err != nil {
@@ -299,9 +444,15 @@ func (l *Loader) loadFromDir(dir string) ([]*Task, error) {
 			if task.Tier == "" {
 				task.Tier = "core"
 			}
+			applyLanguageDefaults(&task)
 			if err := task.Validate(); err != nil {
 				continue // Skip invalid tasks in external dir
 			}
+			// A well-formed task that lists a missing file is an authoring
+			// mistake, reported now rather than at workspace init.
+			if err := l.checkFiles(&task); err != nil {
+				return nil, err
+			}
 
 			tasks = append(tasks, &task)
 		}
@@ -317,6 +468,27 @@ func (l *Loader) loadFromDir(dir string) ([]*Task, error) {
 	return tasks, nil
 }
 
+// checkFiles checks that every file the task lists exists and is readable.
+func (l *Loader) checkFiles(task *Task) error {
+	files := append(task.AllFiles(), task.RobustnessTestFiles()...)
+	taskDir := l.GetTaskDir(task)
+	for _, name := range files {
+		var err error
+		if l.externalDir != "" {
+			var f *os.File
+			if f, err = os.Open(filepath.Join(taskDir, name)); err == nil {
+				_ = f.Close()
+			}
+		} else {
+			_, err = fs.Stat(l.embeddedFS, path.Join(taskDir, name))
+		}
+		if err != nil {
+			return fmt.Errorf("task %s lists file %q that cannot be read: %w", task.ID(), name, err)
+		}
+	}
+	return nil
+}
+
 // GetTaskDir returns the directory path for a task.
 // For embedded tasks, this returns the path relative to the embedded FS root.
 // For external tasks, this returns the absolute filesystem path.
@@ -341,6 +513,37 @@ func (l *Loader) ReadTaskFile(task *Task, filename string) ([]byte, error) {
 	return l.embeddedFS.ReadFile(filePath)
 }
 
+// HashContent returns the task content covered by its attestation hash: the
+// stub, test, support and context files, followed by the prompt hint, JSON
+// assertions and regenerable files if any. Files that cannot be read are
+// skipped.
+func (l *Loader) HashContent(task *Task) []byte {
+	var content []byte
+	for _, f := range task.VisibleFiles() {
+		if data, err := l.ReadTaskFile(task, f); err == nil {
+			content = append(content, data...)
+		}
+	}
+	if task.PromptHint != "" {
+		content = append(content, "\nprompt_hint: "+task.PromptHint...)
+	}
+	for _, a := range task.JSONAssertions {
+		content = append(content, "\njson_assertion: "+a.String()...)
+	}
+	for _, f := range task.Files.Regenerable {
+		content = append(content, "\nregenerable: "+f...)
+	}
+	keys := make([]string, 0, len(task.Env))
+	for k := range task.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		content = append(content, "\nenv: "+k+"="+task.Env[k]...)
+	}
+	return content
+}
+
 // ParseTaskID parses a canonical task identifier in the form "<language>/<slug>".
 // Returns ok=false if the input is not in task ID form.
 func ParseTaskID(s string) (lang Language, slug string, ok bool) {
@@ -417,6 +620,9 @@ func ParseLanguage(s string) (Language, error) {
 	case "zig":
 		return Zig, nil
 	default:
+		if cl, ok := lookupCustomLanguage(Language(strings.ToLower(s))); ok {
+			return cl.Name, nil
+		}
 		return "", fmt.Errorf("unknown language: %s", s)
 	}
 }
@@ -442,7 +648,8 @@ func (l Language) Extension() string {
 	case Zig:
 		return ".zig"
 	default:
-		return ""
+		cl, _ := lookupCustomLanguage(l)
+		return cl.Extension
 	}
 }
 
@@ -1,6 +1,10 @@
 package task
 
 import (
+	"embed"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -276,6 +280,31 @@ func TestTaskValidationCommand(t *testing.T) {
 	}
 }
 
+func TestTaskIsEditable(t *testing.T) {
+	t.Parallel()
+
+	tk := Task{
+		EditableFiles: []string{"go.mod", "internal/*.go", "sort_test.go"},
+		Files: TaskFiles{
+			Stub:    []string{"sort.go.txt"},
+			Test:    []string{"sort_test.go.txt"},
+			Support: []string{"go.mod.txt"},
+		},
+	}
+
+	for path, want := range map[string]bool{
+		"sort.go":          true,
+		"go.mod":           true,
+		"internal/heap.go": true,
+		"sort_test.go":     false, // test files are never editable
+		"helper.go":        false,
+	} {
+		if got := tk.IsEditable(path); got != want {
+			t.Errorf("IsEditable(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
 func TestTaskValidate(t *testing.T) {
 	t.Parallel()
 
@@ -357,6 +386,172 @@ func TestTaskValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid variants",
+			task: Task{
+				Slug:     "test",
+				Language: Go,
+				Files: TaskFiles{
+					Stub: []string{"main.go"},
+					Test: []string{"main_test.go"},
+				},
+				Validation: Validation{Command: "go"},
+				Variants: []Variant{
+					{Name: "small", Params: map[string]string{"N": "1000"}},
+					{Name: "large", Params: map[string]string{"N": "1000000"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "duplicate variant name",
+			task: Task{
+				Slug:     "test",
+				Language: Go,
+				Files: TaskFiles{
+					Stub: []string{"main.go"},
+					Test: []string{"main_test.go"},
+				},
+				Validation: Validation{Command: "go"},
+				Variants:   []Variant{{Name: "small"}, {Name: "small"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid variant name",
+			task: Task{
+				Slug:     "test",
+				Language: Go,
+				Files: TaskFiles{
+					Stub: []string{"main.go"},
+					Test: []string{"main_test.go"},
+				},
+				Validation: Validation{Command: "go"},
+				Variants:   []Variant{{Name: "../escape"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "expected failure",
+			task: Task{
+				Slug:     "test",
+				Language: Go,
+				Files: TaskFiles{
+					Stub: []string{"main.go"},
+					Test: []string{"main_test.go"},
+				},
+				Validation:     Validation{Command: "go"},
+				ExpectedStatus: "fail",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid expected status",
+			task: Task{
+				Slug:     "test",
+				Language: Go,
+				Files: TaskFiles{
+					Stub: []string{"main.go"},
+					Test: []string{"main_test.go"},
+				},
+				Validation:     Validation{Command: "go"},
+				ExpectedStatus: "flaky",
+			},
+			wantErr: true,
+		},
+		{
+			name: "context file",
+			task: Task{
+				Slug:     "test",
+				Language: Go,
+				Files: TaskFiles{
+					Stub: []string{"main.go"},
+					Test: []string{"main_test.go"},
+				},
+				Validation:   Validation{Command: "go"},
+				ContextFiles: []string{"SPEC.md.txt"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "context file also editable",
+			task: Task{
+				Slug:     "test",
+				Language: Go,
+				Files: TaskFiles{
+					Stub: []string{"main.go"},
+					Test: []string{"main_test.go"},
+				},
+				Validation:    Validation{Command: "go"},
+				EditableFiles: []string{"*.md"},
+				ContextFiles:  []string{"SPEC.md.txt"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "regenerable support file",
+			task: Task{
+				Slug:     "test",
+				Language: Go,
+				Files: TaskFiles{
+					Stub:        []string{"main.go"},
+					Test:        []string{"main_test.go"},
+					Support:     []string{"go.mod", "go.sum"},
+					Regenerable: []string{"go.sum"},
+				},
+				Validation: Validation{Command: "go"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "regenerable file not listed as support",
+			task: Task{
+				Slug:     "test",
+				Language: Go,
+				Files: TaskFiles{
+					Stub:        []string{"main.go"},
+					Test:        []string{"main_test.go"},
+					Regenerable: []string{"go.sum"},
+				},
+				Validation: Validation{Command: "go"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "regenerable test file",
+			task: Task{
+				Slug:     "test",
+				Language: Go,
+				Files: TaskFiles{
+					Stub:        []string{"main.go"},
+					Test:        []string{"main_test.go"},
+					Support:     []string{"main_test.go"},
+					Regenerable: []string{"main_test.go"},
+				},
+				Validation: Validation{Command: "go"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "assertion-only task",
+			task: Task{
+				Slug:           "test",
+				Language:       Go,
+				Files:          TaskFiles{Stub: []string{"output.json"}},
+				JSONAssertions: []JSONAssertion{{File: "output.json", Path: ".field", Equals: "value"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "json assertion with invalid path",
+			task: Task{
+				Slug:           "test",
+				Language:       Go,
+				Files:          TaskFiles{Stub: []string{"output.json"}},
+				JSONAssertions: []JSONAssertion{{File: "output.json", Path: "field", Equals: "value"}},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -372,3 +567,81 @@ func TestTaskValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadAllReportsMissingTaskFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	taskDir := filepath.Join(dir, "go", "bank-account")
+	if err := os.MkdirAll(taskDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := strings.Join([]string{
+		`slug = "bank-account"`,
+		`name = "Bank Account"`,
+		`language = "go"`,
+		`difficulty = "hard"`,
+		`description = "Implement a bank account"`,
+		`[files]`,
+		`stub = ["bank_account.go.txt"]`,
+		`test = ["bank_account_test.go.txt"]`,
+		`[validation]`,
+		`command = "go"`,
+		`args = ["test", "./..."]`,
+	}, "\n")
+	if err := os.WriteFile(filepath.Join(taskDir, "task.toml"), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(taskDir, "bank_account.go.txt"), []byte("package bank\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewLoader(embed.FS{}, dir).LoadAll()
+	if err == nil || !strings.Contains(err.Error(), "go/bank-account") || !strings.Contains(err.Error(), "bank_account_test.go.txt") {
+		t.Fatalf("LoadAll() error = %v, want one naming the task and the missing file", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(taskDir, "bank_account_test.go.txt"), []byte("package bank\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if tasks, err := NewLoader(embed.FS{}, dir).LoadAll(); err != nil || len(tasks) != 1 {
+		t.Fatalf("LoadAll() = %v, %v, want the task once its files exist", tasks, err)
+	}
+}
+
+func TestHashContentIncludesPromptHint(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	taskDir := filepath.Join(dir, "go", "demo")
+	if err := os.MkdirAll(taskDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(taskDir, "demo.go.txt"), []byte("package demo\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loader := NewLoader(embed.FS{}, dir)
+	task := &Task{Slug: "demo", Language: Go, Files: TaskFiles{Stub: []string{"demo.go.txt"}}}
+
+	plain := string(loader.HashContent(task))
+	if plain != "package demo\n" {
+		t.Fatalf("HashContent() = %q, want the stub content", plain)
+	}
+	if err := os.WriteFile(filepath.Join(taskDir, "SPEC.md.txt"), []byte("# Spec\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	task.ContextFiles = []string{"SPEC.md.txt"}
+	if withContext := string(loader.HashContent(task)); withContext != "package demo\n# Spec\n" {
+		t.Fatalf("HashContent() = %q, want the stub and context file content", withContext)
+	}
+	task.ContextFiles = nil
+	task.PromptHint = "Return errors, do not panic."
+	if hinted := string(loader.HashContent(task)); hinted == plain || !strings.Contains(hinted, task.PromptHint) {
+		t.Fatalf("HashContent() = %q, want the prompt hint included", hinted)
+	}
+	task.PromptHint = ""
+	task.Env = map[string]string{"RAND_SEED": "42", "GOFLAGS": "-mod=mod"}
+	if withEnv := string(loader.HashContent(task)); withEnv != plain+"\nenv: GOFLAGS=-mod=mod\nenv: RAND_SEED=42" {
+		t.Fatalf("HashContent() = %q, want sorted env entries appended", withEnv)
+	}
+}